@@ -0,0 +1,108 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gotoailab/simple-db-web/handlers"
+)
+
+// SQLiteAuditSink 基于 SQLite 的审计日志
+type SQLiteAuditSink struct {
+	db *sql.DB
+}
+
+// NewSQLiteAuditSink 创建 SQLite 审计日志（使用 InitDB 创建的 audit_log 表）
+func NewSQLiteAuditSink(db *sql.DB) *SQLiteAuditSink {
+	return &SQLiteAuditSink{db: db}
+}
+
+// Record 保存一条记录
+func (s *SQLiteAuditSink) Record(entry handlers.AuditEntry) error {
+	args := []byte("[]")
+	if len(entry.Args) > 0 {
+		var err error
+		if args, err = json.Marshal(entry.Args); err != nil {
+			return fmt.Errorf("failed to encode audit args: %w", err)
+		}
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO audit_log (username, connection_name, db_type, database_name, table_name, operation, source, statement, args, affected_rows, success, error, executed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.User, entry.ConnectionName, entry.DbType, entry.Database, entry.Table, entry.Operation, entry.Source,
+		entry.Statement, string(args), entry.AffectedRows, entry.Success, entry.Error, entry.ExecutedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// Search 按执行时间倒序返回符合条件的一页记录
+func (s *SQLiteAuditSink) Search(filter handlers.AuditFilter) ([]handlers.AuditEntry, int, error) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.User != "" {
+		conditions = append(conditions, "username = ?")
+		args = append(args, filter.User)
+	}
+	if filter.ConnectionName != "" {
+		conditions = append(conditions, "connection_name = ?")
+		args = append(args, filter.ConnectionName)
+	}
+	if filter.Table != "" {
+		conditions = append(conditions, "table_name = ?")
+		args = append(args, filter.Table)
+	}
+	if filter.Operation != "" {
+		conditions = append(conditions, "operation = upper(?)")
+		args = append(args, filter.Operation)
+	}
+	if filter.Search != "" {
+		conditions = append(conditions, "instr(lower(statement), lower(?)) > 0")
+		args = append(args, filter.Search)
+	}
+	switch filter.Status {
+	case "success":
+		conditions = append(conditions, "success = 1")
+	case "error":
+		conditions = append(conditions, "success = 0")
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM audit_log WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit log: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(
+		`SELECT id, username, connection_name, db_type, database_name, table_name, operation, source, statement, args, affected_rows, success, error, executed_at
+		FROM audit_log WHERE `+where+` ORDER BY id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]handlers.AuditEntry, 0)
+	for rows.Next() {
+		var entry handlers.AuditEntry
+		var entryArgs string
+		if err := rows.Scan(&entry.ID, &entry.User, &entry.ConnectionName, &entry.DbType, &entry.Database, &entry.Table, &entry.Operation,
+			&entry.Source, &entry.Statement, &entryArgs, &entry.AffectedRows, &entry.Success, &entry.Error, &entry.ExecutedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		if err := json.Unmarshal([]byte(entryArgs), &entry.Args); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse audit args: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
@@ -66,6 +66,63 @@ func createTables() error {
 	CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at);
 	`
 
+	// 查询历史表
+	queryHistoryTable := `
+	CREATE TABLE IF NOT EXISTS query_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL,
+		connection_name TEXT NOT NULL,
+		db_type TEXT NOT NULL,
+		database_name TEXT NOT NULL,
+		query_text TEXT NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		row_count INTEGER NOT NULL,
+		success INTEGER NOT NULL,
+		error TEXT NOT NULL,
+		executed_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_query_history_username ON query_history(username, id);
+	CREATE INDEX IF NOT EXISTS idx_query_history_executed_at ON query_history(executed_at);
+	`
+
+	// 保存的查询表（tags 为 JSON 数组）
+	savedQueriesTable := `
+	CREATE TABLE IF NOT EXISTS saved_queries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		query_text TEXT NOT NULL,
+		tags TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		shared INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_saved_queries_owner ON saved_queries(owner);
+	`
+
+	// 审计日志表（args 为 JSON 数组）
+	auditLogTable := `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL,
+		connection_name TEXT NOT NULL,
+		db_type TEXT NOT NULL,
+		database_name TEXT NOT NULL,
+		table_name TEXT NOT NULL,
+		operation TEXT NOT NULL,
+		source TEXT NOT NULL,
+		statement TEXT NOT NULL,
+		args TEXT NOT NULL,
+		affected_rows INTEGER NOT NULL,
+		success INTEGER NOT NULL,
+		error TEXT NOT NULL,
+		executed_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_username ON audit_log(username);
+	CREATE INDEX IF NOT EXISTS idx_audit_log_table_name ON audit_log(table_name);
+	`
+
 	if _, err := DB.Exec(usersTable); err != nil {
 		return fmt.Errorf("failed to create users table: %w", err)
 	}
@@ -74,6 +131,18 @@ func createTables() error {
 		return fmt.Errorf("failed to create sessions table: %w", err)
 	}
 
+	if _, err := DB.Exec(queryHistoryTable); err != nil {
+		return fmt.Errorf("failed to create query_history table: %w", err)
+	}
+
+	if _, err := DB.Exec(savedQueriesTable); err != nil {
+		return fmt.Errorf("failed to create saved_queries table: %w", err)
+	}
+
+	if _, err := DB.Exec(auditLogTable); err != nil {
+		return fmt.Errorf("failed to create audit_log table: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/handlers"
+)
+
+// SQLiteQueryHistoryStore 基于 SQLite 的查询历史存储
+type SQLiteQueryHistoryStore struct {
+	db        *sql.DB
+	retention handlers.QueryHistoryRetention
+}
+
+// NewSQLiteQueryHistoryStore 创建 SQLite 查询历史存储（使用 InitDB 创建的 query_history 表）
+func NewSQLiteQueryHistoryStore(db *sql.DB, retention handlers.QueryHistoryRetention) *SQLiteQueryHistoryStore {
+	return &SQLiteQueryHistoryStore{db: db, retention: retention}
+}
+
+// Add 保存一条记录，并按保留策略清理该用户的旧记录
+func (s *SQLiteQueryHistoryStore) Add(entry handlers.QueryHistoryEntry) error {
+	_, err := s.db.Exec(
+		`INSERT INTO query_history (username, connection_name, db_type, database_name, query_text, duration_ms, row_count, success, error, executed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.User, entry.ConnectionName, entry.DbType, entry.Database, entry.Query,
+		entry.DurationMs, entry.RowCount, entry.Success, entry.Error, entry.ExecutedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert query history: %w", err)
+	}
+
+	if s.retention.MaxAge > 0 {
+		if _, err := s.db.Exec("DELETE FROM query_history WHERE executed_at < ?", time.Now().Add(-s.retention.MaxAge)); err != nil {
+			return fmt.Errorf("failed to prune query history: %w", err)
+		}
+	}
+	if s.retention.MaxEntries > 0 {
+		_, err := s.db.Exec(
+			`DELETE FROM query_history WHERE username = ? AND id NOT IN
+			(SELECT id FROM query_history WHERE username = ? ORDER BY id DESC LIMIT ?)`,
+			entry.User, entry.User, s.retention.MaxEntries,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to prune query history: %w", err)
+		}
+	}
+	return nil
+}
+
+// Search 按执行时间倒序返回符合条件的一页记录
+func (s *SQLiteQueryHistoryStore) Search(filter handlers.QueryHistoryFilter) ([]handlers.QueryHistoryEntry, int, error) {
+	conditions := []string{"username = ?"}
+	args := []interface{}{filter.User}
+	if filter.ConnectionName != "" {
+		conditions = append(conditions, "connection_name = ?")
+		args = append(args, filter.ConnectionName)
+	}
+	if filter.Search != "" {
+		conditions = append(conditions, "instr(lower(query_text), lower(?)) > 0")
+		args = append(args, filter.Search)
+	}
+	switch filter.Status {
+	case "success":
+		conditions = append(conditions, "success = 1")
+	case "error":
+		conditions = append(conditions, "success = 0")
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM query_history WHERE "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count query history: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(
+		`SELECT id, username, connection_name, db_type, database_name, query_text, duration_ms, row_count, success, error, executed_at
+		FROM query_history WHERE `+where+` ORDER BY id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]handlers.QueryHistoryEntry, 0)
+	for rows.Next() {
+		var entry handlers.QueryHistoryEntry
+		if err := rows.Scan(&entry.ID, &entry.User, &entry.ConnectionName, &entry.DbType, &entry.Database, &entry.Query,
+			&entry.DurationMs, &entry.RowCount, &entry.Success, &entry.Error, &entry.ExecutedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan query history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// Delete 删除用户的记录，ids 为空时删除该用户的全部记录
+func (s *SQLiteQueryHistoryStore) Delete(user string, ids []int64) error {
+	query := "DELETE FROM query_history WHERE username = ?"
+	args := []interface{}{user}
+	if len(ids) > 0 {
+		query += " AND id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	if _, err := s.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to delete query history: %w", err)
+	}
+	return nil
+}
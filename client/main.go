@@ -44,6 +44,9 @@ type Config struct {
 	AutoOpen    bool
 	DBPath      string
 	Connections string // 预设连接 YAML 文件路径
+	HistoryMax  int    // 每个用户最多保留的查询历史条数
+	HistoryDays int    // 查询历史保留天数
+	AuditLog    string // 审计日志文件路径（JSON Lines）
 }
 
 // ConnectionsConfig YAML 配置文件结构
@@ -94,6 +97,34 @@ func main() {
 		server.SetCustomScript(userManagementScript)
 	}
 
+	// 查询历史和保存的查询：启用认证时按用户保存到 SQLite，否则保存在内存中
+	retention := handlers.QueryHistoryRetention{
+		MaxEntries: config.HistoryMax,
+		MaxAge:     time.Duration(config.HistoryDays) * 24 * time.Hour,
+	}
+	if config.EnableAuth {
+		server.SetQueryHistoryStore(NewSQLiteQueryHistoryStore(DB, retention))
+		server.SetSavedQueryStore(NewSQLiteSavedQueryStore(DB))
+		server.SetUserResolver(requestUsername)
+	} else {
+		server.SetQueryHistoryStore(handlers.NewMemoryQueryHistoryStore(retention))
+	}
+
+	// 审计日志：指定 -audit-log 时写入 JSON Lines 文件，否则启用认证时写入 SQLite；只有管理员可以查看
+	if config.AuditLog != "" {
+		sink, err := handlers.NewFileAuditSink(config.AuditLog)
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer sink.Close()
+		server.SetAuditSink(sink)
+	} else if config.EnableAuth {
+		server.SetAuditSink(NewSQLiteAuditSink(DB))
+	}
+	if config.EnableAuth {
+		server.SetAdminResolver(requestIsAdmin)
+	}
+
 	// 加载预设连接
 	if config.Connections != "" {
 		if err := loadPresetConnections(server, config.Connections); err != nil {
@@ -239,6 +270,9 @@ func parseFlags() *Config {
 	flag.BoolVar(&config.AutoOpen, "open", false, "Automatically open browser after startup")
 	flag.StringVar(&config.DBPath, "db", "client.db", "Database file path (only used when auth is enabled)")
 	flag.StringVar(&config.Connections, "connections", "", "Path to YAML file containing preset connections")
+	flag.IntVar(&config.HistoryMax, "history-max", 500, "Maximum query history entries kept per user (0 means unlimited)")
+	flag.IntVar(&config.HistoryDays, "history-days", 30, "Days to keep query history (0 means unlimited)")
+	flag.StringVar(&config.AuditLog, "audit-log", "", "Audit log file path (JSON lines); defaults to the database file when auth is enabled")
 
 	flag.Parse()
 
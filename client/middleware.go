@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -15,6 +16,28 @@ func SetRoutePrefix(prefix string) {
 	routePrefix = prefix
 }
 
+// usernameContextKey 请求上下文中保存登录用户名的键
+type usernameContextKey struct{}
+
+// requestUsername 返回请求的登录用户名（由 AuthMiddleware 写入请求上下文），供 handlers.Server 识别用户
+func requestUsername(r *http.Request) string {
+	username, _ := r.Context().Value(usernameContextKey{}).(string)
+	return username
+}
+
+// userIDContextKey 请求上下文中保存登录用户 ID 的键
+type userIDContextKey struct{}
+
+// requestIsAdmin 判断请求的登录用户是否为管理员，供 handlers.Server 限制审计日志的查看
+func requestIsAdmin(r *http.Request) bool {
+	userID, ok := r.Context().Value(userIDContextKey{}).(int)
+	if !ok {
+		return false
+	}
+	user, err := GetUserByID(userID)
+	return err == nil && user.IsAdmin
+}
+
 // AuthMiddleware 认证中间件
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -84,6 +107,9 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("user_id", session.UserID)
 		c.Set("username", session.Username)
 		c.Set("session_id", sessionID)
+		// 核心服务器的处理函数只能拿到 http.Request，用户名和用户 ID 同时写入请求上下文
+		ctx := context.WithValue(c.Request.Context(), usernameContextKey{}, session.Username)
+		c.Request = c.Request.WithContext(context.WithValue(ctx, userIDContextKey{}, session.UserID))
 
 		c.Next()
 	}
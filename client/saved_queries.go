@@ -0,0 +1,120 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gotoailab/simple-db-web/handlers"
+)
+
+// SQLiteSavedQueryStore 基于 SQLite 的保存的查询存储
+type SQLiteSavedQueryStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteSavedQueryStore 创建 SQLite 保存的查询存储（使用 InitDB 创建的 saved_queries 表）
+func NewSQLiteSavedQueryStore(db *sql.DB) *SQLiteSavedQueryStore {
+	return &SQLiteSavedQueryStore{db: db}
+}
+
+const savedQueryColumns = "id, name, description, query_text, tags, owner, shared, created_at, updated_at"
+
+// scanSavedQuery 读取一行保存的查询
+func scanSavedQuery(scan func(dest ...interface{}) error) (*handlers.SavedQuery, error) {
+	var query handlers.SavedQuery
+	var tags string
+	if err := scan(&query.ID, &query.Name, &query.Description, &query.Query, &tags, &query.Owner, &query.Shared, &query.CreatedAt, &query.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tags), &query.Tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tags: %w", err)
+	}
+	return &query, nil
+}
+
+// List 按名称顺序返回用户创建的和共享的查询
+func (s *SQLiteSavedQueryStore) List(filter handlers.SavedQueryFilter) ([]handlers.SavedQuery, error) {
+	query := "SELECT " + savedQueryColumns + " FROM saved_queries WHERE (owner = ? OR shared = 1)"
+	args := []interface{}{filter.User}
+	if filter.Search != "" {
+		query += " AND (instr(lower(name), lower(?)) > 0 OR instr(lower(description), lower(?)) > 0 OR instr(lower(query_text), lower(?)) > 0)"
+		args = append(args, filter.Search, filter.Search, filter.Search)
+	}
+	if filter.Tag != "" {
+		query += " AND EXISTS (SELECT 1 FROM json_each(saved_queries.tags) WHERE value = ?)"
+		args = append(args, filter.Tag)
+	}
+	query += " ORDER BY name, id"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved queries: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]handlers.SavedQuery, 0)
+	for rows.Next() {
+		saved, err := scanSavedQuery(rows.Scan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved query: %w", err)
+		}
+		result = append(result, *saved)
+	}
+	return result, rows.Err()
+}
+
+// Get 获取查询，不存在时返回 nil
+func (s *SQLiteSavedQueryStore) Get(id int64) (*handlers.SavedQuery, error) {
+	row := s.db.QueryRow("SELECT "+savedQueryColumns+" FROM saved_queries WHERE id = ?", id)
+	saved, err := scanSavedQuery(row.Scan)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved query: %w", err)
+	}
+	return saved, nil
+}
+
+// Save 新建或更新查询
+func (s *SQLiteSavedQueryStore) Save(query handlers.SavedQuery) (*handlers.SavedQuery, error) {
+	tags, err := json.Marshal(query.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if query.Tags == nil {
+		tags = []byte("[]")
+	}
+
+	if query.ID == 0 {
+		result, err := s.db.Exec(
+			"INSERT INTO saved_queries (name, description, query_text, tags, owner, shared, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			query.Name, query.Description, query.Query, string(tags), query.Owner, query.Shared, query.CreatedAt, query.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create saved query: %w", err)
+		}
+		if query.ID, err = result.LastInsertId(); err != nil {
+			return nil, fmt.Errorf("failed to get saved query ID: %w", err)
+		}
+		return &query, nil
+	}
+
+	_, err = s.db.Exec(
+		"UPDATE saved_queries SET name = ?, description = ?, query_text = ?, tags = ?, owner = ?, shared = ?, created_at = ?, updated_at = ? WHERE id = ?",
+		query.Name, query.Description, query.Query, string(tags), query.Owner, query.Shared, query.CreatedAt, query.UpdatedAt, query.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved query: %w", err)
+	}
+	return &query, nil
+}
+
+// Delete 删除查询
+func (s *SQLiteSavedQueryStore) Delete(id int64) error {
+	if _, err := s.db.Exec("DELETE FROM saved_queries WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete saved query: %w", err)
+	}
+	return nil
+}
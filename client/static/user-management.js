@@ -61,6 +61,7 @@
 
         if (currentUser.is_admin) {
             menuItems.splice(1, 0, { text: t('user.management'), key: 'user.management', action: showUserManagementModal });
+            menuItems.splice(2, 0, { text: t('audit.title'), key: 'audit.title', action: showAuditLogModal });
         }
         
         menuItems.push({ text: t('user.logout'), key: 'user.logout', action: handleLogout, style: 'color: var(--danger-color);' });
@@ -211,6 +212,105 @@
             });
         }
 
+        // 审计日志模态框（仅管理员）
+        function showAuditLogModal() {
+            const modal = createModal(t('audit.title'),
+                '<div style="display: flex; gap: 0.5rem; margin-bottom: 1rem; flex-wrap: wrap;">' +
+                    '<input type="text" id="auditUser" class="form-control" style="flex: 1; min-width: 120px;" placeholder="' + t('audit.user') + '">' +
+                    '<input type="text" id="auditTable" class="form-control" style="flex: 1; min-width: 120px;" placeholder="' + t('audit.table') + '">' +
+                    '<input type="text" id="auditSearch" class="form-control" style="flex: 2; min-width: 160px;" placeholder="' + t('audit.search') + '">' +
+                    '<select id="auditStatus" class="form-control" style="width: auto;">' +
+                        '<option value="">' + t('query.historyAllStatus') + '</option>' +
+                        '<option value="success">' + t('query.historySuccess') + '</option>' +
+                        '<option value="error">' + t('query.historyFailed') + '</option>' +
+                    '</select>' +
+                '</div>' +
+                '<div id="auditList" style="max-height: 60vh; overflow-y: auto;"></div>' +
+                '<div style="display: flex; justify-content: flex-end; align-items: center; gap: 0.5rem; margin-top: 1rem;">' +
+                    '<button id="auditPrev" class="btn btn-secondary">' + t('data.prevPage') + '</button>' +
+                    '<span id="auditPageInfo" style="font-size: 0.875rem; color: var(--text-secondary);"></span>' +
+                    '<button id="auditNext" class="btn btn-secondary">' + t('data.nextPage') + '</button>' +
+                '</div>'
+            );
+            modal.firstChild.style.maxWidth = '1000px';
+
+            const pageSize = 50;
+            let page = 1;
+            let searchTimer = null;
+            const load = () => {
+                const params = new URLSearchParams({ page: page, pageSize: pageSize });
+                ['auditUser', 'auditTable', 'auditSearch', 'auditStatus'].forEach(id => {
+                    const value = document.getElementById(id).value.trim();
+                    if (value) {
+                        params.set({ auditUser: 'user', auditTable: 'table', auditSearch: 'search', auditStatus: 'status' }[id], value);
+                    }
+                });
+                fetch('/api/audit?' + params)
+                    .then(res => res.json())
+                    .then(data => {
+                        if (!data.success) {
+                            throw new Error(t(data.errorCode || 'audit.loadFailed'));
+                        }
+                        displayAuditLog(data.entries || []);
+                        const pages = Math.max(1, Math.ceil(data.total / pageSize));
+                        document.getElementById('auditPageInfo').textContent = t('query.historyPage', { page: page, pages: pages, total: data.total });
+                        document.getElementById('auditPrev').disabled = page <= 1;
+                        document.getElementById('auditNext').disabled = page >= pages;
+                    })
+                    .catch(err => {
+                        document.getElementById('auditList').innerHTML = '<div style="padding: 1rem; color: var(--danger-color);">' + escapeHtml(err.message) + '</div>';
+                    });
+            };
+            const reload = () => {
+                page = 1;
+                load();
+            };
+
+            ['auditUser', 'auditTable', 'auditSearch'].forEach(id => {
+                document.getElementById(id).addEventListener('input', () => {
+                    clearTimeout(searchTimer);
+                    searchTimer = setTimeout(reload, 300);
+                });
+            });
+            document.getElementById('auditStatus').addEventListener('change', reload);
+            document.getElementById('auditPrev').addEventListener('click', () => {
+                if (page > 1) {
+                    page--;
+                    load();
+                }
+            });
+            document.getElementById('auditNext').addEventListener('click', () => {
+                page++;
+                load();
+            });
+            load();
+        }
+
+        function displayAuditLog(entries) {
+            const auditList = document.getElementById('auditList');
+            if (entries.length === 0) {
+                auditList.innerHTML = '<div style="padding: 2rem; text-align: center; color: var(--text-secondary); font-size: 0.875rem;">' + t('audit.empty') + '</div>';
+                return;
+            }
+            auditList.innerHTML = entries.map(entry =>
+                '<div style="padding: 0.75rem; border-bottom: 1px solid var(--border-color);">' +
+                    '<div style="display: flex; flex-wrap: wrap; gap: 0.75rem; font-size: 0.75rem; color: var(--text-secondary); margin-bottom: 0.35rem;">' +
+                        (entry.success
+                            ? '<span style="color: var(--success-color);">' + t('query.historySuccess') + '</span>'
+                            : '<span style="color: var(--danger-color);">' + t('query.historyFailed') + '</span>') +
+                        '<span>' + escapeHtml(new Date(entry.executedAt).toLocaleString()) + '</span>' +
+                        '<span>' + escapeHtml(entry.user || '-') + '</span>' +
+                        '<span>' + escapeHtml(entry.connectionName) + (entry.database ? ' / ' + escapeHtml(entry.database) : '') + (entry.table ? ' / ' + escapeHtml(entry.table) : '') + '</span>' +
+                        '<span>' + escapeHtml(entry.operation) + '</span>' +
+                        '<span>' + t('query.historyRows', { count: entry.affectedRows }) + '</span>' +
+                    '</div>' +
+                    '<div style="font-family: monospace; font-size: 0.875rem; white-space: pre-wrap; word-break: break-all; max-height: 6em; overflow: hidden;">' + escapeHtml(entry.statement) + '</div>' +
+                    (entry.args && entry.args.length > 0 ? '<div style="font-family: monospace; font-size: 0.75rem; color: var(--text-secondary);">' + escapeHtml(JSON.stringify(entry.args)) + '</div>' : '') +
+                    (entry.error ? '<div style="font-size: 0.75rem; color: var(--danger-color);">' + escapeHtml(entry.error) + '</div>' : '') +
+                '</div>'
+            ).join('');
+        }
+
         function loadUsersList() {
             fetch('/api/users')
                 .then(res => res.json())
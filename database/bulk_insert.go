@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BulkInsertDatabase 支持批量插入的数据库接口扩展，用于导入数据
+type BulkInsertDatabase interface {
+	// InsertRows 用一条语句插入多行，rows 中每行的值与 columns 一一对应，返回影响行数
+	// keys 不为空时按这些列（通常是主键）upsert：已存在的行更新为新值，不存在的行插入
+	InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error)
+}
+
+// TableTruncateDatabase 支持清空表的数据库接口扩展
+type TableTruncateDatabase interface {
+	// TruncateTable 删除表中的所有行；在事务中执行时使用 DELETE，保证可以回滚
+	TruncateTable(ctx context.Context, tableName string) error
+}
+
+// ImportBatchRows 批量插入时每条语句的最大行数
+const ImportBatchRows = 500
+
+// ImportBatchSize 返回一条批量插入语句的行数，保证绑定参数的个数不超过各数据库的限制
+// SQL Server 每条语句最多 2100 个参数，SQLite 默认最多 999 个，其他数据库按 65535 计算；Oracle 的 INSERT ALL 行数较多时解析很慢，最多 100 行
+func ImportBatchSize(dbType string, columnCount int) int {
+	limit := 65535
+	size := ImportBatchRows
+	switch dbType {
+	case "sqlserver":
+		limit = 2099
+	case "sqlite":
+		limit = 999
+	case "oracle":
+		size = 100
+	}
+	if columnCount > 0 && limit/columnCount < size {
+		size = limit / columnCount
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// BuildInsertRowsSQL 构建参数化的多行 INSERT 语句，Oracle 使用 INSERT ALL
+// keys 不为空时构建 upsert 语句：MySQL 使用 ON DUPLICATE KEY UPDATE，PostgreSQL 和 SQLite 使用 ON CONFLICT，
+// SQL Server 和 Oracle 使用 MERGE，H2 使用 MERGE ... KEY；ClickHouse 不支持 upsert
+// 参数按方言编号绑定（SQL Server 为 @pN，Oracle 为 :N），编号在所有行之间连续
+func BuildInsertRowsSQL(dbType, tableName string, columns []string, rows [][]interface{}, keys []string) (string, []interface{}, error) {
+	if err := validateIdentifier(tableName); err != nil {
+		return "", nil, err
+	}
+	if len(columns) == 0 {
+		return "", nil, fmt.Errorf("at least one column value is required")
+	}
+	if len(rows) == 0 {
+		return "", nil, fmt.Errorf("no rows to insert")
+	}
+
+	quote := getQuoteFunc(dbType)
+	placeholder := getPlaceholderFunc(dbType)
+	quoted := make([]string, len(columns))
+	isKey := make(map[string]bool, len(keys))
+	for _, name := range keys {
+		isKey[name] = true
+	}
+	var updates []string
+	for i, name := range columns {
+		if err := validateIdentifier(name); err != nil {
+			return "", nil, err
+		}
+		quoted[i] = quote(name)
+		if !isKey[name] {
+			updates = append(updates, name)
+		}
+	}
+	for _, name := range keys {
+		found := false
+		for _, column := range columns {
+			found = found || column == name
+		}
+		if !found {
+			return "", nil, fmt.Errorf("key column %s is not in the inserted columns", name)
+		}
+	}
+
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	tuples := make([]string, len(rows))
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return "", nil, fmt.Errorf("row %d has %d values, expected %d", i+1, len(row), len(columns))
+		}
+		holders := make([]string, len(row))
+		for j, value := range row {
+			args = append(args, value)
+			holders[j] = placeholder(len(args))
+		}
+		tuples[i] = "(" + strings.Join(holders, ", ") + ")"
+	}
+
+	table := sqlTableRef(dbType, tableName)
+	columnList := strings.Join(quoted, ", ")
+	if len(keys) == 0 {
+		if dbType == "oracle" {
+			var b strings.Builder
+			b.WriteString("INSERT ALL")
+			for _, tuple := range tuples {
+				fmt.Fprintf(&b, " INTO %s (%s) VALUES %s", table, columnList, tuple)
+			}
+			b.WriteString(" SELECT 1 FROM DUAL")
+			return b.String(), args, nil
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, columnList, strings.Join(tuples, ", ")), args, nil
+	}
+
+	// sourceRef 返回 MERGE 语句中源列和目标列的引用
+	sourceRef := func(prefix string, names []string) []string {
+		refs := make([]string, len(names))
+		for i, name := range names {
+			refs[i] = prefix + quote(name)
+		}
+		return refs
+	}
+	assignments := func(format string) string {
+		sets := make([]string, len(updates))
+		for i, name := range updates {
+			sets[i] = strings.ReplaceAll(format, "%s", quote(name))
+		}
+		return strings.Join(sets, ", ")
+	}
+	conditions := make([]string, len(keys))
+	for i, name := range keys {
+		conditions[i] = fmt.Sprintf("target.%s = source.%s", quote(name), quote(name))
+	}
+
+	switch dbType {
+	case "mysql":
+		set := assignments("%s = VALUES(%s)")
+		if set == "" {
+			// 只有主键列时保持原值不变
+			set = fmt.Sprintf("%s = %s", quote(keys[0]), quote(keys[0]))
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s", table, columnList, strings.Join(tuples, ", "), set), args, nil
+	case "postgresql", "sqlite":
+		action := "DO NOTHING"
+		if len(updates) > 0 {
+			action = "DO UPDATE SET " + assignments("%s = EXCLUDED.%s")
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) %s", table, columnList, strings.Join(tuples, ", "), strings.Join(sourceRef("", keys), ", "), action), args, nil
+	case "h2":
+		return fmt.Sprintf("MERGE INTO %s (%s) KEY (%s) VALUES %s", table, columnList, strings.Join(sourceRef("", keys), ", "), strings.Join(tuples, ", ")), args, nil
+	case "sqlserver", "oracle":
+		var source string
+		if dbType == "sqlserver" {
+			source = fmt.Sprintf("(VALUES %s) AS source (%s)", strings.Join(tuples, ", "), columnList)
+		} else {
+			// Oracle 没有 VALUES 表构造器，使用 SELECT ... FROM DUAL UNION ALL
+			selects := make([]string, len(rows))
+			index := 0
+			for i := range rows {
+				fields := make([]string, len(columns))
+				for j := range columns {
+					index++
+					fields[j] = placeholder(index)
+					if i == 0 {
+						fields[j] += " " + quoted[j]
+					}
+				}
+				selects[i] = "SELECT " + strings.Join(fields, ", ") + " FROM DUAL"
+			}
+			source = "(" + strings.Join(selects, " UNION ALL ") + ") source"
+		}
+
+		var b strings.Builder
+		if dbType == "sqlserver" {
+			fmt.Fprintf(&b, "MERGE INTO %s AS target USING %s ON %s", table, source, strings.Join(conditions, " AND "))
+		} else {
+			fmt.Fprintf(&b, "MERGE INTO %s target USING %s ON (%s)", table, source, strings.Join(conditions, " AND "))
+		}
+		if len(updates) > 0 {
+			b.WriteString(" WHEN MATCHED THEN UPDATE SET " + assignments("target.%s = source.%s"))
+		}
+		fmt.Fprintf(&b, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", columnList, strings.Join(sourceRef("source.", columns), ", "))
+		if dbType == "sqlserver" {
+			// SQL Server 的 MERGE 必须以分号结束
+			b.WriteString(";")
+		}
+		return b.String(), args, nil
+	default:
+		return "", nil, fmt.Errorf("upsert is not supported for %s", dbType)
+	}
+}
+
+// BuildTruncateTableSQL 构建清空表的语句
+// SQLite 没有 TRUNCATE；在事务中使用 DELETE，因为 MySQL 和 Oracle 的 TRUNCATE 会隐式提交事务
+func BuildTruncateTableSQL(dbType, tableName string, inTransaction bool) (string, error) {
+	if err := validateIdentifier(tableName); err != nil {
+		return "", err
+	}
+	if inTransaction || dbType == "sqlite" {
+		return "DELETE FROM " + sqlTableRef(dbType, tableName), nil
+	}
+	return "TRUNCATE TABLE " + sqlTableRef(dbType, tableName), nil
+}
+
+// insertSQLRows 使用参数化的多行 INSERT（或 upsert）语句插入行
+func insertSQLRows(ctx context.Context, db sqlExecer, dbType, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	query, args, err := BuildInsertRowsSQL(dbType, tableName, columns, rows, keys)
+	if err != nil {
+		return 0, err
+	}
+	return execSQLRows(ctx, db, query, args, "insert")
+}
+
+// truncateSQLTable 清空表
+func truncateSQLTable(ctx context.Context, db sqlExecer, dbType, tableName string, inTransaction bool) error {
+	query, err := BuildTruncateTableSQL(dbType, tableName, inTransaction)
+	if err != nil {
+		return err
+	}
+	if db == nil || reflect.ValueOf(db).IsNil() {
+		return fmt.Errorf("database not connected")
+	}
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to truncate table: %w", err)
+	}
+	return nil
+}
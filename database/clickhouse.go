@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
@@ -212,6 +213,11 @@ func (c *ClickHouse) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询
 func (c *ClickHouse) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return c.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (c *ClickHouse) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	// 获取存储的当前数据库名，确保连接在正确的数据库上下文中
 	c.dbMutex.RLock()
 	currentDB := c.currentDatabase
@@ -222,12 +228,12 @@ func (c *ClickHouse) ExecuteQuery(query string) ([]map[string]interface{}, error
 	if currentDB != "" {
 		// 使用 Exec 执行 USE 语句，确保连接在正确的数据库上下文中
 		// 注意：虽然连接池可能复用连接，但每次查询前执行 USE 可以确保正确性
-		if _, err := c.db.Exec(fmt.Sprintf("USE `%s`", currentDB)); err != nil {
+		if _, err := c.db.ExecContext(ctx, fmt.Sprintf("USE `%s`", currentDB)); err != nil {
 			return nil, fmt.Errorf("failed to switch database context: %w", err)
 		}
 	}
 
-	rows, err := c.db.Query(query)
+	rows, err := c.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -265,19 +271,301 @@ func (c *ClickHouse) ExecuteQuery(query string) ([]map[string]interface{}, error
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (c *ClickHouse) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	// 与 ExecuteQuery 一致，执行前先切换到当前数据库
+	if currentDB != "" {
+		if _, err := c.db.ExecContext(ctx, fmt.Sprintf("USE `%s`", currentDB)); err != nil {
+			return nil, fmt.Errorf("failed to switch database context: %w", err)
+		}
+	}
+
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// InsertRow 使用参数化语句插入一行（ClickHouse 没有主键，不返回生成的键）
+func (c *ClickHouse) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	query, args, err := BuildInsertRowSQL("clickhouse", tableName, values, nil)
+	if err != nil {
+		return nil, err
+	}
+	// 使用 database.table 格式确保写入当前数据库
+	if currentDB != "" {
+		query = strings.Replace(query, "INSERT INTO ", fmt.Sprintf("INSERT INTO `%s`.", currentDB), 1)
+	}
+	if _, err := c.db.ExecContext(ctx, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to insert row: %w", err)
+	}
+	return nil, nil
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入到当前数据库，ClickHouse 不支持 upsert
+func (c *ClickHouse) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	if c.db == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	query, args, err := BuildInsertRowsSQL("clickhouse", tableName, columns, rows, keys)
+	if err != nil {
+		return 0, err
+	}
+	if currentDB != "" {
+		query = strings.Replace(query, "INSERT INTO ", fmt.Sprintf("INSERT INTO `%s`.", currentDB), 1)
+	}
+	if _, err := c.db.ExecContext(ctx, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to insert rows: %w", err)
+	}
+	// ClickHouse 不返回准确的影响行数
+	return int64(len(rows)), nil
+}
+
+// QueryArgsContext 在当前数据库中执行带绑定参数的查询（USE 和查询在同一个连接上执行）
+func (c *ClickHouse) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if currentDB != "" {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", currentDB)); err != nil {
+			return nil, fmt.Errorf("failed to switch database context: %w", err)
+		}
+	}
+	return querySQLArgs(ctx, conn, query, args)
+}
+
+// ExecArgsContext ClickHouse 驱动只能在批量写入中绑定参数，不支持带参数的更新类语句
+func (c *ClickHouse) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return 0, fmt.Errorf("ClickHouse does not support parameterized write statements")
+}
+
+// TruncateTable 清空当前数据库中的表
+func (c *ClickHouse) TruncateTable(ctx context.Context, tableName string) error {
+	if c.db == nil {
+		return fmt.Errorf("database not connected")
+	}
+	if err := validateIdentifier(tableName); err != nil {
+		return err
+	}
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	query := fmt.Sprintf("TRUNCATE TABLE `%s`", tableName)
+	if currentDB != "" {
+		query = fmt.Sprintf("TRUNCATE TABLE `%s`.`%s`", currentDB, tableName)
+	}
+	if _, err := c.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to truncate table: %w", err)
+	}
+	return nil
+}
+
+// CreateTable 在当前数据库中按列信息建表
+func (c *ClickHouse) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	query, err := BuildCreateTableSQL("clickhouse", tableName, columns)
+	if err != nil {
+		return err
+	}
+	if c.db == nil {
+		return fmt.Errorf("database not connected")
+	}
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	if currentDB != "" {
+		query = strings.Replace(query, "CREATE TABLE ", fmt.Sprintf("CREATE TABLE `%s`.", currentDB), 1)
+	}
+	if _, err := c.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// metadataDatabase 返回当前数据库名，未设置时返回错误
+func (c *ClickHouse) metadataDatabase() (string, error) {
+	if c.db == nil {
+		return "", fmt.Errorf("database not connected")
+	}
+	c.dbMutex.RLock()
+	defer c.dbMutex.RUnlock()
+	if c.currentDatabase == "" {
+		return "", fmt.Errorf("current database not set")
+	}
+	return c.currentDatabase, nil
+}
+
+// GetTableMetadata 从 system 表读取表和列的注释、主键（排序键）以及跳数索引（ClickHouse 没有外键）
+func (c *ClickHouse) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	currentDB, err := c.metadataDatabase()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := c.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+
+	err = queryMetadata(ctx, c.db, "SELECT comment, primary_key, sorting_key FROM system.tables WHERE database = ? AND name = ?", func(rows *sql.Rows) error {
+		var primaryKey, sortingKey string
+		if err := rows.Scan(&meta.Comment, &primaryKey, &sortingKey); err != nil {
+			return err
+		}
+		// 未单独指定 PRIMARY KEY 时主键与排序键相同
+		if primaryKey != "" {
+			meta.Indexes = append(meta.Indexes, IndexInfo{Name: "PRIMARY", Columns: splitKeyExpression(primaryKey), Primary: true, Type: "PRIMARY KEY"})
+		}
+		if sortingKey != "" && sortingKey != primaryKey {
+			meta.Indexes = append(meta.Indexes, IndexInfo{Name: "ORDER BY", Columns: splitKeyExpression(sortingKey), Type: "SORTING KEY"})
+		}
+		return nil
+	}, currentDB, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table comment: %w", err)
+	}
+
+	err = queryMetadata(ctx, c.db, "SELECT name, comment FROM system.columns WHERE database = ? AND table = ? AND comment != ''", func(rows *sql.Rows) error {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return err
+		}
+		meta.ColumnComments[name] = comment
+		return nil
+	}, currentDB, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query column comments: %w", err)
+	}
+
+	err = queryMetadata(ctx, c.db, "SELECT name, expr, type FROM system.data_skipping_indices WHERE database = ? AND table = ? ORDER BY name", func(rows *sql.Rows) error {
+		var index IndexInfo
+		var expr string
+		if err := rows.Scan(&index.Name, &expr, &index.Type); err != nil {
+			return err
+		}
+		index.Columns = splitKeyExpression(expr)
+		meta.Indexes = append(meta.Indexes, index)
+		return nil
+	}, currentDB, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	return meta, nil
+}
+
+// GetViews 获取当前数据库中的视图和物化视图，定义为建表语句
+func (c *ClickHouse) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	currentDB, err := c.metadataDatabase()
+	if err != nil {
+		return nil, err
+	}
+	views := []ViewInfo{}
+	err = queryMetadata(ctx, c.db, "SELECT name, create_table_query FROM system.tables WHERE database = ? AND engine IN ('View', 'MaterializedView', 'LiveView') ORDER BY name", func(rows *sql.Rows) error {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return err
+		}
+		views = append(views, view)
+		return nil
+	}, currentDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines ClickHouse 没有存储过程，返回空列表
+func (c *ClickHouse) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	return []RoutineInfo{}, nil
+}
+
+// GetTriggers ClickHouse 没有触发器，返回空列表
+func (c *ClickHouse) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	return []TriggerInfo{}, nil
+}
+
+// ExplainQuery 使用 EXPLAIN 获取查询计划（按缩进表示层级的文本，不支持 analyze）
+// USE 和 EXPLAIN 在同一个连接上执行，保证在当前数据库的上下文中
+func (c *ClickHouse) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	dbName, err := c.metadataDatabase()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("USE `%s`", dbName)); err != nil {
+		return nil, fmt.Errorf("failed to switch database context: %w", err)
+	}
+
+	var lines []string
+	err = queryMetadata(ctx, conn, "EXPLAIN "+query, func(r *sql.Rows) error {
+		var line string
+		if err := r.Scan(&line); err != nil {
+			return err
+		}
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return parseIndentedPlan(lines), nil
+}
+
 // ExecuteUpdate 执行更新（ClickHouse 不支持 UPDATE，返回错误）
 func (c *ClickHouse) ExecuteUpdate(query string) (int64, error) {
+	return c.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新（ClickHouse 不支持 UPDATE，返回错误），ctx 取消或超时时中止执行
+func (c *ClickHouse) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
 	return 0, fmt.Errorf("ClickHouse does not support UPDATE operations")
 }
 
 // ExecuteDelete 执行删除（ClickHouse 不支持 DELETE，返回错误）
 func (c *ClickHouse) ExecuteDelete(query string) (int64, error) {
+	return c.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除（ClickHouse 不支持 DELETE，返回错误），ctx 取消或超时时中止执行
+func (c *ClickHouse) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
 	return 0, fmt.Errorf("ClickHouse does not support DELETE operations")
 }
 
 // ExecuteInsert 执行插入
 func (c *ClickHouse) ExecuteInsert(query string) (int64, error) {
-	result, err := c.db.Exec(query)
+	return c.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (c *ClickHouse) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := c.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -286,6 +574,11 @@ func (c *ClickHouse) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（ClickHouse 不支持分页，只返回10条数据）
 func (c *ClickHouse) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return c.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（ClickHouse 不支持分页，只返回10条数据），ctx 取消或超时时中止执行
+func (c *ClickHouse) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// ClickHouse 不支持分页，只返回10条数据
 	// 注意：total 返回 -1 表示不支持计数
 
@@ -311,7 +604,7 @@ func (c *ClickHouse) GetTableData(tableName string, page, pageSize int, filters
 	}
 	query += " LIMIT 10"
 
-	rows, err := c.db.Query(query, whereArgs...)
+	rows, err := c.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -354,9 +647,71 @@ func (c *ClickHouse) GetTableData(tableName string, page, pageSize int, filters
 
 // GetTableDataByID 基于主键ID获取表数据（ClickHouse不支持，返回错误）
 func (c *ClickHouse) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return c.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（ClickHouse不支持，返回错误），ctx 取消或超时时中止执行
+func (c *ClickHouse) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	return nil, 0, nil, fmt.Errorf("ClickHouse does not support ID-based pagination")
 }
 
+// GetTableDataSorted 获取表数据（多列排序，与 GetTableData 一样只返回10条数据）
+func (c *ClickHouse) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	if currentDB == "" {
+		return nil, 0, fmt.Errorf("database not set")
+	}
+
+	whereClause, whereArgs, err := BuildWhereClause("clickhouse", tableName, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build where clause: %w", err)
+	}
+
+	orderBy, err := BuildOrderByClause("clickhouse", sorts, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build order by clause: %w", err)
+	}
+
+	query := fmt.Sprintf("SELECT * FROM `%s`.`%s`", currentDB, tableName)
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	}
+	query += " LIMIT 10"
+
+	rows, err := c.db.QueryContext(ctx, query, whereArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanRowMaps(rows)
+	// 返回 -1 表示不支持总数统计
+	return results, -1, err
+}
+
+// GetTableDataByCursor 基于游标获取表数据（ClickHouse不支持，返回错误）
+func (c *ClickHouse) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return nil, 0, fmt.Errorf("ClickHouse does not support cursor-based pagination")
+}
+
+// StreamTableRows 流式读取表数据（用于导出），与 GetTableData 不同，不限制返回的行数
+func (c *ClickHouse) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	c.dbMutex.RLock()
+	currentDB := c.currentDatabase
+	c.dbMutex.RUnlock()
+
+	if currentDB == "" {
+		return nil, fmt.Errorf("database not set")
+	}
+	return streamSQLTableRows(ctx, c.db, "clickhouse", fmt.Sprintf("`%s`.`%s`", currentDB, tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（ClickHouse不支持，返回错误）
 func (c *ClickHouse) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	return nil, fmt.Errorf("ClickHouse does not support ID-based pagination")
@@ -0,0 +1,59 @@
+package database
+
+import "context"
+
+// AsContextDatabase 将 Database 转换为 ContextDatabase
+// 如果驱动本身实现了 ContextDatabase 则直接返回，否则包装为仅在执行前检查 ctx 的适配器
+func AsContextDatabase(db Database) ContextDatabase {
+	if cdb, ok := db.(ContextDatabase); ok {
+		return cdb
+	}
+	return &contextAdapter{Database: db}
+}
+
+// contextAdapter 为不支持 context 的自定义驱动提供兼容实现
+type contextAdapter struct {
+	Database
+}
+
+func (a *contextAdapter) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return a.ExecuteQuery(query)
+}
+
+func (a *contextAdapter) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return a.ExecuteUpdate(query)
+}
+
+func (a *contextAdapter) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return a.ExecuteDelete(query)
+}
+
+func (a *contextAdapter) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return a.ExecuteInsert(query)
+}
+
+func (a *contextAdapter) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	return a.GetTableData(tableName, page, pageSize, filters)
+}
+
+func (a *contextAdapter) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, nil, err
+	}
+	return a.GetTableDataByID(tableName, primaryKey, lastId, pageSize, direction, filters)
+}
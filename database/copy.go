@@ -0,0 +1,413 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TableCreateDatabase 支持按列信息建表的数据库接口扩展，用于跨连接复制表
+type TableCreateDatabase interface {
+	// CreateTable 按列信息创建表，列类型必须已经是本数据库的类型（见 TranslateColumns）
+	CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error
+}
+
+// SupportsCreateTable 判断数据库类型是否支持按列信息建表
+func SupportsCreateTable(dbType string) bool {
+	_, ok := copyTypeNames["text"][dbType]
+	return ok
+}
+
+// columnTypeSpec 列类型的通用分类及长度、精度
+// 分类：smallint、int、bigint、decimal、real、double、bool、date、datetime、datetimetz、time、json、uuid、binary、varchar、text
+type columnTypeSpec struct {
+	category  string
+	length    int // varchar 的长度
+	precision int // decimal 的精度，0 表示未指定
+	scale     int
+}
+
+// copyTypeNames 各分类在目标数据库中的类型，decimal 和 varchar 按长度、精度单独生成
+var copyTypeNames = map[string]map[string]string{
+	"smallint":   {"mysql": "SMALLINT", "postgresql": "SMALLINT", "sqlite": "INTEGER", "sqlserver": "SMALLINT", "oracle": "NUMBER(5)", "h2": "SMALLINT", "clickhouse": "Int16"},
+	"int":        {"mysql": "INT", "postgresql": "INTEGER", "sqlite": "INTEGER", "sqlserver": "INT", "oracle": "NUMBER(10)", "h2": "INTEGER", "clickhouse": "Int32"},
+	"bigint":     {"mysql": "BIGINT", "postgresql": "BIGINT", "sqlite": "INTEGER", "sqlserver": "BIGINT", "oracle": "NUMBER(19)", "h2": "BIGINT", "clickhouse": "Int64"},
+	"real":       {"mysql": "FLOAT", "postgresql": "REAL", "sqlite": "REAL", "sqlserver": "REAL", "oracle": "BINARY_FLOAT", "h2": "REAL", "clickhouse": "Float32"},
+	"double":     {"mysql": "DOUBLE", "postgresql": "DOUBLE PRECISION", "sqlite": "REAL", "sqlserver": "FLOAT", "oracle": "BINARY_DOUBLE", "h2": "DOUBLE PRECISION", "clickhouse": "Float64"},
+	"bool":       {"mysql": "TINYINT(1)", "postgresql": "BOOLEAN", "sqlite": "BOOLEAN", "sqlserver": "BIT", "oracle": "NUMBER(1)", "h2": "BOOLEAN", "clickhouse": "Bool"},
+	"date":       {"mysql": "DATE", "postgresql": "DATE", "sqlite": "DATE", "sqlserver": "DATE", "oracle": "DATE", "h2": "DATE", "clickhouse": "Date32"},
+	"datetime":   {"mysql": "DATETIME(6)", "postgresql": "TIMESTAMP", "sqlite": "DATETIME", "sqlserver": "DATETIME2", "oracle": "TIMESTAMP", "h2": "TIMESTAMP", "clickhouse": "DateTime64(6)"},
+	"datetimetz": {"mysql": "DATETIME(6)", "postgresql": "TIMESTAMPTZ", "sqlite": "DATETIME", "sqlserver": "DATETIMEOFFSET", "oracle": "TIMESTAMP WITH TIME ZONE", "h2": "TIMESTAMP WITH TIME ZONE", "clickhouse": "DateTime64(6)"},
+	"time":       {"mysql": "TIME(6)", "postgresql": "TIME", "sqlite": "TIME", "sqlserver": "TIME", "oracle": "VARCHAR2(32)", "h2": "TIME", "clickhouse": "String"},
+	"json":       {"mysql": "JSON", "postgresql": "JSONB", "sqlite": "TEXT", "sqlserver": "NVARCHAR(MAX)", "oracle": "CLOB", "h2": "JSON", "clickhouse": "String"},
+	"uuid":       {"mysql": "CHAR(36)", "postgresql": "UUID", "sqlite": "TEXT", "sqlserver": "UNIQUEIDENTIFIER", "oracle": "VARCHAR2(36)", "h2": "UUID", "clickhouse": "UUID"},
+	"binary":     {"mysql": "LONGBLOB", "postgresql": "BYTEA", "sqlite": "BLOB", "sqlserver": "VARBINARY(MAX)", "oracle": "BLOB", "h2": "VARBINARY", "clickhouse": "String"},
+	"text":       {"mysql": "LONGTEXT", "postgresql": "TEXT", "sqlite": "TEXT", "sqlserver": "NVARCHAR(MAX)", "oracle": "CLOB", "h2": "CLOB", "clickhouse": "String"},
+}
+
+// copyKeyTypeNames 不能作为主键的大字段类型，用作主键列时替换为有长度限制的类型
+var copyKeyTypeNames = map[string]map[string]string{
+	"mysql":     {"LONGTEXT": "VARCHAR(255)", "LONGBLOB": "VARBINARY(255)"},
+	"sqlserver": {"NVARCHAR(MAX)": "NVARCHAR(450)", "VARBINARY(MAX)": "VARBINARY(900)"},
+	"oracle":    {"CLOB": "VARCHAR2(4000)", "BLOB": "RAW(2000)"},
+	"h2":        {"CLOB": "VARCHAR(4000)"},
+}
+
+// parseColumnType 将列类型解析为通用分类，dbType 为列所在数据库的类型（同名类型在不同数据库中含义不同，如 ClickHouse 的 Int8）
+func parseColumnType(dbType, columnType string) columnTypeSpec {
+	lower := strings.ToLower(strings.TrimSpace(columnType))
+	name := importTypeName(columnType)
+
+	// 括号中的数字参数，如 varchar(255)、decimal(10,2)
+	var args []int
+	inner := lower
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		if strings.HasPrefix(inner, wrapper) && strings.HasSuffix(inner, ")") {
+			inner = inner[len(wrapper) : len(inner)-1]
+		}
+	}
+	if i := strings.Index(inner, "("); i >= 0 {
+		if j := strings.Index(inner[i:], ")"); j > 0 {
+			for _, part := range strings.Split(inner[i+1:i+j], ",") {
+				if n, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+					args = append(args, n)
+				}
+			}
+		}
+	}
+	arg := func(i int) int {
+		if i < len(args) {
+			return args[i]
+		}
+		return 0
+	}
+	unsigned := strings.Contains(lower, "unsigned")
+
+	switch name {
+	case "bool", "boolean":
+		return columnTypeSpec{category: "bool"}
+	case "tinyint":
+		// MySQL 的 TINYINT(1) 通常用作布尔值
+		if dbType == "mysql" && arg(0) == 1 {
+			return columnTypeSpec{category: "bool"}
+		}
+		return columnTypeSpec{category: "smallint"}
+	case "bit":
+		if dbType == "sqlserver" || arg(0) <= 1 {
+			return columnTypeSpec{category: "bool"}
+		}
+		return columnTypeSpec{category: "binary"}
+	case "smallint", "int2", "int16", "uint8", "year", "smallserial":
+		if unsigned {
+			return columnTypeSpec{category: "int"}
+		}
+		return columnTypeSpec{category: "smallint"}
+	case "int8":
+		// ClickHouse 的 Int8 为 8 位整数，PostgreSQL 的 int8 为 8 字节整数
+		if dbType == "clickhouse" {
+			return columnTypeSpec{category: "smallint"}
+		}
+		return columnTypeSpec{category: "bigint"}
+	case "mediumint", "int", "integer", "int4", "int32", "uint16", "serial":
+		if unsigned && name != "mediumint" {
+			return columnTypeSpec{category: "bigint"}
+		}
+		return columnTypeSpec{category: "int"}
+	case "bigint", "int64", "uint32", "bigserial":
+		if unsigned {
+			return columnTypeSpec{category: "decimal", precision: 20}
+		}
+		return columnTypeSpec{category: "bigint"}
+	case "uint64":
+		return columnTypeSpec{category: "decimal", precision: 20}
+	case "int128", "uint128", "int256", "uint256":
+		return columnTypeSpec{category: "decimal", precision: 38}
+	case "decimal", "numeric", "dec":
+		return columnTypeSpec{category: "decimal", precision: arg(0), scale: arg(1)}
+	case "decimal32", "decimal64", "decimal128", "decimal256":
+		// ClickHouse 的 DecimalN(S) 只有小数位数参数
+		precision := map[string]int{"decimal32": 9, "decimal64": 18, "decimal128": 38, "decimal256": 76}[name]
+		return columnTypeSpec{category: "decimal", precision: precision, scale: arg(0)}
+	case "number":
+		// Oracle 的 NUMBER(p,0) 按精度对应整数类型，没有精度的 NUMBER 为任意精度
+		switch p, s := arg(0), arg(1); {
+		case p == 0:
+			return columnTypeSpec{category: "decimal"}
+		case s == 0 && p <= 4:
+			return columnTypeSpec{category: "smallint"}
+		case s == 0 && p <= 9:
+			return columnTypeSpec{category: "int"}
+		case s == 0 && p <= 18:
+			return columnTypeSpec{category: "bigint"}
+		default:
+			return columnTypeSpec{category: "decimal", precision: p, scale: s}
+		}
+	case "money":
+		return columnTypeSpec{category: "decimal", precision: 19, scale: 4}
+	case "smallmoney":
+		return columnTypeSpec{category: "decimal", precision: 10, scale: 4}
+	case "real", "float4", "float32", "binary_float":
+		return columnTypeSpec{category: "real"}
+	case "float":
+		// MySQL 的 FLOAT 为单精度，SQL Server 和 PostgreSQL 的 FLOAT 默认为双精度
+		if dbType == "mysql" || (arg(0) > 0 && arg(0) <= 24) {
+			return columnTypeSpec{category: "real"}
+		}
+		return columnTypeSpec{category: "double"}
+	case "double", "float8", "float64", "binary_double":
+		return columnTypeSpec{category: "double"}
+	case "date", "date32":
+		// Oracle 的 DATE 包含时间
+		if dbType == "oracle" {
+			return columnTypeSpec{category: "datetime"}
+		}
+		return columnTypeSpec{category: "date"}
+	case "datetime", "datetime2", "smalldatetime", "datetime64", "timestamp":
+		if strings.Contains(lower, "with time zone") || strings.Contains(lower, "with local time zone") {
+			return columnTypeSpec{category: "datetimetz"}
+		}
+		return columnTypeSpec{category: "datetime"}
+	case "timestamptz", "datetimeoffset":
+		return columnTypeSpec{category: "datetimetz"}
+	case "time", "timetz":
+		return columnTypeSpec{category: "time"}
+	case "json", "jsonb":
+		return columnTypeSpec{category: "json"}
+	case "uuid", "uniqueidentifier":
+		return columnTypeSpec{category: "uuid"}
+	case "char", "nchar", "varchar", "nvarchar", "varchar2", "nvarchar2", "character", "bpchar", "fixedstring":
+		// SQL Server 的 varchar(max) 长度为 -1
+		if arg(0) > 0 {
+			return columnTypeSpec{category: "varchar", length: arg(0)}
+		}
+		return columnTypeSpec{category: "text"}
+	default:
+		if IsBinaryType(name) {
+			return columnTypeSpec{category: "binary"}
+		}
+		return columnTypeSpec{category: "text"}
+	}
+}
+
+// targetTypeName 返回分类在目标数据库中的类型
+func targetTypeName(spec columnTypeSpec, dbType string) string {
+	switch spec.category {
+	case "decimal":
+		p, s := spec.precision, spec.scale
+		max := map[string]int{"mysql": 65, "sqlserver": 38, "oracle": 38, "clickhouse": 76}[dbType]
+		if max > 0 && p > max {
+			p = max
+		}
+		if s > p {
+			s = p
+		}
+		switch dbType {
+		case "sqlite":
+			return "NUMERIC"
+		case "postgresql":
+			if p == 0 {
+				return "NUMERIC"
+			}
+			return fmt.Sprintf("NUMERIC(%d,%d)", p, s)
+		case "oracle":
+			if p == 0 {
+				return "NUMBER"
+			}
+			return fmt.Sprintf("NUMBER(%d,%d)", p, s)
+		case "clickhouse":
+			if p == 0 {
+				p, s = 38, 10
+			}
+			return fmt.Sprintf("Decimal(%d,%d)", p, s)
+		default:
+			if p == 0 {
+				// 未指定精度时保留足够的整数位和小数位
+				p, s = 38, 10
+				if dbType == "mysql" {
+					p, s = 65, 30
+				}
+			}
+			return fmt.Sprintf("DECIMAL(%d,%d)", p, s)
+		}
+	case "varchar":
+		n := spec.length
+		switch dbType {
+		case "mysql":
+			if n > 16383 {
+				return "LONGTEXT"
+			}
+		case "sqlserver":
+			if n > 4000 {
+				return "NVARCHAR(MAX)"
+			}
+			return fmt.Sprintf("NVARCHAR(%d)", n)
+		case "oracle":
+			if n > 4000 {
+				return "CLOB"
+			}
+			return fmt.Sprintf("VARCHAR2(%d)", n)
+		case "clickhouse":
+			return "String"
+		}
+		return fmt.Sprintf("VARCHAR(%d)", n)
+	}
+	return copyTypeNames[spec.category][dbType]
+}
+
+// TranslateColumns 将源数据库的列信息转换为目标数据库的列信息，用于在目标数据库中建表
+// 保留列名、是否可为空和主键；默认值和自增属性与方言相关，不复制（复制的行包含原值）。
+// 主键列不能使用的大字段类型替换为有长度限制的类型，ClickHouse 的可空列使用 Nullable(...)
+func TranslateColumns(sourceType, targetType string, columns []ColumnInfo) ([]ColumnInfo, error) {
+	if !SupportsCreateTable(targetType) {
+		return nil, fmt.Errorf("creating tables is not supported for %s", targetType)
+	}
+	result := make([]ColumnInfo, len(columns))
+	for i, col := range columns {
+		if err := validateIdentifier(col.Name); err != nil {
+			return nil, err
+		}
+		key := col.Key == "PRI"
+		typeName := targetTypeName(parseColumnType(sourceType, col.Type), targetType)
+		if key {
+			if replacement, ok := copyKeyTypeNames[targetType][typeName]; ok {
+				typeName = replacement
+			}
+			if targetType == "mysql" && strings.HasPrefix(typeName, "VARCHAR(") {
+				// InnoDB 索引最长 3072 字节（utf8mb4 每个字符最多 4 字节）
+				if n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(typeName, "VARCHAR("), ")")); n > 768 {
+					typeName = "VARCHAR(768)"
+				}
+			}
+		}
+		nullable := col.Nullable && !key
+		if targetType == "clickhouse" && nullable {
+			typeName = "Nullable(" + typeName + ")"
+		}
+		result[i] = ColumnInfo{Name: col.Name, Type: typeName, Nullable: nullable}
+		if key {
+			result[i].Key = "PRI"
+		}
+	}
+	return result, nil
+}
+
+// BuildCreateTableSQL 构建建表语句，列类型和默认值（SQL 表达式）按原样使用
+// ClickHouse 使用 MergeTree 引擎，按主键排序（没有主键时使用 tuple()），可空性由 Nullable(...) 类型表示
+func BuildCreateTableSQL(dbType, tableName string, columns []ColumnInfo) (string, error) {
+	if err := validateIdentifier(tableName); err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("at least one column is required")
+	}
+	quote := getQuoteFunc(dbType)
+	definitions := make([]string, 0, len(columns)+1)
+	var keys []string
+	for _, col := range columns {
+		if err := validateIdentifier(col.Name); err != nil {
+			return "", err
+		}
+		if col.Type == "" {
+			return "", fmt.Errorf("column %s has no type", col.Name)
+		}
+		definitions = append(definitions, fullColumnDefinition(dbType, col))
+		if col.Key == "PRI" {
+			keys = append(keys, quote(col.Name))
+		}
+	}
+
+	table := sqlTableRef(dbType, tableName)
+	if dbType == "clickhouse" {
+		orderBy := "tuple()"
+		if len(keys) > 0 {
+			orderBy = "(" + strings.Join(keys, ", ") + ")"
+		}
+		return fmt.Sprintf("CREATE TABLE %s (\n  %s\n) ENGINE = MergeTree ORDER BY %s", table, strings.Join(definitions, ",\n  "), orderBy), nil
+	}
+	if len(keys) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table, strings.Join(definitions, ",\n  ")), nil
+}
+
+// fullColumnDefinition 返回包括默认值（SQL 表达式）和自增属性的列定义
+// Oracle 要求 DEFAULT 和标识列子句在 NOT NULL 之前；SQLite 的 INTEGER 主键本身就是自增的，ClickHouse 不支持自增
+func fullColumnDefinition(dbType string, col ColumnInfo) string {
+	definition := getQuoteFunc(dbType)(col.Name) + " " + col.Type
+	if col.DefaultValue != "" {
+		definition += " DEFAULT " + col.DefaultValue
+	}
+	if col.AutoIncrement {
+		switch dbType {
+		case "mysql":
+			definition += " AUTO_INCREMENT"
+		case "postgresql", "oracle", "h2":
+			definition += " GENERATED BY DEFAULT AS IDENTITY"
+		case "sqlserver":
+			definition += " IDENTITY(1,1)"
+		}
+	}
+	if !col.Nullable && dbType != "clickhouse" {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+// createSQLTable 按列信息建表
+func createSQLTable(ctx context.Context, db sqlExecer, dbType, tableName string, columns []ColumnInfo) error {
+	query, err := BuildCreateTableSQL(dbType, tableName, columns)
+	if err != nil {
+		return err
+	}
+	if db == nil || reflect.ValueOf(db).IsNil() {
+		return fmt.Errorf("database not connected")
+	}
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// ConvertCopyValue 将从源数据库读取的值转换为目标列的值
+// 时间和二进制值按原类型写入，布尔值写入数值列时为 1/0，其他值按 CoerceImportValue 的规则转换
+// dbType 为目标数据库的类型
+func ConvertCopyValue(dbType string, column ColumnInfo, value interface{}) (interface{}, error) {
+	category := parseColumnType(dbType, column.Type).category
+	if b, ok := value.(BinaryValue); ok {
+		value = b.Data
+	}
+	switch v := value.(type) {
+	case time.Time:
+		switch category {
+		case "date", "datetime", "datetimetz":
+			return v, nil
+		case "time":
+			return v.Format("15:04:05.999999"), nil
+		}
+	case []byte:
+		// ClickHouse 的 String 可以保存任意字节
+		if category == "binary" || (dbType == "clickhouse" && category == "text") {
+			return v, nil
+		}
+		if !utf8.Valid(v) {
+			// 二进制值写入文本列时使用十六进制
+			return BinaryValue{Data: v}.String(), nil
+		}
+		value = string(v)
+	case bool:
+		// MySQL 的 TINYINT(1) 和 Oracle 的 NUMBER(1) 等数值类型的布尔列写入 1/0
+		numeric := category == "smallint" || category == "int" || category == "bigint" ||
+			category == "decimal" || category == "real" || category == "double"
+		if numeric || importTypeName(column.Type) == "tinyint" {
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+	}
+	return CoerceImportValue(column, value)
+}
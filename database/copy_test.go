@@ -0,0 +1,185 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTranslateColumns(t *testing.T) {
+	tests := []struct {
+		name       string
+		sourceType string
+		targetType string
+		columns    []ColumnInfo
+		expected   []ColumnInfo
+	}{
+		{
+			name:       "MySQL 到 PostgreSQL",
+			sourceType: "mysql",
+			targetType: "postgresql",
+			columns: []ColumnInfo{
+				{Name: "id", Type: "bigint unsigned", Key: "PRI", AutoIncrement: true},
+				{Name: "name", Type: "varchar(64)", Nullable: true, DefaultValue: "''"},
+				{Name: "active", Type: "tinyint(1)"},
+				{Name: "price", Type: "decimal(10,2)", Nullable: true},
+				{Name: "created_at", Type: "datetime"},
+			},
+			expected: []ColumnInfo{
+				{Name: "id", Type: "NUMERIC(20,0)", Key: "PRI"},
+				{Name: "name", Type: "VARCHAR(64)", Nullable: true},
+				{Name: "active", Type: "BOOLEAN"},
+				{Name: "price", Type: "NUMERIC(10,2)", Nullable: true},
+				{Name: "created_at", Type: "TIMESTAMP"},
+			},
+		},
+		{
+			name:       "PostgreSQL 到 MySQL 的文本主键",
+			sourceType: "postgresql",
+			targetType: "mysql",
+			columns: []ColumnInfo{
+				{Name: "code", Type: "text", Key: "PRI"},
+				{Name: "tag", Type: "varchar(1000)", Key: "PRI"},
+				{Name: "payload", Type: "jsonb", Nullable: true},
+				{Name: "data", Type: "bytea", Nullable: true},
+			},
+			expected: []ColumnInfo{
+				{Name: "code", Type: "VARCHAR(255)", Key: "PRI"},
+				{Name: "tag", Type: "VARCHAR(768)", Key: "PRI"},
+				{Name: "payload", Type: "JSON", Nullable: true},
+				{Name: "data", Type: "LONGBLOB", Nullable: true},
+			},
+		},
+		{
+			name:       "Oracle 到 ClickHouse",
+			sourceType: "oracle",
+			targetType: "clickhouse",
+			columns: []ColumnInfo{
+				{Name: "ID", Type: "NUMBER(10)", Key: "PRI"},
+				{Name: "HIRED", Type: "DATE", Nullable: true},
+				{Name: "NOTE", Type: "VARCHAR2(200)", Nullable: true},
+			},
+			expected: []ColumnInfo{
+				{Name: "ID", Type: "Int64", Key: "PRI"},
+				{Name: "HIRED", Type: "Nullable(DateTime64(6))", Nullable: true},
+				{Name: "NOTE", Type: "Nullable(String)", Nullable: true},
+			},
+		},
+		{
+			name:       "ClickHouse 到 SQL Server",
+			sourceType: "clickhouse",
+			targetType: "sqlserver",
+			columns: []ColumnInfo{
+				{Name: "id", Type: "Int8", Key: "PRI"},
+				{Name: "name", Type: "String", Key: "PRI"},
+				{Name: "amount", Type: "Nullable(Decimal64(4))", Nullable: true},
+			},
+			expected: []ColumnInfo{
+				{Name: "id", Type: "SMALLINT", Key: "PRI"},
+				{Name: "name", Type: "NVARCHAR(450)", Key: "PRI"},
+				{Name: "amount", Type: "DECIMAL(18,4)", Nullable: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TranslateColumns(tt.sourceType, tt.targetType, tt.columns)
+			if err != nil {
+				t.Fatalf("TranslateColumns: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("TranslateColumns = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+
+	if _, err := TranslateColumns("mysql", "redis", []ColumnInfo{{Name: "id", Type: "int"}}); err == nil {
+		t.Error("expected an error for a target without table creation")
+	}
+}
+
+func TestBuildCreateTableSQL(t *testing.T) {
+	columns := []ColumnInfo{
+		{Name: "id", Type: "BIGINT", Key: "PRI"},
+		{Name: "name", Type: "VARCHAR(64)", Nullable: true},
+	}
+	tests := []struct {
+		name     string
+		dbType   string
+		columns  []ColumnInfo
+		expected string
+	}{
+		{
+			name:     "MySQL",
+			dbType:   "mysql",
+			columns:  columns,
+			expected: "CREATE TABLE `t` (\n  `id` BIGINT NOT NULL,\n  `name` VARCHAR(64),\n  PRIMARY KEY (`id`)\n)",
+		},
+		{
+			name:     "PostgreSQL",
+			dbType:   "postgresql",
+			columns:  columns,
+			expected: "CREATE TABLE \"t\" (\n  \"id\" BIGINT NOT NULL,\n  \"name\" VARCHAR(64),\n  PRIMARY KEY (\"id\")\n)",
+		},
+		{
+			name:     "ClickHouse",
+			dbType:   "clickhouse",
+			columns:  []ColumnInfo{{Name: "id", Type: "Int64", Key: "PRI"}, {Name: "name", Type: "Nullable(String)", Nullable: true}},
+			expected: "CREATE TABLE `t` (\n  `id` Int64,\n  `name` Nullable(String)\n) ENGINE = MergeTree ORDER BY (`id`)",
+		},
+		{
+			name:     "ClickHouse 没有主键",
+			dbType:   "clickhouse",
+			columns:  []ColumnInfo{{Name: "name", Type: "String"}},
+			expected: "CREATE TABLE `t` (\n  `name` String\n) ENGINE = MergeTree ORDER BY tuple()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := BuildCreateTableSQL(tt.dbType, "t", tt.columns)
+			if err != nil {
+				t.Fatalf("BuildCreateTableSQL: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("BuildCreateTableSQL = %q, want %q", query, tt.expected)
+			}
+		})
+	}
+
+	if _, err := BuildCreateTableSQL("mysql", "t", []ColumnInfo{{Name: "id"}}); err == nil {
+		t.Error("expected an error for a column without type")
+	}
+}
+
+func TestConvertCopyValue(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dbType   string
+		column   ColumnInfo
+		value    interface{}
+		expected interface{}
+	}{
+		{name: "时间写入时间列", dbType: "postgresql", column: ColumnInfo{Type: "TIMESTAMP"}, value: ts, expected: ts},
+		{name: "时间写入 time 列", dbType: "postgresql", column: ColumnInfo{Type: "TIME"}, value: ts, expected: "07:08:09"},
+		{name: "布尔写入 TINYINT(1)", dbType: "mysql", column: ColumnInfo{Type: "TINYINT(1)"}, value: true, expected: int64(1)},
+		{name: "布尔写入 NUMBER(1)", dbType: "oracle", column: ColumnInfo{Type: "NUMBER(1)"}, value: false, expected: int64(0)},
+		{name: "二进制写入二进制列", dbType: "postgresql", column: ColumnInfo{Type: "BYTEA"}, value: BinaryValue{Data: []byte{0xff, 0x00}}, expected: []byte{0xff, 0x00}},
+		{name: "文本字节写入文本列", dbType: "postgresql", column: ColumnInfo{Type: "TEXT"}, value: []byte("abc"), expected: "abc"},
+		{name: "NULL", dbType: "mysql", column: ColumnInfo{Type: "INT", Nullable: true}, value: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ConvertCopyValue(tt.dbType, tt.column, tt.value)
+			if err != nil {
+				t.Fatalf("ConvertCopyValue: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ConvertCopyValue = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
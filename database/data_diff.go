@@ -0,0 +1,323 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DataDiffPageSize 数据对比时每次从一侧读取的行数
+const DataDiffPageSize = 500
+
+// 行差异的状态，以源表为准：inserted 表示只在源表中存在（同步时插入目标表），deleted 表示只在目标表中存在
+const (
+	DataDiffInserted = "inserted"
+	DataDiffDeleted  = "deleted"
+	DataDiffChanged  = "changed"
+)
+
+// DataDiffTable 参与数据对比的一侧
+type DataDiffTable struct {
+	DB      ContextDatabase
+	DBType  string
+	Table   string
+	Key     string       // 主键列名（单列），两侧按主键升序分页读取
+	Columns []ColumnInfo // 参与比较的列（包含主键），与另一侧的 Columns 按位置一一对应
+}
+
+// DataDiffRow 一行的差异，Source 和 Target 只包含参与比较的列，均以源表的列名为键
+type DataDiffRow struct {
+	Status  string                 `json:"status"` // inserted、deleted、changed
+	Key     interface{}            `json:"key"`
+	Columns []string               `json:"columns,omitempty"` // changed 时值不同的列（源表的列名）
+	Source  map[string]interface{} `json:"source,omitempty"`
+	Target  map[string]interface{} `json:"target,omitempty"`
+}
+
+// DataDiffStats 数据对比的统计
+type DataDiffStats struct {
+	SourceTotal   int64 `json:"sourceTotal"`   // 源表中满足过滤条件的行数
+	TargetTotal   int64 `json:"targetTotal"`   // 目标表中满足过滤条件的行数
+	SourceScanned int64 `json:"sourceScanned"` // 已读取的源表行数
+	TargetScanned int64 `json:"targetScanned"` // 已读取的目标表行数
+	Inserted      int64 `json:"inserted"`
+	Deleted       int64 `json:"deleted"`
+	Changed       int64 `json:"changed"`
+	Identical     int64 `json:"identical"`
+}
+
+// CompareTableData 按主键对比两张表的数据，两侧都按主键分页读取（GetTableDataByIDContext）后归并，不会把整张表读入内存
+// 每发现一行差异调用 emit，每读取一页调用 progress。两侧的主键排序规则必须一致（如字符串主键的排序规则不同时无法归并），
+// 发现某一侧的主键不是升序时返回错误
+func CompareTableData(ctx context.Context, source, target DataDiffTable, filters *FilterGroup, emit func(DataDiffRow) error, progress func(DataDiffStats)) (DataDiffStats, error) {
+	var stats DataDiffStats
+	if len(source.Columns) != len(target.Columns) {
+		return stats, fmt.Errorf("source and target must compare the same number of columns")
+	}
+	sourceKey, targetKey := columnIndex(source.Columns, source.Key), columnIndex(target.Columns, target.Key)
+	if sourceKey < 0 || targetKey < 0 {
+		return stats, fmt.Errorf("the key column must be compared")
+	}
+	// 按源表主键的类型比较两侧的主键
+	numeric := isNumericCategory(parseColumnType(source.DBType, source.Columns[sourceKey].Type).category)
+	categories := make([]string, len(source.Columns))
+	for i, col := range source.Columns {
+		categories[i] = parseColumnType(source.DBType, col.Type).category
+	}
+
+	sp := &keyPager{ctx: ctx, side: source, filters: filters, numeric: numeric}
+	tp := &keyPager{ctx: ctx, side: target, filters: filters, numeric: numeric}
+	report := func() {
+		stats.SourceTotal, stats.TargetTotal = sp.total, tp.total
+		stats.SourceScanned, stats.TargetScanned = sp.scanned, tp.scanned
+		if progress != nil && (sp.fetched || tp.fetched) {
+			progress(stats)
+		}
+		sp.fetched, tp.fetched = false, false
+	}
+
+	for {
+		srow, err := sp.peek()
+		if err != nil {
+			return stats, err
+		}
+		trow, err := tp.peek()
+		if err != nil {
+			return stats, err
+		}
+		report()
+		if srow == nil && trow == nil {
+			return stats, nil
+		}
+
+		var diff *DataDiffRow
+		switch {
+		case trow == nil || (srow != nil && compareDiffKeys(numeric, srow[source.Key], trow[target.Key]) < 0):
+			diff = &DataDiffRow{Status: DataDiffInserted, Key: srow[source.Key], Source: pickColumns(source.Columns, source.Columns, srow)}
+			stats.Inserted++
+			sp.advance()
+		case srow == nil || compareDiffKeys(numeric, srow[source.Key], trow[target.Key]) > 0:
+			diff = &DataDiffRow{Status: DataDiffDeleted, Key: trow[target.Key], Target: pickColumns(source.Columns, target.Columns, trow)}
+			stats.Deleted++
+			tp.advance()
+		default:
+			var changed []string
+			for i, col := range source.Columns {
+				if !diffValuesEqual(categories[i], srow[col.Name], trow[target.Columns[i].Name]) {
+					changed = append(changed, col.Name)
+				}
+			}
+			if len(changed) > 0 {
+				diff = &DataDiffRow{
+					Status:  DataDiffChanged,
+					Key:     srow[source.Key],
+					Columns: changed,
+					Source:  pickColumns(source.Columns, source.Columns, srow),
+					Target:  pickColumns(source.Columns, target.Columns, trow),
+				}
+				stats.Changed++
+			} else {
+				stats.Identical++
+			}
+			sp.advance()
+			tp.advance()
+		}
+		if diff != nil && emit != nil {
+			if err := emit(*diff); err != nil {
+				return stats, err
+			}
+		}
+	}
+}
+
+// keyPager 按主键升序分页读取一侧的行
+type keyPager struct {
+	ctx     context.Context
+	side    DataDiffTable
+	filters *FilterGroup
+	numeric bool
+
+	rows    []map[string]interface{}
+	index   int
+	lastKey interface{}
+	started bool
+	done    bool
+	total   int64
+	scanned int64
+	fetched bool // 自上次报告进度以来是否读取了新的一页
+}
+
+// peek 返回当前行，没有更多行时返回 nil
+func (p *keyPager) peek() (map[string]interface{}, error) {
+	if p.index < len(p.rows) {
+		return p.rows[p.index], nil
+	}
+	if p.done {
+		return nil, nil
+	}
+	if err := p.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var lastKey interface{}
+	if p.started {
+		lastKey = p.lastKey
+	}
+	rows, total, _, err := p.side.DB.GetTableDataByIDContext(p.ctx, p.side.Table, p.side.Key, lastKey, DataDiffPageSize, "next", p.filters)
+	if err != nil {
+		return nil, fmt.Errorf("table %s: %w", p.side.Table, err)
+	}
+	p.total = total
+	p.fetched = true
+	p.rows, p.index = rows, 0
+	p.done = len(rows) < DataDiffPageSize
+	for _, row := range rows {
+		key := row[p.side.Key]
+		if key == nil {
+			return nil, fmt.Errorf("table %s: key column %s is missing or NULL", p.side.Table, p.side.Key)
+		}
+		if p.started && compareDiffKeys(p.numeric, key, p.lastKey) <= 0 {
+			return nil, fmt.Errorf("table %s is not ordered by %s as expected (the key ordering differs between the two sides)", p.side.Table, p.side.Key)
+		}
+		p.lastKey, p.started = key, true
+	}
+	p.scanned += int64(len(rows))
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return p.rows[0], nil
+}
+
+func (p *keyPager) advance() {
+	p.index++
+}
+
+// pickColumns 返回行中参与比较的列，以源表的列名为键
+func pickColumns(names, columns []ColumnInfo, row map[string]interface{}) map[string]interface{} {
+	picked := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		picked[names[i].Name] = row[col.Name]
+	}
+	return picked
+}
+
+func columnIndex(columns []ColumnInfo, name string) int {
+	for i, col := range columns {
+		if col.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func isNumericCategory(category string) bool {
+	switch category {
+	case "smallint", "int", "bigint", "decimal", "real", "double":
+		return true
+	}
+	return false
+}
+
+// compareDiffKeys 比较两个主键值，数值主键按数值比较（驱动可能以字符串返回数值），其他主键按文本比较
+func compareDiffKeys(numeric bool, a, b interface{}) int {
+	if numeric {
+		ra, okA := diffNumber(a)
+		rb, okB := diffNumber(b)
+		if okA && okB {
+			return ra.Cmp(rb)
+		}
+	}
+	return strings.Compare(ExportText(a), ExportText(b))
+}
+
+// diffValuesEqual 按列类型比较两侧的值：数值按数值比较（"1.50" 与 1.5 相等），布尔值与 1/0 相等，其他值按文本比较
+func diffValuesEqual(category string, a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if isNumericCategory(category) || category == "bool" {
+		ra, okA := diffNumber(a)
+		rb, okB := diffNumber(b)
+		if okA && okB {
+			return ra.Cmp(rb) == 0
+		}
+	}
+	return ExportText(a) == ExportText(b)
+}
+
+// diffNumber 将数值、数值字符串和布尔值转换为精确的有理数
+func diffNumber(value interface{}) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return big.NewRat(1, 1), true
+		}
+		return new(big.Rat), true
+	case float32, float64:
+		r, ok := new(big.Rat).SetString(fmt.Sprint(v))
+		return r, ok
+	}
+	text := strings.TrimSpace(ExportText(value))
+	switch strings.ToLower(text) {
+	case "true":
+		return big.NewRat(1, 1), true
+	case "false":
+		return new(big.Rat), true
+	}
+	return new(big.Rat).SetString(text)
+}
+
+// BuildDataSyncSQL 生成使目标表的行与源表一致的语句：inserted 为 INSERT，deleted 为 DELETE，changed 为只更新不同列的 UPDATE
+// 值按目标列的类型转换（见 ConvertCopyValue）后写为目标数据库的字面量
+func BuildDataSyncSQL(source, target DataDiffTable, row DataDiffRow) (string, error) {
+	if err := validateIdentifier(target.Table); err != nil {
+		return "", err
+	}
+	ref := sqlTableRef(target.DBType, target.Table)
+	quote := getQuoteFunc(target.DBType)
+	keyIndex := columnIndex(target.Columns, target.Key)
+	if keyIndex < 0 {
+		return "", fmt.Errorf("the key column must be compared")
+	}
+	literal := func(i int, value interface{}) string {
+		if converted, err := ConvertCopyValue(target.DBType, target.Columns[i], value); err == nil {
+			value = converted
+		}
+		return FormatSQLLiteral(target.DBType, value)
+	}
+	where := fmt.Sprintf("%s = %s", quote(target.Key), literal(keyIndex, row.Key))
+
+	switch row.Status {
+	case DataDiffInserted:
+		names := make([]string, len(target.Columns))
+		values := make([]string, len(target.Columns))
+		for i, col := range target.Columns {
+			if err := validateIdentifier(col.Name); err != nil {
+				return "", err
+			}
+			names[i] = quote(col.Name)
+			values[i] = literal(i, row.Source[source.Columns[i].Name])
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", ref, strings.Join(names, ", "), strings.Join(values, ", ")), nil
+	case DataDiffDeleted:
+		return fmt.Sprintf("DELETE FROM %s WHERE %s", ref, where), nil
+	case DataDiffChanged:
+		var sets []string
+		for _, name := range row.Columns {
+			i := columnIndex(source.Columns, name)
+			if i < 0 {
+				continue
+			}
+			if err := validateIdentifier(target.Columns[i].Name); err != nil {
+				return "", err
+			}
+			sets = append(sets, fmt.Sprintf("%s = %s", quote(target.Columns[i].Name), literal(i, row.Source[name])))
+		}
+		if len(sets) == 0 {
+			return "", fmt.Errorf("no columns to update")
+		}
+		return fmt.Sprintf("UPDATE %s SET %s WHERE %s", ref, strings.Join(sets, ", "), where), nil
+	}
+	return "", fmt.Errorf("unknown diff status: %s", row.Status)
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// keyPagedTestDB 按主键升序分页返回内存中的行
+type keyPagedTestDB struct {
+	ContextDatabase
+	rows []map[string]interface{}
+}
+
+func (db *keyPagedTestDB) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	var page []map[string]interface{}
+	for _, row := range db.rows {
+		if lastId != nil && compareDiffKeys(true, row[primaryKey], lastId) <= 0 {
+			continue
+		}
+		if len(page) == pageSize {
+			break
+		}
+		page = append(page, row)
+	}
+	var next interface{}
+	if len(page) > 0 {
+		next = page[len(page)-1][primaryKey]
+	}
+	return page, int64(len(db.rows)), next, nil
+}
+
+func TestCompareTableData(t *testing.T) {
+	// 源表 1..1200；目标表缺少 7 和 1100，多出 1300，500 和 1001 的 name 不同，1200 的 price 不同（目标表的数值以字符串返回）
+	var sourceRows, targetRows []map[string]interface{}
+	for i := 1; i <= 1200; i++ {
+		sourceRows = append(sourceRows, map[string]interface{}{"id": int64(i), "name": fmt.Sprintf("n%d", i), "price": 1.5})
+		if i == 7 || i == 1100 {
+			continue
+		}
+		name := fmt.Sprintf("n%d", i)
+		if i == 500 || i == 1001 {
+			name = "changed"
+		}
+		price := "1.50"
+		if i == 1200 {
+			price = "2"
+		}
+		targetRows = append(targetRows, map[string]interface{}{"ID": fmt.Sprint(i), "NAME": name, "PRICE": price})
+	}
+	targetRows = append(targetRows, map[string]interface{}{"ID": "1300", "NAME": "extra", "PRICE": nil})
+
+	source := DataDiffTable{
+		DB: &keyPagedTestDB{rows: sourceRows}, DBType: "mysql", Table: "items", Key: "id",
+		Columns: []ColumnInfo{{Name: "id", Type: "int", Key: "PRI"}, {Name: "name", Type: "varchar(20)"}, {Name: "price", Type: "decimal(10,2)"}},
+	}
+	target := DataDiffTable{
+		DB: &keyPagedTestDB{rows: targetRows}, DBType: "oracle", Table: "ITEMS", Key: "ID",
+		Columns: []ColumnInfo{{Name: "ID", Type: "NUMBER(10)", Key: "PRI"}, {Name: "NAME", Type: "VARCHAR2(20)"}, {Name: "PRICE", Type: "NUMBER(10,2)", Nullable: true}},
+	}
+
+	var diffs []DataDiffRow
+	stats, err := CompareTableData(context.Background(), source, target, nil, func(row DataDiffRow) error {
+		diffs = append(diffs, row)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("CompareTableData: %v", err)
+	}
+
+	expected := DataDiffStats{SourceTotal: 1200, TargetTotal: 1199, SourceScanned: 1200, TargetScanned: 1199, Inserted: 2, Deleted: 1, Changed: 3, Identical: 1195}
+	if stats != expected {
+		t.Errorf("stats = %+v, want %+v", stats, expected)
+	}
+
+	summary := make([]string, len(diffs))
+	for i, d := range diffs {
+		summary[i] = fmt.Sprintf("%s %v %v", d.Status, d.Key, d.Columns)
+	}
+	sort.Strings(summary)
+	want := []string{"changed 1001 [name]", "changed 1200 [price]", "changed 500 [name]", "deleted 1300 []", "inserted 1100 []", "inserted 7 []"}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("diffs = %v, want %v", summary, want)
+	}
+}
+
+func TestBuildDataSyncSQL(t *testing.T) {
+	source := DataDiffTable{DBType: "mysql", Table: "items", Key: "id",
+		Columns: []ColumnInfo{{Name: "id", Type: "int", Key: "PRI"}, {Name: "name", Type: "varchar(20)", Nullable: true}}}
+	target := DataDiffTable{DBType: "postgresql", Table: "items", Key: "id",
+		Columns: []ColumnInfo{{Name: "id", Type: "integer", Key: "PRI"}, {Name: "name", Type: "varchar(20)", Nullable: true}}}
+
+	tests := []struct {
+		name     string
+		row      DataDiffRow
+		expected string
+	}{
+		{
+			name:     "插入",
+			row:      DataDiffRow{Status: DataDiffInserted, Key: "1", Source: map[string]interface{}{"id": "1", "name": "O'Brien"}},
+			expected: `INSERT INTO "items" ("id", "name") VALUES (1, 'O''Brien')`,
+		},
+		{
+			name:     "删除",
+			row:      DataDiffRow{Status: DataDiffDeleted, Key: int64(2)},
+			expected: `DELETE FROM "items" WHERE "id" = 2`,
+		},
+		{
+			name:     "更新",
+			row:      DataDiffRow{Status: DataDiffChanged, Key: int64(3), Columns: []string{"name"}, Source: map[string]interface{}{"id": int64(3), "name": nil}},
+			expected: `UPDATE "items" SET "name" = NULL WHERE "id" = 3`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := BuildDataSyncSQL(source, target, tt.row)
+			if err != nil {
+				t.Fatalf("BuildDataSyncSQL: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("BuildDataSyncSQL = %q, want %q", query, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffValuesEqual(t *testing.T) {
+	tests := []struct {
+		category string
+		a, b     interface{}
+		expected bool
+	}{
+		{"decimal", "1.50", 1.5, true},
+		{"int", int64(10), "10", true},
+		{"bool", true, int64(1), true},
+		{"bool", "0", false, true},
+		{"text", "007", "7", false},
+		{"text", nil, "", false},
+		{"text", nil, nil, true},
+		{"varchar", []byte("abc"), "abc", true},
+	}
+	for _, tt := range tests {
+		if got := diffValuesEqual(tt.category, tt.a, tt.b); got != tt.expected {
+			t.Errorf("diffValuesEqual(%s, %#v, %#v) = %v, want %v", tt.category, tt.a, tt.b, got, tt.expected)
+		}
+	}
+	if compareDiffKeys(true, "9", "10") != -1 {
+		t.Error("numeric keys should compare by value")
+	}
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DumpBatchRows 转储时每条 INSERT 语句包含的行数
+const DumpBatchRows = 100
+
+// DumpOptions 转储选项
+type DumpOptions struct {
+	Tables     []string `json:"tables"`     // 转储的表，为空时转储所有表
+	NoSchema   bool     `json:"noSchema"`   // 不转储表结构（只转储数据）
+	NoData     bool     `json:"noData"`     // 不转储数据（只转储表结构）
+	DropTables bool     `json:"dropTables"` // 创建表之前先删除同名表
+}
+
+// SupportsSQLDump 判断数据库类型是否支持 SQL 转储
+func SupportsSQLDump(dbType string) bool {
+	switch dbType {
+	case "mysql", "postgresql", "sqlite", "sqlserver", "oracle":
+		return true
+	}
+	return false
+}
+
+// DumpSQL 将表结构（GetTableSchema）和数据（批量 INSERT）转储为 .sql 脚本，返回转储的行数
+// 脚本可以用 SplitSQLScript 拆分后逐条执行恢复；外键和序列值在所有数据插入之后设置，因此恢复时不依赖表的顺序
+func DumpSQL(ctx context.Context, db Database, dbType string, w io.Writer, options DumpOptions) (int64, error) {
+	if !SupportsSQLDump(dbType) {
+		return 0, fmt.Errorf("sql dump is not supported for %s", dbType)
+	}
+	tables := options.Tables
+	if len(tables) == 0 {
+		var err error
+		if tables, err = db.GetTables(); err != nil {
+			return 0, err
+		}
+	}
+
+	d := &sqlDumpWriter{buf: bufio.NewWriter(w), dbType: dbType}
+	d.writeHeader(tables, time.Now())
+	var total int64
+	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		columns, err := db.GetTableColumns(table)
+		if err != nil {
+			return total, fmt.Errorf("table %s: %w", table, err)
+		}
+		fmt.Fprintf(d.buf, "\n-- Table: %s\n", table)
+		if !options.NoSchema {
+			ddl, err := db.GetTableSchema(table)
+			if err != nil {
+				return total, fmt.Errorf("table %s: %w", table, err)
+			}
+			if err := d.writeSchema(table, ddl, columns, options.DropTables); err != nil {
+				return total, err
+			}
+		}
+		if options.NoData {
+			continue
+		}
+
+		names := make([]string, len(columns))
+		for i, col := range columns {
+			names[i] = col.Name
+		}
+		iter, err := StreamTableRows(ctx, db, table, names, nil, nil)
+		if err != nil {
+			return total, fmt.Errorf("table %s: %w", table, err)
+		}
+		count, err := d.writeRows(table, columns, iter)
+		iter.Close()
+		total += count
+		if err != nil {
+			return total, fmt.Errorf("table %s: %w", table, err)
+		}
+	}
+	return total, d.close()
+}
+
+// sqlDumpWriter 写入转储脚本
+type sqlDumpWriter struct {
+	buf      *bufio.Writer
+	dbType   string
+	deferred []string // 所有数据插入之后执行的语句（外键、序列值）
+}
+
+func (d *sqlDumpWriter) writeHeader(tables []string, now time.Time) {
+	fmt.Fprintf(d.buf, "-- SimpleDBWeb SQL dump\n-- Database type: %s\n-- Generated at: %s\n-- Tables: %d\n",
+		d.dbType, now.Format("2006-01-02 15:04:05"), len(tables))
+}
+
+// writeStatement 写入一条语句，Oracle 的 PL/SQL 块以单独一行的 / 结束
+func (d *sqlDumpWriter) writeStatement(stmt string) {
+	stmt = strings.TrimRight(strings.TrimSpace(stmt), ";")
+	if d.dbType == "oracle" && strings.HasPrefix(stmt, "BEGIN") {
+		d.buf.WriteString(stmt + ";\n/\n")
+		return
+	}
+	d.buf.WriteString(stmt + ";\n")
+}
+
+// nextvalPattern 匹配 PostgreSQL serial 列默认值中的序列名
+var nextvalPattern = regexp.MustCompile(`^nextval\('((?:[^']|'')+)'`)
+
+// writeSchema 写入建表语句
+// GetTableSchema 生成的 PostgreSQL 和 SQL Server 建表语句不包含主键，按列信息补充；
+// PostgreSQL serial 列引用的序列在建表前创建，MySQL 的外键延后到数据插入之后添加
+func (d *sqlDumpWriter) writeSchema(table, ddl string, columns []ColumnInfo, drop bool) error {
+	if err := validateIdentifier(table); err != nil {
+		return err
+	}
+	ref := sqlTableRef(d.dbType, table)
+	quote := getQuoteFunc(d.dbType)
+
+	if drop {
+		switch d.dbType {
+		case "oracle":
+			// Oracle 没有 DROP TABLE IF EXISTS，忽略表不存在的错误（ORA-00942）
+			d.writeStatement(fmt.Sprintf("BEGIN\n  EXECUTE IMMEDIATE 'DROP TABLE %s CASCADE CONSTRAINTS';\nEXCEPTION WHEN OTHERS THEN\n  IF SQLCODE != -942 THEN RAISE; END IF;\nEND", ref))
+		default:
+			d.writeStatement("DROP TABLE IF EXISTS " + ref)
+		}
+	}
+
+	if d.dbType == "postgresql" {
+		for _, col := range columns {
+			if m := nextvalPattern.FindStringSubmatch(col.DefaultValue); m != nil {
+				d.writeStatement("CREATE SEQUENCE IF NOT EXISTS " + strings.ReplaceAll(m[1], "''", "'"))
+				d.deferred = append(d.deferred, fmt.Sprintf("SELECT setval('%s', COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)", m[1], quote(col.Name), ref))
+			}
+		}
+	}
+
+	if d.dbType == "mysql" {
+		ddl = d.deferForeignKeys(ref, ddl)
+	}
+	d.writeStatement(ddl)
+
+	if (d.dbType == "postgresql" || d.dbType == "sqlserver") && !strings.Contains(strings.ToUpper(ddl), "PRIMARY KEY") {
+		var keys []string
+		for _, col := range columns {
+			if col.Key == "PRI" {
+				keys = append(keys, quote(col.Name))
+			}
+		}
+		if len(keys) > 0 {
+			d.writeStatement(fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", ref, strings.Join(keys, ", ")))
+		}
+	}
+	return nil
+}
+
+// deferForeignKeys 从 SHOW CREATE TABLE 的结果中移除外键约束（每个定义占一行），改为数据插入之后用 ALTER TABLE 添加
+func (d *sqlDumpWriter) deferForeignKeys(ref, ddl string) string {
+	lines := strings.Split(ddl, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "CONSTRAINT ") && strings.Contains(trimmed, " FOREIGN KEY ") {
+			d.deferred = append(d.deferred, fmt.Sprintf("ALTER TABLE %s ADD %s", ref, strings.TrimSuffix(trimmed, ",")))
+			continue
+		}
+		if strings.HasPrefix(trimmed, ")") && len(kept) > 0 {
+			// 移除外键后，最后一个定义不能以逗号结尾
+			kept[len(kept)-1] = strings.TrimSuffix(kept[len(kept)-1], ",")
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// writeRows 将行写入为批量 INSERT 语句，返回写入的行数
+// SQL Server 的标识列需要在同一批处理中开启 IDENTITY_INSERT 才能插入原值，因此这几条语句之间不加分号
+func (d *sqlDumpWriter) writeRows(table string, columns []ColumnInfo, iter RowIterator) (int64, error) {
+	if err := validateIdentifier(table); err != nil {
+		return 0, err
+	}
+	ref := sqlTableRef(d.dbType, table)
+	quote := getQuoteFunc(d.dbType)
+	names := iter.Columns()
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		if err := validateIdentifier(name); err != nil {
+			return 0, err
+		}
+		quoted[i] = quote(name)
+	}
+	columnList := strings.Join(quoted, ", ")
+
+	identity := false
+	if d.dbType == "sqlserver" {
+		for _, col := range columns {
+			identity = identity || col.AutoIncrement
+		}
+	}
+
+	var count int64
+	batch := make([]string, 0, DumpBatchRows)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		var stmt string
+		if d.dbType == "oracle" {
+			var b strings.Builder
+			b.WriteString("INSERT ALL")
+			for _, tuple := range batch {
+				fmt.Fprintf(&b, "\n  INTO %s (%s) VALUES %s", ref, columnList, tuple)
+			}
+			b.WriteString("\nSELECT 1 FROM DUAL")
+			stmt = b.String()
+		} else {
+			stmt = fmt.Sprintf("INSERT INTO %s (%s) VALUES\n  %s", ref, columnList, strings.Join(batch, ",\n  "))
+		}
+		if identity {
+			stmt = fmt.Sprintf("SET IDENTITY_INSERT %s ON\n%s\nSET IDENTITY_INSERT %s OFF", ref, stmt, ref)
+		}
+		d.writeStatement(stmt)
+		batch = batch[:0]
+	}
+
+	for iter.Next() {
+		values := iter.Values()
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = FormatSQLLiteral(d.dbType, v)
+		}
+		batch = append(batch, "("+strings.Join(literals, ", ")+")")
+		count++
+		if len(batch) >= DumpBatchRows {
+			flush()
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return count, err
+	}
+	flush()
+	return count, nil
+}
+
+// close 写入延后的语句并刷新缓冲
+func (d *sqlDumpWriter) close() error {
+	if len(d.deferred) > 0 {
+		d.buf.WriteString("\n-- Foreign keys and sequences\n")
+		for _, stmt := range d.deferred {
+			d.writeStatement(stmt)
+		}
+	}
+	return d.buf.Flush()
+}
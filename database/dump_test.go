@@ -0,0 +1,112 @@
+package database
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestSQLDumpWriter(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": int64(1), "name": "a;b"},
+		{"id": int64(2), "name": nil},
+	}
+	tests := []struct {
+		name     string
+		dbType   string
+		ddl      string
+		columns  []ColumnInfo
+		expected []string // 用 SplitSQLScript 拆分后的语句
+	}{
+		{
+			name:   "MySQL 外键延后添加",
+			dbType: "mysql",
+			ddl: "CREATE TABLE `t` (\n  `id` int NOT NULL,\n  `name` varchar(10),\n  PRIMARY KEY (`id`),\n" +
+				"  CONSTRAINT `fk_p` FOREIGN KEY (`id`) REFERENCES `p` (`id`)\n) ENGINE=InnoDB",
+			columns: []ColumnInfo{{Name: "id", Key: "PRI"}, {Name: "name"}},
+			expected: []string{
+				"DROP TABLE IF EXISTS `t`",
+				"CREATE TABLE `t` (\n  `id` int NOT NULL,\n  `name` varchar(10),\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB",
+				"INSERT INTO `t` (`id`, `name`) VALUES\n  (1, 'a;b'),\n  (2, NULL)",
+				"ALTER TABLE `t` ADD CONSTRAINT `fk_p` FOREIGN KEY (`id`) REFERENCES `p` (`id`)",
+			},
+		},
+		{
+			name:    "PostgreSQL 补充主键和序列",
+			dbType:  "postgresql",
+			ddl:     `CREATE TABLE "t" ("id" int4 NOT NULL DEFAULT nextval('t_id_seq'::regclass), "name" varchar(10));`,
+			columns: []ColumnInfo{{Name: "id", Key: "PRI", DefaultValue: "nextval('t_id_seq'::regclass)"}, {Name: "name"}},
+			expected: []string{
+				`DROP TABLE IF EXISTS "t"`,
+				"CREATE SEQUENCE IF NOT EXISTS t_id_seq",
+				`CREATE TABLE "t" ("id" int4 NOT NULL DEFAULT nextval('t_id_seq'::regclass), "name" varchar(10))`,
+				`ALTER TABLE "t" ADD PRIMARY KEY ("id")`,
+				"INSERT INTO \"t\" (\"id\", \"name\") VALUES\n  (1, 'a;b'),\n  (2, NULL)",
+				`SELECT setval('t_id_seq', COALESCE((SELECT MAX("id") FROM "t"), 0) + 1, false)`,
+			},
+		},
+		{
+			name:    "SQL Server 标识列",
+			dbType:  "sqlserver",
+			ddl:     "CREATE TABLE [dbo].[t] ([id] int IDENTITY(1,1) NOT NULL, [name] varchar(10));",
+			columns: []ColumnInfo{{Name: "id", Key: "PRI", AutoIncrement: true}, {Name: "name"}},
+			expected: []string{
+				"DROP TABLE IF EXISTS [t]",
+				"CREATE TABLE [dbo].[t] ([id] int IDENTITY(1,1) NOT NULL, [name] varchar(10))",
+				"ALTER TABLE [t] ADD PRIMARY KEY ([id])",
+				"SET IDENTITY_INSERT [t] ON\nINSERT INTO [t] ([id], [name]) VALUES\n  (1, 'a;b'),\n  (2, NULL)\nSET IDENTITY_INSERT [t] OFF",
+			},
+		},
+		{
+			name:    "Oracle",
+			dbType:  "oracle",
+			ddl:     "\n  CREATE TABLE \"APP\".\"T\" (\"ID\" NUMBER, \"NAME\" VARCHAR2(10), PRIMARY KEY (\"ID\"))",
+			columns: []ColumnInfo{{Name: "ID", Key: "PRI"}, {Name: "NAME"}},
+			expected: []string{
+				"BEGIN\n  EXECUTE IMMEDIATE 'DROP TABLE \"T\" CASCADE CONSTRAINTS';\nEXCEPTION WHEN OTHERS THEN\n  IF SQLCODE != -942 THEN RAISE; END IF;\nEND;",
+				"CREATE TABLE \"APP\".\"T\" (\"ID\" NUMBER, \"NAME\" VARCHAR2(10), PRIMARY KEY (\"ID\"))",
+				"INSERT ALL\n  INTO \"T\" (\"ID\", \"NAME\") VALUES (1, 'a;b')\n  INTO \"T\" (\"ID\", \"NAME\") VALUES (2, NULL)\nSELECT 1 FROM DUAL",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			d := &sqlDumpWriter{buf: bufio.NewWriter(&out), dbType: tt.dbType}
+			table := "t"
+			if tt.dbType == "oracle" {
+				table = "T"
+			}
+			if err := d.writeSchema(table, tt.ddl, tt.columns, true); err != nil {
+				t.Fatalf("writeSchema: %v", err)
+			}
+			names := make([]string, len(tt.columns))
+			data := make([]map[string]interface{}, len(rows))
+			for i, row := range rows {
+				data[i] = map[string]interface{}{}
+				for j, col := range tt.columns {
+					names[j] = col.Name
+					data[i][col.Name] = row[strings.ToLower(col.Name)]
+				}
+			}
+			count, err := d.writeRows(table, tt.columns, NewMapRowIterator(names, data))
+			if err != nil || count != 2 {
+				t.Fatalf("writeRows = %d, %v", count, err)
+			}
+			if err := d.close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			statements := SplitSQLScript(tt.dbType, out.String())
+			if len(statements) != len(tt.expected) {
+				t.Fatalf("got %d statements, want %d:\n%s", len(statements), len(tt.expected), out.String())
+			}
+			for i, stmt := range statements {
+				if stmt != tt.expected[i] {
+					t.Errorf("statement %d = %q, want %q", i, stmt, tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -294,7 +294,7 @@ func (e *Elasticsearch) GetTableColumns(tableName string) ([]ColumnInfo, error)
 	if !exists {
 		// 索引不存在，返回默认字段
 		return []ColumnInfo{
-			{Name: "_id", Type: "string", Nullable: false},
+			{Name: "_id", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "_source", Type: "object", Nullable: true},
 		}, nil
 	}
@@ -315,7 +315,7 @@ func (e *Elasticsearch) GetTableColumns(tableName string) ([]ColumnInfo, error)
 			// 如果两种方法都失败，返回默认字段而不是错误
 			// 这样可以避免因为 mapping 获取失败而无法查看数据
 			return []ColumnInfo{
-				{Name: "_id", Type: "string", Nullable: false},
+				{Name: "_id", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "_source", Type: "object", Nullable: true},
 			}, nil
 		}
@@ -325,7 +325,7 @@ func (e *Elasticsearch) GetTableColumns(tableName string) ([]ColumnInfo, error)
 		if err := json.Unmarshal(res.Body, &mapping); err != nil {
 			// 解析失败，返回默认字段
 			return []ColumnInfo{
-				{Name: "_id", Type: "string", Nullable: false},
+				{Name: "_id", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "_source", Type: "object", Nullable: true},
 			}, nil
 		}
@@ -353,10 +353,15 @@ func (e *Elasticsearch) GetTableColumns(tableName string) ([]ColumnInfo, error)
 		}
 	}
 
+	// 文档 ID 作为主键，用于按行编辑和删除
+	if len(columns) > 0 {
+		columns = append([]ColumnInfo{{Name: "_id", Type: "string", Nullable: false, Key: "PRI"}}, columns...)
+	}
+
 	// 如果没有找到字段，至少返回 _id 字段
 	if len(columns) == 0 {
 		columns = []ColumnInfo{
-			{Name: "_id", Type: "string", Nullable: false},
+			{Name: "_id", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "_source", Type: "object", Nullable: true},
 		}
 	}
@@ -421,59 +426,54 @@ func extractFieldsFromMapping(mapping interface{}, prefix string) []ColumnInfo {
 
 // GetTableData 获取索引的数据（分页）
 func (e *Elasticsearch) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return e.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取索引的数据（分页），ctx 取消或超时时中止执行
+func (e *Elasticsearch) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return e.GetTableDataSorted(ctx, tableName, page, pageSize, filters, nil)
+}
+
+// GetTableDataSorted 获取索引数据（分页 + 多列排序）
+// 未指定 Nulls 时缺失字段排在最后（Elasticsearch 默认行为）
+func (e *Elasticsearch) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
 	if e.client == nil {
 		return nil, 0, fmt.Errorf("database not connected")
 	}
+	if err := ValidateSorts(sorts); err != nil {
+		return nil, 0, err
+	}
 
 	// 构建查询
 	var query elastic.Query = elastic.NewMatchAllQuery()
 
 	// 应用过滤条件
-	if filters != nil && len(filters.Conditions) > 0 {
-		var queries []elastic.Query
-		for _, condition := range filters.Conditions {
-			var q elastic.Query
-			switch condition.Operator {
-			case "=":
-				q = elastic.NewTermQuery(condition.Field, condition.Value)
-			case "!=":
-				q = elastic.NewBoolQuery().MustNot(elastic.NewTermQuery(condition.Field, condition.Value))
-			case "LIKE":
-				q = elastic.NewWildcardQuery(condition.Field, "*"+condition.Value+"*")
-			case ">":
-				q = elastic.NewRangeQuery(condition.Field).Gt(condition.Value)
-			case ">=":
-				q = elastic.NewRangeQuery(condition.Field).Gte(condition.Value)
-			case "<":
-				q = elastic.NewRangeQuery(condition.Field).Lt(condition.Value)
-			case "<=":
-				q = elastic.NewRangeQuery(condition.Field).Lte(condition.Value)
-			case "IS NULL":
-				q = elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery(condition.Field))
-			case "IS NOT NULL":
-				q = elastic.NewExistsQuery(condition.Field)
-			default:
-				q = elastic.NewMatchAllQuery()
-			}
-			queries = append(queries, q)
-		}
-
-		// 组合查询
-		if filters.Logic == "OR" {
-			query = elastic.NewBoolQuery().Should(queries...).MinimumShouldMatch("1")
-		} else {
-			query = elastic.NewBoolQuery().Must(queries...)
+	if !filters.IsEmpty() {
+		q, err := buildElasticsearchQuery(filters)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to build filter: %w", err)
 		}
+		query = q
 	}
 
 	// 执行搜索
 	from := (page - 1) * pageSize
-	searchResult, err := e.client.Search().
+	search := e.client.Search().
 		Index(tableName).
 		Query(query).
 		From(from).
-		Size(pageSize).
-		Do(e.ctx)
+		Size(pageSize)
+	for _, s := range sorts {
+		fieldSort := elastic.NewFieldSort(s.Column).Order(!s.IsDesc())
+		switch strings.ToLower(s.Nulls) {
+		case "first":
+			fieldSort = fieldSort.Missing("_first")
+		case "last":
+			fieldSort = fieldSort.Missing("_last")
+		}
+		search = search.SortBy(fieldSort)
+	}
+	searchResult, err := search.Do(ctx)
 
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to search: %w", err)
@@ -515,13 +515,176 @@ func (e *Elasticsearch) GetTableData(tableName string, page, pageSize int, filte
 	return results, total, nil
 }
 
+// buildElasticsearchQuery 将 FilterGroup 转换为 Elasticsearch 查询（支持嵌套条件组）
+func buildElasticsearchQuery(filters *FilterGroup) (elastic.Query, error) {
+	var queries []elastic.Query
+	for _, condition := range filters.Conditions {
+		if condition.Field == "" {
+			continue
+		}
+		q, err := buildElasticsearchCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		if q != nil {
+			queries = append(queries, q)
+		}
+	}
+
+	for i := range filters.Groups {
+		if filters.Groups[i].IsEmpty() {
+			continue
+		}
+		q, err := buildElasticsearchQuery(&filters.Groups[i])
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+
+	// 组合查询
+	if normalizeLogic(filters.Logic) == "OR" {
+		return elastic.NewBoolQuery().Should(queries...).MinimumShouldMatch("1"), nil
+	}
+	return elastic.NewBoolQuery().Must(queries...), nil
+}
+
+// buildElasticsearchCondition 将单个过滤条件转换为 Elasticsearch 查询，无法生成条件时返回 nil
+func buildElasticsearchCondition(condition FilterCondition) (elastic.Query, error) {
+	field := condition.Field
+	operator := strings.ToUpper(strings.TrimSpace(condition.Operator))
+
+	switch operator {
+	case "=":
+		return elastic.NewTermQuery(field, condition.Value), nil
+	case "!=":
+		return elastic.NewBoolQuery().MustNot(elastic.NewTermQuery(field, condition.Value)), nil
+	case "LIKE":
+		return elastic.NewWildcardQuery(field, "*"+condition.Value+"*"), nil
+	case "NOT LIKE":
+		return elastic.NewBoolQuery().MustNot(elastic.NewWildcardQuery(field, "*"+condition.Value+"*")), nil
+	case ">":
+		return elastic.NewRangeQuery(field).Gt(condition.Value), nil
+	case ">=":
+		return elastic.NewRangeQuery(field).Gte(condition.Value), nil
+	case "<":
+		return elastic.NewRangeQuery(field).Lt(condition.Value), nil
+	case "<=":
+		return elastic.NewRangeQuery(field).Lte(condition.Value), nil
+	case "IN", "NOT IN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		terms := make([]interface{}, len(values))
+		for i, v := range values {
+			terms[i] = v
+		}
+		if operator == "NOT IN" {
+			return elastic.NewBoolQuery().MustNot(elastic.NewTermsQuery(field, terms...)), nil
+		}
+		return elastic.NewTermsQuery(field, terms...), nil
+	case "IS NULL":
+		return elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery(field)), nil
+	case "IS NOT NULL":
+		return elastic.NewExistsQuery(field), nil
+	case "BETWEEN", "NOT BETWEEN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("%s requires two values for field %s", operator, field)
+		}
+		between := elastic.NewRangeQuery(field).Gte(values[0]).Lte(values[1])
+		if operator == "NOT BETWEEN" {
+			return elastic.NewBoolQuery().MustNot(between), nil
+		}
+		return between, nil
+	case "CONTAINS", "NOT CONTAINS", "ENDS WITH":
+		if condition.Value == "" {
+			return nil, nil
+		}
+		// 转义通配符，按字面匹配
+		value := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`).Replace(condition.Value)
+		if operator == "ENDS WITH" {
+			return elastic.NewWildcardQuery(field, "*"+value).CaseInsensitive(true), nil
+		}
+		q := elastic.NewWildcardQuery(field, "*"+value+"*").CaseInsensitive(true)
+		if operator == "NOT CONTAINS" {
+			return elastic.NewBoolQuery().MustNot(q), nil
+		}
+		return q, nil
+	case "STARTS WITH":
+		if condition.Value == "" {
+			return nil, nil
+		}
+		return elastic.NewPrefixQuery(field, condition.Value).CaseInsensitive(true), nil
+	case "REGEX", "NOT REGEX":
+		if condition.Value == "" {
+			return nil, nil
+		}
+		q := elastic.NewRegexpQuery(field, condition.Value)
+		if operator == "NOT REGEX" {
+			return elastic.NewBoolQuery().MustNot(q), nil
+		}
+		return q, nil
+	case "LAST", "NEXT", "TODAY", "YESTERDAY", "THIS WEEK", "THIS MONTH", "THIS YEAR":
+		start, end, err := elasticsearchDateRange(operator, condition.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date range for field %s: %w", field, err)
+		}
+		return elastic.NewRangeQuery(field).Gte(start).Lt(end), nil
+	default:
+		return elastic.NewMatchAllQuery(), nil
+	}
+}
+
+// elasticsearchDateRange 将相对日期操作符转换为 Elasticsearch 的日期表达式（date math）
+func elasticsearchDateRange(operator, value string) (string, string, error) {
+	switch operator {
+	case "LAST", "NEXT":
+		amount, unit, err := parseRelativeDuration(value)
+		if err != nil {
+			return "", "", err
+		}
+		units := map[string]string{"minute": "m", "hour": "h", "day": "d", "week": "w", "month": "M", "year": "y"}
+		offset := fmt.Sprintf("%d%s", amount, units[unit])
+		if operator == "LAST" {
+			return "now-" + offset, "now", nil
+		}
+		return "now", "now+" + offset, nil
+	case "TODAY":
+		return "now/d", "now+1d/d", nil
+	case "YESTERDAY":
+		return "now-1d/d", "now/d", nil
+	case "THIS WEEK":
+		return "now/w", "now+1w/w", nil
+	case "THIS MONTH":
+		return "now/M", "now+1M/M", nil
+	case "THIS YEAR":
+		return "now/y", "now+1y/y", nil
+	}
+	return "", "", fmt.Errorf("unsupported date operator: %s", operator)
+}
+
 // GetTableDataByID 基于ID获取数据（Elasticsearch 不支持基于ID的分页，使用普通分页）
 func (e *Elasticsearch) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return e.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于ID获取数据（Elasticsearch 不支持基于ID的分页，使用普通分页），ctx 取消或超时时中止执行
+func (e *Elasticsearch) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// Elasticsearch 不支持基于ID的分页，使用普通分页，页码固定为1
-	data, total, err := e.GetTableData(tableName, 1, pageSize, filters)
+	data, total, err := e.GetTableDataContext(ctx, tableName, 1, pageSize, filters)
 	return data, total, nil, err
 }
 
+// GetTableDataByCursor 基于游标获取数据（Elasticsearch 不支持，返回错误）
+func (e *Elasticsearch) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return nil, 0, fmt.Errorf("Elasticsearch does not support cursor-based pagination")
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（Elasticsearch 不支持）
 func (e *Elasticsearch) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	return nil, fmt.Errorf("Elasticsearch does not support ID-based pagination")
@@ -529,6 +692,11 @@ func (e *Elasticsearch) GetPageIdByPageNumber(tableName string, primaryKey strin
 
 // ExecuteQuery 执行查询（Elasticsearch DSL 查询）
 func (e *Elasticsearch) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return e.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询（Elasticsearch DSL 查询），ctx 取消或超时时中止执行
+func (e *Elasticsearch) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	if e.client == nil {
 		return nil, fmt.Errorf("database not connected")
 	}
@@ -543,7 +711,7 @@ func (e *Elasticsearch) ExecuteQuery(query string) ([]map[string]interface{}, er
 	searchResult, err := e.client.Search().
 		Index("*").
 		Source(searchQuery).
-		Do(e.ctx)
+		Do(ctx)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
@@ -585,6 +753,11 @@ func (e *Elasticsearch) ExecuteQuery(query string) ([]map[string]interface{}, er
 
 // ExecuteUpdate 执行更新（Elasticsearch 使用 Update API）
 func (e *Elasticsearch) ExecuteUpdate(query string) (int64, error) {
+	return e.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新（Elasticsearch 使用 Update API），ctx 取消或超时时中止执行
+func (e *Elasticsearch) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
 	if e.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -609,7 +782,7 @@ func (e *Elasticsearch) ExecuteUpdate(query string) (int64, error) {
 		Index(index).
 		Id(id).
 		Doc(doc).
-		Do(e.ctx)
+		Do(ctx)
 
 	if err != nil {
 		return 0, fmt.Errorf("failed to update: %w", err)
@@ -620,6 +793,11 @@ func (e *Elasticsearch) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除（Elasticsearch 使用 Delete API）
 func (e *Elasticsearch) ExecuteDelete(query string) (int64, error) {
+	return e.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除（Elasticsearch 使用 Delete API），ctx 取消或超时时中止执行
+func (e *Elasticsearch) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
 	if e.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -635,7 +813,7 @@ func (e *Elasticsearch) ExecuteDelete(query string) (int64, error) {
 			_, err := e.client.Delete().
 				Index(index).
 				Id(id).
-				Do(e.ctx)
+				Do(ctx)
 			if err != nil {
 				return 0, fmt.Errorf("failed to delete: %w", err)
 			}
@@ -654,7 +832,7 @@ func (e *Elasticsearch) ExecuteDelete(query string) (int64, error) {
 	_, err := e.client.Delete().
 		Index(index).
 		Id(id).
-		Do(e.ctx)
+		Do(ctx)
 
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete: %w", err)
@@ -665,6 +843,11 @@ func (e *Elasticsearch) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入（Elasticsearch 使用 Index API）
 func (e *Elasticsearch) ExecuteInsert(query string) (int64, error) {
+	return e.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入（Elasticsearch 使用 Index API），ctx 取消或超时时中止执行
+func (e *Elasticsearch) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
 	if e.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -696,7 +879,7 @@ func (e *Elasticsearch) ExecuteInsert(query string) (int64, error) {
 		indexService = indexService.Id(id)
 	}
 
-	_, err := indexService.Do(e.ctx)
+	_, err := indexService.Do(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to insert: %w", err)
 	}
@@ -766,3 +949,144 @@ func BuildElasticsearchDSN(info ConnectionInfo) string {
 	return dsn
 }
 
+// UpdateRows 按文档 ID 更新文档，表名即索引名
+// 编辑展开后的字段时只更新发生变化的字段；只编辑 _source 时用其内容替换整个文档
+func (e *Elasticsearch) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	if e.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+	id, err := elasticsearchDocumentID(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := e.client.Get().Index(tableName).Id(id).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to load document: %w", err)
+	}
+	var original map[string]interface{}
+	if res.Source != nil {
+		if err := json.Unmarshal(res.Source, &original); err != nil {
+			return 0, fmt.Errorf("failed to parse document: %w", err)
+		}
+	}
+
+	doc := make(map[string]interface{})
+	var source interface{}
+	for field, value := range values {
+		switch field {
+		case "_id", "_score", "_index":
+			continue
+		case "_source":
+			source = value
+			continue
+		}
+		if coerced, changed := coerceToOriginal(original[field], value); changed {
+			doc[field] = coerced
+		}
+	}
+
+	if len(doc) == 0 && source != nil {
+		replacement, ok := source.(map[string]interface{})
+		if !ok {
+			if err := json.Unmarshal([]byte(fmt.Sprint(source)), &replacement); err != nil {
+				return 0, fmt.Errorf("invalid _source: %w", err)
+			}
+		}
+		if equalLoosely(original, replacement) {
+			return 0, nil
+		}
+		if _, err := e.client.Index().Index(tableName).Id(id).BodyJson(replacement).Do(ctx); err != nil {
+			return 0, fmt.Errorf("failed to replace document: %w", err)
+		}
+		return 1, nil
+	}
+	if len(doc) == 0 {
+		return 0, nil
+	}
+
+	if _, err := e.client.Update().Index(tableName).Id(id).Doc(doc).Do(ctx); err != nil {
+		return 0, fmt.Errorf("failed to update: %w", err)
+	}
+	return 1, nil
+}
+
+// DeleteRows 按文档 ID 删除文档
+func (e *Elasticsearch) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	if e.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+	id, err := elasticsearchDocumentID(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := e.client.Delete().Index(tableName).Id(id).Do(ctx); err != nil {
+		if elastic.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to delete: %w", err)
+	}
+	return 1, nil
+}
+
+// InsertRow 写入新文档，表名即索引名；提供 _id 时使用指定的文档 ID，否则由 Elasticsearch 生成
+// 字符串值按 mapping 中的字段类型转换；只提供 _source 时使用其内容作为文档
+func (e *Elasticsearch) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	if e.client == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		types[col.Name] = col.Type
+	}
+
+	var id string
+	var source interface{}
+	doc := make(map[string]interface{})
+	for field, value := range values {
+		switch field {
+		case "_id":
+			if value != nil {
+				id = fmt.Sprint(value)
+			}
+		case "_score", "_index":
+			// 元数据字段不写入文档
+		case "_source":
+			source = value
+		default:
+			doc[field] = coerceToColumnType(types[field], value)
+		}
+	}
+	if len(doc) == 0 && source != nil {
+		if m, ok := source.(map[string]interface{}); ok {
+			doc = m
+		} else if err := json.Unmarshal([]byte(fmt.Sprint(source)), &doc); err != nil {
+			return nil, fmt.Errorf("invalid _source: %w", err)
+		}
+	}
+
+	// 等待刷新后返回，使新文档可以立即在表数据中看到
+	service := e.client.Index().Index(tableName).BodyJson(doc).Refresh("wait_for")
+	if id != "" {
+		service = service.Id(id).OpType("create")
+	}
+	res, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index document: %w", err)
+	}
+	return map[string]interface{}{"_id": res.Id}, nil
+}
+
+// elasticsearchDocumentID 从定位文档的键中取出文档 ID
+func elasticsearchDocumentID(keys map[string]interface{}) (string, error) {
+	id, ok := keys["_id"]
+	if !ok || id == nil || fmt.Sprint(id) == "" {
+		return "", fmt.Errorf("document _id is required")
+	}
+	return fmt.Sprint(id), nil
+}
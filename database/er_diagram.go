@@ -0,0 +1,298 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ERDiagram 由外键构成的实体关系图
+type ERDiagram struct {
+	Tables    []ERTable    `json:"tables"`
+	Relations []ERRelation `json:"relations"`
+}
+
+// ERTable 实体（表）
+type ERTable struct {
+	Name    string     `json:"name"`
+	Columns []ERColumn `json:"columns"`
+}
+
+// ERColumn 实体的属性（列）
+type ERColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primaryKey"`
+	ForeignKey bool   `json:"foreignKey"`
+}
+
+// ERRelation 外键关系：Table.Columns 引用 RefTable.RefColumns
+type ERRelation struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	Nullable   bool     `json:"nullable"` // 外键列可以为空，即子表的行不一定引用父表
+}
+
+// LoadERDiagram 读取表的元数据并构建关系图。tables 为空时使用数据库中的所有表；
+// 指定 focus 时只保留这些表以及在 depth 跳以内（按外键双向计算）与它们相连的表
+func LoadERDiagram(ctx context.Context, db MetadataDatabase, tables, focus []string, depth int) (*ERDiagram, error) {
+	// 只看 focus 本身时不需要其他表的外键
+	if len(focus) > 0 && depth <= 0 {
+		var selected []string
+		for _, name := range focus {
+			if table, ok := findTableFold(tables, name); ok {
+				selected = append(selected, table)
+			}
+		}
+		tables = selected
+	}
+
+	metadata := make([]*TableMetadata, 0, len(tables))
+	for _, table := range tables {
+		meta, err := db.GetTableMetadata(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table, err)
+		}
+		metadata = append(metadata, meta)
+	}
+	diagram := BuildERDiagram(metadata)
+	if len(focus) > 0 {
+		diagram = diagram.Neighborhood(focus, depth)
+	}
+	return diagram, nil
+}
+
+// BuildERDiagram 由表的元数据构建关系图，只保留两端都在图中的外键，表名的大小写不一致时按不区分大小写匹配
+func BuildERDiagram(metadata []*TableMetadata) *ERDiagram {
+	diagram := &ERDiagram{Tables: []ERTable{}, Relations: []ERRelation{}}
+	names := make([]string, len(metadata))
+	for i, meta := range metadata {
+		names[i] = meta.Name
+	}
+
+	for _, meta := range metadata {
+		fkColumns := map[string]bool{}
+		nullable := map[string]bool{}
+		for _, col := range meta.Columns {
+			nullable[col.Name] = col.Nullable
+		}
+		for _, fk := range meta.ForeignKeys {
+			refTable, ok := findTableFold(names, fk.RefTable)
+			if !ok {
+				continue
+			}
+			relation := ERRelation{
+				Name:       fk.Name,
+				Table:      meta.Name,
+				Columns:    fk.Columns,
+				RefTable:   refTable,
+				RefColumns: fk.RefColumns,
+			}
+			for _, col := range fk.Columns {
+				fkColumns[col] = true
+				relation.Nullable = relation.Nullable || nullable[col]
+			}
+			diagram.Relations = append(diagram.Relations, relation)
+		}
+
+		primary := map[string]bool{}
+		for _, idx := range meta.Indexes {
+			if idx.Primary {
+				for _, col := range idx.Columns {
+					primary[col] = true
+				}
+			}
+		}
+		table := ERTable{Name: meta.Name, Columns: make([]ERColumn, len(meta.Columns))}
+		for i, col := range meta.Columns {
+			table.Columns[i] = ERColumn{
+				Name:       col.Name,
+				Type:       col.Type,
+				Nullable:   col.Nullable,
+				PrimaryKey: primary[col.Name] || col.Key == "PRI",
+				ForeignKey: fkColumns[col.Name],
+			}
+		}
+		diagram.Tables = append(diagram.Tables, table)
+	}
+
+	sort.Slice(diagram.Tables, func(i, j int) bool { return diagram.Tables[i].Name < diagram.Tables[j].Name })
+	sort.SliceStable(diagram.Relations, func(i, j int) bool {
+		a, b := diagram.Relations[i], diagram.Relations[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.Name < b.Name
+	})
+	return diagram
+}
+
+// Neighborhood 返回只包含 focus 中的表以及在 depth 跳以内与它们相连的表的子图，外键的方向不影响距离
+func (d *ERDiagram) Neighborhood(focus []string, depth int) *ERDiagram {
+	names := make([]string, len(d.Tables))
+	for i, table := range d.Tables {
+		names[i] = table.Name
+	}
+	adjacent := map[string][]string{}
+	for _, rel := range d.Relations {
+		adjacent[rel.Table] = append(adjacent[rel.Table], rel.RefTable)
+		adjacent[rel.RefTable] = append(adjacent[rel.RefTable], rel.Table)
+	}
+
+	included := map[string]bool{}
+	var frontier []string
+	for _, name := range focus {
+		if table, ok := findTableFold(names, name); ok && !included[table] {
+			included[table] = true
+			frontier = append(frontier, table)
+		}
+	}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, table := range frontier {
+			for _, neighbor := range adjacent[table] {
+				if !included[neighbor] {
+					included[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	sub := &ERDiagram{Tables: []ERTable{}, Relations: []ERRelation{}}
+	for _, table := range d.Tables {
+		if included[table.Name] {
+			sub.Tables = append(sub.Tables, table)
+		}
+	}
+	for _, rel := range d.Relations {
+		if included[rel.Table] && included[rel.RefTable] {
+			sub.Relations = append(sub.Relations, rel)
+		}
+	}
+	return sub
+}
+
+var mermaidUnsafePattern = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
+
+// mermaidToken 把名称或类型转换为 Mermaid erDiagram 可以接受的记号（字母开头，只含字母、数字、下划线和连字符）
+func mermaidToken(s string) string {
+	token := mermaidUnsafePattern.ReplaceAllString(s, "_")
+	if token == "" || !(token[0] >= 'A' && token[0] <= 'Z' || token[0] >= 'a' && token[0] <= 'z') {
+		token = "_" + token
+	}
+	return token
+}
+
+// Mermaid 返回 Mermaid erDiagram 文本。类型中的括号、逗号等字符替换为下划线，如 decimal(10,2) 写为 decimal_10_2_
+func (d *ERDiagram) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range d.Tables {
+		fmt.Fprintf(&b, "    %s {\n", mermaidToken(table.Name))
+		for _, col := range table.Columns {
+			var keys []string
+			if col.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if col.ForeignKey {
+				keys = append(keys, "FK")
+			}
+			fmt.Fprintf(&b, "        %s %s", mermaidToken(col.Type), mermaidToken(col.Name))
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, rel := range d.Relations {
+		// 父表一行对应子表零或多行；外键列可为空时子表的行可以不引用父表
+		parent := "||"
+		if rel.Nullable {
+			parent = "|o"
+		}
+		label := rel.Name
+		if label == "" {
+			label = strings.Join(rel.Columns, ", ")
+		}
+		fmt.Fprintf(&b, "    %s %s--o{ %s : \"%s\"\n", mermaidToken(rel.RefTable), parent, mermaidToken(rel.Table), strings.ReplaceAll(label, `"`, "'"))
+	}
+	return b.String()
+}
+
+// DOT 返回 Graphviz DOT 文本，表渲染为 HTML 表格节点，外键从子表的列指向父表的列
+func (d *ERDiagram) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph ER {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=plaintext, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [arrowhead=tee, arrowtail=crow, dir=both];\n")
+
+	// 端口使用列的序号，避免列名中的特殊字符
+	ports := map[string]map[string]int{}
+	for _, table := range d.Tables {
+		ports[table.Name] = map[string]int{}
+		fmt.Fprintf(&b, "    %s [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">\n", dotID(table.Name))
+		fmt.Fprintf(&b, "        <TR><TD BGCOLOR=\"lightgrey\"><B>%s</B></TD></TR>\n", html.EscapeString(table.Name))
+		for i, col := range table.Columns {
+			ports[table.Name][col.Name] = i
+			text := html.EscapeString(col.Name + " " + col.Type)
+			if col.PrimaryKey {
+				text = "<U>" + text + "</U>"
+			}
+			if col.ForeignKey {
+				text += " (FK)"
+			}
+			fmt.Fprintf(&b, "        <TR><TD PORT=\"c%d\" ALIGN=\"LEFT\">%s</TD></TR>\n", i, text)
+		}
+		b.WriteString("    </TABLE>>];\n")
+	}
+	for _, rel := range d.Relations {
+		from, to := dotID(rel.Table), dotID(rel.RefTable)
+		if i, ok := ports[rel.Table][firstOf(rel.Columns)]; ok {
+			from += fmt.Sprintf(":c%d", i)
+		}
+		if i, ok := ports[rel.RefTable][firstOf(rel.RefColumns)]; ok {
+			to += fmt.Sprintf(":c%d", i)
+		}
+		fmt.Fprintf(&b, "    %s -> %s [label=%s];\n", from, to, dotID(rel.Name))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotID 返回带引号的 DOT 标识符
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// findTableFold 在表名列表中查找表，先精确匹配再不区分大小写匹配
+func findTableFold(tables []string, name string) (string, bool) {
+	for _, table := range tables {
+		if table == name {
+			return table, true
+		}
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table, name) {
+			return table, true
+		}
+	}
+	return "", false
+}
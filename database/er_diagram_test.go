@@ -0,0 +1,90 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func erTestMetadata() []*TableMetadata {
+	departments := newTableMetadata("departments", []ColumnInfo{{Name: "id", Type: "int", Key: "PRI"}, {Name: "name", Type: "varchar(64)"}})
+	users := newTableMetadata("users", []ColumnInfo{{Name: "id", Type: "int", Key: "PRI"}, {Name: "dept_id", Type: "int", Nullable: true}})
+	users.ForeignKeys = []ForeignKeyInfo{{Name: "fk_users_dept", Columns: []string{"dept_id"}, RefTable: "DEPARTMENTS", RefColumns: []string{"id"}}}
+	orders := newTableMetadata("orders", []ColumnInfo{{Name: "id", Type: "bigint", Key: "PRI"}, {Name: "user_id", Type: "int"}, {Name: "amount", Type: "decimal(10,2)"}})
+	orders.ForeignKeys = []ForeignKeyInfo{
+		{Name: "fk_orders_user", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		{Name: "fk_orders_missing", Columns: []string{"id"}, RefTable: "archived", RefColumns: []string{"id"}},
+	}
+	logs := newTableMetadata("logs", []ColumnInfo{{Name: "id", Type: "int"}})
+	return []*TableMetadata{users, orders, departments, logs}
+}
+
+func TestBuildERDiagram(t *testing.T) {
+	diagram := BuildERDiagram(erTestMetadata())
+
+	var names []string
+	for _, table := range diagram.Tables {
+		names = append(names, table.Name)
+	}
+	if want := []string{"departments", "logs", "orders", "users"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("tables = %v, want %v", names, want)
+	}
+	// 引用不存在的表的外键被忽略，表名按不区分大小写匹配
+	expected := []ERRelation{
+		{Name: "fk_orders_user", Table: "orders", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		{Name: "fk_users_dept", Table: "users", Columns: []string{"dept_id"}, RefTable: "departments", RefColumns: []string{"id"}, Nullable: true},
+	}
+	if !reflect.DeepEqual(diagram.Relations, expected) {
+		t.Errorf("relations = %+v, want %+v", diagram.Relations, expected)
+	}
+
+	tests := []struct {
+		focus    []string
+		depth    int
+		expected []string
+	}{
+		{[]string{"orders"}, 0, []string{"orders"}},
+		{[]string{"orders"}, 1, []string{"orders", "users"}},
+		{[]string{"ORDERS"}, 2, []string{"departments", "orders", "users"}},
+		{[]string{"departments", "logs"}, 1, []string{"departments", "logs", "users"}},
+	}
+	for _, tt := range tests {
+		sub := diagram.Neighborhood(tt.focus, tt.depth)
+		var got []string
+		for _, table := range sub.Tables {
+			got = append(got, table.Name)
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Neighborhood(%v, %d) = %v, want %v", tt.focus, tt.depth, got, tt.expected)
+		}
+	}
+}
+
+func TestERDiagramText(t *testing.T) {
+	diagram := BuildERDiagram(erTestMetadata()).Neighborhood([]string{"orders"}, 1)
+
+	mermaid := diagram.Mermaid()
+	expected := `erDiagram
+    orders {
+        bigint id PK
+        int user_id FK
+        decimal_10_2_ amount
+    }
+    users {
+        int id PK
+        int dept_id FK
+    }
+    users ||--o{ orders : "fk_orders_user"
+`
+	if mermaid != expected {
+		t.Errorf("Mermaid =\n%s\nwant\n%s", mermaid, expected)
+	}
+
+	dot := diagram.DOT()
+	if !strings.Contains(dot, `"orders":c1 -> "users":c0 [label="fk_orders_user"];`) {
+		t.Errorf("DOT is missing the foreign key edge:\n%s", dot)
+	}
+	if !strings.Contains(dot, `<TD PORT="c2" ALIGN="LEFT">amount decimal(10,2)</TD>`) {
+		t.Errorf("DOT is missing the column row:\n%s", dot)
+	}
+}
@@ -0,0 +1,540 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExplainDatabase 支持查看执行计划的数据库接口扩展
+type ExplainDatabase interface {
+	// ExplainQuery 返回查询的执行计划。analyze 为 true 时实际执行查询以得到实际行数和耗时，
+	// 只有 PostgreSQL 和 MongoDB 支持，其他数据库忽略（QueryPlan.Analyzed 为 false）
+	ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error)
+}
+
+// 执行计划原文的格式
+const (
+	PlanFormatJSON = "json"
+	PlanFormatXML  = "xml"
+	PlanFormatText = "text"
+)
+
+// QueryPlan 统一格式的执行计划：Nodes 为计划树的根节点（多条语句或多个查询块时有多个），Raw 为数据库返回的原文
+type QueryPlan struct {
+	Nodes    []*PlanNode `json:"nodes"`
+	Analyzed bool        `json:"analyzed"`
+	Format   string      `json:"format"`
+	Raw      string      `json:"raw"`
+}
+
+// PlanNode 执行计划中的一个算子。行数、代价和耗时为 nil 表示数据库没有提供；
+// 代价的单位与数据库相关，只能在同一个计划中比较
+type PlanNode struct {
+	Operation     string      `json:"operation"`
+	Object        string      `json:"object,omitempty"` // 访问的表或集合
+	Index         string      `json:"index,omitempty"`
+	Detail        string      `json:"detail,omitempty"` // 条件等附加信息
+	EstimatedRows *float64    `json:"estimatedRows,omitempty"`
+	ActualRows    *float64    `json:"actualRows,omitempty"`
+	Cost          *float64    `json:"cost,omitempty"`
+	ActualTimeMs  *float64    `json:"actualTimeMs,omitempty"`
+	Children      []*PlanNode `json:"children,omitempty"`
+}
+
+// planNumber 把 JSON 或 XML 中的数值（数字或数字字符串）转换为指针，无法解析时返回 nil
+func planNumber(value interface{}) *float64 {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return nil
+		}
+		f = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil
+		}
+		f = parsed
+	default:
+		return nil
+	}
+	return &f
+}
+
+// planString 返回 JSON 中的字符串值，字符串数组以逗号连接
+func planString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// planRow 以 id 和父 id 表示的计划行（Oracle 的 plan_table、SQLite 的 EXPLAIN QUERY PLAN）
+type planRow struct {
+	id     int
+	parent int
+	node   *PlanNode
+}
+
+// buildPlanTree 按父 id 构建计划树，找不到父节点的行作为根节点，子节点保持行的顺序
+func buildPlanTree(rows []planRow) []*PlanNode {
+	byID := make(map[int]*PlanNode, len(rows))
+	for _, row := range rows {
+		byID[row.id] = row.node
+	}
+	roots := []*PlanNode{}
+	for _, row := range rows {
+		if parent, ok := byID[row.parent]; ok && row.parent != row.id {
+			parent.Children = append(parent.Children, row.node)
+		} else {
+			roots = append(roots, row.node)
+		}
+	}
+	return roots
+}
+
+// mysqlAccessTypes MySQL 访问类型对应的算子名称
+var mysqlAccessTypes = map[string]string{
+	"ALL":             "Full Table Scan",
+	"index":           "Full Index Scan",
+	"range":           "Index Range Scan",
+	"ref":             "Index Lookup",
+	"ref_or_null":     "Index Lookup",
+	"eq_ref":          "Unique Index Lookup",
+	"const":           "Constant Lookup",
+	"system":          "Constant Lookup",
+	"fulltext":        "Fulltext Index Lookup",
+	"index_merge":     "Index Merge",
+	"unique_subquery": "Unique Subquery",
+	"index_subquery":  "Index Subquery",
+}
+
+// mysqlOperations 包含子计划的 MySQL 操作，按执行顺序从外到内
+var mysqlOperations = []struct {
+	key       string
+	operation string
+}{
+	{"ordering_operation", "Sort"},
+	{"grouping_operation", "Group"},
+	{"duplicates_removal", "Distinct"},
+	{"windowing", "Window"},
+	{"buffer_result", "Buffer"},
+}
+
+// mysqlSubqueryKeys 包含子查询的 MySQL 键
+var mysqlSubqueryKeys = []string{
+	"materialized_from_subquery", "attached_subqueries", "optimized_away_subqueries",
+	"order_by_subqueries", "group_by_subqueries", "having_subqueries", "select_list_subqueries",
+}
+
+// parseMySQLExplain 解析 EXPLAIN FORMAT=JSON 的输出，查询块、排序、分组、嵌套循环和表访问分别为一个算子
+func parseMySQLExplain(raw string) (*QueryPlan, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	block, ok := doc["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse plan: query_block not found")
+	}
+	return &QueryPlan{Nodes: []*PlanNode{mysqlQueryBlock(block)}, Format: PlanFormatJSON, Raw: raw}, nil
+}
+
+func mysqlQueryBlock(block map[string]interface{}) *PlanNode {
+	node := &PlanNode{Operation: "Query Block"}
+	if id, ok := block["select_id"]; ok {
+		node.Detail = fmt.Sprintf("select #%v", id)
+	}
+	if message := planString(block["message"]); message != "" {
+		node.Detail = message
+	}
+	if cost, ok := block["cost_info"].(map[string]interface{}); ok {
+		node.Cost = planNumber(cost["query_cost"])
+	}
+	node.Children = mysqlPlanChildren(block)
+	return node
+}
+
+func mysqlPlanChildren(obj map[string]interface{}) []*PlanNode {
+	var children []*PlanNode
+	for _, op := range mysqlOperations {
+		if value, ok := obj[op.key].(map[string]interface{}); ok {
+			node := &PlanNode{Operation: op.operation, Children: mysqlPlanChildren(value)}
+			if value["using_filesort"] == true {
+				node.Detail = "using filesort"
+			}
+			if value["using_temporary_table"] == true {
+				node.Detail = strings.TrimPrefix(node.Detail+", using temporary table", ", ")
+			}
+			children = append(children, node)
+		}
+	}
+	if table, ok := obj["table"].(map[string]interface{}); ok {
+		children = append(children, mysqlTableNode(table))
+	}
+	if loop, ok := obj["nested_loop"].([]interface{}); ok {
+		node := &PlanNode{Operation: "Nested Loop"}
+		for _, item := range loop {
+			if value, ok := item.(map[string]interface{}); ok {
+				node.Children = append(node.Children, mysqlPlanChildren(value)...)
+			}
+		}
+		children = append(children, node)
+	}
+	if union, ok := obj["union_result"].(map[string]interface{}); ok {
+		node := &PlanNode{Operation: "Union"}
+		specs, _ := union["query_specifications"].([]interface{})
+		for _, spec := range specs {
+			if value, ok := spec.(map[string]interface{}); ok {
+				if block, ok := value["query_block"].(map[string]interface{}); ok {
+					node.Children = append(node.Children, mysqlQueryBlock(block))
+				}
+			}
+		}
+		children = append(children, node)
+	}
+	for _, key := range mysqlSubqueryKeys {
+		var items []interface{}
+		switch value := obj[key].(type) {
+		case map[string]interface{}:
+			items = []interface{}{value}
+		case []interface{}:
+			items = value
+		}
+		for _, item := range items {
+			if value, ok := item.(map[string]interface{}); ok {
+				if block, ok := value["query_block"].(map[string]interface{}); ok {
+					children = append(children, mysqlQueryBlock(block))
+				}
+			}
+		}
+	}
+	return children
+}
+
+func mysqlTableNode(table map[string]interface{}) *PlanNode {
+	accessType := planString(table["access_type"])
+	operation, ok := mysqlAccessTypes[accessType]
+	if !ok {
+		operation = "Table Access"
+		if accessType != "" {
+			operation += " (" + accessType + ")"
+		}
+	}
+	node := &PlanNode{
+		Operation: operation,
+		Object:    planString(table["table_name"]),
+		Index:     planString(table["key"]),
+		Detail:    planString(table["attached_condition"]),
+	}
+	node.EstimatedRows = planNumber(table["rows_produced_per_join"])
+	if node.EstimatedRows == nil {
+		node.EstimatedRows = planNumber(table["rows_examined_per_scan"])
+	}
+	if cost, ok := table["cost_info"].(map[string]interface{}); ok {
+		node.Cost = planNumber(cost["prefix_cost"])
+	}
+	node.Children = mysqlPlanChildren(table)
+	return node
+}
+
+// postgresConditionKeys 作为算子附加信息显示的 PostgreSQL 计划属性
+var postgresConditionKeys = []string{"Hash Cond", "Merge Cond", "Join Filter", "Index Cond", "Recheck Cond", "Filter", "Sort Key", "Group Key"}
+
+// parsePostgresExplain 解析 EXPLAIN (FORMAT JSON) 的输出；ANALYZE 时实际行数和耗时为每次循环的平均值乘以循环次数
+func parsePostgresExplain(raw string, analyzed bool) (*QueryPlan, error) {
+	var doc []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	plan := &QueryPlan{Nodes: []*PlanNode{}, Analyzed: analyzed, Format: PlanFormatJSON, Raw: raw}
+	for _, item := range doc {
+		if item.Plan != nil {
+			plan.Nodes = append(plan.Nodes, postgresPlanNode(item.Plan))
+		}
+	}
+	return plan, nil
+}
+
+func postgresPlanNode(plan map[string]interface{}) *PlanNode {
+	node := &PlanNode{
+		Operation:     planString(plan["Node Type"]),
+		Object:        planString(plan["Relation Name"]),
+		Index:         planString(plan["Index Name"]),
+		EstimatedRows: planNumber(plan["Plan Rows"]),
+		Cost:          planNumber(plan["Total Cost"]),
+	}
+	if join := planString(plan["Join Type"]); join != "" && join != "Inner" {
+		node.Operation += " (" + join + ")"
+	}
+	var details []string
+	for _, key := range postgresConditionKeys {
+		if value := planString(plan[key]); value != "" {
+			details = append(details, key+": "+value)
+		}
+	}
+	node.Detail = strings.Join(details, "; ")
+	if rows := planNumber(plan["Actual Rows"]); rows != nil {
+		loops := 1.0
+		if value := planNumber(plan["Actual Loops"]); value != nil {
+			loops = *value
+		}
+		actual := *rows * loops
+		node.ActualRows = &actual
+		if ms := planNumber(plan["Actual Total Time"]); ms != nil {
+			total := *ms * loops
+			node.ActualTimeMs = &total
+		}
+	}
+	children, _ := plan["Plans"].([]interface{})
+	for _, child := range children {
+		if value, ok := child.(map[string]interface{}); ok {
+			node.Children = append(node.Children, postgresPlanNode(value))
+		}
+	}
+	return node
+}
+
+// xmlElement 通用的 XML 元素，用于遍历 SQL Server 的 Showplan XML
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Children []xmlElement `xml:",any"`
+}
+
+func (e *xmlElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseSQLServerShowplan 解析 SET SHOWPLAN_XML 的输出，每个 RelOp 为一个算子，每条语句的顶层 RelOp 为一个根节点
+func parseSQLServerShowplan(raw string) (*QueryPlan, error) {
+	var root xmlElement
+	if err := xml.Unmarshal([]byte(raw), &root); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	plan := &QueryPlan{Nodes: []*PlanNode{}, Format: PlanFormatXML, Raw: raw}
+	var walk func(e *xmlElement)
+	walk = func(e *xmlElement) {
+		if e.XMLName.Local == "RelOp" {
+			plan.Nodes = append(plan.Nodes, sqlServerRelOp(e))
+			return
+		}
+		for i := range e.Children {
+			walk(&e.Children[i])
+		}
+	}
+	walk(&root)
+	return plan, nil
+}
+
+func sqlServerRelOp(op *xmlElement) *PlanNode {
+	node := &PlanNode{
+		Operation:     op.attr("PhysicalOp"),
+		EstimatedRows: planNumber(op.attr("EstimateRows")),
+		Cost:          planNumber(op.attr("EstimatedTotalSubtreeCost")),
+	}
+	if logical := op.attr("LogicalOp"); logical != "" && logical != node.Operation {
+		node.Operation += " (" + logical + ")"
+	}
+	// 算子自身的对象和谓词在嵌套的 RelOp 之外
+	var visit func(e *xmlElement)
+	visit = func(e *xmlElement) {
+		for i := range e.Children {
+			child := &e.Children[i]
+			switch child.XMLName.Local {
+			case "RelOp":
+				node.Children = append(node.Children, sqlServerRelOp(child))
+			case "Object":
+				if node.Object == "" {
+					node.Object = strings.Trim(child.attr("Table"), "[]")
+					node.Index = strings.Trim(child.attr("Index"), "[]")
+				}
+			case "Predicate":
+				if node.Detail == "" && len(child.Children) > 0 {
+					node.Detail = child.Children[0].attr("ScalarString")
+				}
+			default:
+				visit(child)
+			}
+		}
+	}
+	visit(op)
+	return node
+}
+
+// oraclePlanRow plan_table 中的一行
+type oraclePlanRow struct {
+	ID               int
+	ParentID         *int
+	Operation        string
+	Options          string
+	ObjectName       string
+	Cardinality      *float64
+	Cost             *float64
+	AccessPredicates string
+	FilterPredicates string
+}
+
+// buildOraclePlan 由 plan_table 的行构建计划树，raw 为 DBMS_XPLAN.DISPLAY 的输出
+func buildOraclePlan(rows []oraclePlanRow, raw string) *QueryPlan {
+	planRows := make([]planRow, len(rows))
+	for i, row := range rows {
+		node := &PlanNode{
+			Operation:     strings.TrimSpace(row.Operation + " " + row.Options),
+			Object:        row.ObjectName,
+			EstimatedRows: row.Cardinality,
+			Cost:          row.Cost,
+		}
+		var details []string
+		if row.AccessPredicates != "" {
+			details = append(details, "access: "+row.AccessPredicates)
+		}
+		if row.FilterPredicates != "" {
+			details = append(details, "filter: "+row.FilterPredicates)
+		}
+		node.Detail = strings.Join(details, "; ")
+		parent := -1
+		if row.ParentID != nil {
+			parent = *row.ParentID
+		}
+		planRows[i] = planRow{id: row.ID, parent: parent, node: node}
+	}
+	return &QueryPlan{Nodes: buildPlanTree(planRows), Format: PlanFormatText, Raw: raw}
+}
+
+// sqlitePlanNode 解析 EXPLAIN QUERY PLAN 的 detail，SCAN / SEARCH 后为表名，如 "SEARCH users USING INDEX idx_email (email=?)"
+func sqlitePlanNode(detail string) *PlanNode {
+	fields := strings.Fields(detail)
+	if len(fields) >= 2 && (fields[0] == "SCAN" || fields[0] == "SEARCH") {
+		object := 1
+		// 3.36 之前的版本为 SCAN TABLE users
+		if fields[1] == "TABLE" && len(fields) >= 3 {
+			object = 2
+		}
+		node := &PlanNode{Operation: fields[0], Object: fields[object], Detail: strings.Join(fields[object+1:], " ")}
+		for i := object + 1; i+1 < len(fields); i++ {
+			if fields[i] == "INDEX" {
+				node.Index = fields[i+1]
+				break
+			}
+		}
+		return node
+	}
+	return &PlanNode{Operation: detail}
+}
+
+// parseIndentedPlan 解析以缩进表示层级的文本计划（ClickHouse 的 EXPLAIN），
+// 每行为 "算子 (说明)"；ReadFrom 类算子的说明为读取的表
+func parseIndentedPlan(lines []string) *QueryPlan {
+	type level struct {
+		indent int
+		node   *PlanNode
+	}
+	plan := &QueryPlan{Nodes: []*PlanNode{}, Format: PlanFormatText, Raw: strings.Join(lines, "\n")}
+	var stack []level
+	for _, line := range lines {
+		text := strings.TrimSpace(line)
+		if text == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		node := &PlanNode{Operation: text}
+		if i := strings.Index(text, " ("); i > 0 && strings.HasSuffix(text, ")") {
+			node.Operation = text[:i]
+			node.Detail = text[i+2 : len(text)-1]
+			if strings.HasPrefix(node.Operation, "ReadFrom") {
+				node.Object, node.Detail = node.Detail, ""
+			}
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			plan.Nodes = append(plan.Nodes, node)
+		} else {
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, node)
+		}
+		stack = append(stack, level{indent, node})
+	}
+	return plan
+}
+
+// parseMongoExplain 解析 explain 命令的输出（扩展 JSON）：有 executionStats 时使用实际执行的阶段，否则使用 winningPlan
+func parseMongoExplain(raw string) (*QueryPlan, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	plan := &QueryPlan{Nodes: []*PlanNode{}, Format: PlanFormatJSON, Raw: raw}
+	var stage map[string]interface{}
+	if stats, ok := doc["executionStats"].(map[string]interface{}); ok {
+		stage, _ = stats["executionStages"].(map[string]interface{})
+		plan.Analyzed = stage != nil
+	}
+	planner, _ := doc["queryPlanner"].(map[string]interface{})
+	if stage == nil && planner != nil {
+		stage, _ = planner["winningPlan"].(map[string]interface{})
+		// 使用基于槽的执行引擎时阶段树在 queryPlan 中
+		if inner, ok := stage["queryPlan"].(map[string]interface{}); ok {
+			stage = inner
+		}
+	}
+	if stage == nil {
+		return nil, fmt.Errorf("failed to parse plan: winning plan not found")
+	}
+	root := mongoPlanStage(stage)
+	if planner != nil && root.Object == "" {
+		root.Object = planString(planner["namespace"])
+	}
+	plan.Nodes = append(plan.Nodes, root)
+	return plan, nil
+}
+
+func mongoPlanStage(stage map[string]interface{}) *PlanNode {
+	node := &PlanNode{
+		Operation:    planString(stage["stage"]),
+		Index:        planString(stage["indexName"]),
+		ActualRows:   planNumber(stage["nReturned"]),
+		ActualTimeMs: planNumber(stage["executionTimeMillisEstimate"]),
+	}
+	if filter, ok := stage["filter"]; ok {
+		if data, err := json.Marshal(filter); err == nil {
+			node.Detail = string(data)
+		}
+	}
+	if input, ok := stage["inputStage"].(map[string]interface{}); ok {
+		node.Children = append(node.Children, mongoPlanStage(input))
+	}
+	inputs, _ := stage["inputStages"].([]interface{})
+	for _, input := range inputs {
+		if value, ok := input.(map[string]interface{}); ok {
+			node.Children = append(node.Children, mongoPlanStage(value))
+		}
+	}
+	return node
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"testing"
+)
+
+// planOutline 返回计划树的算子轮廓，如 "Nested Loop(Full Table Scan users,Index Lookup orders)"
+func planOutline(nodes []*PlanNode) string {
+	var s string
+	for i, node := range nodes {
+		if i > 0 {
+			s += ","
+		}
+		s += node.Operation
+		if node.Object != "" {
+			s += " " + node.Object
+		}
+		if len(node.Children) > 0 {
+			s += "(" + planOutline(node.Children) + ")"
+		}
+	}
+	return s
+}
+
+func planValue(f *float64) float64 {
+	if f == nil {
+		return -1
+	}
+	return *f
+}
+
+func TestParseMySQLExplain(t *testing.T) {
+	raw := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "12.50"},
+		"ordering_operation": {"using_filesort": true, "nested_loop": [
+			{"table": {"table_name": "u", "access_type": "ALL", "rows_examined_per_scan": 10, "rows_produced_per_join": 5,
+				"cost_info": {"prefix_cost": "2.00"}, "attached_condition": "(u.age > 18)"}},
+			{"table": {"table_name": "o", "access_type": "ref", "key": "idx_user", "rows_produced_per_join": 20,
+				"cost_info": {"prefix_cost": "12.50"}}}
+		]}}}`
+	plan, err := parseMySQLExplain(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := planOutline(plan.Nodes), "Query Block(Sort(Nested Loop(Full Table Scan u,Index Lookup o)))"; got != want {
+		t.Errorf("outline = %s, want %s", got, want)
+	}
+	root := plan.Nodes[0]
+	scan := root.Children[0].Children[0].Children[0]
+	if planValue(root.Cost) != 12.5 || planValue(scan.EstimatedRows) != 5 || scan.Detail != "(u.age > 18)" {
+		t.Errorf("unexpected values: cost=%v rows=%v detail=%q", planValue(root.Cost), planValue(scan.EstimatedRows), scan.Detail)
+	}
+	if lookup := root.Children[0].Children[0].Children[1]; lookup.Index != "idx_user" {
+		t.Errorf("index = %q", lookup.Index)
+	}
+}
+
+func TestParsePostgresExplain(t *testing.T) {
+	raw := `[{"Plan": {"Node Type": "Hash Join", "Join Type": "Left", "Total Cost": 35.5, "Plan Rows": 100,
+		"Actual Rows": 40, "Actual Loops": 1, "Actual Total Time": 1.5, "Hash Cond": "(o.user_id = u.id)",
+		"Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "orders", "Alias": "o", "Total Cost": 20, "Plan Rows": 100, "Actual Rows": 10, "Actual Loops": 4, "Actual Total Time": 0.25},
+			{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8, "Plan Rows": 1, "Filter": "(active)"}
+		]}, "Planning Time": 0.1, "Execution Time": 1.7}]`
+	plan, err := parsePostgresExplain(raw, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := planOutline(plan.Nodes), "Hash Join (Left)(Seq Scan orders,Index Scan users)"; got != want {
+		t.Errorf("outline = %s, want %s", got, want)
+	}
+	scan := plan.Nodes[0].Children[0]
+	// 实际行数和耗时乘以循环次数
+	if planValue(scan.ActualRows) != 40 || planValue(scan.ActualTimeMs) != 1 {
+		t.Errorf("actual rows = %v, time = %v", planValue(scan.ActualRows), planValue(scan.ActualTimeMs))
+	}
+	if detail := plan.Nodes[0].Detail; detail != "Hash Cond: (o.user_id = u.id)" {
+		t.Errorf("detail = %q", detail)
+	}
+	if index := plan.Nodes[0].Children[1]; index.Index != "users_pkey" || index.ActualRows != nil {
+		t.Errorf("index scan = %+v", index)
+	}
+}
+
+func TestParseSQLServerShowplan(t *testing.T) {
+	raw := `<ShowPlanXML xmlns="http://schemas.microsoft.com/sqlserver/2004/07/showplan"><BatchSequence><Batch><Statements>
+		<StmtSimple StatementText="SELECT ..."><QueryPlan>
+			<RelOp PhysicalOp="Nested Loops" LogicalOp="Inner Join" EstimateRows="3" EstimatedTotalSubtreeCost="0.0066">
+				<NestedLoops>
+					<RelOp PhysicalOp="Clustered Index Scan" LogicalOp="Clustered Index Scan" EstimateRows="3" EstimatedTotalSubtreeCost="0.0032">
+						<IndexScan><Object Database="[db]" Schema="[dbo]" Table="[users]" Index="[PK_users]"/>
+							<Predicate><ScalarOperator ScalarString="[db].[dbo].[users].[age]&gt;(18)"/></Predicate>
+						</IndexScan>
+					</RelOp>
+					<RelOp PhysicalOp="Index Seek" LogicalOp="Index Seek" EstimateRows="1" EstimatedTotalSubtreeCost="0.0031">
+						<IndexScan><Object Database="[db]" Schema="[dbo]" Table="[orders]" Index="[idx_user]"/></IndexScan>
+					</RelOp>
+				</NestedLoops>
+			</RelOp>
+		</QueryPlan></StmtSimple>
+	</Statements></Batch></BatchSequence></ShowPlanXML>`
+	plan, err := parseSQLServerShowplan(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := planOutline(plan.Nodes), "Nested Loops (Inner Join)(Clustered Index Scan users,Index Seek orders)"; got != want {
+		t.Errorf("outline = %s, want %s", got, want)
+	}
+	scan := plan.Nodes[0].Children[0]
+	if scan.Index != "PK_users" || scan.Detail != "[db].[dbo].[users].[age]>(18)" || planValue(scan.Cost) != 0.0032 {
+		t.Errorf("scan = %+v", scan)
+	}
+}
+
+func TestPlanTrees(t *testing.T) {
+	// SQLite 的 EXPLAIN QUERY PLAN：id、parent、detail
+	rows := []planRow{
+		{id: 2, parent: 0, node: sqlitePlanNode("SCAN TABLE orders")},
+		{id: 5, parent: 0, node: sqlitePlanNode("SEARCH users USING INDEX idx_email (email=?)")},
+		{id: 9, parent: 5, node: sqlitePlanNode("CORRELATED SCALAR SUBQUERY 1")},
+	}
+	nodes := buildPlanTree(rows)
+	if got, want := planOutline(nodes), "SCAN orders,SEARCH users(CORRELATED SCALAR SUBQUERY 1)"; got != want {
+		t.Errorf("sqlite outline = %s, want %s", got, want)
+	}
+	if nodes[1].Index != "idx_email" || nodes[1].Detail != "USING INDEX idx_email (email=?)" {
+		t.Errorf("sqlite search = %+v", nodes[1])
+	}
+
+	// ClickHouse 的 EXPLAIN
+	plan := parseIndentedPlan([]string{
+		"Expression ((Projection + Before ORDER BY))",
+		"  Limit (preliminary LIMIT)",
+		"    ReadFromMergeTree (default.events)",
+		"  Filter",
+	})
+	if got, want := planOutline(plan.Nodes), "Expression(Limit(ReadFromMergeTree default.events),Filter)"; got != want {
+		t.Errorf("clickhouse outline = %s, want %s", got, want)
+	}
+	if detail := plan.Nodes[0].Detail; detail != "(Projection + Before ORDER BY)" {
+		t.Errorf("clickhouse detail = %q", detail)
+	}
+}
+
+func TestParseMongoExplain(t *testing.T) {
+	raw := `{"queryPlanner": {"namespace": "shop.orders", "winningPlan": {"stage": "FETCH", "filter": {"status": {"$eq": "new"}},
+		"inputStage": {"stage": "IXSCAN", "indexName": "user_1"}}},
+		"executionStats": {"nReturned": 2, "executionStages": {"stage": "FETCH", "nReturned": 2, "executionTimeMillisEstimate": 1,
+			"inputStage": {"stage": "IXSCAN", "indexName": "user_1", "nReturned": 5}}}}`
+	plan, err := parseMongoExplain(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := planOutline(plan.Nodes), "FETCH shop.orders(IXSCAN)"; got != want {
+		t.Errorf("outline = %s, want %s", got, want)
+	}
+	if !plan.Analyzed || planValue(plan.Nodes[0].Children[0].ActualRows) != 5 || plan.Nodes[0].Children[0].Index != "user_1" {
+		t.Errorf("unexpected plan: analyzed=%v %+v", plan.Analyzed, plan.Nodes[0].Children[0])
+	}
+}
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// ExportChunkSize 驱动不支持流式读取表数据时，每次分页读取的行数
+const ExportChunkSize = 1000
+
+// TableStreamingDatabase 支持流式读取表数据的数据库接口扩展
+// 整张表（或满足过滤条件的行）通过一次查询逐行读取，内存占用与表的大小无关，用于导出
+type TableStreamingDatabase interface {
+	// StreamTableRows 按过滤条件和排序条件读取表中的行，sorts 为空时使用数据库的默认顺序
+	StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error)
+}
+
+// StreamTableRows 流式读取表中满足过滤条件的行
+// 驱动实现了 TableStreamingDatabase 时直接使用，否则按 ExportChunkSize 分页读取（有排序条件时使用 GetTableDataSorted）
+// columns 为分页读取时的列顺序，第一页中出现但 columns 中没有的字段按字母序追加到末尾
+func StreamTableRows(ctx context.Context, db Database, tableName string, columns []string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	if tdb, ok := db.(TableStreamingDatabase); ok {
+		return tdb.StreamTableRows(ctx, tableName, filters, sorts)
+	}
+
+	it := &pagedRowIterator{
+		ctx:       ctx,
+		db:        db,
+		tableName: tableName,
+		filters:   filters,
+		sorts:     sorts,
+		index:     -1,
+	}
+	if len(sorts) > 0 {
+		sdb, ok := db.(SortableDatabase)
+		if !ok {
+			return nil, fmt.Errorf("%s does not support sorting", db.GetDisplayName())
+		}
+		it.sortable = sdb
+	}
+	if err := it.fetch(); err != nil {
+		return nil, err
+	}
+
+	it.columns = mapColumns(columns, it.rows)
+	return it, nil
+}
+
+// NewMapRowIterator 将已加载的行包装为 RowIterator，列顺序与 columns 一致
+// 行中出现但 columns 中没有的字段按字母序追加到末尾
+func NewMapRowIterator(columns []string, rows []map[string]interface{}) RowIterator {
+	return &pagedRowIterator{columns: mapColumns(columns, rows), rows: rows, index: -1, done: true}
+}
+
+// mapColumns 返回 columns 加上 rows 中额外出现的字段（按字母序）
+func mapColumns(columns []string, rows []map[string]interface{}) []string {
+	seen := make(map[string]bool, len(columns))
+	result := append([]string{}, columns...)
+	for _, name := range columns {
+		seen[name] = true
+	}
+	var extra []string
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				extra = append(extra, name)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(result, extra...)
+}
+
+// pagedRowIterator 按页读取表数据的行迭代器，用于没有实现 TableStreamingDatabase 的驱动
+// 只保留当前页的数据；某一页不足 ExportChunkSize 行或已读取 total 行时结束
+type pagedRowIterator struct {
+	ctx       context.Context
+	db        Database
+	sortable  SortableDatabase
+	tableName string
+	filters   *FilterGroup
+	sorts     []SortSpec
+
+	columns []string
+	rows    []map[string]interface{}
+	index   int
+	page    int
+	read    int64
+	done    bool
+	err     error
+}
+
+// fetch 读取下一页
+func (it *pagedRowIterator) fetch() error {
+	it.page++
+	var rows []map[string]interface{}
+	var total int64
+	var err error
+	if it.sortable != nil {
+		rows, total, err = it.sortable.GetTableDataSorted(it.ctx, it.tableName, it.page, ExportChunkSize, it.filters, it.sorts)
+	} else {
+		rows, total, err = AsContextDatabase(it.db).GetTableDataContext(it.ctx, it.tableName, it.page, ExportChunkSize, it.filters)
+	}
+	if err != nil {
+		return err
+	}
+
+	it.rows = rows
+	it.index = -1
+	it.read += int64(len(rows))
+	if len(rows) < ExportChunkSize || (total >= 0 && it.read >= total) {
+		it.done = true
+	}
+	return nil
+}
+
+func (it *pagedRowIterator) Columns() []string {
+	return it.columns
+}
+
+func (it *pagedRowIterator) ColumnTypes() []ResultColumn {
+	columnTypes := make([]ResultColumn, len(it.columns))
+	for i, name := range it.columns {
+		columnTypes[i].Name = name
+	}
+	return columnTypes
+}
+
+func (it *pagedRowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 >= len(it.rows) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.rows) == 0 {
+			return false
+		}
+	}
+	it.index++
+	return true
+}
+
+func (it *pagedRowIterator) Values() []interface{} {
+	row := it.rows[it.index]
+	values := make([]interface{}, len(it.columns))
+	for i, name := range it.columns {
+		values[i] = row[name]
+	}
+	return values
+}
+
+func (it *pagedRowIterator) Err() error {
+	return it.err
+}
+
+func (it *pagedRowIterator) Close() error {
+	it.rows = nil
+	it.done = true
+	return nil
+}
+
+// streamSQLTableRows 各 SQL 驱动共享的流式读取实现，tableRef 为已按方言引用的表名
+// 结果由 database/sql 按需从连接中读取，不会一次性加载到内存
+func streamSQLTableRows(ctx context.Context, db *sql.DB, dbType, tableRef, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if err := ValidateSorts(sorts); err != nil {
+		return nil, err
+	}
+
+	whereClause, whereArgs, err := BuildWhereClause(dbType, tableName, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build where clause: %w", err)
+	}
+	orderBy, err := BuildOrderByClause(dbType, sorts, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order by clause: %w", err)
+	}
+
+	query := "SELECT * FROM " + tableRef
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	}
+
+	rows, err := db.QueryContext(ctx, query, whereArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
@@ -0,0 +1,348 @@
+package database
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 导出格式
+const (
+	ExportFormatCSV      = "csv"
+	ExportFormatTSV      = "tsv"
+	ExportFormatJSON     = "json"     // 对象数组
+	ExportFormatNDJSON   = "ndjson"   // 每行一个 JSON 对象
+	ExportFormatMarkdown = "markdown" // Markdown 表格
+	ExportFormatSQL      = "sql"      // INSERT 语句
+)
+
+// ExportWriter 导出格式写入器
+// 先调用一次 WriteHeader，再逐行调用 WriteRow，最后调用 Close 写入结尾并刷新缓冲（不会关闭底层的 io.Writer）
+type ExportWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	TableName string // INSERT 语句的目标表
+	Dialect   string // INSERT 语句的 SQL 方言，为空时使用 mysql
+}
+
+// NewExportWriter 创建指定格式的写入器
+func NewExportWriter(format string, w io.Writer, options ExportOptions) (ExportWriter, error) {
+	buf := bufio.NewWriter(w)
+	switch format {
+	case ExportFormatCSV, ExportFormatTSV:
+		cw := csv.NewWriter(buf)
+		if format == ExportFormatTSV {
+			cw.Comma = '\t'
+		}
+		return &csvExportWriter{buf: buf, csv: cw}, nil
+	case ExportFormatJSON:
+		return &jsonExportWriter{buf: buf, array: true}, nil
+	case ExportFormatNDJSON:
+		return &jsonExportWriter{buf: buf}, nil
+	case ExportFormatMarkdown:
+		return &markdownExportWriter{buf: buf}, nil
+	case ExportFormatSQL:
+		if err := validateIdentifier(options.TableName); err != nil {
+			return nil, err
+		}
+		return &sqlExportWriter{buf: buf, dialect: NormalizeSQLDialect(options.Dialect), tableName: options.TableName}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// NormalizeSQLDialect 返回生成 SQL 使用的方言，不是内置 SQL 驱动的类型（包括 MySQL 兼容的国产数据库）使用 mysql
+func NormalizeSQLDialect(dbType string) string {
+	switch dbType {
+	case "mysql", "postgresql", "sqlite", "sqlserver", "oracle", "h2", "clickhouse":
+		return dbType
+	default:
+		return "mysql"
+	}
+}
+
+// ExportText 将值转换为文本格式导出时的字符串，NULL 为空字符串
+// 时间使用 "2006-01-02 15:04:05"（有小数秒时保留），嵌套对象和数组序列化为 JSON
+func ExportText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.RawMessage:
+		return string(v)
+	case BinaryValue:
+		return v.String()
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999")
+	case fmt.Stringer:
+		return v.String()
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if b, err := json.Marshal(value); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// csvExportWriter CSV/TSV 写入器，第一行为列名
+type csvExportWriter struct {
+	buf *bufio.Writer
+	csv *csv.Writer
+}
+
+func (e *csvExportWriter) WriteHeader(columns []string) error {
+	return e.csv.Write(columns)
+}
+
+func (e *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = ExportText(v)
+	}
+	return e.csv.Write(record)
+}
+
+func (e *csvExportWriter) Close() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+// jsonExportWriter JSON/NDJSON 写入器，每行输出为按列顺序排列的对象
+type jsonExportWriter struct {
+	buf     *bufio.Writer
+	array   bool
+	columns []string
+	rows    int
+}
+
+func (e *jsonExportWriter) WriteHeader(columns []string) error {
+	e.columns = columns
+	if e.array {
+		_, err := e.buf.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+func (e *jsonExportWriter) WriteRow(values []interface{}) error {
+	if e.array {
+		if e.rows > 0 {
+			e.buf.WriteString(",")
+		}
+		e.buf.WriteString("\n  ")
+	}
+	e.buf.WriteString("{")
+	for i, v := range values {
+		if i > 0 {
+			e.buf.WriteString(",")
+		}
+		name, _ := json.Marshal(e.columns[i])
+		e.buf.Write(name)
+		e.buf.WriteString(":")
+		e.buf.Write(exportJSONValue(v))
+	}
+	e.buf.WriteString("}")
+	if !e.array {
+		e.buf.WriteString("\n")
+	}
+	e.rows++
+	return nil
+}
+
+func (e *jsonExportWriter) Close() error {
+	if e.array {
+		if e.rows > 0 {
+			e.buf.WriteString("\n")
+		}
+		e.buf.WriteString("]\n")
+	}
+	return e.buf.Flush()
+}
+
+// exportJSONValue 序列化单个值，[]byte 作为字符串输出，无法序列化的值（如 NaN）输出为字符串
+func exportJSONValue(value interface{}) []byte {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return data
+	}
+	data, _ := json.Marshal(ExportText(value))
+	return data
+}
+
+// markdownExportWriter Markdown 表格写入器，NULL 显示为 NULL
+type markdownExportWriter struct {
+	buf *bufio.Writer
+}
+
+func (e *markdownExportWriter) WriteHeader(columns []string) error {
+	cells := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, name := range columns {
+		cells[i] = markdownCell(name)
+		separators[i] = "---"
+	}
+	e.writeLine(cells)
+	e.writeLine(separators)
+	return nil
+}
+
+func (e *markdownExportWriter) WriteRow(values []interface{}) error {
+	cells := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			cells[i] = "NULL"
+		} else {
+			cells[i] = markdownCell(ExportText(v))
+		}
+	}
+	e.writeLine(cells)
+	return nil
+}
+
+func (e *markdownExportWriter) writeLine(cells []string) {
+	e.buf.WriteString("| ")
+	e.buf.WriteString(strings.Join(cells, " | "))
+	e.buf.WriteString(" |\n")
+}
+
+func (e *markdownExportWriter) Close() error {
+	return e.buf.Flush()
+}
+
+// markdownCell 转义单元格中的竖线，换行替换为 <br>
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// sqlExportWriter INSERT 语句写入器，每行一条语句
+type sqlExportWriter struct {
+	buf       *bufio.Writer
+	dialect   string
+	tableName string
+	prefix    string
+}
+
+func (e *sqlExportWriter) WriteHeader(columns []string) error {
+	quote := getQuoteFunc(e.dialect)
+	quoted := make([]string, len(columns))
+	for i, name := range columns {
+		if err := validateIdentifier(name); err != nil {
+			return err
+		}
+		quoted[i] = quote(name)
+	}
+	e.prefix = fmt.Sprintf("INSERT INTO %s (%s) VALUES (", sqlTableRef(e.dialect, e.tableName), strings.Join(quoted, ", "))
+	return nil
+}
+
+func (e *sqlExportWriter) WriteRow(values []interface{}) error {
+	e.buf.WriteString(e.prefix)
+	for i, v := range values {
+		if i > 0 {
+			e.buf.WriteString(", ")
+		}
+		e.buf.WriteString(FormatSQLLiteral(e.dialect, v))
+	}
+	_, err := e.buf.WriteString(");\n")
+	return err
+}
+
+func (e *sqlExportWriter) Close() error {
+	return e.buf.Flush()
+}
+
+// FormatSQLLiteral 将值格式化为指定方言的 SQL 字面量
+// 字符串中的单引号加倍（MySQL 和 ClickHouse 同时转义反斜杠），二进制值使用各方言的十六进制写法，
+// SQL Server 和 Oracle 的布尔值写为 1/0，Oracle 的时间写为 TIMESTAMP 字面量
+func FormatSQLLiteral(dialect string, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if dialect == "sqlserver" || dialect == "oracle" {
+			if v {
+				return "1"
+			}
+			return "0"
+		}
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return formatSQLFloat(dialect, float64(v), 32)
+	case float64:
+		return formatSQLFloat(dialect, v, 64)
+	case json.Number:
+		return v.String()
+	case time.Time:
+		text := v.Format("2006-01-02 15:04:05.999999999")
+		if dialect == "oracle" {
+			return "TIMESTAMP '" + text + "'"
+		}
+		return "'" + text + "'"
+	case BinaryValue:
+		return sqlBinaryLiteral(dialect, v.Data)
+	case []byte:
+		return sqlBinaryLiteral(dialect, v)
+	}
+	return sqlStringLiteral(dialect, ExportText(value))
+}
+
+// formatSQLFloat 格式化浮点数，NaN 和无穷大写为字符串
+func formatSQLFloat(dialect string, f float64, bitSize int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return sqlStringLiteral(dialect, strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
+
+func sqlStringLiteral(dialect, s string) string {
+	if dialect == "mysql" || dialect == "clickhouse" {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sqlBinaryLiteral(dialect string, data []byte) string {
+	h := hex.EncodeToString(data)
+	switch dialect {
+	case "postgresql":
+		return "decode('" + h + "', 'hex')"
+	case "sqlserver":
+		return "0x" + h
+	case "oracle":
+		return "HEXTORAW('" + h + "')"
+	case "clickhouse":
+		return "unhex('" + h + "')"
+	default:
+		return "X'" + h + "'"
+	}
+}
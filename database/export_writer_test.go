@@ -0,0 +1,109 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExportWriter(t *testing.T) {
+	columns := []string{"id", "name", "data"}
+	rows := [][]interface{}{
+		{int64(1), `a "b", c`, nil},
+		{int64(2), "x|y\nz", json.RawMessage(`{"k":1}`)},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{ExportFormatCSV, "id,name,data\n1,\"a \"\"b\"\", c\",\n2,\"x|y\nz\",\"{\"\"k\"\":1}\"\n"},
+		{ExportFormatTSV, "id\tname\tdata\n1\t\"a \"\"b\"\", c\"\t\n2\t\"x|y\nz\"\t\"{\"\"k\"\":1}\"\n"},
+		{ExportFormatJSON, "[\n  {\"id\":1,\"name\":\"a \\\"b\\\", c\",\"data\":null},\n  {\"id\":2,\"name\":\"x|y\\nz\",\"data\":{\"k\":1}}\n]\n"},
+		{ExportFormatNDJSON, "{\"id\":1,\"name\":\"a \\\"b\\\", c\",\"data\":null}\n{\"id\":2,\"name\":\"x|y\\nz\",\"data\":{\"k\":1}}\n"},
+		{ExportFormatMarkdown, "| id | name | data |\n| --- | --- | --- |\n| 1 | a \"b\", c | NULL |\n| 2 | x\\|y<br>z | {\"k\":1} |\n"},
+		{ExportFormatSQL, "INSERT INTO `t` (`id`, `name`, `data`) VALUES (1, 'a \"b\", c', NULL);\nINSERT INTO `t` (`id`, `name`, `data`) VALUES (2, 'x|y\nz', '{\"k\":1}');\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewExportWriter(tt.format, &buf, ExportOptions{TableName: "t"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := writer.WriteHeader(columns); err != nil {
+				t.Fatalf("WriteHeader: %v", err)
+			}
+			for _, row := range rows {
+				if err := writer.WriteRow(row); err != nil {
+					t.Fatalf("WriteRow: %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("output = %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	if _, err := NewExportWriter("xml", &bytes.Buffer{}, ExportOptions{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestFormatSQLLiteral(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dialect  string
+		value    interface{}
+		expected string
+	}{
+		{"MySQL 转义反斜杠", "mysql", `it's C:\tmp`, `'it''s C:\\tmp'`},
+		{"PostgreSQL 不转义反斜杠", "postgresql", `C:\tmp`, `'C:\tmp'`},
+		{"SQL Server 布尔", "sqlserver", true, "1"},
+		{"PostgreSQL 布尔", "postgresql", false, "FALSE"},
+		{"Oracle 时间", "oracle", ts, "TIMESTAMP '2024-01-02 03:04:05'"},
+		{"MySQL 时间", "mysql", ts, "'2024-01-02 03:04:05'"},
+		{"MySQL 二进制", "mysql", BinaryValue{Data: []byte{0xca, 0xfe}}, "X'cafe'"},
+		{"PostgreSQL 二进制", "postgresql", []byte{0x01}, "decode('01', 'hex')"},
+		{"SQL Server 二进制", "sqlserver", []byte{0x01}, "0x01"},
+		{"浮点数", "sqlite", 1.5, "1.5"},
+		{"NULL", "oracle", nil, "NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatSQLLiteral(tt.dialect, tt.value); got != tt.expected {
+				t.Errorf("FormatSQLLiteral(%q, %v) = %s, want %s", tt.dialect, tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMapRowIterator(t *testing.T) {
+	iter := NewMapRowIterator([]string{"id", "name"}, []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": 2, "zeta": true, "extra": "x"},
+	})
+	defer iter.Close()
+
+	if got, want := iter.Columns(), []string{"id", "name", "extra", "zeta"}; len(got) != len(want) || got[2] != want[2] || got[3] != want[3] {
+		t.Fatalf("columns = %v, want %v", got, want)
+	}
+	count := 0
+	for iter.Next() {
+		values := iter.Values()
+		if count == 1 && (values[1] != nil || values[2] != "x") {
+			t.Errorf("values = %v", values)
+		}
+		count++
+	}
+	if count != 2 || iter.Err() != nil {
+		t.Errorf("count = %d, err = %v", count, iter.Err())
+	}
+}
@@ -2,121 +2,369 @@ package database
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// likeEscapeChar LIKE 模式中使用的转义字符（避免不同数据库对反斜杠的处理差异）
+const likeEscapeChar = "!"
+
+// IsEmpty 判断过滤条件组是否为空（包括嵌套的子条件组）
+func (g *FilterGroup) IsEmpty() bool {
+	if g == nil {
+		return true
+	}
+	for _, condition := range g.Conditions {
+		if condition.Field != "" {
+			return false
+		}
+	}
+	for i := range g.Groups {
+		if !g.Groups[i].IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeLogic 返回条件组的逻辑关系（默认为 AND）
+func normalizeLogic(logic string) string {
+	logic = strings.ToUpper(logic)
+	if logic != "AND" && logic != "OR" {
+		return "AND"
+	}
+	return logic
+}
+
 // BuildWhereClause 构建 WHERE 子句
 // dbType: 数据库类型（mysql, postgresql, sqlite, sqlserver, oracle等）
 // tableName: 表名（用于标识符引用）
-// filters: 过滤条件组
+// filters: 过滤条件组（支持嵌套）
 // 返回: WHERE 子句字符串（不包含 WHERE 关键字）和参数列表
 func BuildWhereClause(dbType string, tableName string, filters *FilterGroup) (string, []interface{}, error) {
-	if filters == nil || len(filters.Conditions) == 0 {
+	if filters.IsEmpty() {
 		return "", nil, nil
 	}
 
-	var conditions []string
-	var args []interface{}
-
-	// 确定逻辑关系（默认为 AND）
-	logic := strings.ToUpper(filters.Logic)
-	if logic != "AND" && logic != "OR" {
-		logic = "AND"
+	builder := &whereBuilder{
+		dbType:          dbType,
+		quoteFunc:       getQuoteFunc(dbType),
+		placeholderFunc: getPlaceholderFunc(dbType),
+		argIndex:        1, // PostgreSQL 从 $1 开始
+		now:             time.Now(),
 	}
+	whereClause, err := builder.buildGroup(filters)
+	if err != nil {
+		return "", nil, err
+	}
+	if whereClause == "" {
+		return "", nil, nil
+	}
+	return whereClause, builder.args, nil
+}
 
-	// 获取标识符引用方式和占位符函数
-	quoteFunc := getQuoteFunc(dbType)
-	placeholderFunc := getPlaceholderFunc(dbType)
+// whereBuilder 递归构建 WHERE 子句，保证占位符序号与参数顺序一致
+type whereBuilder struct {
+	dbType          string
+	quoteFunc       func(string) string
+	placeholderFunc func(int) string
+	argIndex        int
+	args            []interface{}
+	now             time.Time
+}
 
-	argIndex := 1 // PostgreSQL 从 $1 开始
+// bind 添加一个参数并返回对应的占位符
+func (b *whereBuilder) bind(value interface{}) string {
+	placeholder := b.placeholderFunc(b.argIndex)
+	b.args = append(b.args, value)
+	b.argIndex++
+	return placeholder
+}
+
+// buildGroup 构建条件组，子条件组使用括号包裹
+func (b *whereBuilder) buildGroup(group *FilterGroup) (string, error) {
+	logic := normalizeLogic(group.Logic)
 
-	for _, condition := range filters.Conditions {
+	var conditions []string
+	for _, condition := range group.Conditions {
 		if condition.Field == "" {
 			continue
 		}
+		clause, err := b.buildCondition(condition)
+		if err != nil {
+			return "", err
+		}
+		if clause != "" {
+			conditions = append(conditions, clause)
+		}
+	}
 
-		// 引用字段名
-		quotedField := quoteFunc(condition.Field)
-
-		operator := strings.ToUpper(strings.TrimSpace(condition.Operator))
-		
-		switch operator {
-		case "=", "!=", "<", ">", "<=", ">=":
-			// 简单的比较操作符
-			if condition.Value != "" {
-				placeholder := placeholderFunc(argIndex)
-				conditions = append(conditions, fmt.Sprintf("%s %s %s", quotedField, operator, placeholder))
-				args = append(args, condition.Value)
-				argIndex++
-			}
-		
-		case "LIKE", "NOT LIKE":
-			// LIKE 操作符
-			if condition.Value != "" {
-				placeholder := placeholderFunc(argIndex)
-				conditions = append(conditions, fmt.Sprintf("%s %s %s", quotedField, operator, placeholder))
-				args = append(args, condition.Value)
-				argIndex++
-			}
-		
-		case "IN", "NOT IN":
-			// IN 操作符
-			values := condition.Values
-			if len(values) == 0 && condition.Value != "" {
-				// 如果提供了 Value 字符串，按逗号分割
-				values = strings.Split(condition.Value, ",")
-				// 去除每个值的空白
-				for i := range values {
-					values[i] = strings.TrimSpace(values[i])
-				}
-			}
-			
-			if len(values) > 0 {
-				placeholders := make([]string, len(values))
-				for i := range placeholders {
-					placeholders[i] = placeholderFunc(argIndex)
-					args = append(args, values[i])
-					argIndex++
-				}
-				conditions = append(conditions, fmt.Sprintf("%s %s (%s)", quotedField, operator, strings.Join(placeholders, ", ")))
-			}
-		
-		case "IS NULL", "IS NOT NULL":
-			// NULL 检查（不需要参数）
-			conditions = append(conditions, fmt.Sprintf("%s %s", quotedField, operator))
-		
-		default:
-			// 默认使用 = 操作符
-			if condition.Value != "" {
-				placeholder := placeholderFunc(argIndex)
-				conditions = append(conditions, fmt.Sprintf("%s = %s", quotedField, placeholder))
-				args = append(args, condition.Value)
-				argIndex++
-			}
-		}
-	}
-
-	if len(conditions) == 0 {
-		return "", nil, nil
+	for i := range group.Groups {
+		clause, err := b.buildGroup(&group.Groups[i])
+		if err != nil {
+			return "", err
+		}
+		if clause != "" {
+			conditions = append(conditions, "("+clause+")")
+		}
 	}
 
-	whereClause := strings.Join(conditions, " "+logic+" ")
-	return whereClause, args, nil
+	return strings.Join(conditions, " "+logic+" "), nil
 }
 
-// getPlaceholderFunc 根据数据库类型返回占位符函数
-func getPlaceholderFunc(dbType string) func(int) string {
-	switch dbType {
+// buildCondition 构建单个条件，值为空等无法生成条件的情况返回空字符串
+func (b *whereBuilder) buildCondition(condition FilterCondition) (string, error) {
+	// 引用字段名
+	quotedField := b.quoteFunc(condition.Field)
+
+	operator := strings.ToUpper(strings.TrimSpace(condition.Operator))
+
+	switch operator {
+	case "=", "!=", "<", ">", "<=", ">=", "LIKE", "NOT LIKE":
+		// 简单的比较操作符和 LIKE
+		if condition.Value == "" {
+			return "", nil
+		}
+		return fmt.Sprintf("%s %s %s", quotedField, operator, b.bind(condition.Value)), nil
+
+	case "IN", "NOT IN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return "", nil
+		}
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = b.bind(values[i])
+		}
+		return fmt.Sprintf("%s %s (%s)", quotedField, operator, strings.Join(placeholders, ", ")), nil
+
+	case "IS NULL", "IS NOT NULL":
+		// NULL 检查（不需要参数）
+		return fmt.Sprintf("%s %s", quotedField, operator), nil
+
+	case "BETWEEN", "NOT BETWEEN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return "", nil
+		}
+		if len(values) != 2 {
+			return "", fmt.Errorf("%s requires two values for field %s", operator, condition.Field)
+		}
+		return fmt.Sprintf("%s %s %s AND %s", quotedField, operator, b.bind(values[0]), b.bind(values[1])), nil
+
+	case "CONTAINS", "NOT CONTAINS", "STARTS WITH", "ENDS WITH":
+		if condition.Value == "" {
+			return "", nil
+		}
+		return b.buildCaseInsensitiveMatch(quotedField, operator, condition.Value), nil
+
+	case "REGEX", "NOT REGEX":
+		if condition.Value == "" {
+			return "", nil
+		}
+		return b.buildRegexMatch(quotedField, operator, condition.Value)
+
+	case "LAST", "NEXT", "TODAY", "YESTERDAY", "THIS WEEK", "THIS MONTH", "THIS YEAR":
+		start, end, err := RelativeDateRange(operator, condition.Value, b.now)
+		if err != nil {
+			return "", fmt.Errorf("invalid date range for field %s: %w", condition.Field, err)
+		}
+		return fmt.Sprintf("(%s >= %s AND %s < %s)", quotedField, b.bind(b.dateValue(start)), quotedField, b.bind(b.dateValue(end))), nil
+
+	default:
+		// 默认使用 = 操作符
+		if condition.Value == "" {
+			return "", nil
+		}
+		return fmt.Sprintf("%s = %s", quotedField, b.bind(condition.Value)), nil
+	}
+}
+
+// buildCaseInsensitiveMatch 构建不区分大小写的包含/前缀/后缀匹配
+// 值中的 % 和 _ 会被转义，按字面匹配
+func (b *whereBuilder) buildCaseInsensitiveMatch(quotedField, operator, value string) string {
+	not := ""
+	if operator == "NOT CONTAINS" {
+		not = "NOT "
+	}
+
+	switch b.dbType {
+	case "clickhouse":
+		// ClickHouse 的 LIKE 不支持 ESCAPE 子句，固定使用反斜杠转义
+		pattern := likePattern(operator, escapeLike(value, `\`, false))
+		return fmt.Sprintf("toString(%s) %sILIKE %s", quotedField, not, b.bind(pattern))
 	case "postgresql":
-		return func(index int) string {
-			return fmt.Sprintf("$%d", index)
+		pattern := likePattern(operator, escapeLike(value, likeEscapeChar, false))
+		return fmt.Sprintf("CAST(%s AS TEXT) %sILIKE %s ESCAPE '%s'", quotedField, not, b.bind(pattern), likeEscapeChar)
+	default:
+		// SQL Server 的 LIKE 还把 [ 视为通配符
+		pattern := likePattern(operator, escapeLike(strings.ToLower(value), likeEscapeChar, b.dbType == "sqlserver"))
+		return fmt.Sprintf("LOWER(%s) %sLIKE %s ESCAPE '%s'", quotedField, not, b.bind(pattern), likeEscapeChar)
+	}
+}
+
+// buildRegexMatch 构建正则匹配条件，不支持正则的数据库返回错误
+func (b *whereBuilder) buildRegexMatch(quotedField, operator, pattern string) (string, error) {
+	negate := operator == "NOT REGEX"
+	switch b.dbType {
+	case "mysql":
+		if negate {
+			return fmt.Sprintf("%s NOT REGEXP %s", quotedField, b.bind(pattern)), nil
+		}
+		return fmt.Sprintf("%s REGEXP %s", quotedField, b.bind(pattern)), nil
+	case "postgresql":
+		if negate {
+			return fmt.Sprintf("CAST(%s AS TEXT) !~ %s", quotedField, b.bind(pattern)), nil
+		}
+		return fmt.Sprintf("CAST(%s AS TEXT) ~ %s", quotedField, b.bind(pattern)), nil
+	case "oracle", "h2":
+		if negate {
+			return fmt.Sprintf("NOT REGEXP_LIKE(%s, %s)", quotedField, b.bind(pattern)), nil
+		}
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s)", quotedField, b.bind(pattern)), nil
+	case "clickhouse":
+		if negate {
+			return fmt.Sprintf("NOT match(toString(%s), %s)", quotedField, b.bind(pattern)), nil
 		}
+		return fmt.Sprintf("match(toString(%s), %s)", quotedField, b.bind(pattern)), nil
 	default:
-		// MySQL, SQLite, SQL Server, Oracle 等都使用 ?
-		return func(index int) string {
-			return "?"
+		return "", fmt.Errorf("regex match is not supported by %s", b.dbType)
+	}
+}
+
+// dateValue 转换日期参数
+// MySQL、SQLite、H2、ClickHouse 使用字符串格式，其他数据库直接使用 time.Time
+func (b *whereBuilder) dateValue(t time.Time) interface{} {
+	switch b.dbType {
+	case "mysql", "sqlite", "h2", "clickhouse":
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return t
+}
+
+// conditionValues 返回条件的多个值（Values 优先，否则按逗号分割 Value）
+func conditionValues(condition FilterCondition) []string {
+	if len(condition.Values) > 0 {
+		return condition.Values
+	}
+	if condition.Value == "" {
+		return nil
+	}
+	values := strings.Split(condition.Value, ",")
+	// 去除每个值的空白
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(value, escapeChar string, escapeBracket bool) string {
+	replacements := []string{escapeChar, escapeChar + escapeChar, "%", escapeChar + "%", "_", escapeChar + "_"}
+	if escapeBracket {
+		replacements = append(replacements, "[", escapeChar+"[")
+	}
+	return strings.NewReplacer(replacements...).Replace(value)
+}
+
+// likePattern 根据操作符为已转义的值添加通配符
+func likePattern(operator, escaped string) string {
+	switch operator {
+	case "STARTS WITH":
+		return escaped + "%"
+	case "ENDS WITH":
+		return "%" + escaped
+	default:
+		return "%" + escaped + "%"
+	}
+}
+
+// RelativeDateRange 计算相对日期操作符对应的时间范围 [start, end)
+// LAST/NEXT 的值格式为 "<数量> <单位>"，如 "7 days"、"12 hours"，省略单位时按天计算
+// TODAY、YESTERDAY、THIS WEEK（周一开始）、THIS MONTH、THIS YEAR 按 now 所在时区的自然日期计算
+func RelativeDateRange(operator, value string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.ToUpper(strings.TrimSpace(operator)) {
+	case "LAST", "NEXT":
+		amount, unit, err := parseRelativeDuration(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
 		}
+		if strings.EqualFold(operator, "LAST") {
+			return addDuration(now, -amount, unit), now, nil
+		}
+		return now, addDuration(now, amount, unit), nil
+	case "TODAY":
+		return today, today.AddDate(0, 0, 1), nil
+	case "YESTERDAY":
+		return today.AddDate(0, 0, -1), today, nil
+	case "THIS WEEK":
+		offset := (int(today.Weekday()) + 6) % 7
+		start := today.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), nil
+	case "THIS MONTH":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	case "THIS YEAR":
+		start := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(1, 0, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unsupported date operator: %s", operator)
+}
+
+// parseRelativeDuration 解析 "<数量> <单位>" 格式的相对时间
+func parseRelativeDuration(value string) (int, string, error) {
+	fields := strings.Fields(strings.ToLower(value))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, "", fmt.Errorf("invalid relative duration: %q", value)
+	}
+	amount, err := strconv.Atoi(fields[0])
+	if err != nil || amount <= 0 {
+		return 0, "", fmt.Errorf("invalid relative duration: %q", value)
+	}
+	unit := "day"
+	if len(fields) == 2 {
+		unit = strings.TrimSuffix(fields[1], "s")
 	}
+	switch unit {
+	case "minute", "hour", "day", "week", "month", "year":
+		return amount, unit, nil
+	}
+	return 0, "", fmt.Errorf("invalid relative duration unit: %q", value)
+}
+
+// addDuration 按单位增加时间（月和年按日历计算）
+func addDuration(t time.Time, amount int, unit string) time.Time {
+	switch unit {
+	case "minute":
+		return t.Add(time.Duration(amount) * time.Minute)
+	case "hour":
+		return t.Add(time.Duration(amount) * time.Hour)
+	case "week":
+		return t.AddDate(0, 0, 7*amount)
+	case "month":
+		return t.AddDate(0, amount, 0)
+	case "year":
+		return t.AddDate(amount, 0, 0)
+	default:
+		return t.AddDate(0, 0, amount)
+	}
+}
+
+// getPlaceholderFunc 根据数据库类型返回占位符函数
+// PostgreSQL 使用 $N，SQL Server 使用 @pN，Oracle 使用 :N，其他使用 ?（见 bindPlaceholder）
+func getPlaceholderFunc(dbType string) func(int) string {
+	return func(index int) string {
+		return bindPlaceholder(dbType, index)
+	}
+}
+
+// buildIDCursorCondition 构建按主键分页的游标条件，column 为引用后的主键列，op 为 > 或 <
+// 游标值的参数排在过滤条件的 filterArgs 个参数之后
+func buildIDCursorCondition(dbType, column, op string, filterArgs int) string {
+	return fmt.Sprintf("%s %s %s", column, op, getPlaceholderFunc(dbType)(filterArgs+1))
 }
 
 // getQuoteFunc 根据数据库类型返回字段引用函数
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildWhereClause(t *testing.T) {
+	tests := []struct {
+		name         string
+		dbType       string
+		filters      *FilterGroup
+		expected     string
+		expectedArgs []interface{}
+	}{
+		{
+			name:     "空条件",
+			dbType:   "mysql",
+			filters:  &FilterGroup{Groups: []FilterGroup{{}}},
+			expected: "",
+		},
+		{
+			name:   "嵌套条件组",
+			dbType: "postgresql",
+			filters: &FilterGroup{
+				Logic: "OR",
+				Groups: []FilterGroup{
+					{Conditions: []FilterCondition{{Field: "a", Operator: "=", Value: "1"}, {Field: "b", Operator: ">", Value: "2"}}},
+					{Conditions: []FilterCondition{{Field: "c", Operator: "IN", Value: "x, y"}, {Field: "d", Operator: "IS NULL"}}},
+				},
+			},
+			expected:     `("a" = $1 AND "b" > $2) OR ("c" IN ($3, $4) AND "d" IS NULL)`,
+			expectedArgs: []interface{}{"1", "2", "x", "y"},
+		},
+		{
+			name:         "BETWEEN",
+			dbType:       "sqlserver",
+			filters:      &FilterGroup{Conditions: []FilterCondition{{Field: "age", Operator: "NOT BETWEEN", Values: []string{"18", "30"}}}},
+			expected:     "[age] NOT BETWEEN @p1 AND @p2",
+			expectedArgs: []interface{}{"18", "30"},
+		},
+		{
+			name:         "MySQL 不区分大小写包含，转义通配符",
+			dbType:       "mysql",
+			filters:      &FilterGroup{Conditions: []FilterCondition{{Field: "name", Operator: "CONTAINS", Value: "50%_Off!"}}},
+			expected:     "LOWER(`name`) LIKE ? ESCAPE '!'",
+			expectedArgs: []interface{}{"%50!%!_off!!%"},
+		},
+		{
+			name:         "PostgreSQL 前缀匹配使用 ILIKE",
+			dbType:       "postgresql",
+			filters:      &FilterGroup{Conditions: []FilterCondition{{Field: "name", Operator: "STARTS WITH", Value: "Ab"}}},
+			expected:     `CAST("name" AS TEXT) ILIKE $1 ESCAPE '!'`,
+			expectedArgs: []interface{}{"Ab%"},
+		},
+		{
+			name:         "SQL Server 后缀匹配转义方括号",
+			dbType:       "sqlserver",
+			filters:      &FilterGroup{Conditions: []FilterCondition{{Field: "name", Operator: "ENDS WITH", Value: "[x]"}}},
+			expected:     "LOWER([name]) LIKE @p1 ESCAPE '!'",
+			expectedArgs: []interface{}{"%![x]"},
+		},
+		{
+			name:         "ClickHouse 正则",
+			dbType:       "clickhouse",
+			filters:      &FilterGroup{Conditions: []FilterCondition{{Field: "name", Operator: "NOT REGEX", Value: "^a.*"}}},
+			expected:     "NOT match(toString(`name`), ?)",
+			expectedArgs: []interface{}{"^a.*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := BuildWhereClause(tt.dbType, "t", tt.filters)
+			if err != nil {
+				t.Fatalf("BuildWhereClause() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("BuildWhereClause() = %q, want %q", got, tt.expected)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("BuildWhereClause() args = %v, want %v", args, tt.expectedArgs)
+			}
+		})
+	}
+}
+
+func TestFilteredIDCursor(t *testing.T) {
+	filters := &FilterGroup{Conditions: []FilterCondition{{Field: "name", Operator: "=", Value: "a"}}}
+	tests := []struct {
+		dbType   string
+		column   string
+		expected string
+	}{
+		{"mysql", "`id`", "`name` = ? AND `id` > ?"},
+		{"postgresql", `"id"`, `"name" = $1 AND "id" > $2`},
+		{"sqlserver", "[id]", "[name] = @p1 AND [id] > @p2"},
+		{"oracle", `"ID"`, `"NAME" = :1 AND "ID" > :2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dbType, func(t *testing.T) {
+			where, args, err := BuildWhereClause(tt.dbType, "t", filters)
+			if err != nil {
+				t.Fatalf("BuildWhereClause() error = %v", err)
+			}
+			got := where + " AND " + buildIDCursorCondition(tt.dbType, tt.column, ">", len(args))
+			if got != tt.expected {
+				t.Errorf("condition = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildWhereClauseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		dbType  string
+		filters *FilterGroup
+	}{
+		{name: "BETWEEN 缺少值", dbType: "mysql", filters: &FilterGroup{Conditions: []FilterCondition{{Field: "a", Operator: "BETWEEN", Value: "1"}}}},
+		{name: "SQL Server 不支持正则", dbType: "sqlserver", filters: &FilterGroup{Conditions: []FilterCondition{{Field: "a", Operator: "REGEX", Value: "x"}}}},
+		{name: "无效的相对时间", dbType: "mysql", filters: &FilterGroup{Conditions: []FilterCondition{{Field: "a", Operator: "LAST", Value: "soon"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := BuildWhereClause(tt.dbType, "t", tt.filters); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRelativeDateRange(t *testing.T) {
+	now := time.Date(2024, 3, 14, 15, 30, 0, 0, time.UTC) // 星期四
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		operator string
+		value    string
+		start    time.Time
+		end      time.Time
+	}{
+		{operator: "LAST", value: "7 days", start: now.AddDate(0, 0, -7), end: now},
+		{operator: "LAST", value: "3", start: now.AddDate(0, 0, -3), end: now},
+		{operator: "NEXT", value: "2 hours", start: now, end: now.Add(2 * time.Hour)},
+		{operator: "LAST", value: "1 month", start: now.AddDate(0, -1, 0), end: now},
+		{operator: "TODAY", start: day(2024, 3, 14), end: day(2024, 3, 15)},
+		{operator: "YESTERDAY", start: day(2024, 3, 13), end: day(2024, 3, 14)},
+		{operator: "THIS WEEK", start: day(2024, 3, 11), end: day(2024, 3, 18)},
+		{operator: "THIS MONTH", start: day(2024, 3, 1), end: day(2024, 4, 1)},
+		{operator: "THIS YEAR", start: day(2024, 1, 1), end: day(2025, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator+" "+tt.value, func(t *testing.T) {
+			start, end, err := RelativeDateRange(tt.operator, tt.value, now)
+			if err != nil {
+				t.Fatalf("RelativeDateRange() error = %v", err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("RelativeDateRange() = [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
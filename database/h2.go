@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -165,6 +166,8 @@ func (h *H2) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 		}
 
 		col.Nullable = (nullable == "YES")
+		// AUTO_INCREMENT/IDENTITY 列的默认值为 NEXT VALUE FOR 序列
+		col.AutoIncrement = strings.Contains(strings.ToUpper(defaultVal.String), "NEXT VALUE FOR")
 		if defaultVal.Valid {
 			col.DefaultValue = defaultVal.String
 		}
@@ -176,10 +179,15 @@ func (h *H2) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询
 func (h *H2) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return h.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (h *H2) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	if h.db == nil {
 		return nil, fmt.Errorf("database not connected")
 	}
-	rows, err := h.db.Query(query)
+	rows, err := h.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -217,12 +225,84 @@ func (h *H2) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (h *H2) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	if h.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	rows, err := h.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (h *H2) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, h.db, "h2")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (h *H2) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, h.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (h *H2) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, h.db, "h2", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (h *H2) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, h.db, "h2", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (h *H2) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, h.db, "h2", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (h *H2) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, h.db, "h2", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (h *H2) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, h.db, "h2", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (h *H2) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, h.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (h *H2) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, h.db, query, args)
+}
+
+// TruncateTable 清空表
+func (h *H2) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, h.db, "h2", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (h *H2) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, h.db, "h2", tableName, columns)
+}
+
 // ExecuteUpdate 执行更新
 func (h *H2) ExecuteUpdate(query string) (int64, error) {
+	return h.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (h *H2) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
 	if h.db == nil {
 		return 0, fmt.Errorf("数据库未连接")
 	}
-	result, err := h.db.Exec(query)
+	result, err := h.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -231,10 +311,15 @@ func (h *H2) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (h *H2) ExecuteDelete(query string) (int64, error) {
+	return h.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (h *H2) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
 	if h.db == nil {
 		return 0, fmt.Errorf("数据库未连接")
 	}
-	result, err := h.db.Exec(query)
+	result, err := h.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -243,10 +328,15 @@ func (h *H2) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (h *H2) ExecuteInsert(query string) (int64, error) {
+	return h.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (h *H2) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
 	if h.db == nil {
 		return 0, fmt.Errorf("数据库未连接")
 	}
-	result, err := h.db.Exec(query)
+	result, err := h.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -255,6 +345,11 @@ func (h *H2) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (h *H2) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return h.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (h *H2) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	if h.db == nil {
 		return nil, 0, fmt.Errorf("database not connected")
 	}
@@ -270,7 +365,7 @@ func (h *H2) GetTableData(tableName string, page, pageSize int, filters *FilterG
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := h.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := h.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -282,7 +377,7 @@ func (h *H2) GetTableData(tableName string, page, pageSize int, filters *FilterG
 	}
 	query += fmt.Sprintf(" LIMIT %d OFFSET %d", pageSize, offset)
 
-	rows, err := h.db.Query(query, whereArgs...)
+	rows, err := h.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -324,6 +419,11 @@ func (h *H2) GetTableData(tableName string, page, pageSize int, filters *FilterG
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (h *H2) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return h.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (h *H2) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	if h.db == nil {
 		return nil, 0, nil, fmt.Errorf("database not connected")
 	}
@@ -339,7 +439,7 @@ func (h *H2) GetTableDataByID(tableName string, primaryKey string, lastId interf
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := h.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := h.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -392,7 +492,7 @@ func (h *H2) GetTableDataByID(tableName string, primaryKey string, lastId interf
 		`, strings.ToUpper(tableName), wherePart, strings.ToUpper(primaryKey), pageSize)
 	}
 	
-	rows, err = h.db.Query(query, queryArgs...)
+	rows, err = h.db.QueryContext(ctx, query, queryArgs...)
 
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
@@ -450,6 +550,27 @@ func (h *H2) GetTableDataByID(tableName string, primaryKey string, lastId interf
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (h *H2) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if h.db == nil {
+		return nil, 0, fmt.Errorf("database not connected")
+	}
+	return querySortedTableData(ctx, h.db, "h2", strings.ToUpper(tableName), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (h *H2) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if h.db == nil {
+		return nil, 0, fmt.Errorf("database not connected")
+	}
+	return queryTableDataByCursor(ctx, h.db, "h2", strings.ToUpper(tableName), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (h *H2) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, h.db, "h2", strings.ToUpper(tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (h *H2) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if h.db == nil {
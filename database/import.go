@@ -0,0 +1,384 @@
+package database
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ImportReader 导入文件的记录读取器
+// 格式常量与导出相同（ExportFormatCSV、ExportFormatTSV、ExportFormatJSON、ExportFormatNDJSON）
+type ImportReader interface {
+	// Columns 返回文件中的列名：CSV/TSV 为第一行，JSON 为前几条记录中出现的字段（按出现顺序）
+	Columns() []string
+
+	// Next 读取下一条记录，字段名到值；记录中没有的字段不出现在结果中，读完时返回 io.EOF
+	Next() (map[string]interface{}, error)
+
+	// Line 返回最近读取的记录在文件中的位置：CSV/TSV 为行号，JSON 为记录序号
+	Line() int
+
+	Close() error
+}
+
+// importColumnSampleSize JSON 文件中用于确定列名的记录数
+const importColumnSampleSize = 100
+
+// NewImportReader 创建指定格式的读取器
+// CSV/TSV 的第一行为列名，全部为空的行被跳过；JSON 格式接受对象数组或每行一个对象（NDJSON），数字保留为 json.Number
+func NewImportReader(format string, r io.Reader) (ImportReader, error) {
+	switch format {
+	case ExportFormatCSV, ExportFormatTSV:
+		cr := csv.NewReader(r)
+		if format == ExportFormatTSV {
+			cr.Comma = '\t'
+		}
+		cr.FieldsPerRecord = -1
+		return newCSVImportReader(cr)
+	case ExportFormatJSON, ExportFormatNDJSON:
+		return newJSONImportReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+}
+
+// csvImportReader CSV/TSV 读取器
+type csvImportReader struct {
+	csv     *csv.Reader
+	columns []string
+	line    int
+}
+
+func newCSVImportReader(cr *csv.Reader) (*csvImportReader, error) {
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// 去掉 Excel 等工具写入的 UTF-8 BOM
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[i] = strings.TrimSpace(name)
+	}
+	return &csvImportReader{csv: cr, columns: columns}, nil
+}
+
+func (c *csvImportReader) Columns() []string {
+	return c.columns
+}
+
+func (c *csvImportReader) Next() (map[string]interface{}, error) {
+	for {
+		record, err := c.csv.Read()
+		if err != nil {
+			return nil, err
+		}
+		c.line, _ = c.csv.FieldPos(0)
+		if row := ImportRecord(c.columns, record); row != nil {
+			return row, nil
+		}
+	}
+}
+
+func (c *csvImportReader) Line() int {
+	return c.line
+}
+
+func (c *csvImportReader) Close() error {
+	return nil
+}
+
+// ImportRecord 按列名将一行单元格转换为记录，列名为空的单元格被忽略，缺少的单元格不出现在记录中
+// 所有单元格都为空时返回 nil
+func ImportRecord(columns, cells []string) map[string]interface{} {
+	row := make(map[string]interface{}, len(columns))
+	empty := true
+	for i, value := range cells {
+		if i >= len(columns) || columns[i] == "" {
+			continue
+		}
+		if value != "" {
+			empty = false
+		}
+		row[columns[i]] = value
+	}
+	if empty {
+		return nil
+	}
+	return row
+}
+
+// jsonImportReader JSON 数组和 NDJSON 读取器，前 importColumnSampleSize 条记录预先读取用于确定列名
+type jsonImportReader struct {
+	decoder *json.Decoder
+	array   bool
+	columns []string
+	pending []map[string]interface{}
+	line    int
+	err     error
+}
+
+func newJSONImportReader(r io.Reader) (*jsonImportReader, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	j := &jsonImportReader{decoder: decoder}
+
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('['):
+		j.array = true
+	case json.Delim('{'):
+	default:
+		return nil, fmt.Errorf("expected a JSON array or objects")
+	}
+
+	seen := map[string]bool{}
+	started := !j.array
+	for len(j.pending) < importColumnSampleSize {
+		record, names, err := j.read(started)
+		started = false
+		if err != nil {
+			j.err = err
+			break
+		}
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				j.columns = append(j.columns, name)
+			}
+		}
+		j.pending = append(j.pending, record)
+	}
+	if j.err != nil && j.err != io.EOF && len(j.pending) == 0 {
+		return nil, j.err
+	}
+	return j, nil
+}
+
+// read 读取下一个对象，返回字段值和按出现顺序排列的字段名；started 表示对象的 '{' 已被读取
+func (j *jsonImportReader) read(started bool) (map[string]interface{}, []string, error) {
+	if !started {
+		if j.array && !j.decoder.More() {
+			return nil, nil, io.EOF
+		}
+		token, err := j.decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		if token != json.Delim('{') {
+			return nil, nil, fmt.Errorf("record %d is not an object", j.line+1)
+		}
+	}
+	j.line++
+
+	record := map[string]interface{}{}
+	var names []string
+	for j.decoder.More() {
+		token, err := j.decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		name := token.(string)
+		var value interface{}
+		if err := j.decoder.Decode(&value); err != nil {
+			return nil, nil, fmt.Errorf("record %d: %w", j.line, err)
+		}
+		if _, ok := record[name]; !ok {
+			names = append(names, name)
+		}
+		record[name] = value
+	}
+	// 读取对象结尾的 '}'
+	if _, err := j.decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+	return record, names, nil
+}
+
+func (j *jsonImportReader) Columns() []string {
+	return j.columns
+}
+
+func (j *jsonImportReader) Next() (map[string]interface{}, error) {
+	if len(j.pending) > 0 {
+		record := j.pending[0]
+		j.pending = j.pending[1:]
+		return record, nil
+	}
+	if j.err != nil {
+		return nil, j.err
+	}
+	record, _, err := j.read(false)
+	if err != nil {
+		j.err = err
+		return nil, err
+	}
+	return record, nil
+}
+
+// Line 预先读取的记录按序号计算位置
+func (j *jsonImportReader) Line() int {
+	return j.line - len(j.pending)
+}
+
+func (j *jsonImportReader) Close() error {
+	return nil
+}
+
+// importTimeLayouts 导入日期和时间列时接受的格式，没有时区的时间按原样写入（不做时区转换）
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
+// importTypeName 返回列类型的基本名称（小写），去掉长度、精度、unsigned 等修饰以及 ClickHouse 的 Nullable/LowCardinality 包装
+func importTypeName(columnType string) string {
+	t := strings.ToLower(strings.TrimSpace(columnType))
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		if strings.HasPrefix(t, wrapper) && strings.HasSuffix(t, ")") {
+			t = t[len(wrapper) : len(t)-1]
+		}
+	}
+	if i := strings.IndexAny(t, "( "); i >= 0 {
+		t = t[:i]
+	}
+	return t
+}
+
+// CoerceImportValue 按列类型校验并转换导入的值，无法转换时返回错误
+// 整数和浮点数转换为数值，DECIMAL 等定点数校验后保留字符串以免丢失精度，布尔值接受 true/false、1/0、yes/no 等写法，
+// 日期时间按 importTimeLayouts 解析（也接受 Excel 的日期序列号），二进制列接受 0x 开头的十六进制，JSON 列校验格式；
+// 其他类型转换为字符串。nil 原样返回
+func CoerceImportValue(column ColumnInfo, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text := ExportText(value)
+	trimmed := strings.TrimSpace(text)
+
+	switch name := importTypeName(column.Type); name {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "int2", "int4", "int8",
+		"serial", "smallserial", "bigserial", "year", "int16", "int32", "int64", "int128", "int256",
+		"uint8", "uint16", "uint32", "uint64", "uint128", "uint256":
+		if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return n, nil
+		}
+		if n, err := strconv.ParseUint(trimmed, 10, 64); err == nil {
+			return n, nil
+		}
+		// 接受没有小数部分的浮点写法，如 Excel 中的 "3.0"
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f), nil
+		}
+		return nil, fmt.Errorf("invalid integer: %q", text)
+	case "decimal", "numeric", "number", "dec", "money", "smallmoney",
+		"decimal32", "decimal64", "decimal128", "decimal256":
+		if f, err := strconv.ParseFloat(trimmed, 64); err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("invalid number: %q", text)
+		}
+		return trimmed, nil
+	case "float", "double", "real", "float4", "float8", "float32", "float64", "binary_float", "binary_double":
+		f, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %q", text)
+		}
+		return f, nil
+	case "bool", "boolean":
+		b, ok := parseImportBool(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("invalid boolean: %q", text)
+		}
+		return b, nil
+	case "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "timestamp", "timestamptz", "datetime64", "date32":
+		t, ok := parseImportTime(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("invalid date/time: %q", text)
+		}
+		return t, nil
+	case "time", "timetz":
+		for _, layout := range []string{"15:04:05.999999999", "15:04", "15:04:05.999999999Z07:00"} {
+			if _, err := time.Parse(layout, trimmed); err == nil {
+				return trimmed, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid time: %q", text)
+	case "json", "jsonb":
+		if !json.Valid([]byte(text)) {
+			return nil, fmt.Errorf("invalid JSON: %q", text)
+		}
+		return text, nil
+	case "bit":
+		// SQL Server 的 BIT 为布尔值，MySQL 的 BIT 为位串（导出为十六进制）
+		if b, ok := parseImportBool(trimmed); ok {
+			return b, nil
+		}
+		return parseImportBinary(text)
+	default:
+		if IsBinaryType(name) {
+			return parseImportBinary(text)
+		}
+		return text, nil
+	}
+}
+
+// parseImportBool 解析布尔值的常见写法
+func parseImportBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "true", "t", "1", "yes", "y", "on":
+		return true, true
+	case "false", "f", "0", "no", "n", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// parseImportTime 按 importTimeLayouts 解析日期时间，纯数字按 Excel 日期序列号（1900 日期系统）转换
+func parseImportTime(s string) (time.Time, bool) {
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 0 && serial < 2958466 {
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return base.Add(time.Duration(math.Round(serial*86400)) * time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// parseImportBinary 0x 开头的值按十六进制解码（与导出的格式一致），其他值按原始字节写入
+func parseImportBinary(s string) (interface{}, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		data, err := hex.DecodeString(s[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex value: %q", s)
+		}
+		return data, nil
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		columns []string
+		records []map[string]interface{}
+		lines   []int
+	}{
+		{
+			name:    "CSV 去掉 BOM 并跳过空行",
+			format:  ExportFormatCSV,
+			input:   "\ufeffid, name\n1,\"a\nb\"\n,\n2\n",
+			columns: []string{"id", "name"},
+			records: []map[string]interface{}{{"id": "1", "name": "a\nb"}, {"id": "2"}},
+			lines:   []int{2, 5},
+		},
+		{
+			name:    "TSV",
+			format:  ExportFormatTSV,
+			input:   "id\tname\n1\tx\n",
+			columns: []string{"id", "name"},
+			records: []map[string]interface{}{{"id": "1", "name": "x"}},
+			lines:   []int{2},
+		},
+		{
+			name:    "JSON 数组按出现顺序确定列",
+			format:  ExportFormatJSON,
+			input:   `[{"name":"a","id":1},{"id":2,"extra":true}]`,
+			columns: []string{"name", "id", "extra"},
+			records: []map[string]interface{}{{"name": "a", "id": json.Number("1")}, {"id": json.Number("2"), "extra": true}},
+			lines:   []int{1, 2},
+		},
+		{
+			name:    "NDJSON",
+			format:  ExportFormatNDJSON,
+			input:   "{\"id\":1}\n{\"id\":2,\"tags\":[\"x\"]}\n",
+			columns: []string{"id", "tags"},
+			records: []map[string]interface{}{{"id": json.Number("1")}, {"id": json.Number("2"), "tags": []interface{}{"x"}}},
+			lines:   []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewImportReader(tt.format, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer reader.Close()
+			if !reflect.DeepEqual(reader.Columns(), tt.columns) {
+				t.Errorf("columns = %v, want %v", reader.Columns(), tt.columns)
+			}
+			var records []map[string]interface{}
+			var lines []int
+			for {
+				record, err := reader.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Next: %v", err)
+				}
+				records = append(records, record)
+				lines = append(lines, reader.Line())
+			}
+			if !reflect.DeepEqual(records, tt.records) {
+				t.Errorf("records = %#v, want %#v", records, tt.records)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("lines = %v, want %v", lines, tt.lines)
+			}
+		})
+	}
+
+	if _, err := NewImportReader(ExportFormatJSON, strings.NewReader(`"text"`)); err == nil {
+		t.Error("expected error for JSON that is not an array or object")
+	}
+}
+
+func TestCoerceImportValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnType string
+		value      interface{}
+		expected   interface{}
+		wantErr    bool
+	}{
+		{"整数", "int(11) unsigned", " 42 ", int64(42), false},
+		{"JSON 数字", "bigint", json.Number("7"), int64(7), false},
+		{"整数的浮点写法", "INTEGER", "3.0", int64(3), false},
+		{"非法整数", "int", "abc", nil, true},
+		{"ClickHouse Nullable", "Nullable(UInt64)", "18446744073709551615", uint64(18446744073709551615), false},
+		{"定点数保留字符串", "decimal(10,2)", "12.50", "12.50", false},
+		{"浮点数", "double precision", "1.5", 1.5, false},
+		{"布尔值", "boolean", "yes", true, false},
+		{"非法布尔值", "bool", "maybe", nil, true},
+		{"日期时间", "datetime", "2024-01-02 03:04:05", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"ISO 时间", "timestamp with time zone", "2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"Excel 日期序列号", "date", "45293", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"非法日期", "date", "yesterday", nil, true},
+		{"JSON 列", "jsonb", map[string]interface{}{"k": 1}, `{"k":1}`, false},
+		{"非法 JSON", "json", "{", nil, true},
+		{"十六进制二进制", "varbinary(16)", "0xcafe", []byte{0xca, 0xfe}, false},
+		{"SQL Server BIT", "bit", "1", true, false},
+		{"字符串", "varchar(255)", json.Number("10"), "10", false},
+		{"NULL", "int", nil, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceImportValue(ColumnInfo{Name: "c", Type: tt.columnType}, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %#v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("CoerceImportValue(%q, %#v) = %#v, want %#v", tt.columnType, tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildInsertRowsSQL(t *testing.T) {
+	columns := []string{"id", "name"}
+	rows := [][]interface{}{{int64(1), "a"}, {int64(2), "b"}}
+	tests := []struct {
+		name     string
+		dbType   string
+		keys     []string
+		expected string
+	}{
+		{"MySQL", "mysql", nil, "INSERT INTO `t` (`id`, `name`) VALUES (?, ?), (?, ?)"},
+		{"PostgreSQL", "postgresql", nil, `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4)`},
+		{"SQL Server", "sqlserver", nil, "INSERT INTO [t] ([id], [name]) VALUES (@p1, @p2), (@p3, @p4)"},
+		{"Oracle INSERT ALL", "oracle", nil, `INSERT ALL INTO "T" ("ID", "NAME") VALUES (:1, :2) INTO "T" ("ID", "NAME") VALUES (:3, :4) SELECT 1 FROM DUAL`},
+		{"MySQL upsert", "mysql", []string{"id"}, "INSERT INTO `t` (`id`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"},
+		{"PostgreSQL upsert", "postgresql", []string{"id"}, `INSERT INTO "t" ("id", "name") VALUES ($1, $2), ($3, $4) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`},
+		{"SQLite upsert", "sqlite", []string{"id"}, "INSERT INTO `t` (`id`, `name`) VALUES (?, ?), (?, ?) ON CONFLICT (`id`) DO UPDATE SET `name` = EXCLUDED.`name`"},
+		{"H2 MERGE", "h2", []string{"id"}, "MERGE INTO T (`id`, `name`) KEY (`id`) VALUES (?, ?), (?, ?)"},
+		{
+			"SQL Server MERGE", "sqlserver", []string{"id"},
+			"MERGE INTO [t] AS target USING (VALUES (@p1, @p2), (@p3, @p4)) AS source ([id], [name]) ON target.[id] = source.[id]" +
+				" WHEN MATCHED THEN UPDATE SET target.[name] = source.[name]" +
+				" WHEN NOT MATCHED THEN INSERT ([id], [name]) VALUES (source.[id], source.[name]);",
+		},
+		{
+			"Oracle MERGE", "oracle", []string{"id"},
+			`MERGE INTO "T" target USING (SELECT :1 "ID", :2 "NAME" FROM DUAL UNION ALL SELECT :3, :4 FROM DUAL) source ON (target."ID" = source."ID")` +
+				` WHEN MATCHED THEN UPDATE SET target."NAME" = source."NAME"` +
+				` WHEN NOT MATCHED THEN INSERT ("ID", "NAME") VALUES (source."ID", source."NAME")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildInsertRowsSQL(tt.dbType, "t", columns, rows, tt.keys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("query = %q, want %q", query, tt.expected)
+			}
+			if want := []interface{}{int64(1), "a", int64(2), "b"}; !reflect.DeepEqual(args, want) {
+				t.Errorf("args = %#v, want %#v", args, want)
+			}
+		})
+	}
+
+	if _, _, err := BuildInsertRowsSQL("clickhouse", "t", columns, rows, []string{"id"}); err == nil {
+		t.Error("expected error for clickhouse upsert")
+	}
+	if _, _, err := BuildInsertRowsSQL("mysql", "t", columns, rows, []string{"code"}); err == nil {
+		t.Error("expected error for key column that is not inserted")
+	}
+	if _, _, err := BuildInsertRowsSQL("mysql", "t", columns, [][]interface{}{{int64(1)}}, nil); err == nil {
+		t.Error("expected error for row with missing values")
+	}
+	if got := ImportBatchSize("sqlserver", 10); got != 209 {
+		t.Errorf("ImportBatchSize(sqlserver, 10) = %d, want 209", got)
+	}
+}
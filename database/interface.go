@@ -1,5 +1,7 @@
 package database
 
+import "context"
+
 // Database 定义数据库操作的通用接口
 type Database interface {
 	// Connect 建立数据库连接
@@ -73,13 +75,39 @@ type Database interface {
 	GetDisplayName() string
 }
 
+// ContextDatabase 支持 context 的数据库接口扩展
+// 实现该接口的驱动可以在请求取消或超时时中止正在执行的语句
+type ContextDatabase interface {
+	Database
+
+	// ExecuteQueryContext 执行查询SQL，ctx 取消时中止
+	ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error)
+
+	// ExecuteUpdateContext 执行更新SQL，ctx 取消时中止
+	ExecuteUpdateContext(ctx context.Context, query string) (int64, error)
+
+	// ExecuteDeleteContext 执行删除SQL，ctx 取消时中止
+	ExecuteDeleteContext(ctx context.Context, query string) (int64, error)
+
+	// ExecuteInsertContext 执行插入SQL，ctx 取消时中止
+	ExecuteInsertContext(ctx context.Context, query string) (int64, error)
+
+	// GetTableDataContext 获取表的数据（分页），ctx 取消时中止
+	GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error)
+
+	// GetTableDataByIDContext 基于主键ID获取表数据，ctx 取消时中止
+	GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error)
+}
+
 // ColumnInfo 列信息
 type ColumnInfo struct {
-	Name         string `json:"name"`
-	Type         string `json:"type"`
-	Nullable     bool   `json:"nullable"`
-	DefaultValue string `json:"default_value"`
-	Key          string `json:"key"` // PRI, UNI, MUL等
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Nullable      bool   `json:"nullable"`
+	DefaultValue  string `json:"default_value"`
+	Key           string `json:"key"`                      // PRI, UNI, MUL等
+	AutoIncrement bool   `json:"auto_increment,omitempty"` // 是否为自增/标识列（插入时由数据库生成值）
+	Generated     bool   `json:"generated,omitempty"`      // 是否为计算列、生成列等只能由数据库写入的列（插入时不能指定值）
 }
 
 // ProxyConfig 代理配置
@@ -95,27 +123,35 @@ type ProxyConfig struct {
 
 // ConnectionInfo 连接信息
 type ConnectionInfo struct {
-	Name     string       `json:"name"`     // 连接名称（可选，用于显示）
-	Type     string       `json:"type"`    // mysql, postgresql等
-	Host     string       `json:"host"`    // 数据库主机地址
-	Port     string       `json:"port"`     // 数据库端口
-	User     string       `json:"user"`    // 数据库用户名
-	Password string       `json:"password"` // 数据库密码
-	Database string       `json:"database"` // 数据库名
-	DSN      string       `json:"dsn"`      // 如果提供DSN，则优先使用
-	Proxy    *ProxyConfig `json:"proxy"`    // 代理配置（可选）
+	Name         string       `json:"name"`          // 连接名称（可选，用于显示）
+	Type         string       `json:"type"`          // mysql, postgresql等
+	Host         string       `json:"host"`          // 数据库主机地址
+	Port         string       `json:"port"`          // 数据库端口
+	User         string       `json:"user"`          // 数据库用户名
+	Password     string       `json:"password"`      // 数据库密码
+	Database     string       `json:"database"`      // 数据库名
+	DSN          string       `json:"dsn"`           // 如果提供DSN，则优先使用
+	Proxy        *ProxyConfig `json:"proxy"`         // 代理配置（可选）
+	QueryTimeout int          `json:"query_timeout"` // 语句超时时间（秒，可选，0表示使用服务器默认值）
 }
 
 // FilterCondition 过滤条件
+// 操作符：
+//   - 比较：=, !=, <, >, <=, >=, LIKE, NOT LIKE, IN, NOT IN, IS NULL, IS NOT NULL, BETWEEN, NOT BETWEEN
+//   - 文本（不区分大小写）：CONTAINS, NOT CONTAINS, STARTS WITH, ENDS WITH
+//   - 正则：REGEX, NOT REGEX（取决于数据库是否支持）
+//   - 相对日期：LAST, NEXT（值如 "7 days"），TODAY, YESTERDAY, THIS WEEK, THIS MONTH, THIS YEAR
 type FilterCondition struct {
 	Field    string   `json:"field"`    // 字段名
-	Operator string   `json:"operator"` // 操作符：=, !=, <, >, <=, >=, LIKE, NOT LIKE, IN, NOT IN, IS NULL, IS NOT NULL
-	Value    string   `json:"value"`     // 值（对于 IN/NOT IN，使用逗号分隔的多个值）
-	Values   []string `json:"values"`   // 值数组（用于 IN/NOT IN，如果提供则优先使用）
+	Operator string   `json:"operator"` // 操作符
+	Value    string   `json:"value"`    // 值（对于 IN/NOT IN/BETWEEN，使用逗号分隔的多个值）
+	Values   []string `json:"values"`   // 值数组（用于 IN/NOT IN/BETWEEN，如果提供则优先使用）
 }
 
-// FilterGroup 过滤条件组（支持 AND/OR 逻辑）
+// FilterGroup 过滤条件组（支持 AND/OR 逻辑和嵌套）
+// Conditions 和 Groups 中的每一项按 Logic 组合，如 (a AND b) OR (c AND d)
 type FilterGroup struct {
 	Conditions []FilterCondition `json:"conditions"` // 过滤条件列表
+	Groups     []FilterGroup     `json:"groups"`     // 嵌套的子条件组
 	Logic      string            `json:"logic"`      // 逻辑关系：AND 或 OR，默认为 AND
 }
@@ -0,0 +1,285 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// MetadataDatabase 可选接口：返回结构化的元数据（索引、外键、检查约束、注释、视图、存储过程/函数和触发器）
+// GetTableSchema 只返回格式化的字符串，ColumnInfo 只包含列的基本信息，需要结构化信息时使用该接口
+type MetadataDatabase interface {
+	// GetTableMetadata 返回表的列、注释、索引、外键和检查约束
+	GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error)
+
+	// GetViews 返回当前数据库中的视图及其定义
+	GetViews(ctx context.Context) ([]ViewInfo, error)
+
+	// GetRoutines 返回当前数据库中的存储过程和函数，数据库不支持时返回空列表
+	GetRoutines(ctx context.Context) ([]RoutineInfo, error)
+
+	// GetTriggers 返回表的触发器，tableName 为空时返回当前数据库中所有表的触发器
+	GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error)
+}
+
+// TableMetadata 表的结构化元数据
+type TableMetadata struct {
+	Name           string                `json:"name"`
+	Comment        string                `json:"comment,omitempty"`
+	Columns        []ColumnInfo          `json:"columns"`
+	ColumnComments map[string]string     `json:"columnComments"` // 列名到注释，只包含有注释的列
+	Indexes        []IndexInfo           `json:"indexes"`        // 主键索引在前
+	ForeignKeys    []ForeignKeyInfo      `json:"foreignKeys"`
+	Checks         []CheckConstraintInfo `json:"checks"`
+}
+
+// IndexInfo 索引（包括主键和唯一约束对应的索引）
+type IndexInfo struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"` // 按索引中的顺序，表达式索引为表达式
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+	Type    string   `json:"type,omitempty"` // 索引类型，如 BTREE、HASH、FULLTEXT、CLUSTERED，取值依数据库而定
+}
+
+// ForeignKeyInfo 外键，Columns 与 RefColumns 按位置一一对应
+type ForeignKeyInfo struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"refTable"`
+	RefColumns []string `json:"refColumns"`
+	OnUpdate   string   `json:"onUpdate,omitempty"` // NO ACTION、RESTRICT、CASCADE、SET NULL、SET DEFAULT
+	OnDelete   string   `json:"onDelete,omitempty"`
+}
+
+// CheckConstraintInfo 检查约束
+type CheckConstraintInfo struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// ViewInfo 视图
+type ViewInfo struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// 存储过程和函数的类型
+const (
+	RoutineProcedure = "PROCEDURE"
+	RoutineFunction  = "FUNCTION"
+)
+
+// RoutineInfo 存储过程或函数
+type RoutineInfo struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`                 // PROCEDURE 或 FUNCTION
+	ReturnType string `json:"returnType,omitempty"` // 函数的返回类型
+	Definition string `json:"definition"`
+}
+
+// TriggerInfo 触发器
+type TriggerInfo struct {
+	Name       string `json:"name"`
+	Table      string `json:"table"`
+	Timing     string `json:"timing"` // BEFORE、AFTER、INSTEAD OF
+	Event      string `json:"event"`  // INSERT、UPDATE、DELETE，多个事件用 " OR " 连接
+	Definition string `json:"definition"`
+}
+
+// newTableMetadata 返回列表字段已初始化的 TableMetadata，保证 JSON 中为 [] 而不是 null
+func newTableMetadata(tableName string, columns []ColumnInfo) *TableMetadata {
+	return &TableMetadata{
+		Name:           tableName,
+		Columns:        columns,
+		ColumnComments: map[string]string{},
+		Indexes:        []IndexInfo{},
+		ForeignKeys:    []ForeignKeyInfo{},
+		Checks:         []CheckConstraintInfo{},
+	}
+}
+
+// sqlQueryer 可以执行查询的连接（*sql.DB 或 *sql.Conn）
+type sqlQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryMetadata 执行元数据查询，对每一行调用 scan
+func queryMetadata(ctx context.Context, db sqlQueryer, query string, scan func(rows *sql.Rows) error, args ...interface{}) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// addIndexColumn 把一列追加到名为 name 的索引，索引不存在时新建；查询结果需按索引分组排序
+func addIndexColumn(indexes []IndexInfo, name, column string, unique, primary bool, indexType string) []IndexInfo {
+	if n := len(indexes); n > 0 && indexes[n-1].Name == name {
+		indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+		return indexes
+	}
+	return append(indexes, IndexInfo{Name: name, Columns: []string{column}, Unique: unique || primary, Primary: primary, Type: indexType})
+}
+
+// addForeignKeyColumn 把一对列追加到名为 name 的外键，外键不存在时新建；查询结果需按外键分组排序
+func addForeignKeyColumn(keys []ForeignKeyInfo, name, column, refTable, refColumn, onUpdate, onDelete string) []ForeignKeyInfo {
+	if n := len(keys); n > 0 && keys[n-1].Name == name {
+		keys[n-1].Columns = append(keys[n-1].Columns, column)
+		keys[n-1].RefColumns = append(keys[n-1].RefColumns, refColumn)
+		return keys
+	}
+	return append(keys, ForeignKeyInfo{
+		Name:       name,
+		Columns:    []string{column},
+		RefTable:   refTable,
+		RefColumns: []string{refColumn},
+		OnUpdate:   onUpdate,
+		OnDelete:   onDelete,
+	})
+}
+
+// pgForeignKeyAction 将 pg_constraint 中的 confupdtype/confdeltype 转换为动作名称
+func pgForeignKeyAction(code string) string {
+	switch code {
+	case "a":
+		return "NO ACTION"
+	case "r":
+		return "RESTRICT"
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "d":
+		return "SET DEFAULT"
+	}
+	return ""
+}
+
+// pgTriggerType 解析 pg_trigger.tgtype 中的时机和事件
+func pgTriggerType(tgtype int) (timing, event string) {
+	switch {
+	case tgtype&64 != 0:
+		timing = "INSTEAD OF"
+	case tgtype&2 != 0:
+		timing = "BEFORE"
+	default:
+		timing = "AFTER"
+	}
+	var events []string
+	for _, e := range []struct {
+		bit  int
+		name string
+	}{{4, "INSERT"}, {16, "UPDATE"}, {8, "DELETE"}, {32, "TRUNCATE"}} {
+		if tgtype&e.bit != 0 {
+			events = append(events, e.name)
+		}
+	}
+	return timing, strings.Join(events, " OR ")
+}
+
+var triggerHeaderPattern = regexp.MustCompile(`(?is)\bTRIGGER\b.*?\b(?:(BEFORE|AFTER|INSTEAD\s+OF)\s+)?((?:INSERT|UPDATE|DELETE)(?:\s+OR\s+(?:INSERT|UPDATE|DELETE))*)\b`)
+
+// parseTriggerHeader 从 CREATE TRIGGER 语句中解析时机和事件，未写时机时为 BEFORE（SQLite 的默认值）
+func parseTriggerHeader(definition string) (timing, event string) {
+	match := triggerHeaderPattern.FindStringSubmatch(definition)
+	if match == nil {
+		return "", ""
+	}
+	timing = strings.ToUpper(strings.Join(strings.Fields(match[1]), " "))
+	if timing == "" {
+		timing = "BEFORE"
+	}
+	return timing, strings.ToUpper(strings.Join(strings.Fields(match[2]), " "))
+}
+
+// parseCheckConstraints 从 CREATE TABLE 语句中解析 CHECK 约束（用于没有约束目录的 SQLite），
+// 约束名来自 CHECK 前的 CONSTRAINT 子句，列级约束没有名称
+func parseCheckConstraints(createSQL string) []CheckConstraintInfo {
+	checks := []CheckConstraintInfo{}
+	upper := strings.ToUpper(createSQL)
+	for i := 0; i < len(createSQL); i++ {
+		switch createSQL[i] {
+		case '\'', '"', '`':
+			// 跳过字符串和带引号的标识符
+			if end := strings.IndexByte(createSQL[i+1:], createSQL[i]); end >= 0 {
+				i += end + 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(upper[i:], "CHECK") || (i > 0 && isIdentifierByte(createSQL[i-1])) {
+			continue
+		}
+		rest := strings.TrimLeft(createSQL[i+5:], " \t\r\n")
+		if !strings.HasPrefix(rest, "(") {
+			continue
+		}
+		start := len(createSQL) - len(rest)
+		end := matchParen(createSQL, start)
+		if end < 0 {
+			break
+		}
+		name := ""
+		if fields := strings.Fields(createSQL[:i]); len(fields) >= 2 && strings.EqualFold(fields[len(fields)-2], "CONSTRAINT") {
+			name = strings.Trim(fields[len(fields)-1], "`\"[]")
+		}
+		checks = append(checks, CheckConstraintInfo{Name: name, Expression: strings.TrimSpace(createSQL[start+1 : end])})
+		i = end
+	}
+	return checks
+}
+
+// matchParen 返回与 start 处左括号匹配的右括号位置，忽略字符串中的括号
+func matchParen(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"', '`':
+			if end := strings.IndexByte(s[i+1:], s[i]); end >= 0 {
+				i += end + 1
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// splitKeyExpression 拆分 ClickHouse 的排序键/主键表达式（如 "a, toDate(b)"），忽略括号中的逗号
+func splitKeyExpression(expr string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(expr[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(expr[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCheckConstraints(t *testing.T) {
+	createSQL := "CREATE TABLE t (\n" +
+		"  id INTEGER PRIMARY KEY,\n" +
+		"  price REAL CHECK (price >= 0),\n" +
+		"  note TEXT DEFAULT 'CHECK (x)',\n" +
+		"  rechecked INTEGER,\n" +
+		"  CONSTRAINT valid_range CHECK(price < (1000 * (rechecked + 1)))\n" +
+		")"
+	expected := []CheckConstraintInfo{
+		{Name: "", Expression: "price >= 0"},
+		{Name: "valid_range", Expression: "price < (1000 * (rechecked + 1))"},
+	}
+	if got := parseCheckConstraints(createSQL); !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseCheckConstraints = %+v, want %+v", got, expected)
+	}
+}
+
+func TestParseTriggerHeader(t *testing.T) {
+	tests := []struct {
+		sql    string
+		timing string
+		event  string
+	}{
+		{"CREATE TRIGGER audit AFTER UPDATE ON t BEGIN SELECT 1; END", "AFTER", "UPDATE"},
+		{"CREATE TRIGGER IF NOT EXISTS v_ins INSTEAD OF INSERT ON v BEGIN SELECT 1; END", "INSTEAD OF", "INSERT"},
+		{"create trigger del delete on t begin select 1; end", "BEFORE", "DELETE"},
+		{"CREATE TRIGGER upd BEFORE UPDATE OF price ON t BEGIN SELECT 1; END", "BEFORE", "UPDATE"},
+	}
+	for _, tt := range tests {
+		timing, event := parseTriggerHeader(tt.sql)
+		if timing != tt.timing || event != tt.event {
+			t.Errorf("parseTriggerHeader(%q) = %q, %q, want %q, %q", tt.sql, timing, event, tt.timing, tt.event)
+		}
+	}
+}
+
+func TestPgTriggerType(t *testing.T) {
+	// ROW | BEFORE | INSERT | UPDATE
+	if timing, event := pgTriggerType(1 | 2 | 4 | 16); timing != "BEFORE" || event != "INSERT OR UPDATE" {
+		t.Errorf("pgTriggerType = %q, %q", timing, event)
+	}
+	if timing, event := pgTriggerType(1 | 64 | 8); timing != "INSTEAD OF" || event != "DELETE" {
+		t.Errorf("pgTriggerType = %q, %q", timing, event)
+	}
+}
+
+func TestSplitKeyExpression(t *testing.T) {
+	got := splitKeyExpression("id, toDate(created_at), cityHash64(a, b)")
+	expected := []string{"id", "toDate(created_at)", "cityHash64(a, b)"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("splitKeyExpression = %q, want %q", got, expected)
+	}
+}
+
+func TestAddIndexColumn(t *testing.T) {
+	var indexes []IndexInfo
+	indexes = addIndexColumn(indexes, "PRIMARY", "id", false, true, "BTREE")
+	indexes = addIndexColumn(indexes, "idx_name", "last", false, false, "BTREE")
+	indexes = addIndexColumn(indexes, "idx_name", "first", false, false, "BTREE")
+	expected := []IndexInfo{
+		{Name: "PRIMARY", Columns: []string{"id"}, Unique: true, Primary: true, Type: "BTREE"},
+		{Name: "idx_name", Columns: []string{"last", "first"}, Type: "BTREE"},
+	}
+	if !reflect.DeepEqual(indexes, expected) {
+		t.Errorf("addIndexColumn = %+v, want %+v", indexes, expected)
+	}
+}
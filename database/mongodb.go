@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -174,6 +175,11 @@ func (m *MongoDB) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询（MongoDB 使用 JSON 查询）
 func (m *MongoDB) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return m.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询（MongoDB 使用 JSON 查询），ctx 取消或超时时中止执行
+func (m *MongoDB) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	if m.client == nil {
 		return nil, fmt.Errorf("database not connected")
 	}
@@ -181,45 +187,20 @@ func (m *MongoDB) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		return nil, fmt.Errorf("database not selected")
 	}
 
-	// MongoDB 查询需要解析为 JSON/BSON
-	// 这里简化处理，假设查询格式为: db.collection.find({...})
-	// 实际使用时需要更复杂的解析逻辑
-	
-	// 尝试解析查询字符串
-	// 格式: db.collectionName.find({...}) 或 collectionName.find({...})
-	parts := strings.Fields(query)
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("MongoDB query format error, expected: collectionName.find({...})")
-	}
-
-	collectionName := parts[0]
-	if strings.HasPrefix(collectionName, "db.") {
-		collectionName = strings.TrimPrefix(collectionName, "db.")
+	collectionName, filter, err := parseMongoFindQuery(query)
+	if err != nil {
+		return nil, err
 	}
-
 	collection := m.database.Collection(collectionName)
-	
-	// 解析查询条件（简化处理）
-	var filter bson.M
-	if len(parts) > 2 {
-		// 尝试解析 JSON 查询条件
-		filterStr := strings.Join(parts[2:], " ")
-		if err := bson.UnmarshalExtJSON([]byte(filterStr), true, &filter); err != nil {
-			// 如果解析失败，使用空查询
-			filter = bson.M{}
-		}
-	} else {
-		filter = bson.M{}
-	}
 
-	cursor, err := collection.Find(m.ctx, filter)
+	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
-	defer cursor.Close(m.ctx)
+	defer cursor.Close(ctx)
 
 	var results = make([]map[string]interface{}, 0)
-	for cursor.Next(m.ctx) {
+	for cursor.Next(ctx) {
 		var doc bson.M
 		if err := cursor.Decode(&doc); err != nil {
 			return nil, err
@@ -240,8 +221,67 @@ func (m *MongoDB) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 	return results, cursor.Err()
 }
 
+// parseMongoFindQuery 解析查询字符串，返回集合名和查询条件
+// MongoDB 查询需要解析为 JSON/BSON，这里简化处理，格式: db.collectionName.find({...}) 或 collectionName.find({...})
+func parseMongoFindQuery(query string) (string, bson.M, error) {
+	parts := strings.Fields(query)
+	if len(parts) < 2 {
+		return "", nil, fmt.Errorf("MongoDB query format error, expected: collectionName.find({...})")
+	}
+
+	collectionName := strings.TrimPrefix(parts[0], "db.")
+
+	// 解析查询条件（简化处理）
+	filter := bson.M{}
+	if len(parts) > 2 {
+		// 尝试解析 JSON 查询条件，解析失败时使用空查询
+		filterStr := strings.Join(parts[2:], " ")
+		if err := bson.UnmarshalExtJSON([]byte(filterStr), true, &filter); err != nil {
+			filter = bson.M{}
+		}
+	}
+	return collectionName, filter, nil
+}
+
+// ExplainQuery 使用 explain 命令获取查询的执行计划，analyze 为 true 时使用 executionStats 级别（会实际执行查询）
+func (m *MongoDB) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return nil, fmt.Errorf("database not selected")
+	}
+	collectionName, filter, err := parseMongoFindQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	verbosity := "queryPlanner"
+	if analyze {
+		verbosity = "executionStats"
+	}
+	command := bson.D{
+		{Key: "explain", Value: bson.D{{Key: "find", Value: collectionName}, {Key: "filter", Value: filter}}},
+		{Key: "verbosity", Value: verbosity},
+	}
+	var result bson.M
+	if err := m.database.RunCommand(ctx, command).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	raw, err := bson.MarshalExtJSONIndent(result, false, false, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return parseMongoExplain(string(raw))
+}
+
 // ExecuteUpdate 执行更新
 func (m *MongoDB) ExecuteUpdate(query string) (int64, error) {
+	return m.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (m *MongoDB) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
 	if m.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -269,6 +309,11 @@ func (m *MongoDB) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (m *MongoDB) ExecuteDelete(query string) (int64, error) {
+	return m.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (m *MongoDB) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
 	if m.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -300,7 +345,7 @@ func (m *MongoDB) ExecuteDelete(query string) (int64, error) {
 		filter = bson.M{}
 	}
 
-	result, err := collection.DeleteMany(m.ctx, filter)
+	result, err := collection.DeleteMany(ctx, filter)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -310,6 +355,11 @@ func (m *MongoDB) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (m *MongoDB) ExecuteInsert(query string) (int64, error) {
+	return m.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (m *MongoDB) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
 	if m.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -341,7 +391,7 @@ func (m *MongoDB) ExecuteInsert(query string) (int64, error) {
 		return 0, fmt.Errorf("missing insert document")
 	}
 
-	_, err := collection.InsertOne(m.ctx, doc)
+	_, err := collection.InsertOne(ctx, doc)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -351,6 +401,11 @@ func (m *MongoDB) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取集合数据（分页）
 func (m *MongoDB) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return m.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取集合数据（分页），ctx 取消或超时时中止执行
+func (m *MongoDB) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	if m.client == nil {
 		return nil, 0, fmt.Errorf("database not connected")
 	}
@@ -361,13 +416,13 @@ func (m *MongoDB) GetTableData(tableName string, page, pageSize int, filters *Fi
 	collection := m.database.Collection(tableName)
 
 	// 将过滤条件转换为 MongoDB 查询
-	filter := bson.M{}
-	if filters != nil && len(filters.Conditions) > 0 {
-		filter = convertFiltersToMongoDB(filters)
+	filter, err := convertFiltersToMongoDB(filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build filter: %w", err)
 	}
 
 	// 获取总数
-	total, err := collection.CountDocuments(m.ctx, filter)
+	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
@@ -377,14 +432,14 @@ func (m *MongoDB) GetTableData(tableName string, page, pageSize int, filters *Fi
 	limit := int64(pageSize)
 
 	opts := options.Find().SetSkip(skip).SetLimit(limit)
-	cursor, err := collection.Find(m.ctx, filter, opts)
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
-	defer cursor.Close(m.ctx)
+	defer cursor.Close(ctx)
 
 	var results = make([]map[string]interface{}, 0)
-	for cursor.Next(m.ctx) {
+	for cursor.Next(ctx) {
 		var doc bson.M
 		if err := cursor.Decode(&doc); err != nil {
 			return nil, 0, err
@@ -404,101 +459,152 @@ func (m *MongoDB) GetTableData(tableName string, page, pageSize int, filters *Fi
 	return results, total, cursor.Err()
 }
 
-// convertFiltersToMongoDB 将 FilterGroup 转换为 MongoDB 查询条件
-func convertFiltersToMongoDB(filters *FilterGroup) bson.M {
-	if filters == nil || len(filters.Conditions) == 0 {
-		return bson.M{}
+// convertFiltersToMongoDB 将 FilterGroup 转换为 MongoDB 查询条件（支持嵌套条件组）
+func convertFiltersToMongoDB(filters *FilterGroup) (bson.M, error) {
+	if filters.IsEmpty() {
+		return bson.M{}, nil
 	}
 
 	// 确定逻辑关系（默认为 AND）
-	logic := strings.ToUpper(filters.Logic)
-	if logic != "AND" && logic != "OR" {
-		logic = "AND"
-	}
+	logic := normalizeLogic(filters.Logic)
 
 	conditions := []bson.M{}
 	for _, condition := range filters.Conditions {
 		if condition.Field == "" {
 			continue
 		}
-
-		operator := strings.ToUpper(strings.TrimSpace(condition.Operator))
-		
-		switch operator {
-		case "=":
-			conditions = append(conditions, bson.M{condition.Field: condition.Value})
-		case "!=":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$ne": condition.Value}})
-		case "<":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$lt": condition.Value}})
-		case ">":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$gt": condition.Value}})
-		case "<=":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$lte": condition.Value}})
-		case ">=":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$gte": condition.Value}})
-		case "LIKE":
-			// MongoDB 使用正则表达式实现 LIKE
-			pattern := strings.ReplaceAll(condition.Value, "%", ".*")
-			pattern = strings.ReplaceAll(pattern, "_", ".")
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$regex": pattern, "$options": "i"}})
-		case "NOT LIKE":
-			pattern := strings.ReplaceAll(condition.Value, "%", ".*")
-			pattern = strings.ReplaceAll(pattern, "_", ".")
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$not": bson.M{"$regex": pattern, "$options": "i"}}})
-		case "IN":
-			values := condition.Values
-			if len(values) == 0 && condition.Value != "" {
-				values = strings.Split(condition.Value, ",")
-				for i := range values {
-					values[i] = strings.TrimSpace(values[i])
-				}
-			}
-			if len(values) > 0 {
-				conditions = append(conditions, bson.M{condition.Field: bson.M{"$in": values}})
-			}
-		case "NOT IN":
-			values := condition.Values
-			if len(values) == 0 && condition.Value != "" {
-				values = strings.Split(condition.Value, ",")
-				for i := range values {
-					values[i] = strings.TrimSpace(values[i])
-				}
-			}
-			if len(values) > 0 {
-				conditions = append(conditions, bson.M{condition.Field: bson.M{"$nin": values}})
-			}
-		case "IS NULL":
-			conditions = append(conditions, bson.M{condition.Field: nil})
-		case "IS NOT NULL":
-			conditions = append(conditions, bson.M{condition.Field: bson.M{"$ne": nil}})
-		default:
-			// 默认使用 = 操作符
-			if condition.Value != "" {
-				conditions = append(conditions, bson.M{condition.Field: condition.Value})
-			}
+		cond, err := convertConditionToMongoDB(condition, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if cond != nil {
+			conditions = append(conditions, cond)
 		}
 	}
 
-	if len(conditions) == 0 {
-		return bson.M{}
+	for i := range filters.Groups {
+		if filters.Groups[i].IsEmpty() {
+			continue
+		}
+		cond, err := convertFiltersToMongoDB(&filters.Groups[i])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
 	}
 
-	if logic == "OR" {
-		return bson.M{"$or": conditions}
+	switch {
+	case len(conditions) == 0:
+		return bson.M{}, nil
+	case len(conditions) == 1:
+		return conditions[0], nil
+	case logic == "OR":
+		return bson.M{"$or": conditions}, nil
+	default:
+		// 使用 $and 组合，避免同一字段的多个条件互相覆盖
+		return bson.M{"$and": conditions}, nil
 	}
-	// AND 逻辑：合并所有条件
-	result := bson.M{}
-	for _, cond := range conditions {
-		for k, v := range cond {
-			result[k] = v
+}
+
+// convertConditionToMongoDB 将单个过滤条件转换为 MongoDB 查询条件，无法生成条件时返回 nil
+func convertConditionToMongoDB(condition FilterCondition, now time.Time) (bson.M, error) {
+	field := condition.Field
+	operator := strings.ToUpper(strings.TrimSpace(condition.Operator))
+
+	switch operator {
+	case "=":
+		return bson.M{field: condition.Value}, nil
+	case "!=":
+		return bson.M{field: bson.M{"$ne": condition.Value}}, nil
+	case "<":
+		return bson.M{field: bson.M{"$lt": condition.Value}}, nil
+	case ">":
+		return bson.M{field: bson.M{"$gt": condition.Value}}, nil
+	case "<=":
+		return bson.M{field: bson.M{"$lte": condition.Value}}, nil
+	case ">=":
+		return bson.M{field: bson.M{"$gte": condition.Value}}, nil
+	case "LIKE":
+		// MongoDB 使用正则表达式实现 LIKE
+		pattern := strings.ReplaceAll(condition.Value, "%", ".*")
+		pattern = strings.ReplaceAll(pattern, "_", ".")
+		return bson.M{field: bson.M{"$regex": pattern, "$options": "i"}}, nil
+	case "NOT LIKE":
+		pattern := strings.ReplaceAll(condition.Value, "%", ".*")
+		pattern = strings.ReplaceAll(pattern, "_", ".")
+		return bson.M{field: bson.M{"$not": bson.M{"$regex": pattern, "$options": "i"}}}, nil
+	case "IN", "NOT IN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if operator == "IN" {
+			return bson.M{field: bson.M{"$in": values}}, nil
+		}
+		return bson.M{field: bson.M{"$nin": values}}, nil
+	case "IS NULL":
+		return bson.M{field: nil}, nil
+	case "IS NOT NULL":
+		return bson.M{field: bson.M{"$ne": nil}}, nil
+	case "BETWEEN", "NOT BETWEEN":
+		values := conditionValues(condition)
+		if len(values) == 0 {
+			return nil, nil
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("%s requires two values for field %s", operator, field)
+		}
+		between := bson.M{"$gte": values[0], "$lte": values[1]}
+		if operator == "NOT BETWEEN" {
+			return bson.M{field: bson.M{"$not": between}}, nil
+		}
+		return bson.M{field: between}, nil
+	case "CONTAINS", "NOT CONTAINS", "STARTS WITH", "ENDS WITH":
+		if condition.Value == "" {
+			return nil, nil
 		}
+		pattern := regexp.QuoteMeta(condition.Value)
+		switch operator {
+		case "STARTS WITH":
+			pattern = "^" + pattern
+		case "ENDS WITH":
+			pattern = pattern + "$"
+		}
+		regex := bson.M{"$regex": pattern, "$options": "i"}
+		if operator == "NOT CONTAINS" {
+			return bson.M{field: bson.M{"$not": regex}}, nil
+		}
+		return bson.M{field: regex}, nil
+	case "REGEX", "NOT REGEX":
+		if condition.Value == "" {
+			return nil, nil
+		}
+		if operator == "NOT REGEX" {
+			return bson.M{field: bson.M{"$not": bson.M{"$regex": condition.Value}}}, nil
+		}
+		return bson.M{field: bson.M{"$regex": condition.Value}}, nil
+	case "LAST", "NEXT", "TODAY", "YESTERDAY", "THIS WEEK", "THIS MONTH", "THIS YEAR":
+		start, end, err := RelativeDateRange(operator, condition.Value, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date range for field %s: %w", field, err)
+		}
+		return bson.M{field: bson.M{"$gte": start, "$lt": end}}, nil
+	default:
+		// 默认使用 = 操作符
+		if condition.Value == "" {
+			return nil, nil
+		}
+		return bson.M{field: condition.Value}, nil
 	}
-	return result
 }
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (m *MongoDB) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return m.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (m *MongoDB) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	if m.client == nil {
 		return nil, 0, nil, fmt.Errorf("数据库未连接")
 	}
@@ -514,9 +620,9 @@ func (m *MongoDB) GetTableDataByID(tableName string, primaryKey string, lastId i
 	}
 
 	// 将过滤条件转换为 MongoDB 查询
-	filter := bson.M{}
-	if filters != nil && len(filters.Conditions) > 0 {
-		filter = convertFiltersToMongoDB(filters)
+	filter, err := convertFiltersToMongoDB(filters)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to build filter: %w", err)
 	}
 
 	// 合并ID条件
@@ -545,7 +651,7 @@ func (m *MongoDB) GetTableDataByID(tableName string, primaryKey string, lastId i
 	}
 
 	// 获取总数
-	total, err := collection.CountDocuments(m.ctx, filter)
+	total, err := collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
@@ -578,17 +684,17 @@ func (m *MongoDB) GetTableDataByID(tableName string, primaryKey string, lastId i
 	}
 
 	opts := options.Find().SetSort(sort).SetLimit(int64(pageSize))
-	cursor, err := collection.Find(m.ctx, filter, opts)
+	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
 	}
-	defer cursor.Close(m.ctx)
+	defer cursor.Close(ctx)
 
 	var results = make([]map[string]interface{}, 0)
 	var nextId interface{} = nil
 	var firstId interface{} = nil
 
-	for cursor.Next(m.ctx) {
+	for cursor.Next(ctx) {
 		var doc bson.M
 		if err := cursor.Decode(&doc); err != nil {
 			return nil, 0, nil, err
@@ -628,6 +734,188 @@ func (m *MongoDB) GetTableDataByID(tableName string, primaryKey string, lastId i
 	return results, total, nextId, cursor.Err()
 }
 
+// GetTableDataSorted 获取集合数据（分页 + 多列排序）
+// MongoDB 中 null 和缺失字段始终视为最小值，Nulls 设置不生效
+func (m *MongoDB) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if m.client == nil {
+		return nil, 0, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return nil, 0, fmt.Errorf("database not selected")
+	}
+	if err := ValidateSorts(sorts); err != nil {
+		return nil, 0, err
+	}
+
+	collection := m.database.Collection(tableName)
+
+	filter, err := convertFiltersToMongoDB(filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build filter: %w", err)
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
+	}
+
+	opts := options.Find().SetSkip(int64((page - 1) * pageSize)).SetLimit(int64(pageSize))
+	if len(sorts) > 0 {
+		opts.SetSort(convertSortsToMongoDB(sorts, false))
+	}
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results, err := decodeMongoDocuments(ctx, cursor)
+	return results, total, err
+}
+
+// GetTableDataByCursor 基于游标获取集合数据（keyset 分页，支持非主键排序）
+func (m *MongoDB) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursorValues []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if m.client == nil {
+		return nil, 0, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return nil, 0, fmt.Errorf("database not selected")
+	}
+	if primaryKey == "" {
+		primaryKey = "_id"
+	}
+	reverse := direction == "prev"
+	if reverse && len(cursorValues) == 0 {
+		return nil, 0, fmt.Errorf("cursor is required for previous page")
+	}
+
+	keys := SortKeys(sorts, primaryKey)
+	if err := ValidateSorts(keys); err != nil {
+		return nil, 0, err
+	}
+	if len(cursorValues) > 0 && len(cursorValues) != len(keys) {
+		return nil, 0, fmt.Errorf("cursor has %d values, expected %d", len(cursorValues), len(keys))
+	}
+
+	collection := m.database.Collection(tableName)
+
+	filter, err := convertFiltersToMongoDB(filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build filter: %w", err)
+	}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
+	}
+
+	// 过滤条件与游标条件使用 $and 组合，避免字段名冲突
+	if len(cursorValues) > 0 {
+		filter = bson.M{"$and": []bson.M{filter, buildMongoKeysetFilter(keys, cursorValues, reverse)}}
+	}
+
+	opts := options.Find().SetSort(convertSortsToMongoDB(keys, reverse)).SetLimit(int64(pageSize))
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	results, err := decodeMongoDocuments(ctx, cursor)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if reverse {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	return results, total, nil
+}
+
+// convertSortsToMongoDB 将排序条件转换为 MongoDB 的 sort 文档
+func convertSortsToMongoDB(sorts []SortSpec, reverse bool) bson.D {
+	sortDoc := bson.D{}
+	for _, s := range sorts {
+		order := 1
+		if s.IsDesc() != reverse {
+			order = -1
+		}
+		sortDoc = append(sortDoc, bson.E{Key: s.Column, Value: order})
+	}
+	return sortDoc
+}
+
+// buildMongoKeysetFilter 构建 keyset 分页条件
+// 与 BuildKeysetCondition 相同的展开方式：前 i 个键相等，第 i 个键严格位于游标之后
+func buildMongoKeysetFilter(keys []SortSpec, cursorValues []interface{}, reverse bool) bson.M {
+	values := make([]interface{}, len(cursorValues))
+	for i, v := range cursorValues {
+		values[i] = v
+		// 结果中的 ObjectID 已转换为十六进制字符串，比较前需要还原
+		if idStr, ok := v.(string); ok && keys[i].Column == "_id" {
+			if oid, err := primitive.ObjectIDFromHex(idStr); err == nil {
+				values[i] = oid
+			}
+		}
+	}
+
+	terms := []bson.M{}
+	for i := range keys {
+		parts := []bson.M{}
+		for j := 0; j < i; j++ {
+			parts = append(parts, bson.M{keys[j].Column: values[j]})
+		}
+
+		column := keys[i].Column
+		desc := keys[i].IsDesc() != reverse
+		if values[i] == nil {
+			if desc {
+				// 降序时 null 排在最后，之后不存在更多的值
+				continue
+			}
+			parts = append(parts, bson.M{column: bson.M{"$ne": nil}})
+		} else if desc {
+			parts = append(parts, bson.M{"$or": []bson.M{
+				{column: bson.M{"$lt": values[i]}},
+				{column: nil},
+			}})
+		} else {
+			parts = append(parts, bson.M{column: bson.M{"$gt": values[i]}})
+		}
+		terms = append(terms, bson.M{"$and": parts})
+	}
+
+	if len(terms) == 0 {
+		// 游标已是最后一个文档
+		return bson.M{"_id": bson.M{"$exists": false}}
+	}
+	return bson.M{"$or": terms}
+}
+
+// decodeMongoDocuments 读取游标中的所有文档，ObjectID 转换为十六进制字符串
+func decodeMongoDocuments(ctx context.Context, cursor *mongo.Cursor) ([]map[string]interface{}, error) {
+	var results = make([]map[string]interface{}, 0)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		result := make(map[string]interface{})
+		for key, value := range doc {
+			if oid, ok := value.(primitive.ObjectID); ok {
+				result[key] = oid.Hex()
+			} else {
+				result[key] = value
+			}
+		}
+		results = append(results, result)
+	}
+	return results, cursor.Err()
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (m *MongoDB) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if m.client == nil {
@@ -733,3 +1021,163 @@ func BuildMongoDBDSN(info ConnectionInfo) string {
 	return dsn
 }
 
+// UpdateRows 按 _id 等字段更新文档，编辑后的字符串值按原字段类型转换，只更新发生变化的字段
+func (m *MongoDB) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	if m.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return 0, fmt.Errorf("database not selected")
+	}
+	filter, err := mongoKeyFilter(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	collection := m.database.Collection(tableName)
+	var original bson.M
+	if err := collection.FindOne(ctx, filter).Decode(&original); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to load document: %w", err)
+	}
+
+	set := bson.M{}
+	for field, value := range values {
+		if field == "_id" {
+			continue
+		}
+		if dt, ok := original[field].(primitive.DateTime); ok {
+			if str, ok := value.(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+					if !t.Equal(dt.Time()) {
+						set[field] = primitive.NewDateTimeFromTime(t)
+					}
+					continue
+				}
+			}
+		}
+		if coerced, changed := coerceToOriginal(original[field], value); changed {
+			set[field] = coerced
+		}
+	}
+	if len(set) == 0 {
+		return 0, nil
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update documents: %w", err)
+	}
+	return result.MatchedCount, nil
+}
+
+// DeleteRows 按 _id 等字段删除文档
+func (m *MongoDB) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	if m.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return 0, fmt.Errorf("database not selected")
+	}
+	filter, err := mongoKeyFilter(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := m.database.Collection(tableName).DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete documents: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// SnapshotRows 按键读取文档，字段值保留 BSON 类型（包括 ObjectID），用于撤销行修改
+func (m *MongoDB) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return nil, fmt.Errorf("database not selected")
+	}
+	filter, err := mongoKeyFilter(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := m.database.Collection(tableName).Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load documents: %w", err)
+	}
+	defer cursor.Close(ctx)
+	results := make([]map[string]interface{}, 0)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+		results = append(results, doc)
+	}
+	return results, cursor.Err()
+}
+
+// InsertRow 插入文档，编辑表单提交的字符串值按示例文档推断的字段类型转换，返回新文档的 _id
+func (m *MongoDB) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	if m.client == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if m.database == nil {
+		return nil, fmt.Errorf("database not selected")
+	}
+
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		types[col.Name] = col.Type
+	}
+
+	doc := bson.M{}
+	for field, value := range values {
+		str, isString := value.(string)
+		switch {
+		case field == "_id" && isString:
+			if oid, err := primitive.ObjectIDFromHex(str); err == nil {
+				value = oid
+			}
+		case types[field] == "primitive.DateTime" && isString:
+			if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				value = primitive.NewDateTimeFromTime(t)
+			}
+		default:
+			value = coerceToColumnType(types[field], value)
+		}
+		doc[field] = value
+	}
+
+	result, err := m.database.Collection(tableName).InsertOne(ctx, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert document: %w", err)
+	}
+	id := result.InsertedID
+	if oid, ok := id.(primitive.ObjectID); ok {
+		id = oid.Hex()
+	}
+	return map[string]interface{}{"_id": id}, nil
+}
+
+// mongoKeyFilter 将定位文档的键转换为查询条件，十六进制字符串形式的 _id 还原为 ObjectID
+func mongoKeyFilter(keys map[string]interface{}) (bson.M, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key fields to identify documents")
+	}
+	filter := bson.M{}
+	for field, value := range keys {
+		if idStr, ok := value.(string); ok && field == "_id" {
+			if oid, err := primitive.ObjectIDFromHex(idStr); err == nil {
+				value = oid
+			}
+		}
+		filter[field] = value
+	}
+	return filter, nil
+}
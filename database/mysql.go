@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -123,6 +124,9 @@ func (m *MySQL) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 		col.Nullable = (null == "YES")
 		col.Key = key
+		col.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		// 生成列的 Extra 为 VIRTUAL GENERATED 或 STORED GENERATED（DEFAULT_GENERATED 只表示表达式默认值）
+		col.Generated = strings.HasSuffix(strings.ToUpper(extra), " GENERATED")
 		if defaultVal.Valid {
 			col.DefaultValue = defaultVal.String
 		}
@@ -134,7 +138,12 @@ func (m *MySQL) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询
 func (m *MySQL) ExecuteQuery(query string) ([]map[string]interface{}, error) {
-	rows, err := m.db.Query(query)
+	return m.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (m *MySQL) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	rows, err := m.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -172,9 +181,243 @@ func (m *MySQL) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (m *MySQL) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	rows, err := m.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (m *MySQL) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, m.db, "mysql")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (m *MySQL) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, m.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (m *MySQL) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, m.db, "mysql", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (m *MySQL) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, m.db, "mysql", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (m *MySQL) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, m.db, "mysql", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (m *MySQL) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, m.db, "mysql", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (m *MySQL) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, m.db, "mysql", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (m *MySQL) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, m.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (m *MySQL) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, m.db, query, args)
+}
+
+// TruncateTable 清空表
+func (m *MySQL) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, m.db, "mysql", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (m *MySQL) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, m.db, "mysql", tableName, columns)
+}
+
+// GetTableMetadata 从 information_schema 读取表的注释、索引、外键和检查约束
+func (m *MySQL) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	columns, err := m.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+	schema := m.dbConfig.Database
+
+	err = queryMetadata(ctx, m.db, "SELECT TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", func(rows *sql.Rows) error {
+		return rows.Scan(&meta.Comment)
+	}, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table comment: %w", err)
+	}
+
+	err = queryMetadata(ctx, m.db, "SELECT COLUMN_NAME, COLUMN_COMMENT FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_COMMENT <> ''", func(rows *sql.Rows) error {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return err
+		}
+		meta.ColumnComments[name] = comment
+		return nil
+	}, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query column comments: %w", err)
+	}
+
+	// 函数索引（8.0.13+）的 COLUMN_NAME 为 NULL
+	err = queryMetadata(ctx, m.db, `
+		SELECT INDEX_NAME, COLUMN_NAME, NON_UNIQUE, INDEX_TYPE
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME = 'PRIMARY' DESC, INDEX_NAME, SEQ_IN_INDEX`, func(rows *sql.Rows) error {
+		var name, indexType string
+		var column sql.NullString
+		var nonUnique int
+		if err := rows.Scan(&name, &column, &nonUnique, &indexType); err != nil {
+			return err
+		}
+		if !column.Valid {
+			column.String = "(expression)"
+		}
+		meta.Indexes = addIndexColumn(meta.Indexes, name, column.String, nonUnique == 0, name == "PRIMARY", indexType)
+		return nil
+	}, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	err = queryMetadata(ctx, m.db, `
+		SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE k
+		JOIN information_schema.REFERENTIAL_CONSTRAINTS r
+			ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME
+		WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ?
+		ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION`, func(rows *sql.Rows) error {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		meta.ForeignKeys = addForeignKeyColumn(meta.ForeignKeys, name, column, refTable, refColumn, onUpdate, onDelete)
+		return nil
+	}, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+
+	// CHECK_CONSTRAINTS 在 MySQL 8.0.16 之前不存在，查询失败时视为没有检查约束
+	checks := []CheckConstraintInfo{}
+	err = queryMetadata(ctx, m.db, `
+		SELECT c.CONSTRAINT_NAME, c.CHECK_CLAUSE
+		FROM information_schema.CHECK_CONSTRAINTS c
+		JOIN information_schema.TABLE_CONSTRAINTS t
+			ON t.CONSTRAINT_SCHEMA = c.CONSTRAINT_SCHEMA AND t.CONSTRAINT_NAME = c.CONSTRAINT_NAME
+		WHERE t.TABLE_SCHEMA = ? AND t.TABLE_NAME = ? AND t.CONSTRAINT_TYPE = 'CHECK'
+		ORDER BY c.CONSTRAINT_NAME`, func(rows *sql.Rows) error {
+		var check CheckConstraintInfo
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return err
+		}
+		checks = append(checks, check)
+		return nil
+	}, schema, tableName)
+	if err == nil {
+		meta.Checks = checks
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return meta, nil
+}
+
+// GetViews 获取当前数据库中的视图
+func (m *MySQL) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	views := []ViewInfo{}
+	err := queryMetadata(ctx, m.db, "SELECT TABLE_NAME, VIEW_DEFINITION FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME", func(rows *sql.Rows) error {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return err
+		}
+		views = append(views, view)
+		return nil
+	}, m.dbConfig.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines 获取当前数据库中的存储过程和函数，定义为例程体（不含参数列表）
+func (m *MySQL) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	routines := []RoutineInfo{}
+	err := queryMetadata(ctx, m.db, `
+		SELECT ROUTINE_NAME, ROUTINE_TYPE, DTD_IDENTIFIER, ROUTINE_DEFINITION
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ?
+		ORDER BY ROUTINE_TYPE, ROUTINE_NAME`, func(rows *sql.Rows) error {
+		var routine RoutineInfo
+		var returnType, definition sql.NullString
+		if err := rows.Scan(&routine.Name, &routine.Type, &returnType, &definition); err != nil {
+			return err
+		}
+		routine.ReturnType, routine.Definition = returnType.String, definition.String
+		routines = append(routines, routine)
+		return nil
+	}, m.dbConfig.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query routines: %w", err)
+	}
+	return routines, nil
+}
+
+// GetTriggers 获取表的触发器，tableName 为空时返回所有表的触发器
+func (m *MySQL) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	triggers := []TriggerInfo{}
+	err := queryMetadata(ctx, m.db, `
+		SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE, ACTION_TIMING, EVENT_MANIPULATION, ACTION_STATEMENT
+		FROM information_schema.TRIGGERS
+		WHERE TRIGGER_SCHEMA = ? AND (? = '' OR EVENT_OBJECT_TABLE = ?)
+		ORDER BY EVENT_OBJECT_TABLE, ACTION_ORDER`, func(rows *sql.Rows) error {
+		var trigger TriggerInfo
+		if err := rows.Scan(&trigger.Name, &trigger.Table, &trigger.Timing, &trigger.Event, &trigger.Definition); err != nil {
+			return err
+		}
+		triggers = append(triggers, trigger)
+		return nil
+	}, m.dbConfig.Database, tableName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// ExplainQuery 使用 EXPLAIN FORMAT=JSON 获取执行计划（不支持 analyze）
+func (m *MySQL) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if m.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	var raw string
+	if err := m.db.QueryRowContext(ctx, "EXPLAIN FORMAT=JSON "+query).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return parseMySQLExplain(raw)
+}
+
 // ExecuteUpdate 执行更新
 func (m *MySQL) ExecuteUpdate(query string) (int64, error) {
-	result, err := m.db.Exec(query)
+	return m.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (m *MySQL) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	result, err := m.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -183,7 +426,12 @@ func (m *MySQL) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (m *MySQL) ExecuteDelete(query string) (int64, error) {
-	result, err := m.db.Exec(query)
+	return m.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (m *MySQL) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	result, err := m.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -192,7 +440,12 @@ func (m *MySQL) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (m *MySQL) ExecuteInsert(query string) (int64, error) {
-	result, err := m.db.Exec(query)
+	return m.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (m *MySQL) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := m.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -201,6 +454,11 @@ func (m *MySQL) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (m *MySQL) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return m.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (m *MySQL) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("mysql", tableName, filters)
 	if err != nil {
@@ -213,7 +471,7 @@ func (m *MySQL) GetTableData(tableName string, page, pageSize int, filters *Filt
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := m.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := m.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -225,7 +483,7 @@ func (m *MySQL) GetTableData(tableName string, page, pageSize int, filters *Filt
 	}
 	query += fmt.Sprintf(" LIMIT %d OFFSET %d", pageSize, offset)
 
-	rows, err := m.db.Query(query, whereArgs...)
+	rows, err := m.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -267,6 +525,11 @@ func (m *MySQL) GetTableData(tableName string, page, pageSize int, filters *Filt
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (m *MySQL) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return m.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (m *MySQL) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("mysql", tableName, filters)
 	if err != nil {
@@ -279,7 +542,7 @@ func (m *MySQL) GetTableDataByID(tableName string, primaryKey string, lastId int
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := m.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := m.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -330,7 +593,7 @@ func (m *MySQL) GetTableDataByID(tableName string, primaryKey string, lastId int
 		query += fmt.Sprintf(" ORDER BY `%s` ASC LIMIT %d", primaryKey, pageSize)
 	}
 	
-	rows, err = m.db.Query(query, queryArgs...)
+	rows, err = m.db.QueryContext(ctx, query, queryArgs...)
 	
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
@@ -392,6 +655,21 @@ func (m *MySQL) GetTableDataByID(tableName string, primaryKey string, lastId int
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (m *MySQL) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return querySortedTableData(ctx, m.db, "mysql", fmt.Sprintf("`%s`", tableName), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (m *MySQL) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return queryTableDataByCursor(ctx, m.db, "mysql", fmt.Sprintf("`%s`", tableName), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (m *MySQL) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, m.db, "mysql", fmt.Sprintf("`%s`", tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (m *MySQL) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if page <= 1 {
@@ -140,6 +140,9 @@ func (m *BaseMysqlBasedDialect) GetTableColumns(tableName string) ([]ColumnInfo,
 				col.Key = fmt.Sprint(v)
 			}
 		}
+		if v, ok := r["Extra"]; ok {
+			col.AutoIncrement = strings.Contains(strings.ToLower(fmt.Sprintf("%s", v)), "auto_increment")
+		}
 		if v, ok := r["Default"]; ok {
 			switch dv := v.(type) {
 			case nil:
@@ -46,6 +46,7 @@ func (m *MysqlBasedDamengDialect) getColumnsFromSchema(schema string) ([]ColumnI
 		// 检查是否包含 IDENTITY，如果包含则标记为主键
 		if identityReg.MatchString(typeAndAttrs) {
 			col.Key = "PRI"
+			col.AutoIncrement = true
 			// 从类型字符串中移除 IDENTITY 部分
 			typeAndAttrs = identityReg.ReplaceAllString(typeAndAttrs, "")
 			typeAndAttrs = strings.TrimSpace(typeAndAttrs)
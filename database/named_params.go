@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParameterizedDatabase 支持执行带绑定参数语句的数据库接口扩展
+// args 通过驱动绑定，不会拼接到 SQL 中；占位符使用各方言的写法（见 BindNamedParameters）
+type ParameterizedDatabase interface {
+	// QueryArgsContext 执行带参数的查询
+	QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecArgsContext 执行带参数的更新类语句，返回影响行数
+	ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error)
+}
+
+// namedParameter 语句中的一个 :name 占位符
+type namedParameter struct {
+	name       string
+	start, end int
+}
+
+// scanNamedParameters 找出语句中的 :name 占位符
+// 基于 tokenizeSQL 的词法单元，跳过字符串、带引号的标识符、注释和 $tag$ 字符串，
+// 以及 PostgreSQL 的 :: 类型转换和方括号内的数组切片（如 arr[a:b]）
+func scanNamedParameters(dbType, query string) []namedParameter {
+	var params []namedParameter
+	tokens := tokenizeSQL(dbType, query)
+	for i := 0; i+1 < len(tokens); i++ {
+		colon, next := tokens[i], tokens[i+1]
+		if colon.kind != sqlTokenPunct || colon.text != ":" || next.pos != colon.pos+1 {
+			continue
+		}
+		if next.kind == sqlTokenPunct && next.text == ":" {
+			// :: 类型转换
+			i++
+			continue
+		}
+		if next.kind != sqlTokenWord {
+			continue
+		}
+		n := 0
+		for n < len(next.text) && isParameterChar(next.text[n], n == 0) {
+			n++
+		}
+		if n > 0 {
+			params = append(params, namedParameter{name: next.text[:n], start: colon.pos, end: next.pos + n})
+			i++
+		}
+	}
+	return params
+}
+
+// isParameterChar 判断是否为参数名的字符，参数名不能以数字开头
+func isParameterChar(c byte, first bool) bool {
+	if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+// SQLParameterNames 返回语句中 :name 占位符的参数名（去重，按出现顺序）
+// 保存的查询不属于某个连接，按标准 SQL 的词法规则识别
+func SQLParameterNames(query string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, p := range scanNamedParameters("", query) {
+		if !seen[p.name] {
+			seen[p.name] = true
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// bindPlaceholder 返回各方言驱动的绑定参数占位符
+func bindPlaceholder(dbType string, index int) string {
+	switch dbType {
+	case "postgresql":
+		return "$" + strconv.Itoa(index)
+	case "sqlserver":
+		return "@p" + strconv.Itoa(index)
+	case "oracle":
+		return ":" + strconv.Itoa(index)
+	default:
+		return "?"
+	}
+}
+
+// BindNamedParameters 把语句中的 :name 占位符替换为当前方言的绑定参数占位符，返回改写后的语句和按顺序排列的参数值
+// params 中缺少语句用到的参数时返回错误
+func BindNamedParameters(dbType, query string, params map[string]interface{}) (string, []interface{}, error) {
+	found := scanNamedParameters(dbType, query)
+	if len(found) == 0 {
+		return query, nil, nil
+	}
+
+	// PostgreSQL 和 SQL Server 按编号绑定，同名参数复用同一个编号；其余方言按出现顺序绑定
+	reuse := dbType == "postgresql" || dbType == "sqlserver"
+	indexes := make(map[string]int)
+	var args []interface{}
+	var b strings.Builder
+	last := 0
+	for _, p := range found {
+		value, ok := params[p.name]
+		if !ok {
+			return "", nil, fmt.Errorf("missing value for parameter :%s", p.name)
+		}
+		index, bound := indexes[p.name]
+		if !reuse || !bound {
+			args = append(args, value)
+			index = len(args)
+			indexes[p.name] = index
+		}
+		b.WriteString(query[last:p.start])
+		b.WriteString(bindPlaceholder(dbType, index))
+		last = p.end
+	}
+	b.WriteString(query[last:])
+	return b.String(), args, nil
+}
+
+// querySQLArgs 执行带参数的查询
+func querySQLArgs(ctx context.Context, db sqlQueryer, query string, args []interface{}) ([]map[string]interface{}, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return scanRowMaps(rows)
+}
+
+// execSQLArgs 执行带参数的更新类语句
+func execSQLArgs(ctx context.Context, db sqlExecer, query string, args []interface{}) (int64, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute statement: %w", err)
+	}
+	return result.RowsAffected()
+}
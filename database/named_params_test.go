@@ -0,0 +1,61 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindNamedParameters(t *testing.T) {
+	query := "SELECT * FROM orders -- :ignored\nWHERE user_id = :user_id AND note <> ':text' AND created::date > :since OR owner = :user_id /* :c */"
+	params := map[string]interface{}{"user_id": 7, "since": "2024-01-01"}
+
+	if got, want := SQLParameterNames(query), []string{"user_id", "since"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		dbType string
+		query  string
+		args   []interface{}
+	}{
+		{"mysql", "SELECT * FROM orders -- :ignored\nWHERE user_id = ? AND note <> ':text' AND created::date > ? OR owner = ? /* :c */", []interface{}{7, "2024-01-01", 7}},
+		{"postgresql", "SELECT * FROM orders -- :ignored\nWHERE user_id = $1 AND note <> ':text' AND created::date > $2 OR owner = $1 /* :c */", []interface{}{7, "2024-01-01"}},
+		{"oracle", "SELECT * FROM orders -- :ignored\nWHERE user_id = :1 AND note <> ':text' AND created::date > :2 OR owner = :3 /* :c */", []interface{}{7, "2024-01-01", 7}},
+	}
+	for _, tt := range tests {
+		bound, args, err := BindNamedParameters(tt.dbType, query, params)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dbType, err)
+		}
+		if bound != tt.query || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: got %q %v, want %q %v", tt.dbType, bound, args, tt.query, tt.args)
+		}
+	}
+
+	if _, _, err := BindNamedParameters("mysql", "SELECT :a, :b", map[string]interface{}{"a": 1}); err == nil {
+		t.Error("expected an error for a missing parameter")
+	}
+}
+
+func TestSQLParameterNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"类型转换", "SELECT :id::int, x::text FROM t", []string{"id"}},
+		{"数组切片", "SELECT arr[lo:hi], arr[1:2] FROM t WHERE id = :id", []string{"id"}},
+		{"$tag$ 字符串", "DO $body$ BEGIN PERFORM :skipped; END $body$; SELECT :a", []string{"a"}},
+		{"$$ 字符串", "SELECT $$ :skipped $$, :b", []string{"b"}},
+		{"MySQL 变量赋值", "SET @x := :value", []string{"value"}},
+		{"参数名不能以数字开头", "SELECT :1, :_p1", []string{"_p1"}},
+		{"冒号与参数名之间有空格", "SELECT a : b", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SQLParameterNames(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("names = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
 	_ "github.com/sijms/go-ora/v2"
 )
@@ -109,18 +112,19 @@ func (o *Oracle) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 			END as data_type,
 			nullable,
 			data_default,
+			virtual_column,
 			CASE 
 				WHEN constraint_type = 'P' THEN 'PRI'
 				ELSE ''
 			END as key_type
-		FROM user_tab_columns
+		FROM user_tab_cols
 		LEFT JOIN (
 			SELECT cols.column_name, cons.constraint_type
 			FROM user_constraints cons
 			INNER JOIN user_cons_columns cols ON cons.constraint_name = cols.constraint_name
 			WHERE cons.table_name = UPPER('%s') AND cons.constraint_type = 'P'
-		) pk ON user_tab_columns.column_name = pk.column_name
-		WHERE table_name = UPPER('%s')
+		) pk ON user_tab_cols.column_name = pk.column_name
+		WHERE table_name = UPPER('%s') AND hidden_column = 'NO'
 		ORDER BY column_id
 	`, tableName, tableName)
 
@@ -135,13 +139,17 @@ func (o *Oracle) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 		var col ColumnInfo
 		var nullable string
 		var defaultVal sql.NullString
+		var virtual string
 		var keyType sql.NullString
 
-		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &keyType); err != nil {
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &virtual, &keyType); err != nil {
 			return nil, err
 		}
 
 		col.Nullable = (nullable == "Y")
+		// 标识列的默认值为 "ISEQ$$_xxx".nextval，使用序列默认值的列同样由数据库生成
+		col.AutoIncrement = strings.Contains(strings.ToLower(defaultVal.String), ".nextval")
+		col.Generated = virtual == "YES"
 		if keyType.Valid {
 			col.Key = keyType.String
 		}
@@ -151,12 +159,42 @@ func (o *Oracle) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 		columns = append(columns, col)
 	}
-	return columns, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	o.markAlwaysIdentityColumns(tableName, columns)
+	return columns, nil
+}
+
+// markAlwaysIdentityColumns 把 GENERATED ALWAYS 标识列标记为生成列：Oracle 不允许为这类列指定值
+// user_tab_identity_cols 从 12c 开始提供，查询失败时忽略
+func (o *Oracle) markAlwaysIdentityColumns(tableName string, columns []ColumnInfo) {
+	rows, err := o.db.Query("SELECT column_name FROM user_tab_identity_cols WHERE table_name = UPPER(:1) AND generation_type = 'ALWAYS'", tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) != nil {
+			return
+		}
+		for i := range columns {
+			if columns[i].Name == name {
+				columns[i].Generated = true
+			}
+		}
+	}
 }
 
 // ExecuteQuery 执行查询
 func (o *Oracle) ExecuteQuery(query string) ([]map[string]interface{}, error) {
-	rows, err := o.db.Query(query)
+	return o.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (o *Oracle) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	rows, err := o.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -194,9 +232,321 @@ func (o *Oracle) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (o *Oracle) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	rows, err := o.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (o *Oracle) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, o.db, "oracle")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (o *Oracle) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, o.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (o *Oracle) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, o.db, "oracle", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (o *Oracle) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, o.db, "oracle", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (o *Oracle) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, o.db, "oracle", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (o *Oracle) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, o.db, "oracle", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (o *Oracle) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, o.db, "oracle", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (o *Oracle) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, o.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (o *Oracle) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, o.db, query, args)
+}
+
+// TruncateTable 清空表
+func (o *Oracle) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, o.db, "oracle", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (o *Oracle) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, o.db, "oracle", tableName, columns)
+}
+
+// GetTableMetadata 从 user_* 数据字典视图读取表的注释、索引、外键和检查约束
+func (o *Oracle) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	columns, err := o.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+
+	err = queryMetadata(ctx, o.db, "SELECT comments FROM user_tab_comments WHERE table_name = UPPER(:1)", func(rows *sql.Rows) error {
+		var comment sql.NullString
+		if err := rows.Scan(&comment); err != nil {
+			return err
+		}
+		meta.Comment = comment.String
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table comment: %w", err)
+	}
+
+	err = queryMetadata(ctx, o.db, "SELECT column_name, comments FROM user_col_comments WHERE table_name = UPPER(:1) AND comments IS NOT NULL", func(rows *sql.Rows) error {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return err
+		}
+		meta.ColumnComments[name] = comment
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query column comments: %w", err)
+	}
+
+	// 函数索引的列名为系统生成的虚拟列（SYS_NC...）
+	err = queryMetadata(ctx, o.db, `
+		SELECT i.index_name, c.column_name, i.uniqueness, i.index_type, NVL2(p.constraint_name, 1, 0) AS is_primary
+		FROM user_indexes i
+		JOIN user_ind_columns c ON c.index_name = i.index_name
+		LEFT JOIN user_constraints p ON p.index_name = i.index_name AND p.table_name = i.table_name AND p.constraint_type = 'P'
+		WHERE i.table_name = UPPER(:1)
+		ORDER BY is_primary DESC, i.index_name, c.column_position`, func(rows *sql.Rows) error {
+		var name, column, uniqueness, indexType string
+		var primary int
+		if err := rows.Scan(&name, &column, &uniqueness, &indexType, &primary); err != nil {
+			return err
+		}
+		meta.Indexes = addIndexColumn(meta.Indexes, name, column, uniqueness == "UNIQUE", primary == 1, indexType)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	// Oracle 的外键没有 ON UPDATE 动作
+	err = queryMetadata(ctx, o.db, `
+		SELECT c.constraint_name, cc.column_name, r.table_name, rc.column_name, c.delete_rule
+		FROM user_constraints c
+		JOIN user_cons_columns cc ON cc.constraint_name = c.constraint_name
+		JOIN all_constraints r ON r.owner = c.r_owner AND r.constraint_name = c.r_constraint_name
+		JOIN all_cons_columns rc ON rc.owner = r.owner AND rc.constraint_name = r.constraint_name AND rc.position = cc.position
+		WHERE c.constraint_type = 'R' AND c.table_name = UPPER(:1)
+		ORDER BY c.constraint_name, cc.position`, func(rows *sql.Rows) error {
+		var name, column, refTable, refColumn, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onDelete); err != nil {
+			return err
+		}
+		meta.ForeignKeys = addForeignKeyColumn(meta.ForeignKeys, name, column, refTable, refColumn, "", onDelete)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+
+	err = queryMetadata(ctx, o.db, `
+		SELECT constraint_name, search_condition, generated
+		FROM user_constraints
+		WHERE constraint_type = 'C' AND table_name = UPPER(:1)
+		ORDER BY constraint_name`, func(rows *sql.Rows) error {
+		var check CheckConstraintInfo
+		var generated string
+		if err := rows.Scan(&check.Name, &check.Expression, &generated); err != nil {
+			return err
+		}
+		// NOT NULL 列也以系统命名的检查约束存储，已体现在列的可空性中
+		if generated == "GENERATED NAME" && oracleNotNullCheckPattern.MatchString(check.Expression) {
+			return nil
+		}
+		meta.Checks = append(meta.Checks, check)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check constraints: %w", err)
+	}
+	return meta, nil
+}
+
+var oracleNotNullCheckPattern = regexp.MustCompile(`^"[^"]+" IS NOT NULL$`)
+
+// GetViews 获取当前用户的视图
+func (o *Oracle) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	views := []ViewInfo{}
+	err := queryMetadata(ctx, o.db, "SELECT view_name, text FROM user_views ORDER BY view_name", func(rows *sql.Rows) error {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return err
+		}
+		views = append(views, view)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines 获取当前用户的独立存储过程和函数（不含包），定义由 user_source 中的源码行拼接而成
+func (o *Oracle) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	routines := []RoutineInfo{}
+	var source strings.Builder
+	err := queryMetadata(ctx, o.db, `
+		SELECT name, type, text
+		FROM user_source
+		WHERE type IN ('PROCEDURE', 'FUNCTION')
+		ORDER BY type, name, line`, func(rows *sql.Rows) error {
+		var name, routineType string
+		var text sql.NullString
+		if err := rows.Scan(&name, &routineType, &text); err != nil {
+			return err
+		}
+		if n := len(routines); n == 0 || routines[n-1].Name != name || routines[n-1].Type != routineType {
+			if n > 0 {
+				routines[n-1].Definition = source.String()
+			}
+			source.Reset()
+			routines = append(routines, RoutineInfo{Name: name, Type: routineType})
+		}
+		source.WriteString(text.String)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query routines: %w", err)
+	}
+	if n := len(routines); n > 0 {
+		routines[n-1].Definition = source.String()
+	}
+	return routines, nil
+}
+
+// GetTriggers 获取表的触发器，tableName 为空时返回当前用户所有表的触发器
+func (o *Oracle) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	triggers := []TriggerInfo{}
+	query := `
+		SELECT trigger_name, table_name, trigger_type, triggering_event, description, trigger_body
+		FROM user_triggers
+		WHERE base_object_type = 'TABLE'`
+	args := []interface{}{}
+	if tableName != "" {
+		query += " AND table_name = UPPER(:1)"
+		args = append(args, tableName)
+	}
+	query += " ORDER BY table_name, trigger_name"
+	err := queryMetadata(ctx, o.db, query, func(rows *sql.Rows) error {
+		var trigger TriggerInfo
+		var triggerType, description, body string
+		if err := rows.Scan(&trigger.Name, &trigger.Table, &triggerType, &trigger.Event, &description, &body); err != nil {
+			return err
+		}
+		// trigger_type 形如 BEFORE EACH ROW、AFTER STATEMENT、INSTEAD OF
+		trigger.Timing = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(triggerType, " EACH ROW"), " STATEMENT"))
+		trigger.Definition = "CREATE OR REPLACE TRIGGER " + strings.TrimSpace(description) + "\n" + body
+		triggers = append(triggers, trigger)
+		return nil
+	}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// ExplainQuery 使用 EXPLAIN PLAN 把执行计划写入 plan_table，按行构建计划树，原文使用 DBMS_XPLAN.DISPLAY（不支持 analyze）
+// plan_table 是会话级的临时表，因此所有语句在同一个连接上执行
+func (o *Oracle) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if o.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	conn, err := o.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	statementID := fmt.Sprintf("sdw%d", time.Now().UnixNano())
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", statementID, query)); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "DELETE FROM plan_table WHERE statement_id = :1", statementID)
+
+	var rows []oraclePlanRow
+	err = queryMetadata(ctx, conn, `
+		SELECT id, parent_id, operation, NVL(options, ' '), NVL(object_name, ' '), cardinality, cost,
+			NVL(access_predicates, ' '), NVL(filter_predicates, ' ')
+		FROM plan_table WHERE statement_id = :1 ORDER BY id`, func(r *sql.Rows) error {
+		var row oraclePlanRow
+		var parent sql.NullInt64
+		var cardinality, cost sql.NullFloat64
+		if err := r.Scan(&row.ID, &parent, &row.Operation, &row.Options, &row.ObjectName, &cardinality, &cost, &row.AccessPredicates, &row.FilterPredicates); err != nil {
+			return err
+		}
+		if parent.Valid {
+			id := int(parent.Int64)
+			row.ParentID = &id
+		}
+		if cardinality.Valid {
+			row.Cardinality = &cardinality.Float64
+		}
+		if cost.Valid {
+			row.Cost = &cost.Float64
+		}
+		row.Options = strings.TrimSpace(row.Options)
+		row.ObjectName = strings.TrimSpace(row.ObjectName)
+		row.AccessPredicates = strings.TrimSpace(row.AccessPredicates)
+		row.FilterPredicates = strings.TrimSpace(row.FilterPredicates)
+		rows = append(rows, row)
+		return nil
+	}, statementID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var lines []string
+	err = queryMetadata(ctx, conn, "SELECT plan_table_output FROM TABLE(DBMS_XPLAN.DISPLAY('PLAN_TABLE', :1, 'TYPICAL'))", func(r *sql.Rows) error {
+		var line sql.NullString
+		if err := r.Scan(&line); err != nil {
+			return err
+		}
+		lines = append(lines, line.String)
+		return nil
+	}, statementID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to display plan: %w", err)
+	}
+	return buildOraclePlan(rows, strings.Join(lines, "\n")), nil
+}
+
 // ExecuteUpdate 执行更新
 func (o *Oracle) ExecuteUpdate(query string) (int64, error) {
-	result, err := o.db.Exec(query)
+	return o.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (o *Oracle) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	result, err := o.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -205,7 +555,12 @@ func (o *Oracle) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (o *Oracle) ExecuteDelete(query string) (int64, error) {
-	result, err := o.db.Exec(query)
+	return o.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (o *Oracle) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	result, err := o.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -214,7 +569,12 @@ func (o *Oracle) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (o *Oracle) ExecuteInsert(query string) (int64, error) {
-	result, err := o.db.Exec(query)
+	return o.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (o *Oracle) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := o.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -223,6 +583,11 @@ func (o *Oracle) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (o *Oracle) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return o.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (o *Oracle) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("oracle", tableName, filters)
 	if err != nil {
@@ -235,7 +600,7 @@ func (o *Oracle) GetTableData(tableName string, page, pageSize int, filters *Fil
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := o.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := o.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -249,7 +614,7 @@ func (o *Oracle) GetTableData(tableName string, page, pageSize int, filters *Fil
 		OFFSET %d ROWS FETCH NEXT %d ROWS ONLY
 	`, offset, pageSize)
 
-	rows, err := o.db.Query(query, whereArgs...)
+	rows, err := o.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -295,6 +660,11 @@ func (o *Oracle) GetTableData(tableName string, page, pageSize int, filters *Fil
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (o *Oracle) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return o.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (o *Oracle) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("oracle", tableName, filters)
 	if err != nil {
@@ -307,7 +677,7 @@ func (o *Oracle) GetTableDataByID(tableName string, primaryKey string, lastId in
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := o.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := o.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -322,14 +692,14 @@ func (o *Oracle) GetTableDataByID(tableName string, primaryKey string, lastId in
 		if lastId == nil {
 			return nil, 0, nil, fmt.Errorf("lastId is required for previous page")
 		}
-		idCondition = fmt.Sprintf("\"%s\" < ?", strings.ToUpper(primaryKey))
+		idCondition = buildIDCursorCondition("oracle", `"`+strings.ToUpper(primaryKey)+`"`, "<", len(whereArgs))
 		queryArgs = append(whereArgs, lastId)
 	} else {
 		if lastId == nil {
 			idCondition = ""
 			queryArgs = whereArgs
 		} else {
-			idCondition = fmt.Sprintf("\"%s\" > ?", strings.ToUpper(primaryKey))
+			idCondition = buildIDCursorCondition("oracle", `"`+strings.ToUpper(primaryKey)+`"`, ">", len(whereArgs))
 			queryArgs = append(whereArgs, lastId)
 		}
 	}
@@ -360,7 +730,7 @@ func (o *Oracle) GetTableDataByID(tableName string, primaryKey string, lastId in
 		`, strings.ToUpper(tableName), wherePart, strings.ToUpper(primaryKey), pageSize)
 	}
 	
-	rows, err = o.db.Query(query, queryArgs...)
+	rows, err = o.db.QueryContext(ctx, query, queryArgs...)
 
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
@@ -418,6 +788,21 @@ func (o *Oracle) GetTableDataByID(tableName string, primaryKey string, lastId in
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (o *Oracle) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return querySortedTableData(ctx, o.db, "oracle", fmt.Sprintf("\"%s\"", strings.ToUpper(tableName)), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (o *Oracle) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return queryTableDataByCursor(ctx, o.db, "oracle", fmt.Sprintf("\"%s\"", strings.ToUpper(tableName)), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (o *Oracle) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, o.db, "oracle", fmt.Sprintf("\"%s\"", strings.ToUpper(tableName)), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (o *Oracle) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if page <= 1 {
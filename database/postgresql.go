@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -124,6 +125,8 @@ func (p *PostgreSQL) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 			udt_name as data_type,
 			is_nullable,
 			column_default,
+			is_identity,
+			is_generated,
 			CASE 
 				WHEN constraint_type = 'PRIMARY KEY' THEN 'PRI'
 				WHEN constraint_type = 'UNIQUE' THEN 'UNI'
@@ -155,13 +158,18 @@ func (p *PostgreSQL) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 		var col ColumnInfo
 		var nullable string
 		var defaultVal sql.NullString
+		var isIdentity sql.NullString
+		var isGenerated sql.NullString
 		var keyType sql.NullString
 
-		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &keyType); err != nil {
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &isIdentity, &isGenerated, &keyType); err != nil {
 			return nil, err
 		}
 
 		col.Nullable = (nullable == "YES")
+		// 标识列或 serial 列（默认值为 nextval(...)）
+		col.AutoIncrement = isIdentity.String == "YES" || strings.HasPrefix(defaultVal.String, "nextval(")
+		col.Generated = isGenerated.String == "ALWAYS"
 		if keyType.Valid {
 			col.Key = keyType.String
 		}
@@ -176,7 +184,12 @@ func (p *PostgreSQL) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询
 func (p *PostgreSQL) ExecuteQuery(query string) ([]map[string]interface{}, error) {
-	rows, err := p.db.Query(query)
+	return p.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (p *PostgreSQL) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	rows, err := p.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -214,9 +227,263 @@ func (p *PostgreSQL) ExecuteQuery(query string) ([]map[string]interface{}, error
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (p *PostgreSQL) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (p *PostgreSQL) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, p.db, "postgresql")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (p *PostgreSQL) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, p.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (p *PostgreSQL) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, p.db, "postgresql", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (p *PostgreSQL) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, p.db, "postgresql", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (p *PostgreSQL) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, p.db, "postgresql", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (p *PostgreSQL) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, p.db, "postgresql", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (p *PostgreSQL) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, p.db, "postgresql", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (p *PostgreSQL) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, p.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (p *PostgreSQL) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, p.db, query, args)
+}
+
+// TruncateTable 清空表
+func (p *PostgreSQL) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, p.db, "postgresql", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (p *PostgreSQL) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, p.db, "postgresql", tableName, columns)
+}
+
+// GetTableMetadata 从 pg_catalog 读取表的注释、索引、外键和检查约束（public 模式）
+func (p *PostgreSQL) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	columns, err := p.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+
+	err = queryMetadata(ctx, p.db, `
+		SELECT COALESCE(obj_description(c.oid, 'pg_class'), '')
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1`, func(rows *sql.Rows) error {
+		return rows.Scan(&meta.Comment)
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table comment: %w", err)
+	}
+
+	err = queryMetadata(ctx, p.db, `
+		SELECT a.attname, col_description(a.attrelid, a.attnum)
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND c.relname = $1 AND a.attnum > 0 AND NOT a.attisdropped
+			AND col_description(a.attrelid, a.attnum) IS NOT NULL`, func(rows *sql.Rows) error {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return err
+		}
+		meta.ColumnComments[name] = comment
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query column comments: %w", err)
+	}
+
+	// pg_get_indexdef 按位置返回索引的列名或表达式
+	err = queryMetadata(ctx, p.db, `
+		SELECT i.relname, pg_get_indexdef(ix.indexrelid, k.n, true), ix.indisunique, ix.indisprimary, am.amname
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		CROSS JOIN LATERAL generate_series(1, ix.indnatts) AS k(n)
+		WHERE n.nspname = 'public' AND t.relname = $1
+		ORDER BY ix.indisprimary DESC, i.relname, k.n`, func(rows *sql.Rows) error {
+		var name, column, indexType string
+		var unique, primary bool
+		if err := rows.Scan(&name, &column, &unique, &primary, &indexType); err != nil {
+			return err
+		}
+		meta.Indexes = addIndexColumn(meta.Indexes, name, column, unique, primary, strings.ToUpper(indexType))
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	err = queryMetadata(ctx, p.db, `
+		SELECT con.conname, a.attname, rt.relname, ra.attname, con.confupdtype, con.confdeltype
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class rt ON rt.oid = con.confrelid
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+		WHERE con.contype = 'f' AND n.nspname = 'public' AND t.relname = $1
+		ORDER BY con.conname, k.ord`, func(rows *sql.Rows) error {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		meta.ForeignKeys = addForeignKeyColumn(meta.ForeignKeys, name, column, refTable, refColumn, pgForeignKeyAction(onUpdate), pgForeignKeyAction(onDelete))
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+
+	err = queryMetadata(ctx, p.db, `
+		SELECT con.conname, pg_get_constraintdef(con.oid, true)
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE con.contype = 'c' AND n.nspname = 'public' AND t.relname = $1
+		ORDER BY con.conname`, func(rows *sql.Rows) error {
+		var check CheckConstraintInfo
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return err
+		}
+		// pg_get_constraintdef 返回 "CHECK (...)"
+		check.Expression = strings.TrimSpace(strings.TrimPrefix(check.Expression, "CHECK"))
+		meta.Checks = append(meta.Checks, check)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check constraints: %w", err)
+	}
+	return meta, nil
+}
+
+// GetViews 获取 public 模式中的视图
+func (p *PostgreSQL) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	views := []ViewInfo{}
+	err := queryMetadata(ctx, p.db, "SELECT viewname, definition FROM pg_views WHERE schemaname = 'public' ORDER BY viewname", func(rows *sql.Rows) error {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return err
+		}
+		views = append(views, view)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines 获取 public 模式中的存储过程和函数（PostgreSQL 11+），定义为完整的 CREATE 语句
+func (p *PostgreSQL) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	routines := []RoutineInfo{}
+	err := queryMetadata(ctx, p.db, `
+		SELECT p.proname, CASE p.prokind WHEN 'p' THEN 'PROCEDURE' ELSE 'FUNCTION' END,
+			pg_get_function_result(p.oid), pg_get_functiondef(p.oid)
+		FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = 'public' AND p.prokind IN ('f', 'p')
+		ORDER BY 2, p.proname`, func(rows *sql.Rows) error {
+		var routine RoutineInfo
+		var returnType sql.NullString
+		if err := rows.Scan(&routine.Name, &routine.Type, &returnType, &routine.Definition); err != nil {
+			return err
+		}
+		routine.ReturnType = returnType.String
+		routines = append(routines, routine)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query routines: %w", err)
+	}
+	return routines, nil
+}
+
+// GetTriggers 获取表的触发器（不含约束内部使用的触发器），tableName 为空时返回 public 模式中所有表的触发器
+func (p *PostgreSQL) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	triggers := []TriggerInfo{}
+	err := queryMetadata(ctx, p.db, `
+		SELECT t.tgname, c.relname, t.tgtype, pg_get_triggerdef(t.oid, true)
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT t.tgisinternal AND n.nspname = 'public' AND ($1::text = '' OR c.relname = $1)
+		ORDER BY c.relname, t.tgname`, func(rows *sql.Rows) error {
+		var trigger TriggerInfo
+		var tgtype int
+		if err := rows.Scan(&trigger.Name, &trigger.Table, &tgtype, &trigger.Definition); err != nil {
+			return err
+		}
+		trigger.Timing, trigger.Event = pgTriggerType(tgtype)
+		triggers = append(triggers, trigger)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// ExplainQuery 使用 EXPLAIN (FORMAT JSON) 获取执行计划，analyze 为 true 时加上 ANALYZE（会实际执行查询）
+func (p *PostgreSQL) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if p.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	options := "FORMAT JSON"
+	if analyze {
+		options = "ANALYZE, " + options
+	}
+	var raw string
+	if err := p.db.QueryRowContext(ctx, "EXPLAIN ("+options+") "+query).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return parsePostgresExplain(raw, analyze)
+}
+
 // ExecuteUpdate 执行更新
 func (p *PostgreSQL) ExecuteUpdate(query string) (int64, error) {
-	result, err := p.db.Exec(query)
+	return p.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (p *PostgreSQL) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	result, err := p.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -225,7 +492,12 @@ func (p *PostgreSQL) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (p *PostgreSQL) ExecuteDelete(query string) (int64, error) {
-	result, err := p.db.Exec(query)
+	return p.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (p *PostgreSQL) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	result, err := p.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -234,7 +506,12 @@ func (p *PostgreSQL) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (p *PostgreSQL) ExecuteInsert(query string) (int64, error) {
-	result, err := p.db.Exec(query)
+	return p.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (p *PostgreSQL) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := p.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -243,6 +520,11 @@ func (p *PostgreSQL) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (p *PostgreSQL) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return p.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (p *PostgreSQL) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("postgresql", tableName, filters)
 	if err != nil {
@@ -255,7 +537,7 @@ func (p *PostgreSQL) GetTableData(tableName string, page, pageSize int, filters
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := p.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := p.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -267,7 +549,7 @@ func (p *PostgreSQL) GetTableData(tableName string, page, pageSize int, filters
 	}
 	query += fmt.Sprintf(` LIMIT %d OFFSET %d`, pageSize, offset)
 
-	rows, err := p.db.Query(query, whereArgs...)
+	rows, err := p.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -309,6 +591,11 @@ func (p *PostgreSQL) GetTableData(tableName string, page, pageSize int, filters
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (p *PostgreSQL) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return p.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (p *PostgreSQL) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("postgresql", tableName, filters)
 	if err != nil {
@@ -321,7 +608,7 @@ func (p *PostgreSQL) GetTableDataByID(tableName string, primaryKey string, lastI
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := p.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := p.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -374,7 +661,7 @@ func (p *PostgreSQL) GetTableDataByID(tableName string, primaryKey string, lastI
 		query += fmt.Sprintf(` ORDER BY "%s" ASC LIMIT %d`, primaryKey, pageSize)
 	}
 	
-	rows, err = p.db.Query(query, queryArgs...)
+	rows, err = p.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
 	}
@@ -435,6 +722,21 @@ func (p *PostgreSQL) GetTableDataByID(tableName string, primaryKey string, lastI
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (p *PostgreSQL) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return querySortedTableData(ctx, p.db, "postgresql", fmt.Sprintf(`"%s"`, tableName), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (p *PostgreSQL) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return queryTableDataByCursor(ctx, p.db, "postgresql", fmt.Sprintf(`"%s"`, tableName), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (p *PostgreSQL) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, p.db, "postgresql", fmt.Sprintf(`"%s"`, tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (p *PostgreSQL) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if page <= 1 {
@@ -249,7 +249,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	switch tableName {
 	case "keys":
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "ttl", Type: "integer", Nullable: true},
 			{Name: "memory", Type: "integer", Nullable: true},
@@ -257,7 +257,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	case "string":
 		// 类型列表：显示键列表
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "value", Type: "string", Nullable: true},
 			{Name: "ttl", Type: "integer", Nullable: true},
@@ -266,7 +266,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	case "hash":
 		// 类型列表：显示键列表
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "size", Type: "integer", Nullable: true},
 			{Name: "ttl", Type: "integer", Nullable: true},
@@ -275,7 +275,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	case "list":
 		// 类型列表：显示键列表
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "size", Type: "integer", Nullable: true},
 			{Name: "ttl", Type: "integer", Nullable: true},
@@ -284,7 +284,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	case "set":
 		// 类型列表：显示键列表
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "size", Type: "integer", Nullable: true},
 			{Name: "ttl", Type: "integer", Nullable: true},
@@ -293,7 +293,7 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	case "zset":
 		// 类型列表：显示键列表
 		return []ColumnInfo{
-			{Name: "key", Type: "string", Nullable: false},
+			{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 			{Name: "type", Type: "string", Nullable: false},
 			{Name: "size", Type: "integer", Nullable: true},
 			{Name: "ttl", Type: "integer", Nullable: true},
@@ -309,31 +309,31 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 		switch keyType {
 		case "string":
 			return []ColumnInfo{
-				{Name: "key", Type: "string", Nullable: false},
+				{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "value", Type: "string", Nullable: true},
 			}, nil
 		case "hash":
 			return []ColumnInfo{
-				{Name: "field", Type: "string", Nullable: false},
+				{Name: "field", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "value", Type: "string", Nullable: true},
 			}, nil
 		case "list":
 			return []ColumnInfo{
-				{Name: "index", Type: "integer", Nullable: false},
+				{Name: "index", Type: "integer", Nullable: false, Key: "PRI"},
 				{Name: "value", Type: "string", Nullable: true},
 			}, nil
 		case "set":
 			return []ColumnInfo{
-				{Name: "member", Type: "string", Nullable: false},
+				{Name: "member", Type: "string", Nullable: false, Key: "PRI"},
 			}, nil
 		case "zset":
 			return []ColumnInfo{
-				{Name: "member", Type: "string", Nullable: false},
+				{Name: "member", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "score", Type: "float", Nullable: false},
 			}, nil
 		default:
 			return []ColumnInfo{
-				{Name: "key", Type: "string", Nullable: false},
+				{Name: "key", Type: "string", Nullable: false, Key: "PRI"},
 				{Name: "type", Type: "string", Nullable: false},
 			}, nil
 		}
@@ -343,6 +343,11 @@ func (r *Redis) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 // GetTableData 获取表的数据（分页）
 // tableName: 数据类型（strings, hashes等）或键名
 func (r *Redis) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return r.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表的数据（分页），ctx 取消或超时时中止执行
+func (r *Redis) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	if r.client == nil {
 		return nil, 0, fmt.Errorf("database not connected")
 	}
@@ -350,15 +355,34 @@ func (r *Redis) GetTableData(tableName string, page, pageSize int, filters *Filt
 	// 如果 tableName 是数据类型，使用 SCAN 获取该类型的键
 	if tableName == "keys" || tableName == "string" || tableName == "hash" ||
 		tableName == "list" || tableName == "set" || tableName == "zset" {
-		return r.getKeysByType(tableName, page, pageSize, filters)
+		return r.getKeysByType(ctx, tableName, page, pageSize, filters)
 	}
 
 	// 否则，tableName 是一个具体的键，获取该键的数据
-	return r.getKeyData(tableName, page, pageSize)
+	return r.getKeyData(ctx, tableName, page, pageSize)
+}
+
+// GetTableDataSorted 获取数据并排序
+// Redis 不支持服务端排序，只对当前页的数据在内存中排序
+func (r *Redis) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if err := ValidateSorts(sorts); err != nil {
+		return nil, 0, err
+	}
+	data, total, err := r.GetTableDataContext(ctx, tableName, page, pageSize, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	SortRows(data, sorts)
+	return data, total, nil
+}
+
+// GetTableDataByCursor 基于游标获取数据（Redis 不支持，返回错误）
+func (r *Redis) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return nil, 0, fmt.Errorf("Redis does not support cursor-based pagination")
 }
 
 // getKeysByType 根据类型获取键列表（不支持完整分页，只获取当前页数据）
-func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+func (r *Redis) getKeysByType(ctx context.Context, dataType string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 使用 SCAN 命令迭代获取键，只获取当前页需要的数据
 	// 注意：Redis 不支持真正的分页，这里只获取当前页的数据，不扫描所有键
 
@@ -372,6 +396,21 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 				pattern = strings.ReplaceAll(pattern, "_", "?")
 				break
 			}
+			if condition.Field == "key" && condition.Value != "" {
+				// 包含/前缀/后缀匹配转换为 Redis 模式（Redis 的模式匹配区分大小写）
+				value := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(condition.Value)
+				switch strings.ToUpper(condition.Operator) {
+				case "CONTAINS":
+					pattern = "*" + value + "*"
+				case "STARTS WITH":
+					pattern = value + "*"
+				case "ENDS WITH":
+					pattern = "*" + value
+				}
+				if pattern != "*" {
+					break
+				}
+			}
 		}
 	}
 
@@ -386,7 +425,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 
 	// 使用 SCAN 迭代，直到获取到足够的匹配键
 	for len(matchedKeys) < skipCount+neededCount && scanTimes < maxScanTimes {
-		keys, nextCursor, err := r.client.Scan(r.ctx, cursor, pattern, 1000).Result()
+		keys, nextCursor, err := r.client.Scan(ctx, cursor, pattern, 1000).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to scan keys: %w", err)
 		}
@@ -395,7 +434,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 		// 如果指定了类型，过滤键
 		if dataType != "keys" {
 			for _, key := range keys {
-				keyType, err := r.client.Type(r.ctx, key).Result()
+				keyType, err := r.client.Type(ctx, key).Result()
 				if err == nil {
 					// 直接匹配 Redis 类型（使用单数形式）
 					if keyType == "none" {
@@ -433,9 +472,9 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 	// 构建结果
 	results := make([]map[string]interface{}, 0, len(keys))
 	for _, key := range keys {
-		keyType, _ := r.client.Type(r.ctx, key).Result()
-		ttl, _ := r.client.TTL(r.ctx, key).Result()
-		memory, _ := r.client.MemoryUsage(r.ctx, key).Result()
+		keyType, _ := r.client.Type(ctx, key).Result()
+		ttl, _ := r.client.TTL(ctx, key).Result()
+		memory, _ := r.client.MemoryUsage(ctx, key).Result()
 
 		row := map[string]interface{}{
 			"key":  key,
@@ -460,7 +499,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 		switch keyType {
 		case "string":
 			// String 类型：显示值预览
-			if val, err := r.client.Get(r.ctx, key).Result(); err == nil {
+			if val, err := r.client.Get(ctx, key).Result(); err == nil {
 				// 限制预览长度
 				if len(val) > 100 {
 					row["value"] = val[:100] + "..."
@@ -474,7 +513,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 			row["size"] = nil
 		case "hash":
 			// Hash 类型：显示大小
-			if size, err := r.client.HLen(r.ctx, key).Result(); err == nil {
+			if size, err := r.client.HLen(ctx, key).Result(); err == nil {
 				row["size"] = size
 			} else {
 				row["size"] = nil
@@ -482,7 +521,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 			row["value"] = nil
 		case "list":
 			// List 类型：显示大小
-			if size, err := r.client.LLen(r.ctx, key).Result(); err == nil {
+			if size, err := r.client.LLen(ctx, key).Result(); err == nil {
 				row["size"] = size
 			} else {
 				row["size"] = nil
@@ -490,7 +529,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 			row["value"] = nil
 		case "set":
 			// Set 类型：显示大小
-			if size, err := r.client.SCard(r.ctx, key).Result(); err == nil {
+			if size, err := r.client.SCard(ctx, key).Result(); err == nil {
 				row["size"] = size
 			} else {
 				row["size"] = nil
@@ -498,7 +537,7 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 			row["value"] = nil
 		case "zset":
 			// Sorted Set 类型：显示大小
-			if size, err := r.client.ZCard(r.ctx, key).Result(); err == nil {
+			if size, err := r.client.ZCard(ctx, key).Result(); err == nil {
 				row["size"] = size
 			} else {
 				row["size"] = nil
@@ -518,15 +557,15 @@ func (r *Redis) getKeysByType(dataType string, page, pageSize int, filters *Filt
 }
 
 // getKeyData 获取具体键的数据
-func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interface{}, int64, error) {
-	keyType, err := r.client.Type(r.ctx, key).Result()
+func (r *Redis) getKeyData(ctx context.Context, key string, page, pageSize int) ([]map[string]interface{}, int64, error) {
+	keyType, err := r.client.Type(ctx, key).Result()
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get key type: %w", err)
 	}
 
 	switch keyType {
 	case "string":
-		val, err := r.client.Get(r.ctx, key).Result()
+		val, err := r.client.Get(ctx, key).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to get key value: %w", err)
 		}
@@ -536,7 +575,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 
 	case "hash":
 		// 先检查键是否存在和类型是否正确
-		exists, err := r.client.Exists(r.ctx, key).Result()
+		exists, err := r.client.Exists(ctx, key).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to check key existence: %w", err)
 		}
@@ -545,7 +584,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 		}
 
 		// 获取 hash 的长度
-		hashLen, err := r.client.HLen(r.ctx, key).Result()
+		hashLen, err := r.client.HLen(ctx, key).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to get hash length: %w", err)
 		}
@@ -556,7 +595,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 		}
 
 		// 获取所有字段和值（Hash 类型需要获取全部数据才能分页）
-		allFields, err := r.client.HGetAll(r.ctx, key).Result()
+		allFields, err := r.client.HGetAll(ctx, key).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to get hash: %w", err)
 		}
@@ -612,7 +651,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 		start := int64((page - 1) * pageSize)
 		end := start + int64(pageSize) - 1
 
-		values, err := r.client.LRange(r.ctx, key, start, end).Result()
+		values, err := r.client.LRange(ctx, key, start, end).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to get list range: %w", err)
 		}
@@ -638,7 +677,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 
 		// 迭代直到获取到足够的成员
 		for len(allMembers) < skipCount+neededCount {
-			members, nextCursor, err := r.client.SScan(r.ctx, key, cursor, "", int64(neededCount*2)).Result()
+			members, nextCursor, err := r.client.SScan(ctx, key, cursor, "", int64(neededCount*2)).Result()
 			if err != nil {
 				return nil, -1, fmt.Errorf("failed to scan set: %w", err)
 			}
@@ -675,7 +714,7 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 		start := int64((page - 1) * pageSize)
 		end := start + int64(pageSize) - 1
 
-		members, err := r.client.ZRangeWithScores(r.ctx, key, start, end).Result()
+		members, err := r.client.ZRangeWithScores(ctx, key, start, end).Result()
 		if err != nil {
 			return nil, -1, fmt.Errorf("failed to get sorted set range: %w", err)
 		}
@@ -698,8 +737,13 @@ func (r *Redis) getKeyData(key string, page, pageSize int) ([]map[string]interfa
 
 // GetTableDataByID 基于主键ID获取表数据（Redis 不支持基于ID的分页，直接调用 GetTableData）
 func (r *Redis) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return r.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（Redis 不支持基于ID的分页，直接调用 GetTableData），ctx 取消或超时时中止执行
+func (r *Redis) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// Redis 不支持基于ID的分页，直接使用普通分页，页码固定为1
-	data, total, err := r.GetTableData(tableName, 1, pageSize, filters)
+	data, total, err := r.GetTableDataContext(ctx, tableName, 1, pageSize, filters)
 	return data, total, nil, err
 }
 
@@ -720,6 +764,11 @@ func (r *Redis) GetPageIdByPageNumber(tableName string, primaryKey string, page,
 //   - SCAN cursor [MATCH pattern] [COUNT count]
 //   - INFO [section]
 func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return r.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询（Redis 命令），ctx 取消或超时时中止执行
+func (r *Redis) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
 	if r.client == nil {
 		return nil, fmt.Errorf("database not connected")
 	}
@@ -738,7 +787,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if len(args) < 1 {
 			return nil, fmt.Errorf("GET command requires a key")
 		}
-		val, err := r.client.Get(r.ctx, args[0]).Result()
+		val, err := r.client.Get(ctx, args[0]).Result()
 		if err == redis.Nil {
 			return []map[string]interface{}{{"key": args[0], "value": nil}}, nil
 		}
@@ -751,7 +800,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if len(args) < 1 {
 			return nil, fmt.Errorf("HGETALL command requires a key")
 		}
-		fields, err := r.client.HGetAll(r.ctx, args[0]).Result()
+		fields, err := r.client.HGetAll(ctx, args[0]).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute HGETALL: %w", err)
 		}
@@ -773,7 +822,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if err1 != nil || err2 != nil {
 			return nil, fmt.Errorf("LRANGE start and stop must be integers")
 		}
-		values, err := r.client.LRange(r.ctx, args[0], int64(start), int64(stop)).Result()
+		values, err := r.client.LRange(ctx, args[0], int64(start), int64(stop)).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute LRANGE: %w", err)
 		}
@@ -790,7 +839,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if len(args) < 1 {
 			return nil, fmt.Errorf("SMEMBERS command requires a key")
 		}
-		members, err := r.client.SMembers(r.ctx, args[0]).Result()
+		members, err := r.client.SMembers(ctx, args[0]).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute SMEMBERS: %w", err)
 		}
@@ -811,7 +860,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if err1 != nil || err2 != nil {
 			return nil, fmt.Errorf("ZRANGE start and stop must be integers")
 		}
-		members, err := r.client.ZRangeWithScores(r.ctx, args[0], int64(start), int64(stop)).Result()
+		members, err := r.client.ZRangeWithScores(ctx, args[0], int64(start), int64(stop)).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute ZRANGE: %w", err)
 		}
@@ -829,7 +878,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if len(args) > 0 {
 			section = args[0]
 		}
-		info, err := r.client.Info(r.ctx, section).Result()
+		info, err := r.client.Info(ctx, section).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute INFO: %w", err)
 		}
@@ -855,7 +904,7 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 		if len(args) < 1 {
 			return nil, fmt.Errorf("KEYS command requires a pattern")
 		}
-		keys, err := r.client.Keys(r.ctx, args[0]).Result()
+		keys, err := r.client.Keys(ctx, args[0]).Result()
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute KEYS: %w", err)
 		}
@@ -874,6 +923,11 @@ func (r *Redis) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 
 // ExecuteUpdate 执行更新（Redis SET, HSET 等命令）
 func (r *Redis) ExecuteUpdate(query string) (int64, error) {
+	return r.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新（Redis SET, HSET 等命令），ctx 取消或超时时中止执行
+func (r *Redis) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
 	if r.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -891,7 +945,7 @@ func (r *Redis) ExecuteUpdate(query string) (int64, error) {
 		if len(args) < 2 {
 			return 0, fmt.Errorf("SET command requires key and value")
 		}
-		err := r.client.Set(r.ctx, args[0], strings.Join(args[1:], " "), 0).Err()
+		err := r.client.Set(ctx, args[0], strings.Join(args[1:], " "), 0).Err()
 		if err != nil {
 			return 0, fmt.Errorf("failed to execute SET: %w", err)
 		}
@@ -901,7 +955,7 @@ func (r *Redis) ExecuteUpdate(query string) (int64, error) {
 		if len(args) < 3 {
 			return 0, fmt.Errorf("HSET command requires key, field and value")
 		}
-		err := r.client.HSet(r.ctx, args[0], args[1], strings.Join(args[2:], " ")).Err()
+		err := r.client.HSet(ctx, args[0], args[1], strings.Join(args[2:], " ")).Err()
 		if err != nil {
 			return 0, fmt.Errorf("failed to execute HSET: %w", err)
 		}
@@ -914,6 +968,11 @@ func (r *Redis) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除（Redis DEL 命令）
 func (r *Redis) ExecuteDelete(query string) (int64, error) {
+	return r.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除（Redis DEL 命令），ctx 取消或超时时中止执行
+func (r *Redis) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
 	if r.client == nil {
 		return 0, fmt.Errorf("database not connected")
 	}
@@ -929,7 +988,7 @@ func (r *Redis) ExecuteDelete(query string) (int64, error) {
 	}
 
 	keys := parts[1:]
-	count, err := r.client.Del(r.ctx, keys...).Result()
+	count, err := r.client.Del(ctx, keys...).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute DEL: %w", err)
 	}
@@ -939,7 +998,12 @@ func (r *Redis) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入（Redis SET, HSET 等命令，与 ExecuteUpdate 相同）
 func (r *Redis) ExecuteInsert(query string) (int64, error) {
-	return r.ExecuteUpdate(query)
+	return r.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入（Redis SET, HSET 等命令，与 ExecuteUpdate 相同），ctx 取消或超时时中止执行
+func (r *Redis) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	return r.ExecuteUpdateContext(ctx, query)
 }
 
 // GetDatabases 获取所有数据库索引（Redis 默认有 16 个数据库，索引 0-15）
@@ -1037,3 +1101,353 @@ func BuildRedisDSN(info ConnectionInfo) string {
 
 	return dsn
 }
+
+// redisTypeTables 按数据类型列出键的虚拟表
+var redisTypeTables = map[string]bool{
+	"keys": true, "string": true, "hash": true, "list": true, "set": true, "zset": true,
+}
+
+// redisListDeleted 删除列表元素时使用的占位值：LREM 只能按值删除，先用 LSET 把目标位置替换为占位值
+const redisListDeleted = "__simple_db_web_deleted__"
+
+// UpdateRows 按键修改数据
+// 类型列表（keys、string 等）中按 key 修改 ttl（空值表示永不过期，值列只是预览不可修改）；
+// 键表中按 field（hash）、index（list）、member（set、zset）修改值，string 键修改 value
+func (r *Redis) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	if r.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+
+	if redisTypeTables[tableName] {
+		key, err := redisKeyValue(keys, "key")
+		if err != nil {
+			return 0, err
+		}
+		ttlValue, ok := values["ttl"]
+		if !ok {
+			return 0, nil
+		}
+		return r.updateTTL(ctx, key, ttlValue)
+	}
+
+	keyType, err := r.client.Type(ctx, tableName).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get key type: %w", err)
+	}
+	switch keyType {
+	case "none":
+		return 0, nil
+	case "string":
+		value, ok := values["value"]
+		if !ok {
+			return 0, nil
+		}
+		if err := r.client.SetArgs(ctx, tableName, redisString(value), redis.SetArgs{KeepTTL: true}).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set value: %w", err)
+		}
+		return 1, nil
+	case "hash":
+		field, err := redisKeyValue(keys, "field")
+		if err != nil {
+			return 0, err
+		}
+		value, ok := values["value"]
+		if !ok {
+			return 0, nil
+		}
+		exists, err := r.client.HExists(ctx, tableName, field).Result()
+		if err != nil || !exists {
+			return 0, err
+		}
+		if err := r.client.HSet(ctx, tableName, field, redisString(value)).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set field: %w", err)
+		}
+		return 1, nil
+	case "list":
+		index, err := redisListIndex(keys)
+		if err != nil {
+			return 0, err
+		}
+		value, ok := values["value"]
+		if !ok {
+			return 0, nil
+		}
+		if err := r.client.LSet(ctx, tableName, index, redisString(value)).Err(); err != nil {
+			return 0, fmt.Errorf("failed to set list element: %w", err)
+		}
+		return 1, nil
+	case "set":
+		member, err := redisKeyValue(keys, "member")
+		if err != nil {
+			return 0, err
+		}
+		newMember, ok := values["member"]
+		if !ok || redisString(newMember) == member {
+			return 0, nil
+		}
+		removed, err := r.client.SRem(ctx, tableName, member).Result()
+		if err != nil || removed == 0 {
+			return 0, err
+		}
+		if err := r.client.SAdd(ctx, tableName, redisString(newMember)).Err(); err != nil {
+			return 0, fmt.Errorf("failed to add member: %w", err)
+		}
+		return 1, nil
+	case "zset":
+		member, err := redisKeyValue(keys, "member")
+		if err != nil {
+			return 0, err
+		}
+		score, err := r.client.ZScore(ctx, tableName, member).Result()
+		if err == redis.Nil {
+			return 0, nil
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to get score: %w", err)
+		}
+		if v, ok := values["score"]; ok {
+			if score, err = strconv.ParseFloat(redisString(v), 64); err != nil {
+				return 0, fmt.Errorf("invalid score: %v", v)
+			}
+		}
+		newMember := member
+		if v, ok := values["member"]; ok {
+			newMember = redisString(v)
+		}
+		if newMember != member {
+			if err := r.client.ZRem(ctx, tableName, member).Err(); err != nil {
+				return 0, fmt.Errorf("failed to remove member: %w", err)
+			}
+		}
+		if err := r.client.ZAdd(ctx, tableName, redis.Z{Score: score, Member: newMember}).Err(); err != nil {
+			return 0, fmt.Errorf("failed to add member: %w", err)
+		}
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("unsupported key type: %s", keyType)
+	}
+}
+
+// DeleteRows 按键删除数据
+// 类型列表中删除整个键；键表中删除 hash 字段、list 元素、set/zset 成员，string 键删除整个键
+func (r *Redis) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	if r.client == nil {
+		return 0, fmt.Errorf("database not connected")
+	}
+
+	if redisTypeTables[tableName] {
+		key, err := redisKeyValue(keys, "key")
+		if err != nil {
+			return 0, err
+		}
+		return r.client.Del(ctx, key).Result()
+	}
+
+	keyType, err := r.client.Type(ctx, tableName).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get key type: %w", err)
+	}
+	switch keyType {
+	case "none":
+		return 0, nil
+	case "string":
+		return r.client.Del(ctx, tableName).Result()
+	case "hash":
+		field, err := redisKeyValue(keys, "field")
+		if err != nil {
+			return 0, err
+		}
+		return r.client.HDel(ctx, tableName, field).Result()
+	case "list":
+		index, err := redisListIndex(keys)
+		if err != nil {
+			return 0, err
+		}
+		if err := r.client.LSet(ctx, tableName, index, redisListDeleted).Err(); err != nil {
+			return 0, fmt.Errorf("failed to delete list element: %w", err)
+		}
+		return r.client.LRem(ctx, tableName, 1, redisListDeleted).Result()
+	case "set":
+		member, err := redisKeyValue(keys, "member")
+		if err != nil {
+			return 0, err
+		}
+		return r.client.SRem(ctx, tableName, member).Result()
+	case "zset":
+		member, err := redisKeyValue(keys, "member")
+		if err != nil {
+			return 0, err
+		}
+		return r.client.ZRem(ctx, tableName, member).Result()
+	default:
+		return 0, fmt.Errorf("unsupported key type: %s", keyType)
+	}
+}
+
+// InsertRow 新增数据
+// 类型列表 string 中按 key、value、ttl 新建字符串键（键已存在时报错），其他类型的键需要至少一个元素，请使用命令创建；
+// 键表中新增 hash 字段、追加 list 元素、新增 set/zset 成员，返回新数据的定位键
+func (r *Redis) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	if r.client == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	if redisTypeTables[tableName] {
+		if tableName != "string" {
+			return nil, fmt.Errorf("creating %s keys is not supported, use a command instead", tableName)
+		}
+		key, err := redisKeyValue(values, "key")
+		if err != nil {
+			return nil, err
+		}
+		var ttl time.Duration
+		if v, ok := values["ttl"]; ok && v != nil && redisString(v) != "" {
+			seconds, err := strconv.ParseFloat(redisString(v), 64)
+			if err != nil || seconds <= 0 {
+				return nil, fmt.Errorf("invalid ttl: %v", v)
+			}
+			ttl = time.Duration(seconds * float64(time.Second))
+		}
+		created, err := r.client.SetNX(ctx, key, redisString(values["value"]), ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to set value: %w", err)
+		}
+		if !created {
+			return nil, fmt.Errorf("key already exists: %s", key)
+		}
+		return map[string]interface{}{"key": key}, nil
+	}
+
+	keyType, err := r.client.Type(ctx, tableName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key type: %w", err)
+	}
+	switch keyType {
+	case "none":
+		return nil, fmt.Errorf("key does not exist: %s", tableName)
+	case "hash":
+		field, err := redisKeyValue(values, "field")
+		if err != nil {
+			return nil, err
+		}
+		created, err := r.client.HSetNX(ctx, tableName, field, redisString(values["value"])).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to set field: %w", err)
+		}
+		if !created {
+			return nil, fmt.Errorf("field already exists: %s", field)
+		}
+		return map[string]interface{}{"field": field}, nil
+	case "list":
+		length, err := r.client.RPush(ctx, tableName, redisString(values["value"])).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to push list element: %w", err)
+		}
+		return map[string]interface{}{"index": length - 1}, nil
+	case "set":
+		member, err := redisKeyValue(values, "member")
+		if err != nil {
+			return nil, err
+		}
+		added, err := r.client.SAdd(ctx, tableName, member).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to add member: %w", err)
+		}
+		if added == 0 {
+			return nil, fmt.Errorf("member already exists: %s", member)
+		}
+		return map[string]interface{}{"member": member}, nil
+	case "zset":
+		member, err := redisKeyValue(values, "member")
+		if err != nil {
+			return nil, err
+		}
+		score := 0.0
+		if v, ok := values["score"]; ok && v != nil && redisString(v) != "" {
+			if score, err = strconv.ParseFloat(redisString(v), 64); err != nil {
+				return nil, fmt.Errorf("invalid score: %v", v)
+			}
+		}
+		added, err := r.client.ZAddNX(ctx, tableName, redis.Z{Score: score, Member: member}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to add member: %w", err)
+		}
+		if added == 0 {
+			return nil, fmt.Errorf("member already exists: %s", member)
+		}
+		return map[string]interface{}{"member": member}, nil
+	default:
+		// string 键只有一个值，应编辑而不是新增
+		return nil, fmt.Errorf("cannot add rows to a %s key", keyType)
+	}
+}
+
+// updateTTL 修改键的过期时间，空值表示移除过期时间；与当前值相差不到 1 秒时不修改
+func (r *Redis) updateTTL(ctx context.Context, key string, value interface{}) (int64, error) {
+	current, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get ttl: %w", err)
+	}
+	if current == -2 {
+		// 键不存在
+		return 0, nil
+	}
+
+	if value == nil || redisString(value) == "" {
+		if current < 0 {
+			return 0, nil
+		}
+		if err := r.client.Persist(ctx, key).Err(); err != nil {
+			return 0, fmt.Errorf("failed to persist key: %w", err)
+		}
+		return 1, nil
+	}
+
+	seconds, err := strconv.ParseFloat(redisString(value), 64)
+	if err != nil || seconds <= 0 {
+		return 0, fmt.Errorf("invalid ttl: %v", value)
+	}
+	ttl := time.Duration(seconds * float64(time.Second))
+	if current > 0 && (current-ttl).Abs() < time.Second {
+		return 0, nil
+	}
+	if err := r.client.Expire(ctx, key, ttl).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set ttl: %w", err)
+	}
+	return 1, nil
+}
+
+// redisKeyValue 取出定位数据的键值
+func redisKeyValue(keys map[string]interface{}, name string) (string, error) {
+	value, ok := keys[name]
+	if !ok || value == nil {
+		return "", fmt.Errorf("%s is required", name)
+	}
+	return redisString(value), nil
+}
+
+// redisListIndex 取出定位列表元素的下标
+func redisListIndex(keys map[string]interface{}) (int64, error) {
+	value, err := redisKeyValue(keys, "index")
+	if err != nil {
+		return 0, err
+	}
+	index, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid list index: %s", value)
+	}
+	return index, nil
+}
+
+// redisString 将值转换为 Redis 字符串
+func redisString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// 二进制值的编码方式
+const (
+	BinaryEncodingHex    = "hex"    // 十六进制，形如 0x4a4b
+	BinaryEncodingBase64 = "base64" // 标准 base64
+)
+
+// NormalizeBinaryEncoding 规范化二进制编码方式，不支持的值回退为十六进制
+func NormalizeBinaryEncoding(encoding string) string {
+	if strings.EqualFold(encoding, BinaryEncodingBase64) {
+		return BinaryEncodingBase64
+	}
+	return BinaryEncodingHex
+}
+
+// ResultColumn 结果集列信息
+type ResultColumn struct {
+	Name     string `json:"name"`     // 列名（保留原始名称，允许重复）
+	Type     string `json:"type"`     // 数据库类型名（如 VARCHAR、BIGINT），驱动无法提供时为空
+	Nullable *bool  `json:"nullable"` // 是否可为空，nil 表示未知
+}
+
+// ResultSet 有序、带类型信息的结果集
+// 与 []map[string]interface{} 不同，它保留 SELECT 中的列顺序和重复列名
+type ResultSet struct {
+	Columns        []ResultColumn  `json:"columns"`
+	Rows           [][]interface{} `json:"rows"`
+	BinaryEncoding string          `json:"binaryEncoding"` // 二进制值的编码方式
+}
+
+// BinaryValue 二进制值
+// JSON 序列化时按 Encoding 编码为字符串，避免直接转换为字符串导致数据损坏
+type BinaryValue struct {
+	Data     []byte
+	Encoding string
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (b BinaryValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// String 返回编码后的字符串
+func (b BinaryValue) String() string {
+	if b.Encoding == BinaryEncodingBase64 {
+		return base64.StdEncoding.EncodeToString(b.Data)
+	}
+	return "0x" + hex.EncodeToString(b.Data)
+}
+
+// IsBinaryType 判断数据库类型是否为二进制类型
+func IsBinaryType(dbType string) bool {
+	t := strings.ToUpper(dbType)
+	if i := strings.Index(t, "("); i >= 0 {
+		t = t[:i]
+	}
+	t = strings.TrimSpace(t)
+	switch t {
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "BIT",
+		"BYTEA", "IMAGE", "RAW", "LONG RAW", "GEOMETRY", "POINT", "LINESTRING", "POLYGON":
+		return true
+	}
+	return false
+}
+
+// IsJSONType 判断数据库类型是否为 JSON 类型
+func IsJSONType(dbType string) bool {
+	t := strings.ToUpper(strings.TrimSpace(dbType))
+	return t == "JSON" || t == "JSONB"
+}
+
+// ConvertValue 根据数据库类型转换扫描得到的值，用于 JSON 输出
+// 二进制类型编码为 BinaryValue，JSON 类型保留原始 JSON，其他 []byte 转为字符串（DECIMAL 等保持原始精度）
+func ConvertValue(dbType string, value interface{}, binaryEncoding string) interface{} {
+	var raw []byte
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		// 部分驱动已将 []byte 转为字符串，二进制和 JSON 类型仍需还原
+		if !IsBinaryType(dbType) && !IsJSONType(dbType) {
+			return v
+		}
+		raw = []byte(v)
+	default:
+		return value
+	}
+
+	if IsBinaryType(dbType) {
+		return BinaryValue{Data: raw, Encoding: binaryEncoding}
+	}
+	if IsJSONType(dbType) && json.Valid(raw) {
+		return json.RawMessage(raw)
+	}
+	return string(raw)
+}
+
+// QueryResultSet 执行查询并返回有序结果集
+// 支持流式查询的驱动会携带列类型信息，其他驱动只返回列名
+func QueryResultSet(ctx context.Context, db Database, query string, binaryEncoding string) (*ResultSet, error) {
+	iter, err := StreamQuery(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	return ReadResultSet(iter, binaryEncoding)
+}
+
+// ReadResultSet 从行迭代器读取完整的结果集
+// binaryEncoding 不是 base64 时使用十六进制编码
+func ReadResultSet(iter RowIterator, binaryEncoding string) (*ResultSet, error) {
+	defer iter.Close()
+
+	binaryEncoding = NormalizeBinaryEncoding(binaryEncoding)
+
+	columns := iter.ColumnTypes()
+	rs := &ResultSet{
+		Columns:        columns,
+		Rows:           make([][]interface{}, 0),
+		BinaryEncoding: binaryEncoding,
+	}
+	for iter.Next() {
+		values := iter.Values()
+		SetBinaryEncoding(values, binaryEncoding)
+		rs.Rows = append(rs.Rows, values)
+	}
+	return rs, iter.Err()
+}
+
+// SetBinaryEncoding 设置一行值中所有二进制值的编码方式
+func SetBinaryEncoding(values []interface{}, binaryEncoding string) {
+	for i := range values {
+		if b, ok := values[i].(BinaryValue); ok {
+			b.Encoding = binaryEncoding
+			values[i] = b
+		}
+	}
+}
+
+// NewResultSetFromMaps 根据表的列信息将 map 结果转换为有序结果集
+// 列顺序与 columns 一致，map 中存在但 columns 中没有的键追加到末尾
+func NewResultSetFromMaps(columns []ColumnInfo, rows []map[string]interface{}, binaryEncoding string) *ResultSet {
+	binaryEncoding = NormalizeBinaryEncoding(binaryEncoding)
+
+	rs := &ResultSet{
+		Columns:        make([]ResultColumn, 0, len(columns)),
+		Rows:           make([][]interface{}, 0, len(rows)),
+		BinaryEncoding: binaryEncoding,
+	}
+	known := make(map[string]bool)
+	for _, col := range columns {
+		nullable := col.Nullable
+		rs.Columns = append(rs.Columns, ResultColumn{Name: col.Name, Type: col.Type, Nullable: &nullable})
+		known[col.Name] = true
+	}
+	// 补充列信息中没有的字段（如 MongoDB 文档中的动态字段）
+	extra := NewSliceRowIterator(rows).Columns()
+	for _, name := range extra {
+		if !known[name] {
+			rs.Columns = append(rs.Columns, ResultColumn{Name: name})
+		}
+	}
+
+	for _, row := range rows {
+		values := make([]interface{}, len(rs.Columns))
+		for i, col := range rs.Columns {
+			values[i] = ConvertValue(col.Type, row[col.Name], binaryEncoding)
+		}
+		rs.Rows = append(rs.Rows, values)
+	}
+	return rs
+}
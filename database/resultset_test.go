@@ -0,0 +1,58 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		value    interface{}
+		encoding string
+		expected string // JSON 序列化结果
+	}{
+		{name: "NULL 值", dbType: "VARCHAR", value: nil, encoding: BinaryEncodingHex, expected: `null`},
+		{name: "文本类型的 []byte 转为字符串", dbType: "VARCHAR", value: []byte("hello"), encoding: BinaryEncodingHex, expected: `"hello"`},
+		{name: "DECIMAL 保持原始精度", dbType: "DECIMAL", value: []byte("12345678901234567890.123"), encoding: BinaryEncodingHex, expected: `"12345678901234567890.123"`},
+		{name: "BLOB 使用十六进制编码", dbType: "BLOB", value: []byte{0x00, 0xff, 0x10}, encoding: BinaryEncodingHex, expected: `"0x00ff10"`},
+		{name: "VARBINARY(16) 使用 base64 编码", dbType: "varbinary(16)", value: []byte{0x00, 0xff}, encoding: BinaryEncodingBase64, expected: `"AP8="`},
+		{name: "已转为字符串的 BYTEA 仍按二进制编码", dbType: "BYTEA", value: string([]byte{0xde, 0xad}), encoding: BinaryEncodingHex, expected: `"0xdead"`},
+		{name: "JSON 列保留为 JSON", dbType: "JSON", value: []byte(`{"a":[1,2]}`), encoding: BinaryEncodingHex, expected: `{"a":[1,2]}`},
+		{name: "无效的 JSON 回退为字符串", dbType: "JSONB", value: []byte(`{bad`), encoding: BinaryEncodingHex, expected: `"{bad"`},
+		{name: "其他类型保持不变", dbType: "BIGINT", value: int64(42), encoding: BinaryEncodingHex, expected: `42`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(ConvertValue(tt.dbType, tt.value, tt.encoding))
+			if err != nil {
+				t.Fatalf("marshal failed: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("ConvertValue() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewResultSetFromMaps(t *testing.T) {
+	columns := []ColumnInfo{
+		{Name: "id", Type: "int", Key: "PRI"},
+		{Name: "data", Type: "blob", Nullable: true},
+	}
+	rows := []map[string]interface{}{
+		{"data": string([]byte{0x01}), "id": int64(1), "extra": "x"},
+	}
+
+	rs := NewResultSetFromMaps(columns, rows, "")
+	got, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	expected := `{"columns":[{"name":"id","type":"int","nullable":false},{"name":"data","type":"blob","nullable":true},{"name":"extra","type":"","nullable":null}],"rows":[[1,"0x01","x"]],"binaryEncoding":"hex"}`
+	if string(got) != expected {
+		t.Errorf("NewResultSetFromMaps() = %s, want %s", got, expected)
+	}
+}
@@ -0,0 +1,528 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RowMutationDatabase 支持按键修改行数据的数据库接口扩展
+// keys 为定位行的列（通常是主键）到值的映射，值为 nil 时匹配 NULL；values 为要更新的列和新值
+// 驱动使用各自的标识符引用方式和绑定参数，而不是拼接 SQL 字符串
+type RowMutationDatabase interface {
+	// UpdateRows 更新满足 keys 的行，返回影响行数
+	UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error)
+
+	// DeleteRows 删除满足 keys 的行，返回影响行数
+	DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error)
+}
+
+// RowInsertDatabase 支持按列插入行的数据库接口扩展
+type RowInsertDatabase interface {
+	// InsertRow 插入一行，columns 为 GetTableColumns 返回的列信息，values 中未出现的列使用数据库默认值
+	// 返回新行的主键（列名到值）：包含 values 中提供的主键值以及驱动能够取回的生成值，表没有主键时返回 nil
+	InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error)
+}
+
+// RowSnapshotDatabase 支持按键读取整行的数据库接口扩展，用于在修改前保存旧值以便撤销
+// 返回的值保持驱动读取到的类型（二进制列为 []byte，MongoDB 保留 ObjectID 等 BSON 类型），
+// 可以原样交给同一个驱动的 UpdateRows 和 InsertRow 写回
+type RowSnapshotDatabase interface {
+	// SnapshotRows 返回满足 keys 的行的全部列
+	SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error)
+}
+
+// sqlExecer 可执行带参数语句的对象（*sql.DB 和 *sql.Tx）
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// sqlQueryExecer 可执行语句并读取单行结果的对象（*sql.DB 和 *sql.Tx）
+type sqlQueryExecer interface {
+	sqlExecer
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// sqlTableRef 返回各方言引用后的表名
+func sqlTableRef(dbType, tableName string) string {
+	switch dbType {
+	case "h2":
+		// H2 的表名默认为大写，不加引号以兼容不同的大小写模式
+		return strings.ToUpper(tableName)
+	default:
+		return getQuoteFunc(dbType)(tableName)
+	}
+}
+
+// validateIdentifier 校验表名、列名，禁止包含引号和方括号，避免破坏标识符引用
+func validateIdentifier(name string) error {
+	if name == "" {
+		return fmt.Errorf("identifier is empty")
+	}
+	if strings.ContainsAny(name, "`\"[]") {
+		return fmt.Errorf("invalid identifier: %s", name)
+	}
+	return nil
+}
+
+// sortedKeys 返回按名称排序的列名，保证生成的语句稳定
+func sortedKeys(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildUpdateRowsSQL 构建参数化的 UPDATE 语句
+// keys 不能为空，避免误更新整张表
+func BuildUpdateRowsSQL(dbType, tableName string, keys, values map[string]interface{}) (string, []interface{}, error) {
+	if len(values) == 0 {
+		return "", nil, fmt.Errorf("no values to update")
+	}
+	if err := validateIdentifier(tableName); err != nil {
+		return "", nil, err
+	}
+
+	quote := getQuoteFunc(dbType)
+	placeholder := getPlaceholderFunc(dbType)
+	var args []interface{}
+	sets := make([]string, 0, len(values))
+	for _, name := range sortedKeys(values) {
+		if err := validateIdentifier(name); err != nil {
+			return "", nil, err
+		}
+		args = append(args, values[name])
+		sets = append(sets, fmt.Sprintf("%s = %s", quote(name), placeholder(len(args))))
+	}
+
+	where, whereArgs, err := buildKeyCondition(dbType, keys, len(args)+1)
+	if err != nil {
+		return "", nil, err
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", sqlTableRef(dbType, tableName), strings.Join(sets, ", "), where)
+	return query, append(args, whereArgs...), nil
+}
+
+// BuildDeleteRowsSQL 构建参数化的 DELETE 语句
+// keys 不能为空，避免误删除整张表
+func BuildDeleteRowsSQL(dbType, tableName string, keys map[string]interface{}) (string, []interface{}, error) {
+	if err := validateIdentifier(tableName); err != nil {
+		return "", nil, err
+	}
+	where, args, err := buildKeyCondition(dbType, keys, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", sqlTableRef(dbType, tableName), where), args, nil
+}
+
+// buildKeyCondition 构建定位行的条件，nil 值使用 IS NULL
+func buildKeyCondition(dbType string, keys map[string]interface{}, argIndex int) (string, []interface{}, error) {
+	if len(keys) == 0 {
+		return "", nil, fmt.Errorf("no key columns to identify rows")
+	}
+
+	quote := getQuoteFunc(dbType)
+	placeholder := getPlaceholderFunc(dbType)
+	var args []interface{}
+	conditions := make([]string, 0, len(keys))
+	for _, name := range sortedKeys(keys) {
+		if err := validateIdentifier(name); err != nil {
+			return "", nil, err
+		}
+		if keys[name] == nil {
+			conditions = append(conditions, quote(name)+" IS NULL")
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = %s", quote(name), placeholder(argIndex+len(args))))
+		args = append(args, keys[name])
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// BuildInsertRowSQL 构建参数化的 INSERT 语句
+// returning 为需要返回的列：PostgreSQL 使用 RETURNING，SQL Server 使用 OUTPUT INSERTED，其他方言忽略
+func BuildInsertRowSQL(dbType, tableName string, values map[string]interface{}, returning []string) (string, []interface{}, error) {
+	return buildInsertRowSQL(dbType, tableName, values, returning, false)
+}
+
+// buildInsertRowSQL 构建参数化的 INSERT 语句，identity 为 true 时 values 中包含标识列的值：
+// PostgreSQL 加上 OVERRIDING SYSTEM VALUE，SQL Server 在插入前后打开和关闭 IDENTITY_INSERT，其他方言可以直接指定
+func buildInsertRowSQL(dbType, tableName string, values map[string]interface{}, returning []string, identity bool) (string, []interface{}, error) {
+	if err := validateIdentifier(tableName); err != nil {
+		return "", nil, err
+	}
+
+	quote := getQuoteFunc(dbType)
+	placeholder := getPlaceholderFunc(dbType)
+	names := sortedKeys(values)
+	columns := make([]string, 0, len(names))
+	holders := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		if err := validateIdentifier(name); err != nil {
+			return "", nil, err
+		}
+		args = append(args, values[name])
+		columns = append(columns, quote(name))
+		holders = append(holders, placeholder(len(args)))
+	}
+	for _, name := range returning {
+		if err := validateIdentifier(name); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var output, suffix string
+	if len(returning) > 0 {
+		quoted := make([]string, len(returning))
+		switch dbType {
+		case "postgresql":
+			for i, name := range returning {
+				quoted[i] = quote(name)
+			}
+			suffix = " RETURNING " + strings.Join(quoted, ", ")
+		case "sqlserver":
+			for i, name := range returning {
+				quoted[i] = "INSERTED." + quote(name)
+			}
+			output = " OUTPUT " + strings.Join(quoted, ", ")
+		}
+	}
+
+	table := sqlTableRef(dbType, tableName)
+	if len(columns) == 0 {
+		// 所有列都使用默认值
+		switch dbType {
+		case "mysql":
+			return fmt.Sprintf("INSERT INTO %s () VALUES ()", table), nil, nil
+		case "oracle", "clickhouse":
+			return "", nil, fmt.Errorf("at least one column value is required")
+		default:
+			return fmt.Sprintf("INSERT INTO %s%s DEFAULT VALUES%s", table, output, suffix), nil, nil
+		}
+	}
+	var overriding string
+	if identity && dbType == "postgresql" {
+		overriding = " OVERRIDING SYSTEM VALUE"
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s)%s%s VALUES (%s)%s", table, strings.Join(columns, ", "), output, overriding, strings.Join(holders, ", "), suffix)
+	if identity && dbType == "sqlserver" {
+		// 插入失败时也要关闭 IDENTITY_INSERT，避免连接池中的连接残留该设置
+		query = fmt.Sprintf("SET IDENTITY_INSERT %[1]s ON; BEGIN TRY %[2]s; END TRY BEGIN CATCH SET IDENTITY_INSERT %[1]s OFF; THROW; END CATCH; SET IDENTITY_INSERT %[1]s OFF", table, query)
+	}
+	return query, args, nil
+}
+
+// DescribeRowChange 返回按键修改行时执行的语句，用于变更集预览
+// op 为 insert、update 或 delete；SQL 数据库返回参数化语句和参数，MongoDB、Elasticsearch 和 Redis 返回等价操作的文字描述
+func DescribeRowChange(dbType, tableName, op string, keys, values map[string]interface{}) (string, []interface{}, error) {
+	switch op {
+	case "insert", "update", "delete":
+	default:
+		return "", nil, fmt.Errorf("unknown operation: %s", op)
+	}
+
+	toJSON := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+	switch dbType {
+	case "mongodb":
+		switch op {
+		case "insert":
+			return fmt.Sprintf("db.%s.insertOne(%s)", tableName, toJSON(values)), nil, nil
+		case "update":
+			return fmt.Sprintf("db.%s.updateMany(%s, %s)", tableName, toJSON(keys), toJSON(map[string]interface{}{"$set": values})), nil, nil
+		default:
+			return fmt.Sprintf("db.%s.deleteMany(%s)", tableName, toJSON(keys)), nil, nil
+		}
+	case "elasticsearch":
+		id := fmt.Sprint(keys["_id"])
+		switch op {
+		case "insert":
+			if v, ok := values["_id"]; ok && v != nil {
+				return fmt.Sprintf("PUT /%s/_create/%v %s", tableName, v, toJSON(values)), nil, nil
+			}
+			return fmt.Sprintf("POST /%s/_doc %s", tableName, toJSON(values)), nil, nil
+		case "update":
+			return fmt.Sprintf("POST /%s/_update/%s %s", tableName, id, toJSON(map[string]interface{}{"doc": values})), nil, nil
+		default:
+			return fmt.Sprintf("DELETE /%s/_doc/%s", tableName, id), nil, nil
+		}
+	case "redis":
+		desc := map[string]interface{}{}
+		if len(keys) > 0 {
+			desc["where"] = keys
+		}
+		if len(values) > 0 {
+			desc["values"] = values
+		}
+		return fmt.Sprintf("%s %s %s", strings.ToUpper(op), tableName, toJSON(desc)), nil, nil
+	}
+
+	switch op {
+	case "insert":
+		return BuildInsertRowSQL(dbType, tableName, values, nil)
+	case "update":
+		return BuildUpdateRowsSQL(dbType, tableName, keys, values)
+	default:
+		return BuildDeleteRowsSQL(dbType, tableName, keys)
+	}
+}
+
+// insertSQLRow 使用参数化语句插入一行，并尽量取回数据库生成的主键
+// PostgreSQL 和 SQL Server 通过 RETURNING/OUTPUT 取回未提供的主键列，
+// MySQL 和 SQLite 通过 LastInsertId 取回唯一的自增主键，其他方言只返回提供的主键值
+// 生成列的值被忽略；标识列的值为 nil 时由数据库生成，否则按提供的值插入（例如撤销删除时还原原来的行）
+func insertSQLRow(ctx context.Context, db sqlQueryExecer, dbType, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	if db == nil || reflect.ValueOf(db).IsNil() {
+		return nil, fmt.Errorf("database not connected")
+	}
+
+	values, identity := writableValues(columns, values)
+
+	var keys map[string]interface{}
+	var generated []ColumnInfo
+	for _, col := range columns {
+		if col.Key != "PRI" {
+			continue
+		}
+		if keys == nil {
+			keys = make(map[string]interface{})
+		}
+		if v, ok := values[col.Name]; ok {
+			keys[col.Name] = v
+		} else {
+			generated = append(generated, col)
+		}
+	}
+
+	var returning []string
+	if dbType == "postgresql" || dbType == "sqlserver" {
+		for _, col := range generated {
+			returning = append(returning, col.Name)
+		}
+	}
+	query, args, err := buildInsertRowSQL(dbType, tableName, values, returning, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(returning) > 0 {
+		dest := make([]interface{}, len(returning))
+		ptrs := make([]interface{}, len(returning))
+		for i := range dest {
+			ptrs[i] = &dest[i]
+		}
+		if err := db.QueryRowContext(ctx, query, args...).Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to insert row: %w", err)
+		}
+		for i, name := range returning {
+			if b, ok := dest[i].([]byte); ok {
+				keys[name] = string(b)
+			} else {
+				keys[name] = dest[i]
+			}
+		}
+		return keys, nil
+	}
+
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert row: %w", err)
+	}
+	if (dbType == "mysql" || dbType == "sqlite") && len(generated) == 1 && generated[0].AutoIncrement {
+		if id, err := result.LastInsertId(); err == nil {
+			keys[generated[0].Name] = id
+		}
+	}
+	return keys, nil
+}
+
+// writableValues 去掉生成列和值为 nil 的标识列，并返回是否为标识列指定了值
+func writableValues(columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, bool) {
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	identity := false
+	for _, col := range columns {
+		v, ok := result[col.Name]
+		switch {
+		case !ok:
+		case col.Generated, col.AutoIncrement && v == nil:
+			delete(result, col.Name)
+		case col.AutoIncrement:
+			identity = true
+		}
+	}
+	return result, identity
+}
+
+// coerceToColumnType 按列类型转换字符串值，用于没有固定表结构的数据库（MongoDB、Elasticsearch）插入数据
+// 无法转换时保留字符串
+func coerceToColumnType(columnType string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	lower := strings.ToLower(columnType)
+	switch {
+	case lower == "long" || lower == "short" || lower == "byte" || strings.Contains(lower, "int"):
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return n
+		}
+	case strings.Contains(lower, "float") || strings.Contains(lower, "double"):
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			return f
+		}
+	case strings.HasPrefix(lower, "bool"):
+		if b, err := strconv.ParseBool(str); err == nil {
+			return b
+		}
+	case lower == "object" || lower == "nested" || lower == "primitive.m" || lower == "primitive.a" ||
+		strings.HasPrefix(lower, "map[") || strings.HasPrefix(lower, "[]"):
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(str), &parsed); err == nil {
+			return parsed
+		}
+	}
+	return str
+}
+
+// updateSQLRows 使用参数化语句更新行
+func updateSQLRows(ctx context.Context, db sqlExecer, dbType, tableName string, keys, values map[string]interface{}) (int64, error) {
+	query, args, err := BuildUpdateRowsSQL(dbType, tableName, keys, values)
+	if err != nil {
+		return 0, err
+	}
+	return execSQLRows(ctx, db, query, args, "update")
+}
+
+// deleteSQLRows 使用参数化语句删除行
+func deleteSQLRows(ctx context.Context, db sqlExecer, dbType, tableName string, keys map[string]interface{}) (int64, error) {
+	query, args, err := BuildDeleteRowsSQL(dbType, tableName, keys)
+	if err != nil {
+		return 0, err
+	}
+	return execSQLRows(ctx, db, query, args, "delete")
+}
+
+// snapshotSQLRows 使用参数化语句按键读取整行，二进制值保留为 []byte 以便原样写回
+func snapshotSQLRows(ctx context.Context, db sqlQueryer, dbType, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	if db == nil || reflect.ValueOf(db).IsNil() {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if err := validateIdentifier(tableName); err != nil {
+		return nil, err
+	}
+	where, args, err := buildKeyCondition(dbType, keys, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE %s", sqlTableRef(dbType, tableName), where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to read rows: %w", err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
+
+// execSQLRows 执行语句并返回影响行数
+func execSQLRows(ctx context.Context, db sqlExecer, query string, args []interface{}, action string) (int64, error) {
+	if db == nil || reflect.ValueOf(db).IsNil() {
+		return 0, fmt.Errorf("database not connected")
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to %s rows: %w", action, err)
+	}
+	return result.RowsAffected()
+}
+
+// coerceToOriginal 按原值的类型转换编辑后的值（前端编辑表单提交的都是字符串）
+// 返回转换后的值以及与原值相比是否发生了变化；无法转换时保留字符串
+func coerceToOriginal(original, value interface{}) (interface{}, bool) {
+	str, ok := value.(string)
+	if !ok || original == nil {
+		return value, !reflect.DeepEqual(original, value)
+	}
+
+	var coerced interface{} = str
+	switch original.(type) {
+	case float64, float32:
+		if f, err := strconv.ParseFloat(str, 64); err == nil {
+			coerced = f
+		}
+	case int, int32, int64:
+		if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+			coerced = n
+		} else if f, err := strconv.ParseFloat(str, 64); err == nil {
+			coerced = f
+		}
+	case bool:
+		if b, err := strconv.ParseBool(str); err == nil {
+			coerced = b
+		}
+	default:
+		// 嵌套对象和数组（包括驱动自定义的 map/slice 类型）按 JSON 解析
+		switch reflect.ValueOf(original).Kind() {
+		case reflect.Map, reflect.Slice:
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(str), &parsed); err == nil {
+				coerced = parsed
+			}
+		}
+	}
+	return coerced, !equalLoosely(original, coerced)
+}
+
+// equalLoosely 比较两个值，数字按数值比较
+func equalLoosely(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			return fa == fb
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	// 文档中的嵌套对象与 JSON 解析结果类型可能不同，按 JSON 序列化结果比较
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
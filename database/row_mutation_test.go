@@ -0,0 +1,278 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildUpdateRowsSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		table    string
+		keys     map[string]interface{}
+		values   map[string]interface{}
+		expected string
+		args     []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "MySQL 反引号和问号占位符",
+			dbType:   "mysql",
+			table:    "users",
+			keys:     map[string]interface{}{"id": int64(1)},
+			values:   map[string]interface{}{"name": "O'Brien", "age": nil},
+			expected: "UPDATE `users` SET `age` = ?, `name` = ? WHERE `id` = ?",
+			args:     []interface{}{nil, "O'Brien", int64(1)},
+		},
+		{
+			name:     "PostgreSQL 编号占位符和复合主键",
+			dbType:   "postgresql",
+			table:    "order_items",
+			keys:     map[string]interface{}{"order_id": int64(7), "line": int64(2)},
+			values:   map[string]interface{}{"qty": "3"},
+			expected: `UPDATE "order_items" SET "qty" = $1 WHERE "line" = $2 AND "order_id" = $3`,
+			args:     []interface{}{"3", int64(2), int64(7)},
+		},
+		{
+			name:     "SQL Server 方括号和 NULL 键",
+			dbType:   "sqlserver",
+			table:    "t",
+			keys:     map[string]interface{}{"code": nil, "id": "a"},
+			values:   map[string]interface{}{"v": "x"},
+			expected: "UPDATE [t] SET [v] = @p1 WHERE [code] IS NULL AND [id] = @p2",
+			args:     []interface{}{"x", "a"},
+		},
+		{
+			name:     "Oracle 大写标识符",
+			dbType:   "oracle",
+			table:    "emp",
+			keys:     map[string]interface{}{"empno": int64(10)},
+			values:   map[string]interface{}{"ename": "KING"},
+			expected: `UPDATE "EMP" SET "ENAME" = :1 WHERE "EMPNO" = :2`,
+			args:     []interface{}{"KING", int64(10)},
+		},
+		{
+			name:    "没有定位条件",
+			dbType:  "mysql",
+			table:   "users",
+			keys:    map[string]interface{}{},
+			values:  map[string]interface{}{"name": "a"},
+			wantErr: true,
+		},
+		{
+			name:    "列名包含引号",
+			dbType:  "mysql",
+			table:   "users",
+			keys:    map[string]interface{}{"id": int64(1)},
+			values:  map[string]interface{}{"name` = 1, `x": "a"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := BuildUpdateRowsSQL(tt.dbType, tt.table, tt.keys, tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("query = %q, want %q", query, tt.expected)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBuildDeleteRowsSQL(t *testing.T) {
+	query, args, err := BuildDeleteRowsSQL("sqlite", "logs", map[string]interface{}{"id": int64(5), "deleted_at": nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "DELETE FROM `logs` WHERE `deleted_at` IS NULL AND `id` = ?"; query != expected {
+		t.Errorf("query = %q, want %q", query, expected)
+	}
+	if !reflect.DeepEqual(args, []interface{}{int64(5)}) {
+		t.Errorf("args = %#v", args)
+	}
+
+	if _, _, err := BuildDeleteRowsSQL("sqlite", "logs", nil); err == nil {
+		t.Error("expected error for empty keys")
+	}
+}
+
+func TestBuildInsertRowSQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		dbType    string
+		values    map[string]interface{}
+		returning []string
+		identity  bool
+		expected  string
+		args      []interface{}
+	}{
+		{
+			name:     "MySQL",
+			dbType:   "mysql",
+			values:   map[string]interface{}{"name": "a", "age": int64(3)},
+			expected: "INSERT INTO `t` (`age`, `name`) VALUES (?, ?)",
+			args:     []interface{}{int64(3), "a"},
+		},
+		{
+			name:      "PostgreSQL RETURNING",
+			dbType:    "postgresql",
+			values:    map[string]interface{}{"name": "a"},
+			returning: []string{"id"},
+			expected:  `INSERT INTO "t" ("name") VALUES ($1) RETURNING "id"`,
+			args:      []interface{}{"a"},
+		},
+		{
+			name:      "SQL Server OUTPUT INSERTED",
+			dbType:    "sqlserver",
+			values:    map[string]interface{}{"name": "a"},
+			returning: []string{"id"},
+			expected:  "INSERT INTO [t] ([name]) OUTPUT INSERTED.[id] VALUES (@p1)",
+			args:      []interface{}{"a"},
+		},
+		{
+			name:     "PostgreSQL 指定标识列的值",
+			dbType:   "postgresql",
+			values:   map[string]interface{}{"id": int64(7), "name": "a"},
+			identity: true,
+			expected: `INSERT INTO "t" ("id", "name") OVERRIDING SYSTEM VALUE VALUES ($1, $2)`,
+			args:     []interface{}{int64(7), "a"},
+		},
+		{
+			name:     "SQL Server 指定标识列的值",
+			dbType:   "sqlserver",
+			values:   map[string]interface{}{"id": int64(7)},
+			identity: true,
+			expected: "SET IDENTITY_INSERT [t] ON; BEGIN TRY INSERT INTO [t] ([id]) VALUES (@p1); END TRY BEGIN CATCH SET IDENTITY_INSERT [t] OFF; THROW; END CATCH; SET IDENTITY_INSERT [t] OFF",
+			args:     []interface{}{int64(7)},
+		},
+		{
+			name:     "MySQL 指定自增列的值",
+			dbType:   "mysql",
+			values:   map[string]interface{}{"id": int64(7)},
+			identity: true,
+			expected: "INSERT INTO `t` (`id`) VALUES (?)",
+			args:     []interface{}{int64(7)},
+		},
+		{
+			name:      "全部使用默认值",
+			dbType:    "sqlserver",
+			values:    map[string]interface{}{},
+			returning: []string{"id"},
+			expected:  "INSERT INTO [t] OUTPUT INSERTED.[id] DEFAULT VALUES",
+		},
+		{
+			name:     "MySQL 全部使用默认值",
+			dbType:   "mysql",
+			values:   nil,
+			expected: "INSERT INTO `t` () VALUES ()",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := buildInsertRowSQL(tt.dbType, "t", tt.values, tt.returning, tt.identity)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if query != tt.expected {
+				t.Errorf("query = %q, want %q", query, tt.expected)
+			}
+			if len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+
+	if _, _, err := BuildInsertRowSQL("oracle", "t", nil, nil); err == nil {
+		t.Error("expected error for oracle insert without values")
+	}
+}
+
+func TestWritableValues(t *testing.T) {
+	columns := []ColumnInfo{
+		{Name: "id", Key: "PRI", AutoIncrement: true},
+		{Name: "name"},
+		{Name: "total", Generated: true},
+	}
+
+	values, identity := writableValues(columns, map[string]interface{}{"id": int64(7), "name": "a", "total": int64(3)})
+	if !identity || !reflect.DeepEqual(values, map[string]interface{}{"id": int64(7), "name": "a"}) {
+		t.Errorf("values = %#v, identity = %v", values, identity)
+	}
+
+	values, identity = writableValues(columns, map[string]interface{}{"id": nil, "name": "a"})
+	if identity || !reflect.DeepEqual(values, map[string]interface{}{"name": "a"}) {
+		t.Errorf("values = %#v, identity = %v", values, identity)
+	}
+}
+
+func TestDescribeRowChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		op       string
+		keys     map[string]interface{}
+		values   map[string]interface{}
+		expected string
+	}{
+		{"SQL 使用参数化语句", "mysql", "delete", map[string]interface{}{"id": int64(1)}, nil, "DELETE FROM `t` WHERE `id` = ?"},
+		{"MongoDB", "mongodb", "update", map[string]interface{}{"_id": "a"}, map[string]interface{}{"n": int64(1)}, `db.t.updateMany({"_id":"a"}, {"$set":{"n":1}})`},
+		{"Elasticsearch 指定 _id 新增", "elasticsearch", "insert", nil, map[string]interface{}{"_id": "x"}, `PUT /t/_create/x {"_id":"x"}`},
+		{"Redis", "redis", "delete", map[string]interface{}{"key": "k"}, nil, `DELETE t {"where":{"key":"k"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, _, err := DescribeRowChange(tt.dbType, "t", tt.op, tt.keys, tt.values)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if statement != tt.expected {
+				t.Errorf("statement = %q, want %q", statement, tt.expected)
+			}
+		})
+	}
+
+	if _, _, err := DescribeRowChange("mysql", "t", "upsert", nil, nil); err == nil {
+		t.Error("expected error for unknown operation")
+	}
+}
+
+func TestCoerceToOriginal(t *testing.T) {
+	tests := []struct {
+		name     string
+		original interface{}
+		value    interface{}
+		expected interface{}
+		changed  bool
+	}{
+		{"数字", float64(3), "4.5", float64(4.5), true},
+		{"数字未变化", int32(3), "3", int64(3), false},
+		{"布尔", true, "false", false, true},
+		{"对象", map[string]interface{}{"a": float64(1)}, `{"a":1}`, map[string]interface{}{"a": float64(1)}, false},
+		{"无法转换时保留字符串", float64(1), "abc", "abc", true},
+		{"新字段", nil, "x", "x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := coerceToOriginal(tt.original, tt.value)
+			if !reflect.DeepEqual(got, tt.expected) || changed != tt.changed {
+				t.Errorf("coerceToOriginal(%v, %v) = %#v, %v; want %#v, %v", tt.original, tt.value, got, changed, tt.expected, tt.changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+)
+
+// RowIterator 查询结果行迭代器
+// 先通过 Columns 获取列名，再通过 Next/Values 逐行读取，使用完毕后必须调用 Close
+type RowIterator interface {
+	// Columns 返回结果集的列名（按查询顺序）
+	Columns() []string
+
+	// ColumnTypes 返回结果集的列信息（类型和可空性取决于驱动是否提供）
+	ColumnTypes() []ResultColumn
+
+	// Next 移动到下一行，没有更多数据或出错时返回 false
+	Next() bool
+
+	// Values 返回当前行的值，顺序与 Columns 一致，每次调用返回新的切片
+	Values() []interface{}
+
+	// Err 返回迭代过程中发生的错误
+	Err() error
+
+	// Close 释放底层资源
+	Close() error
+}
+
+// StreamingDatabase 支持流式读取查询结果的数据库接口扩展
+// 实现该接口的驱动不需要把整个结果集加载到内存
+type StreamingDatabase interface {
+	// QueryRows 执行查询并返回行迭代器
+	QueryRows(ctx context.Context, query string) (RowIterator, error)
+}
+
+// StreamQuery 流式执行查询
+// 驱动实现了 StreamingDatabase 时直接使用，否则回退到 ExecuteQueryContext 并包装结果
+func StreamQuery(ctx context.Context, db Database, query string) (RowIterator, error) {
+	if sdb, ok := db.(StreamingDatabase); ok {
+		return sdb.QueryRows(ctx, query)
+	}
+	results, err := AsContextDatabase(db).ExecuteQueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return NewSliceRowIterator(results), nil
+}
+
+// sqlRowIterator 基于 *sql.Rows 的行迭代器
+type sqlRowIterator struct {
+	rows        *sql.Rows
+	columns     []string
+	columnTypes []ResultColumn
+	values      []interface{}
+	err         error
+}
+
+// NewSQLRowIterator 将 *sql.Rows 包装为 RowIterator
+// 值按列类型转换：二进制类型为 BinaryValue，JSON 类型为 json.RawMessage，其他 []byte 为字符串
+func NewSQLRowIterator(rows *sql.Rows) (RowIterator, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	columnTypes := make([]ResultColumn, len(columns))
+	for i, name := range columns {
+		columnTypes[i].Name = name
+	}
+	// 部分驱动不支持列类型信息，此时只保留列名
+	if types, err := rows.ColumnTypes(); err == nil && len(types) == len(columns) {
+		for i, ct := range types {
+			columnTypes[i].Type = ct.DatabaseTypeName()
+			if nullable, ok := ct.Nullable(); ok {
+				columnTypes[i].Nullable = &nullable
+			}
+		}
+	}
+
+	return &sqlRowIterator{rows: rows, columns: columns, columnTypes: columnTypes}, nil
+}
+
+func (it *sqlRowIterator) Columns() []string {
+	return it.columns
+}
+
+func (it *sqlRowIterator) ColumnTypes() []ResultColumn {
+	return it.columnTypes
+}
+
+func (it *sqlRowIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+
+	values := make([]interface{}, len(it.columns))
+	valuePtrs := make([]interface{}, len(it.columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	if err := it.rows.Scan(valuePtrs...); err != nil {
+		it.err = err
+		return false
+	}
+
+	for i, val := range values {
+		values[i] = ConvertValue(it.columnTypes[i].Type, val, BinaryEncodingHex)
+	}
+	it.values = values
+	return true
+}
+
+func (it *sqlRowIterator) Values() []interface{} {
+	return it.values
+}
+
+func (it *sqlRowIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+func (it *sqlRowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// sliceRowIterator 基于已加载结果的行迭代器，用于不支持流式查询的驱动
+type sliceRowIterator struct {
+	rows    []map[string]interface{}
+	columns []string
+	index   int
+}
+
+// NewSliceRowIterator 将 []map[string]interface{} 结果包装为 RowIterator
+// 列名取所有行中出现过的键，按首次出现的顺序排列（同一行内按字母序）
+func NewSliceRowIterator(rows []map[string]interface{}) RowIterator {
+	seen := make(map[string]bool)
+	columns := make([]string, 0)
+	for _, row := range rows {
+		keys := make([]string, 0, len(row))
+		for k := range row {
+			if !seen[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			seen[k] = true
+			columns = append(columns, k)
+		}
+	}
+	return &sliceRowIterator{rows: rows, columns: columns, index: -1}
+}
+
+func (it *sliceRowIterator) Columns() []string {
+	return it.columns
+}
+
+func (it *sliceRowIterator) ColumnTypes() []ResultColumn {
+	columnTypes := make([]ResultColumn, len(it.columns))
+	for i, name := range it.columns {
+		columnTypes[i].Name = name
+	}
+	return columnTypes
+}
+
+func (it *sliceRowIterator) Next() bool {
+	if it.index+1 >= len(it.rows) {
+		return false
+	}
+	it.index++
+	return true
+}
+
+func (it *sliceRowIterator) Values() []interface{} {
+	row := it.rows[it.index]
+	values := make([]interface{}, len(it.columns))
+	for i, col := range it.columns {
+		values[i] = row[col]
+	}
+	return values
+}
+
+func (it *sliceRowIterator) Err() error {
+	return nil
+}
+
+func (it *sliceRowIterator) Close() error {
+	it.rows = nil
+	return nil
+}
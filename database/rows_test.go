@@ -0,0 +1,62 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSliceRowIterator(t *testing.T) {
+	tests := []struct {
+		name            string
+		rows            []map[string]interface{}
+		expectedColumns []string
+		expectedValues  [][]interface{}
+	}{
+		{
+			name:            "空结果集",
+			rows:            []map[string]interface{}{},
+			expectedColumns: []string{},
+			expectedValues:  nil,
+		},
+		{
+			name: "同一行内的列按字母序排列",
+			rows: []map[string]interface{}{
+				{"name": "a", "id": 1},
+				{"name": "b", "id": 2},
+			},
+			expectedColumns: []string{"id", "name"},
+			expectedValues:  [][]interface{}{{1, "a"}, {2, "b"}},
+		},
+		{
+			name: "后续行出现的新列追加到末尾，缺失的列为 nil",
+			rows: []map[string]interface{}{
+				{"id": 1},
+				{"id": 2, "extra": true},
+			},
+			expectedColumns: []string{"id", "extra"},
+			expectedValues:  [][]interface{}{{1, nil}, {2, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iter := NewSliceRowIterator(tt.rows)
+			defer iter.Close()
+
+			if !reflect.DeepEqual(iter.Columns(), tt.expectedColumns) {
+				t.Fatalf("columns = %v, want %v", iter.Columns(), tt.expectedColumns)
+			}
+
+			var values [][]interface{}
+			for iter.Next() {
+				values = append(values, iter.Values())
+			}
+			if err := iter.Err(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(values, tt.expectedValues) {
+				t.Errorf("values = %v, want %v", values, tt.expectedValues)
+			}
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 表差异的状态，以源库为准：added 表示只在源库中存在（目标库需要添加），removed 表示只在目标库中存在
+const (
+	SchemaDiffAdded   = "added"
+	SchemaDiffRemoved = "removed"
+	SchemaDiffChanged = "changed"
+)
+
+// ColumnDiff 两个库中同名列的差异
+type ColumnDiff struct {
+	Name    string     `json:"name"`
+	Source  ColumnInfo `json:"source"`
+	Target  ColumnInfo `json:"target"`
+	Changes []string   `json:"changes"` // 不同的属性：type、nullable、default、key、autoIncrement
+}
+
+// TableDiff 一张表的差异
+type TableDiff struct {
+	Name           string       `json:"name"`       // 源库中的表名（removed 时为目标库中的表名）
+	TargetName     string       `json:"targetName"` // 目标库中的表名（大小写可能与源库不同）
+	Status         string       `json:"status"`     // added、removed、changed
+	Columns        []ColumnInfo `json:"columns"`    // added/removed 时为表的所有列
+	AddedColumns   []ColumnInfo `json:"addedColumns"`
+	RemovedColumns []ColumnInfo `json:"removedColumns"`
+	ChangedColumns []ColumnDiff `json:"changedColumns"`
+}
+
+// SchemaDiff 两个库的表结构差异
+type SchemaDiff struct {
+	SourceType string      `json:"sourceType"`
+	TargetType string      `json:"targetType"`
+	Tables     []TableDiff `json:"tables"`
+	Unchanged  []string    `json:"unchanged"` // 结构相同的表
+}
+
+// LoadSchema 读取库中表的列信息，tables 为空时读取所有表，否则只读取其中存在的表（表名不区分大小写）
+func LoadSchema(ctx context.Context, db Database, tables []string) (map[string][]ColumnInfo, error) {
+	names, err := db.GetTables()
+	if err != nil {
+		return nil, err
+	}
+	schema := make(map[string][]ColumnInfo, len(names))
+	for _, table := range names {
+		if len(tables) > 0 && !containsFold(tables, table) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		columns, err := db.GetTableColumns(table)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", table, err)
+		}
+		schema[table] = columns
+	}
+	return schema, nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareSchemas 比较源库和目标库的表结构，表名和列名不区分大小写匹配
+// 同类型数据库之间按类型名称比较，不同类型之间按类型的通用分类（见 parseColumnType）比较；默认值只在同类型数据库之间比较
+func CompareSchemas(sourceType string, source map[string][]ColumnInfo, targetType string, target map[string][]ColumnInfo) SchemaDiff {
+	diff := SchemaDiff{SourceType: sourceType, TargetType: targetType, Tables: []TableDiff{}, Unchanged: []string{}}
+
+	targetNames := make(map[string]string, len(target))
+	for name := range target {
+		targetNames[strings.ToLower(name)] = name
+	}
+	matched := make(map[string]bool, len(target))
+
+	for _, name := range sortedSchemaTables(source) {
+		tname := name
+		_, ok := target[name]
+		if !ok {
+			tname, ok = targetNames[strings.ToLower(name)]
+			ok = ok && !matched[tname]
+		}
+		if !ok {
+			diff.Tables = append(diff.Tables, TableDiff{Name: name, Status: SchemaDiffAdded, Columns: source[name]})
+			continue
+		}
+		matched[tname] = true
+		table := compareTable(sourceType, source[name], targetType, target[tname])
+		if len(table.AddedColumns) == 0 && len(table.RemovedColumns) == 0 && len(table.ChangedColumns) == 0 {
+			diff.Unchanged = append(diff.Unchanged, name)
+			continue
+		}
+		table.Name, table.TargetName, table.Status = name, tname, SchemaDiffChanged
+		diff.Tables = append(diff.Tables, table)
+	}
+	for _, name := range sortedSchemaTables(target) {
+		if !matched[name] {
+			diff.Tables = append(diff.Tables, TableDiff{Name: name, TargetName: name, Status: SchemaDiffRemoved, Columns: target[name]})
+		}
+	}
+	return diff
+}
+
+// compareTable 比较同一张表在两个库中的列，添加和修改的列按源库中的顺序，删除的列按目标库中的顺序
+func compareTable(sourceType string, source []ColumnInfo, targetType string, target []ColumnInfo) TableDiff {
+	table := TableDiff{AddedColumns: []ColumnInfo{}, RemovedColumns: []ColumnInfo{}, ChangedColumns: []ColumnDiff{}}
+	matched := make([]bool, len(target))
+	for _, col := range source {
+		index := -1
+		for i, t := range target {
+			if t.Name == col.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			for i, t := range target {
+				if !matched[i] && strings.EqualFold(t.Name, col.Name) {
+					index = i
+					break
+				}
+			}
+		}
+		if index < 0 {
+			table.AddedColumns = append(table.AddedColumns, col)
+			continue
+		}
+		matched[index] = true
+		if changes := compareColumn(sourceType, col, targetType, target[index]); len(changes) > 0 {
+			table.ChangedColumns = append(table.ChangedColumns, ColumnDiff{Name: col.Name, Source: col, Target: target[index], Changes: changes})
+		}
+	}
+	for i, col := range target {
+		if !matched[i] {
+			table.RemovedColumns = append(table.RemovedColumns, col)
+		}
+	}
+	return table
+}
+
+// compareColumn 返回两列不同的属性
+func compareColumn(sourceType string, source ColumnInfo, targetType string, target ColumnInfo) []string {
+	var changes []string
+	if sourceType == targetType {
+		if normalizeColumnType(source.Type) != normalizeColumnType(target.Type) {
+			changes = append(changes, "type")
+		}
+	} else if parseColumnType(sourceType, source.Type) != parseColumnType(targetType, target.Type) {
+		changes = append(changes, "type")
+	}
+	if source.Nullable != target.Nullable {
+		changes = append(changes, "nullable")
+	}
+	if sourceType == targetType && strings.TrimSpace(source.DefaultValue) != strings.TrimSpace(target.DefaultValue) {
+		changes = append(changes, "default")
+	}
+	if source.Key != target.Key {
+		changes = append(changes, "key")
+	}
+	if source.AutoIncrement != target.AutoIncrement {
+		changes = append(changes, "autoIncrement")
+	}
+	return changes
+}
+
+// normalizeColumnType 忽略大小写和空白比较类型名称
+func normalizeColumnType(columnType string) string {
+	return strings.ToLower(strings.Join(strings.Fields(columnType), ""))
+}
+
+// sortedSchemaTables 返回按名称排序的表名
+func sortedSchemaTables(m map[string][]ColumnInfo) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SchemaSyncOptions 生成同步脚本的选项，删除表和列默认不生成
+type SchemaSyncOptions struct {
+	DropTables  bool `json:"dropTables"`  // 删除只在目标库中存在的表
+	DropColumns bool `json:"dropColumns"` // 删除只在目标库中存在的列
+}
+
+// BuildSchemaSyncSQL 生成使目标库的表结构与源库一致的语句（目标库的方言）
+// 返回的 warnings 为无法自动生成的变更（如主键、自增属性和 SQLite 的列修改），需要手动处理
+func BuildSchemaSyncSQL(diff SchemaDiff, options SchemaSyncOptions) (statements []string, warnings []string, err error) {
+	sourceType, targetType := diff.SourceType, diff.TargetType
+	if !SupportsCreateTable(targetType) {
+		return nil, nil, fmt.Errorf("schema sync is not supported for %s", targetType)
+	}
+	quote := getQuoteFunc(targetType)
+
+	for _, table := range diff.Tables {
+		switch table.Status {
+		case SchemaDiffAdded:
+			columns, err := syncColumns(sourceType, targetType, table.Columns)
+			if err != nil {
+				return nil, nil, err
+			}
+			query, err := BuildCreateTableSQL(targetType, table.Name, columns)
+			if err != nil {
+				return nil, nil, err
+			}
+			statements = append(statements, query)
+		case SchemaDiffRemoved:
+			if options.DropTables {
+				if err := validateIdentifier(table.Name); err != nil {
+					return nil, nil, err
+				}
+				statements = append(statements, "DROP TABLE "+sqlTableRef(targetType, table.Name))
+			}
+		case SchemaDiffChanged:
+			if err := validateIdentifier(table.TargetName); err != nil {
+				return nil, nil, err
+			}
+			ref := sqlTableRef(targetType, table.TargetName)
+			added, err := syncColumns(sourceType, targetType, table.AddedColumns)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i, col := range added {
+				definition := columnDefinition(targetType, col)
+				if sourceType == targetType && table.AddedColumns[i].DefaultValue != "" {
+					definition += " DEFAULT " + defaultExpression(targetType, table.AddedColumns[i].DefaultValue)
+				}
+				statements = append(statements, addColumnSQL(targetType, ref, definition))
+			}
+			if options.DropColumns {
+				for _, col := range table.RemovedColumns {
+					if err := validateIdentifier(col.Name); err != nil {
+						return nil, nil, err
+					}
+					statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", ref, quote(col.Name)))
+				}
+			}
+			for _, change := range table.ChangedColumns {
+				stmts, warns, err := alterColumnSQL(sourceType, targetType, ref, table.TargetName, change)
+				if err != nil {
+					return nil, nil, err
+				}
+				statements = append(statements, stmts...)
+				warnings = append(warnings, warns...)
+			}
+		}
+	}
+	return statements, warnings, nil
+}
+
+// syncColumns 返回列在目标库中的定义：同类型数据库之间保留原类型，否则按 TranslateColumns 转换
+func syncColumns(sourceType, targetType string, columns []ColumnInfo) ([]ColumnInfo, error) {
+	translated, err := TranslateColumns(sourceType, targetType, columns)
+	if err != nil || sourceType != targetType {
+		return translated, err
+	}
+	for i, col := range columns {
+		translated[i].Type = col.Type
+		translated[i].Nullable = col.Nullable
+	}
+	return translated, nil
+}
+
+// columnDefinition 返回列定义，ClickHouse 的可空性由类型表示
+func columnDefinition(dbType string, col ColumnInfo) string {
+	definition := getQuoteFunc(dbType)(col.Name) + " " + col.Type
+	if !col.Nullable && dbType != "clickhouse" {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+// addColumnSQL 添加列的语句
+func addColumnSQL(dbType, ref, definition string) string {
+	switch dbType {
+	case "sqlserver":
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", ref, definition)
+	case "oracle":
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s)", ref, definition)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", ref, definition)
+}
+
+// alterColumnSQL 修改列类型、可空性和默认值的语句；主键和自增属性的变化只返回提示
+func alterColumnSQL(sourceType, targetType, ref, table string, change ColumnDiff) ([]string, []string, error) {
+	var statements, warnings []string
+	has := make(map[string]bool, len(change.Changes))
+	for _, c := range change.Changes {
+		has[c] = true
+	}
+	if has["key"] || has["autoIncrement"] {
+		warnings = append(warnings, fmt.Sprintf("%s.%s: key or auto-increment changes must be applied manually", table, change.Target.Name))
+	}
+	if !has["type"] && !has["nullable"] && !has["default"] {
+		return nil, warnings, nil
+	}
+	if targetType == "sqlite" {
+		warnings = append(warnings, fmt.Sprintf("%s.%s: SQLite cannot alter columns, the table must be rebuilt", table, change.Target.Name))
+		return nil, warnings, nil
+	}
+
+	columns, err := syncColumns(sourceType, targetType, []ColumnInfo{change.Source})
+	if err != nil {
+		return nil, nil, err
+	}
+	col := columns[0]
+	if err := validateIdentifier(change.Target.Name); err != nil {
+		return nil, nil, err
+	}
+	name := getQuoteFunc(targetType)(change.Target.Name)
+	col.Name = change.Target.Name
+	// 主键列的可空性由主键决定
+	col.Nullable = change.Source.Nullable && col.Key != "PRI"
+	sameType := sourceType == targetType
+	defaultValue := ""
+	if change.Source.DefaultValue != "" {
+		defaultValue = defaultExpression(targetType, change.Source.DefaultValue)
+	}
+
+	switch targetType {
+	case "mysql":
+		definition := columnDefinition(targetType, col)
+		if sameType && defaultValue != "" {
+			definition += " DEFAULT " + defaultValue
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", ref, definition))
+		return statements, warnings, nil
+	case "clickhouse":
+		if has["type"] || has["nullable"] {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s", ref, name, col.Type))
+		}
+	case "postgresql", "h2":
+		if has["type"] {
+			keyword := "TYPE"
+			if targetType == "h2" {
+				keyword = "SET DATA TYPE"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s", ref, name, keyword, col.Type))
+		}
+		if has["nullable"] {
+			action := "SET NOT NULL"
+			if col.Nullable {
+				action = "DROP NOT NULL"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", ref, name, action))
+		}
+	case "sqlserver":
+		if has["type"] || has["nullable"] {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", ref, columnDefinition(targetType, col)+nullSuffix(col)))
+		}
+	case "oracle":
+		if has["type"] || has["nullable"] {
+			// Oracle 修改为已有的可空性时报错（ORA-01442），只在可空性变化时指定
+			definition := name + " " + col.Type
+			if has["nullable"] {
+				definition = columnDefinition(targetType, col) + nullSuffix(col)
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY (%s)", ref, definition))
+		}
+	}
+
+	if has["default"] {
+		switch {
+		case targetType == "postgresql" || targetType == "h2":
+			if defaultValue == "" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", ref, name))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", ref, name, defaultValue))
+			}
+		case targetType == "oracle":
+			if defaultValue == "" {
+				defaultValue = "NULL"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY (%s DEFAULT %s)", ref, name, defaultValue))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s.%s: default value changes must be applied manually", table, change.Target.Name))
+		}
+	}
+	return statements, warnings, nil
+}
+
+// defaultExpression 返回 DEFAULT 子句中的表达式
+// MySQL 的 DESCRIBE 返回默认值的原值（字符串不带引号），除数字、CURRENT_TIMESTAMP 等函数和括号表达式外按字符串处理；
+// 其他数据库返回的已经是表达式
+func defaultExpression(dbType, value string) string {
+	if dbType != "mysql" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil || strings.HasPrefix(value, "(") {
+		return value
+	}
+	upper := strings.ToUpper(value)
+	if upper == "NULL" || strings.HasPrefix(upper, "CURRENT_TIMESTAMP") || strings.HasPrefix(upper, "NOW(") {
+		return value
+	}
+	return FormatSQLLiteral(dbType, value)
+}
+
+// nullSuffix 可空列显式声明 NULL（SQL Server 的 ALTER COLUMN 省略时默认为可空，Oracle 需要显式取消 NOT NULL）
+func nullSuffix(col ColumnInfo) string {
+	if col.Nullable {
+		return " NULL"
+	}
+	return ""
+}
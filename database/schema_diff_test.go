@@ -0,0 +1,145 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	source := map[string][]ColumnInfo{
+		"users": {
+			{Name: "id", Type: "int", Key: "PRI", AutoIncrement: true},
+			{Name: "name", Type: "varchar(64)", Nullable: true},
+			{Name: "email", Type: "varchar(128)"},
+		},
+		"orders": {{Name: "id", Type: "int", Key: "PRI"}},
+		"same":   {{Name: "id", Type: "INT", Key: "PRI"}},
+	}
+	target := map[string][]ColumnInfo{
+		"USERS": {
+			{Name: "ID", Type: "int", Key: "PRI", AutoIncrement: true},
+			{Name: "name", Type: "varchar(32)"},
+			{Name: "legacy", Type: "text", Nullable: true},
+		},
+		"same": {{Name: "id", Type: "int", Key: "PRI"}},
+		"logs": {{Name: "id", Type: "bigint"}},
+	}
+
+	diff := CompareSchemas("mysql", source, "mysql", target)
+	if !reflect.DeepEqual(diff.Unchanged, []string{"same"}) {
+		t.Errorf("Unchanged = %v", diff.Unchanged)
+	}
+	statuses := map[string]string{}
+	for _, table := range diff.Tables {
+		statuses[table.Name] = table.Status
+	}
+	expected := map[string]string{"orders": SchemaDiffAdded, "users": SchemaDiffChanged, "logs": SchemaDiffRemoved}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Fatalf("statuses = %v, want %v", statuses, expected)
+	}
+
+	var users TableDiff
+	for _, table := range diff.Tables {
+		if table.Name == "users" {
+			users = table
+		}
+	}
+	if users.TargetName != "USERS" {
+		t.Errorf("TargetName = %q", users.TargetName)
+	}
+	if len(users.AddedColumns) != 1 || users.AddedColumns[0].Name != "email" {
+		t.Errorf("AddedColumns = %+v", users.AddedColumns)
+	}
+	if len(users.RemovedColumns) != 1 || users.RemovedColumns[0].Name != "legacy" {
+		t.Errorf("RemovedColumns = %+v", users.RemovedColumns)
+	}
+	if len(users.ChangedColumns) != 1 || !reflect.DeepEqual(users.ChangedColumns[0].Changes, []string{"type", "nullable"}) {
+		t.Errorf("ChangedColumns = %+v", users.ChangedColumns)
+	}
+
+	// 不同类型数据库之间按类型分类比较
+	cross := CompareSchemas("mysql", map[string][]ColumnInfo{"t": {{Name: "n", Type: "int"}, {Name: "s", Type: "varchar(10)"}}},
+		"postgresql", map[string][]ColumnInfo{"t": {{Name: "n", Type: "integer"}, {Name: "s", Type: "character varying(20)"}}})
+	if len(cross.Tables) != 1 || len(cross.Tables[0].ChangedColumns) != 1 || cross.Tables[0].ChangedColumns[0].Name != "s" {
+		t.Errorf("cross-dialect diff = %+v", cross.Tables)
+	}
+}
+
+func TestBuildSchemaSyncSQL(t *testing.T) {
+	source := map[string][]ColumnInfo{
+		"users": {
+			{Name: "id", Type: "integer", Key: "PRI"},
+			{Name: "name", Type: "varchar(64)"},
+			{Name: "email", Type: "text", Nullable: true, DefaultValue: "'none'::text"},
+		},
+		"orders": {{Name: "id", Type: "bigint", Key: "PRI"}},
+	}
+	target := map[string][]ColumnInfo{
+		"users": {
+			{Name: "id", Type: "integer", Key: "PRI"},
+			{Name: "name", Type: "varchar(32)", Nullable: true},
+			{Name: "legacy", Type: "text", Nullable: true},
+		},
+		"logs": {{Name: "id", Type: "bigint"}},
+	}
+
+	tests := []struct {
+		name     string
+		dbType   string
+		options  SchemaSyncOptions
+		expected []string
+	}{
+		{
+			name:   "PostgreSQL 不删除",
+			dbType: "postgresql",
+			expected: []string{
+				"CREATE TABLE \"orders\" (\n  \"id\" bigint NOT NULL,\n  PRIMARY KEY (\"id\")\n)",
+				`ALTER TABLE "users" ADD COLUMN "email" text DEFAULT 'none'::text`,
+				`ALTER TABLE "users" ALTER COLUMN "name" TYPE varchar(64)`,
+				`ALTER TABLE "users" ALTER COLUMN "name" SET NOT NULL`,
+			},
+		},
+		{
+			name:    "PostgreSQL 删除表和列",
+			dbType:  "postgresql",
+			options: SchemaSyncOptions{DropTables: true, DropColumns: true},
+			expected: []string{
+				"CREATE TABLE \"orders\" (\n  \"id\" bigint NOT NULL,\n  PRIMARY KEY (\"id\")\n)",
+				`ALTER TABLE "users" ADD COLUMN "email" text DEFAULT 'none'::text`,
+				`ALTER TABLE "users" DROP COLUMN "legacy"`,
+				`ALTER TABLE "users" ALTER COLUMN "name" TYPE varchar(64)`,
+				`ALTER TABLE "users" ALTER COLUMN "name" SET NOT NULL`,
+				`DROP TABLE "logs"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := CompareSchemas(tt.dbType, source, tt.dbType, target)
+			statements, warnings, err := BuildSchemaSyncSQL(diff, tt.options)
+			if err != nil {
+				t.Fatalf("BuildSchemaSyncSQL: %v", err)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("unexpected warnings: %v", warnings)
+			}
+			if !reflect.DeepEqual(statements, tt.expected) {
+				t.Errorf("statements = %q, want %q", statements, tt.expected)
+			}
+		})
+	}
+
+	// MySQL 修改列时使用 MODIFY COLUMN，字符串默认值加引号
+	diff := CompareSchemas("mysql",
+		map[string][]ColumnInfo{"t": {{Name: "s", Type: "varchar(20)", DefaultValue: "abc"}}},
+		"mysql",
+		map[string][]ColumnInfo{"t": {{Name: "s", Type: "varchar(10)", Nullable: true}}})
+	statements, _, err := BuildSchemaSyncSQL(diff, SchemaSyncOptions{})
+	if err != nil {
+		t.Fatalf("BuildSchemaSyncSQL: %v", err)
+	}
+	if want := []string{"ALTER TABLE `t` MODIFY COLUMN `s` varchar(20) NOT NULL DEFAULT 'abc'"}; !reflect.DeepEqual(statements, want) {
+		t.Errorf("statements = %q, want %q", statements, want)
+	}
+}
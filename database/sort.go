@@ -0,0 +1,334 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortSpec 排序条件
+type SortSpec struct {
+	Column    string `json:"column"`    // 排序列名
+	Direction string `json:"direction"` // asc 或 desc，默认为 asc
+	Nulls     string `json:"nulls"`     // first 或 last，为空时使用数据库默认顺序
+}
+
+// IsDesc 是否为降序
+func (s SortSpec) IsDesc() bool {
+	return strings.EqualFold(s.Direction, "desc")
+}
+
+// SortableDatabase 支持服务端排序的数据库接口扩展
+type SortableDatabase interface {
+	// GetTableDataSorted 获取表的数据（分页 + 多列排序）
+	// sorts 为空时与 GetTableData 行为一致
+	GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error)
+
+	// GetTableDataByCursor 基于游标获取表数据（keyset 分页），排序列可以不是主键
+	// cursor: 边界行的排序列值 + 主键值（顺序与 SortKeys 一致），nil 表示第一页
+	// direction: "next" 返回游标之后的行，"prev" 返回游标之前的行（结果仍按正序排列）
+	GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error)
+}
+
+// ValidateSorts 校验排序条件
+func ValidateSorts(sorts []SortSpec) error {
+	for _, s := range sorts {
+		if s.Column == "" {
+			return fmt.Errorf("sort column cannot be empty")
+		}
+		if strings.ContainsAny(s.Column, "`\"[]") {
+			return fmt.Errorf("invalid sort column: %s", s.Column)
+		}
+		switch strings.ToLower(s.Direction) {
+		case "", "asc", "desc":
+		default:
+			return fmt.Errorf("invalid sort direction: %s", s.Direction)
+		}
+		switch strings.ToLower(s.Nulls) {
+		case "", "first", "last":
+		default:
+			return fmt.Errorf("invalid nulls ordering: %s", s.Nulls)
+		}
+	}
+	return nil
+}
+
+// SortKeys 返回 keyset 分页使用的排序键：排序条件 + 主键（主键已在排序条件中时不重复添加）
+func SortKeys(sorts []SortSpec, primaryKey string) []SortSpec {
+	keys := make([]SortSpec, 0, len(sorts)+1)
+	hasPrimaryKey := false
+	for _, s := range sorts {
+		if s.Column == primaryKey {
+			hasPrimaryKey = true
+		}
+		keys = append(keys, s)
+	}
+	if primaryKey != "" && !hasPrimaryKey {
+		keys = append(keys, SortSpec{Column: primaryKey, Direction: "asc"})
+	}
+	return keys
+}
+
+// nullsFirst 判断排序列中的 NULL 是否排在最前面
+// 未指定 Nulls 时使用数据库的默认行为：PostgreSQL/Oracle 视 NULL 为最大值，ClickHouse 默认 NULLS LAST，其他视 NULL 为最小值
+func nullsFirst(dbType string, s SortSpec) bool {
+	switch strings.ToLower(s.Nulls) {
+	case "first":
+		return true
+	case "last":
+		return false
+	}
+	switch dbType {
+	case "postgresql", "oracle":
+		return s.IsDesc()
+	case "clickhouse":
+		return false
+	default:
+		return !s.IsDesc()
+	}
+}
+
+// supportsNullsOrdering 判断方言是否支持 NULLS FIRST/LAST 语法
+func supportsNullsOrdering(dbType string) bool {
+	switch dbType {
+	case "postgresql", "oracle", "sqlite", "clickhouse", "h2":
+		return true
+	}
+	return false
+}
+
+// BuildOrderByClause 构建 ORDER BY 子句（不包含 ORDER BY 关键字）
+// reverse 为 true 时生成完全相反的顺序（用于 keyset 分页向前翻页）
+func BuildOrderByClause(dbType string, sorts []SortSpec, reverse bool) (string, error) {
+	if len(sorts) == 0 {
+		return "", nil
+	}
+	if err := ValidateSorts(sorts); err != nil {
+		return "", err
+	}
+
+	quoteFunc := getQuoteFunc(dbType)
+	parts := make([]string, 0, len(sorts))
+	for _, s := range sorts {
+		column := quoteFunc(s.Column)
+		desc := s.IsDesc() != reverse
+		first := nullsFirst(dbType, s) != reverse
+
+		direction := "ASC"
+		if desc {
+			direction = "DESC"
+		}
+
+		if supportsNullsOrdering(dbType) {
+			nulls := "NULLS LAST"
+			if first {
+				nulls = "NULLS FIRST"
+			}
+			parts = append(parts, fmt.Sprintf("%s %s %s", column, direction, nulls))
+			continue
+		}
+
+		// 不支持 NULLS FIRST/LAST 的数据库（MySQL、SQL Server 等）视 NULL 为最小值
+		// 与默认顺序不一致时通过 CASE 表达式调整
+		if first != !desc {
+			nullsDirection := "DESC"
+			if first {
+				nullsDirection = "ASC"
+			}
+			parts = append(parts, fmt.Sprintf("CASE WHEN %s IS NULL THEN 0 ELSE 1 END %s", column, nullsDirection))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", column, direction))
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// BuildKeysetCondition 构建 keyset 分页条件（不包含 WHERE 关键字）
+// keys: 排序键（通常为 SortKeys 的结果），cursor: 边界行对应的值
+// reverse 为 false 时返回排序位置在边界行之后的行，为 true 时返回之前的行
+// argIndex: 第一个占位符的序号（PostgreSQL、SQL Server 和 Oracle 按序号绑定）
+func BuildKeysetCondition(dbType string, keys []SortSpec, cursor []interface{}, reverse bool, argIndex int) (string, []interface{}, error) {
+	if len(cursor) == 0 {
+		return "", nil, nil
+	}
+	if len(cursor) != len(keys) {
+		return "", nil, fmt.Errorf("cursor has %d values, expected %d", len(cursor), len(keys))
+	}
+	if err := ValidateSorts(keys); err != nil {
+		return "", nil, err
+	}
+
+	quoteFunc := getQuoteFunc(dbType)
+	placeholderFunc := getPlaceholderFunc(dbType)
+
+	var terms []string
+	var args []interface{}
+	for i := range keys {
+		// 前 i 列相等，且第 i 列严格位于边界之后
+		var parts []string
+		var termArgs []interface{}
+		nextIndex := argIndex
+		for j := 0; j < i; j++ {
+			column := quoteFunc(keys[j].Column)
+			if cursor[j] == nil {
+				parts = append(parts, fmt.Sprintf("%s IS NULL", column))
+			} else {
+				parts = append(parts, fmt.Sprintf("%s = %s", column, placeholderFunc(nextIndex)))
+				termArgs = append(termArgs, cursor[j])
+				nextIndex++
+			}
+		}
+
+		column := quoteFunc(keys[i].Column)
+		desc := keys[i].IsDesc() != reverse
+		first := nullsFirst(dbType, keys[i]) != reverse
+		if cursor[i] == nil {
+			if !first {
+				// NULL 排在最后，边界值为 NULL 时该列不存在更靠后的值
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%s IS NOT NULL", column))
+		} else {
+			operator := ">"
+			if desc {
+				operator = "<"
+			}
+			condition := fmt.Sprintf("%s %s %s", column, operator, placeholderFunc(nextIndex))
+			if !first {
+				condition = fmt.Sprintf("(%s OR %s IS NULL)", condition, column)
+			}
+			parts = append(parts, condition)
+			termArgs = append(termArgs, cursor[i])
+			nextIndex++
+		}
+
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, termArgs...)
+		argIndex = nextIndex
+	}
+
+	if len(terms) == 0 {
+		// 边界行已是最后一行
+		return "1 = 0", nil, nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args, nil
+}
+
+// CursorFromRow 从行数据中提取游标值（顺序与 keys 一致）
+// 时间类型转换为数据库可以直接比较的字符串格式
+func CursorFromRow(row map[string]interface{}, keys []SortSpec) []interface{} {
+	cursor := make([]interface{}, len(keys))
+	for i, k := range keys {
+		value := row[k.Column]
+		if t, ok := value.(time.Time); ok {
+			value = t.Format("2006-01-02 15:04:05.999999999")
+		}
+		cursor[i] = value
+	}
+	return cursor
+}
+
+// EncodeCursor 将游标编码为不透明的字符串，便于通过 URL 传递
+func EncodeCursor(cursor []interface{}) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor 解码 EncodeCursor 生成的游标
+// 整数保持为 int64，避免大整数主键丢失精度
+func DecodeCursor(token string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var cursor []interface{}
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	for i, v := range cursor {
+		if n, ok := v.(json.Number); ok {
+			if iv, err := n.Int64(); err == nil {
+				cursor[i] = iv
+			} else if fv, err := n.Float64(); err == nil {
+				cursor[i] = fv
+			} else {
+				cursor[i] = n.String()
+			}
+		}
+	}
+	return cursor, nil
+}
+
+// SortRows 在内存中对行数据排序（用于不支持服务端排序的数据源，如 Redis）
+// NULL 视为最小值，与 Nulls 设置一致时调整位置
+func SortRows(rows []map[string]interface{}, sorts []SortSpec) {
+	if len(sorts) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, s := range sorts {
+			a, b := rows[i][s.Column], rows[j][s.Column]
+			if a == nil || b == nil {
+				if a == nil && b == nil {
+					continue
+				}
+				// a 为 NULL 时，NULL 排在前面则 a 在前
+				return (a == nil) == nullsFirst("", s)
+			}
+			c := compareValues(a, b)
+			if c == 0 {
+				continue
+			}
+			if s.IsDesc() {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// compareValues 比较两个非空值，数字按数值比较，其他按字符串比较
+func compareValues(a, b interface{}) int {
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if aok && bok {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// toFloat 将数字类型转换为 float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildOrderByClause(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		sorts    []SortSpec
+		reverse  bool
+		expected string
+	}{
+		{name: "无排序条件", dbType: "mysql", sorts: nil, expected: ""},
+		{name: "MySQL 默认 NULL 顺序", dbType: "mysql", sorts: []SortSpec{{Column: "name"}, {Column: "age", Direction: "desc"}}, expected: "`name` ASC, `age` DESC"},
+		{name: "MySQL 升序 NULLS LAST", dbType: "mysql", sorts: []SortSpec{{Column: "name", Nulls: "last"}}, expected: "CASE WHEN `name` IS NULL THEN 0 ELSE 1 END DESC, `name` ASC"},
+		{name: "SQL Server 降序 NULLS FIRST", dbType: "sqlserver", sorts: []SortSpec{{Column: "name", Direction: "desc", Nulls: "first"}}, expected: "CASE WHEN [name] IS NULL THEN 0 ELSE 1 END ASC, [name] DESC"},
+		{name: "PostgreSQL 默认 NULL 最大", dbType: "postgresql", sorts: []SortSpec{{Column: "name"}, {Column: "age", Direction: "desc"}}, expected: `"name" ASC NULLS LAST, "age" DESC NULLS FIRST`},
+		{name: "反向排序", dbType: "postgresql", sorts: []SortSpec{{Column: "name", Nulls: "first"}}, reverse: true, expected: `"name" DESC NULLS LAST`},
+		{name: "Oracle 列名大写", dbType: "oracle", sorts: []SortSpec{{Column: "name"}}, expected: `"NAME" ASC NULLS LAST`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildOrderByClause(tt.dbType, tt.sorts, tt.reverse)
+			if err != nil {
+				t.Fatalf("BuildOrderByClause() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("BuildOrderByClause() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := BuildOrderByClause("mysql", []SortSpec{{Column: "a`b"}}, false); err == nil {
+		t.Error("expected error for column name containing quote")
+	}
+}
+
+func TestBuildKeysetCondition(t *testing.T) {
+	keys := []SortSpec{{Column: "name"}, {Column: "id"}}
+	tests := []struct {
+		name         string
+		dbType       string
+		cursor       []interface{}
+		reverse      bool
+		expected     string
+		expectedArgs []interface{}
+	}{
+		{
+			name:         "MySQL 下一页",
+			dbType:       "mysql",
+			cursor:       []interface{}{"bob", int64(7)},
+			expected:     "((`name` > ?) OR (`name` = ? AND `id` > ?))",
+			expectedArgs: []interface{}{"bob", "bob", int64(7)},
+		},
+		{
+			name:         "MySQL 上一页",
+			dbType:       "mysql",
+			cursor:       []interface{}{"bob", int64(7)},
+			reverse:      true,
+			expected:     "(((`name` < ? OR `name` IS NULL)) OR (`name` = ? AND (`id` < ? OR `id` IS NULL)))",
+			expectedArgs: []interface{}{"bob", "bob", int64(7)},
+		},
+		{
+			name:         "PostgreSQL 的 NULL 排在最后",
+			dbType:       "postgresql",
+			cursor:       []interface{}{"bob", int64(7)},
+			expected:     `((("name" > $3 OR "name" IS NULL)) OR ("name" = $4 AND ("id" > $5 OR "id" IS NULL)))`,
+			expectedArgs: []interface{}{"bob", "bob", int64(7)},
+		},
+		{
+			name:         "SQL Server 的 NULL 排在最前",
+			dbType:       "sqlserver",
+			cursor:       []interface{}{"bob", int64(7)},
+			expected:     "(([name] > @p3) OR ([name] = @p4 AND [id] > @p5))",
+			expectedArgs: []interface{}{"bob", "bob", int64(7)},
+		},
+		{
+			name:         "Oracle 按序号绑定",
+			dbType:       "oracle",
+			cursor:       []interface{}{"bob", int64(7)},
+			expected:     `((("NAME" > :3 OR "NAME" IS NULL)) OR ("NAME" = :4 AND ("ID" > :5 OR "ID" IS NULL)))`,
+			expectedArgs: []interface{}{"bob", "bob", int64(7)},
+		},
+		{
+			name:         "游标值为 NULL",
+			dbType:       "mysql",
+			cursor:       []interface{}{nil, int64(7)},
+			expected:     "((`name` IS NOT NULL) OR (`name` IS NULL AND `id` > ?))",
+			expectedArgs: []interface{}{int64(7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argIndex := 1
+			if tt.dbType != "mysql" {
+				argIndex = 3
+			}
+			got, args, err := BuildKeysetCondition(tt.dbType, keys, tt.cursor, tt.reverse, argIndex)
+			if err != nil {
+				t.Fatalf("BuildKeysetCondition() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("BuildKeysetCondition() = %q, want %q", got, tt.expected)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("BuildKeysetCondition() args = %v, want %v", args, tt.expectedArgs)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := []interface{}{"bob", nil, int64(9007199254740993), 1.5}
+	token, err := EncodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	got, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cursor) {
+		t.Errorf("DecodeCursor() = %v, want %v", got, cursor)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"k": "b", "n": int64(2)},
+		{"k": "a", "n": nil},
+		{"k": "c", "n": int64(10)},
+	}
+	SortRows(rows, []SortSpec{{Column: "n", Direction: "desc"}})
+
+	var got []string
+	for _, row := range rows {
+		got = append(got, row["k"].(string))
+	}
+	if expected := []string{"c", "b", "a"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("SortRows() = %v, want %v", got, expected)
+	}
+}
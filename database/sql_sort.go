@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// 以下为各 SQL 驱动共享的排序分页实现
+// tableRef 为已按方言引用的表名（如 `users`、"USERS"、[users]）
+
+// querySortedTableData 按排序条件分页查询表数据
+func querySortedTableData(ctx context.Context, db *sql.DB, dbType, tableRef, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	whereClause, whereArgs, err := BuildWhereClause(dbType, tableName, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build where clause: %w", err)
+	}
+
+	orderBy, err := BuildOrderByClause(dbType, sorts, false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build order by clause: %w", err)
+	}
+
+	total, err := countTableRows(ctx, db, tableRef, whereClause, whereArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT * FROM " + tableRef
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	} else if dbType == "sqlserver" {
+		// SQL Server 的 OFFSET/FETCH 必须配合 ORDER BY 使用
+		query += " ORDER BY (SELECT NULL)"
+	}
+	query += paginationClause(dbType, (page-1)*pageSize, pageSize)
+
+	rows, err := db.QueryContext(ctx, query, whereArgs...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanRowMaps(rows)
+	return results, total, err
+}
+
+// queryTableDataByCursor 按排序条件进行 keyset 分页查询
+// 排序键为 sorts + 主键，保证顺序唯一；prev 方向按相反顺序查询后再反转结果
+func queryTableDataByCursor(ctx context.Context, db *sql.DB, dbType, tableRef, tableName, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	if primaryKey == "" {
+		return nil, 0, fmt.Errorf("primary key is required for cursor pagination")
+	}
+	reverse := direction == "prev"
+	if reverse && len(cursor) == 0 {
+		return nil, 0, fmt.Errorf("cursor is required for previous page")
+	}
+
+	whereClause, whereArgs, err := BuildWhereClause(dbType, tableName, filters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build where clause: %w", err)
+	}
+
+	total, err := countTableRows(ctx, db, tableRef, whereClause, whereArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keys := SortKeys(sorts, primaryKey)
+	keysetClause, keysetArgs, err := BuildKeysetCondition(dbType, keys, cursor, reverse, len(whereArgs)+1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build cursor condition: %w", err)
+	}
+	orderBy, err := BuildOrderByClause(dbType, keys, reverse)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build order by clause: %w", err)
+	}
+
+	conditions := []string{}
+	if whereClause != "" {
+		conditions = append(conditions, "("+whereClause+")")
+	}
+	if keysetClause != "" {
+		conditions = append(conditions, keysetClause)
+	}
+
+	query := "SELECT * FROM " + tableRef
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY " + orderBy
+	query += paginationClause(dbType, 0, pageSize)
+
+	rows, err := db.QueryContext(ctx, query, append(whereArgs, keysetArgs...)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query data: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanRowMaps(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if reverse {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	return results, total, nil
+}
+
+// countTableRows 统计满足条件的行数
+func countTableRows(ctx context.Context, db *sql.DB, tableRef, whereClause string, whereArgs []interface{}) (int64, error) {
+	var total int64
+	countQuery := "SELECT COUNT(*) FROM " + tableRef
+	if whereClause != "" {
+		countQuery += " WHERE " + whereClause
+	}
+	if err := db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to query total count: %w", err)
+	}
+	return total, nil
+}
+
+// paginationClause 返回各方言的分页子句
+func paginationClause(dbType string, offset, limit int) string {
+	switch dbType {
+	case "sqlserver", "oracle":
+		return fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+	default:
+		return fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	}
+}
+
+// scanRowMaps 将查询结果扫描为 map 列表，[]byte 转为字符串
+func scanRowMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results = make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range columns {
+			val := values[i]
+			if b, ok := val.([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = val
+			}
+		}
+		results = append(results, row)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,391 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StatementKind SQL 语句类别
+type StatementKind string
+
+const (
+	StatementRead  StatementKind = "read"  // 返回结果集的语句，如 SELECT、SHOW、EXPLAIN
+	StatementWrite StatementKind = "write" // 修改数据的语句，如 INSERT、UPDATE、MERGE、CALL
+	StatementDDL   StatementKind = "ddl"   // 修改结构或权限的语句，如 CREATE、ALTER、GRANT
+	StatementOther StatementKind = "other" // 其他语句，如 SET、USE、BEGIN
+)
+
+// StatementInfo SQL 语句分类结果
+type StatementInfo struct {
+	// Keyword 主语句关键字（大写），CTE 取 WITH 之后的主语句，如 WITH ... SELECT 为 SELECT
+	Keyword string
+	Kind    StatementKind
+	// Returning 写语句是否带 RETURNING/OUTPUT 子句（会返回结果集）
+	Returning bool
+	// Session 是否为事务控制或修改连接状态的语句（如 BEGIN、COMMIT、SET、USE、PRAGMA x = y），
+	// 这类语句的效果留在执行它的连接上
+	Session bool
+}
+
+// statementKinds 语句关键字到类别的映射
+var statementKinds = map[string]StatementKind{
+	"SELECT":   StatementRead,
+	"SHOW":     StatementRead,
+	"EXPLAIN":  StatementRead,
+	"DESCRIBE": StatementRead,
+	"DESC":     StatementRead,
+	"VALUES":   StatementRead,
+	"TABLE":    StatementRead,
+	"PRAGMA":   StatementRead,
+
+	"INSERT":  StatementWrite,
+	"UPDATE":  StatementWrite,
+	"DELETE":  StatementWrite,
+	"REPLACE": StatementWrite,
+	"MERGE":   StatementWrite,
+	"UPSERT":  StatementWrite,
+	"CALL":    StatementWrite,
+	"EXEC":    StatementWrite,
+	"EXECUTE": StatementWrite,
+	"COPY":    StatementWrite,
+	"LOAD":    StatementWrite,
+
+	"CREATE":   StatementDDL,
+	"ALTER":    StatementDDL,
+	"DROP":     StatementDDL,
+	"TRUNCATE": StatementDDL,
+	"RENAME":   StatementDDL,
+	"GRANT":    StatementDDL,
+	"REVOKE":   StatementDDL,
+	"COMMENT":  StatementDDL,
+}
+
+// returningKeywords 可以带 RETURNING/OUTPUT 子句返回结果集的语句
+var returningKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "REPLACE": true, "UPSERT": true,
+}
+
+// sessionKeywords 事务控制和修改连接状态的语句关键字
+var sessionKeywords = map[string]bool{
+	"START": true, "COMMIT": true, "ROLLBACK": true, "SAVEPOINT": true, "RELEASE": true, "ABORT": true, "END": true,
+	"SET": true, "RESET": true, "USE": true, "LOCK": true, "UNLOCK": true, "DISCARD": true,
+}
+
+// beginTransactionWords BEGIN 之后表示开启事务的关键字，其他情况下 BEGIN 是 PL/SQL 或 T-SQL 语句块
+var beginTransactionWords = map[string]bool{
+	"TRANSACTION": true, "TRAN": true, "WORK": true, "DISTRIBUTED": true, "ISOLATION": true, "READ": true,
+	"DEFERRED": true, "IMMEDIATE": true, "EXCLUSIVE": true,
+}
+
+// sqlTokenKind 词法单元类型
+type sqlTokenKind int
+
+const (
+	sqlTokenWord   sqlTokenKind = iota // 关键字或未引用的标识符
+	sqlTokenQuoted                     // 字符串或引用标识符
+	sqlTokenPunct                      // 标点和运算符
+)
+
+// sqlToken SQL 词法单元
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	pos  int // 在原始 SQL 中的起始位置
+}
+
+// mysqlStyleDialects 字符串中的反斜杠为转义符、# 开始单行注释的方言（MySQL 及其兼容数据库、ClickHouse）
+var mysqlStyleDialects = map[string]bool{
+	"mysql": true, "mariadb": true, "tidb": true, "oceanbase": true, "oceandb": true, "clickhouse": true,
+}
+
+// tokenizeSQL 将 SQL 切分为词法单元，跳过注释和空白
+// 支持 -- 单行注释、/* */ 块注释、单引号字符串（含引号重复）、双引号/反引号/方括号引用标识符以及 PostgreSQL 的 $tag$ 字符串；
+// MySQL 风格的方言（见 mysqlStyleDialects）还支持 # 单行注释和字符串中的反斜杠转义，
+// PostgreSQL 的 E'...' 字符串同样使用反斜杠转义，其他方言中反斜杠是普通字符
+func tokenizeSQL(dbType, query string) []sqlToken {
+	mysqlStyle := mysqlStyleDialects[dbType]
+	var tokens []sqlToken
+	s := query
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case c == '-' && strings.HasPrefix(s[i:], "--"), c == '#' && mysqlStyle:
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			backslash := c == '\'' && (mysqlStyle || dbType == "postgresql" && isEscapeStringPrefix(s, i))
+			end := scanQuoted(s, i, c, backslash)
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuoted, text: s[i:end], pos: i})
+			i = end
+		case c == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuoted, text: s[i : i+end+1], pos: i})
+			i += end + 1
+		case c == '$' && dollarTag(s[i:]) != "":
+			tag := dollarTag(s[i:])
+			end := strings.Index(s[i+len(tag):], tag)
+			if end < 0 {
+				end = len(s) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuoted, text: s[i : i+len(tag)+end], pos: i})
+			i += len(tag) + end
+		case isWordByte(c):
+			start := i
+			for i < len(s) && isWordByte(s[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenWord, text: s[start:i], pos: start})
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlTokenPunct, text: s[i : i+1], pos: i})
+			i++
+		}
+	}
+	return tokens
+}
+
+// isEscapeStringPrefix 判断位置 i 的单引号之前是否为 PostgreSQL 转义字符串的 E 前缀（如 E'a\'b'）
+func isEscapeStringPrefix(s string, i int) bool {
+	return i > 0 && (s[i-1] == 'E' || s[i-1] == 'e') && (i == 1 || !isWordByte(s[i-2]))
+}
+
+// scanQuoted 返回以 quote 开头的引用串结束后的位置，未闭合时返回字符串末尾
+// backslash 为 true 时反斜杠转义下一个字符
+func scanQuoted(s string, start int, quote byte, backslash bool) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarTag 识别 PostgreSQL 的 $$ 或 $tag$ 起始标记，不是则返回空字符串
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1]
+		}
+		if !isWordByte(s[i]) || (i == 1 && s[i] >= '0' && s[i] <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// isWordByte 判断是否为关键字/标识符字符（非 ASCII 字符视为标识符的一部分）
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// ClassifySQL 对单条 SQL 语句进行分类，dbType 决定字符串和注释的词法规则（见 tokenizeSQL）
+// 会跳过开头的注释和括号；WITH 语句按其后的主语句分类；空语句返回空 Keyword
+func ClassifySQL(dbType, query string) StatementInfo {
+	tokens := tokenizeSQL(dbType, query)
+
+	start := -1
+	for i, tok := range tokens {
+		if tok.kind == sqlTokenWord {
+			start = i
+			break
+		}
+		if tok.text != "(" {
+			break
+		}
+	}
+	if start < 0 {
+		return StatementInfo{Kind: StatementOther}
+	}
+
+	keyword := strings.ToUpper(tokens[start].text)
+	depth := 0
+	if keyword == "WITH" {
+		// CTE：主语句是 WITH 之后第一个位于括号外的语句关键字
+		keyword = ""
+		for _, tok := range tokens[start+1:] {
+			switch {
+			case tok.text == "(":
+				depth++
+			case tok.text == ")":
+				depth--
+			case depth == 0 && tok.kind == sqlTokenWord:
+				if _, ok := statementKinds[strings.ToUpper(tok.text)]; ok {
+					keyword = strings.ToUpper(tok.text)
+				}
+			}
+			if keyword != "" {
+				break
+			}
+		}
+		if keyword == "" {
+			return StatementInfo{Keyword: "WITH", Kind: StatementOther}
+		}
+	}
+
+	info := StatementInfo{Keyword: keyword, Kind: StatementOther}
+	if kind, ok := statementKinds[keyword]; ok {
+		info.Kind = kind
+	}
+	if tokens[start].text != "WITH" {
+		info.Session = isSessionStatement(keyword, tokens[start+1:])
+	}
+
+	// 扫描括号外的子句：PRAGMA x = y 和 SELECT ... INTO 为写操作，其中 PRAGMA x = y 修改的是连接的设置；
+	// RETURNING/OUTPUT 会返回结果集
+	depth = 0
+	for _, tok := range tokens[start+1:] {
+		switch {
+		case tok.text == "(":
+			depth++
+		case tok.text == ")":
+			depth--
+		case depth != 0:
+		case keyword == "PRAGMA" && tok.text == "=":
+			info.Kind = StatementWrite
+			info.Session = true
+		case keyword == "SELECT" && tok.kind == sqlTokenWord && strings.EqualFold(tok.text, "INTO"):
+			// SELECT ... INTO 建表或写入变量、文件，不返回结果集
+			info.Kind = StatementWrite
+		case tok.kind == sqlTokenWord && returningKeywords[keyword]:
+			switch strings.ToUpper(tok.text) {
+			case "RETURNING", "OUTPUT":
+				info.Returning = true
+			}
+		}
+	}
+	return info
+}
+
+// isSessionStatement 判断以 keyword 开头的语句是否为事务控制或修改连接状态的语句
+// rest 为关键字之后的词法单元，用于区分 BEGIN 事务和语句块，以及识别 Oracle 的 ALTER SESSION
+func isSessionStatement(keyword string, rest []sqlToken) bool {
+	switch keyword {
+	case "BEGIN":
+		return len(rest) == 0 || rest[0].text == ";" || beginTransactionWords[strings.ToUpper(rest[0].text)]
+	case "ALTER":
+		return len(rest) > 0 && strings.EqualFold(rest[0].text, "SESSION")
+	}
+	return sessionKeywords[keyword]
+}
+
+// ReturnsRows 判断语句是否应该按查询执行（返回结果集）
+func (i StatementInfo) ReturnsRows() bool {
+	return i.Kind == StatementRead || (i.Kind == StatementWrite && i.Returning)
+}
+
+// tableModifiers 写语句和 DDL 中位于目标表名之前的修饰关键字
+var tableModifiers = map[string]bool{
+	"INTO": true, "FROM": true, "ONLY": true, "IGNORE": true, "LOW_PRIORITY": true, "HIGH_PRIORITY": true,
+	"DELAYED": true, "QUICK": true, "OR": true, "REPLACE": true, "ABORT": true, "FAIL": true, "ROLLBACK": true,
+	"TEMPORARY": true, "TEMP": true, "GLOBAL": true, "LOCAL": true, "UNLOGGED": true, "IF": true, "NOT": true, "EXISTS": true,
+}
+
+// StatementTable 尽力识别写语句和表级 DDL 的目标表名（去掉引号，带模式前缀时以 . 连接）
+// 无法识别或不是针对表的语句（如 CREATE INDEX、GRANT）返回空字符串
+func StatementTable(dbType, query string) string {
+	keyword := ClassifySQL(dbType, query).Keyword
+	switch keyword {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE", "MERGE", "UPSERT", "CREATE", "ALTER", "DROP", "TRUNCATE":
+	default:
+		return ""
+	}
+	tokens := tokenizeSQL(dbType, query)
+
+	// 定位主语句关键字（CTE 取 WITH 之后位于括号外的关键字）
+	start, depth := -1, 0
+	for i, tok := range tokens {
+		switch {
+		case tok.text == "(":
+			depth++
+		case tok.text == ")":
+			depth--
+		case depth == 0 && tok.kind == sqlTokenWord && strings.ToUpper(tok.text) == keyword:
+			start = i
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	// CREATE/ALTER/DROP 只识别 TABLE 对象，TRUNCATE 的 TABLE 关键字可以省略
+	needTable := keyword == "CREATE" || keyword == "ALTER" || keyword == "DROP"
+	i := start + 1
+	for ; i < len(tokens) && tokens[i].kind == sqlTokenWord; i++ {
+		word := strings.ToUpper(tokens[i].text)
+		if word == "TABLE" {
+			needTable = false
+			continue
+		}
+		if !tableModifiers[word] {
+			break
+		}
+	}
+	if needTable {
+		return ""
+	}
+
+	var parts []string
+	for i < len(tokens) {
+		tok := tokens[i]
+		name := tok.text
+		// SQL Server 临时表名（#tmp）被切分为 # 和标识符
+		if tok.text == "#" && i+1 < len(tokens) && tokens[i+1].pos == tok.pos+1 && tokens[i+1].kind == sqlTokenWord {
+			i++
+			name = "#" + tokens[i].text
+		} else if tok.kind == sqlTokenPunct {
+			return ""
+		}
+		parts = append(parts, unquoteIdentifier(name))
+		end := tokens[i].pos + len(tokens[i].text)
+		if i+2 < len(tokens) && tokens[i+1].text == "." && tokens[i+1].pos == end && tokens[i+2].kind != sqlTokenPunct {
+			i += 2
+			continue
+		}
+		break
+	}
+	return strings.Join(parts, ".")
+}
+
+// unquoteIdentifier 去掉标识符两侧的引号或方括号
+func unquoteIdentifier(name string) string {
+	if len(name) >= 2 {
+		switch name[0] {
+		case '"', '`':
+			if name[len(name)-1] == name[0] {
+				return strings.ReplaceAll(name[1:len(name)-1], name[:1]+name[:1], name[:1])
+			}
+		case '[':
+			if name[len(name)-1] == ']' {
+				return name[1 : len(name)-1]
+			}
+		}
+	}
+	return name
+}
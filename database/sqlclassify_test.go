@@ -0,0 +1,77 @@
+package database
+
+import "testing"
+
+func TestClassifySQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		query    string
+		expected StatementInfo
+	}{
+		{name: "空语句", query: "  -- only comment", expected: StatementInfo{Kind: StatementOther}},
+		{name: "小写 SELECT", query: "select 1", expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "开头注释", dbType: "mysql", query: "/* report */\n-- daily\n# mysql\nSELECT * FROM t", expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "括号开头", query: "(SELECT 1) UNION (SELECT 2)", expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "CTE 查询", query: "WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n+1 FROM t) SELECT * FROM t", expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "CTE 删除", query: "WITH old AS (SELECT id FROM t) DELETE FROM t WHERE id IN (SELECT id FROM old)", expected: StatementInfo{Keyword: "DELETE", Kind: StatementWrite}},
+		{name: "SHOW", query: "SHOW TABLES", expected: StatementInfo{Keyword: "SHOW", Kind: StatementRead}},
+		{name: "EXPLAIN", query: "explain analyze select 1", expected: StatementInfo{Keyword: "EXPLAIN", Kind: StatementRead}},
+		{name: "PRAGMA 读取", query: "PRAGMA table_info(users)", expected: StatementInfo{Keyword: "PRAGMA", Kind: StatementRead}},
+		{name: "带模式名的 PRAGMA 设置", query: "PRAGMA main.cache_size = -2000", expected: StatementInfo{Keyword: "PRAGMA", Kind: StatementWrite, Session: true}},
+		{name: "PRAGMA 设置", query: "PRAGMA foreign_keys = ON", expected: StatementInfo{Keyword: "PRAGMA", Kind: StatementWrite, Session: true}},
+		{name: "CALL", query: "CALL refresh()", expected: StatementInfo{Keyword: "CALL", Kind: StatementWrite}},
+		{name: "SELECT INTO", query: "SELECT * INTO archive FROM orders WHERE note = 'into'", expected: StatementInfo{Keyword: "SELECT", Kind: StatementWrite}},
+		{name: "子查询中的 INTO 不影响", query: "SELECT * FROM (SELECT 1 AS into_x) t", expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "MERGE", query: "MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN UPDATE SET v = s.v", expected: StatementInfo{Keyword: "MERGE", Kind: StatementWrite}},
+		{name: "INSERT RETURNING", query: "INSERT INTO t (v) VALUES ('returning') RETURNING id", expected: StatementInfo{Keyword: "INSERT", Kind: StatementWrite, Returning: true}},
+		{name: "字符串中的关键字", query: "UPDATE t SET v = 'it''s -- RETURNING'", expected: StatementInfo{Keyword: "UPDATE", Kind: StatementWrite}},
+		{name: "PostgreSQL 字符串以反斜杠结尾", dbType: "postgresql", query: `SELECT 'C:\' INTO archive`, expected: StatementInfo{Keyword: "SELECT", Kind: StatementWrite}},
+		{name: "MySQL 字符串中的反斜杠转义", dbType: "mysql", query: `SELECT 'C:\' INTO archive'`, expected: StatementInfo{Keyword: "SELECT", Kind: StatementRead}},
+		{name: "SQL Server 临时表", dbType: "sqlserver", query: "INSERT INTO #tmp OUTPUT inserted.id VALUES (1)", expected: StatementInfo{Keyword: "INSERT", Kind: StatementWrite, Returning: true}},
+		{name: "美元符号字符串", query: "CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql", expected: StatementInfo{Keyword: "CREATE", Kind: StatementDDL}},
+		{name: "SET", query: "SET NAMES utf8mb4", expected: StatementInfo{Keyword: "SET", Kind: StatementOther, Session: true}},
+		{name: "开启事务", query: "BEGIN", expected: StatementInfo{Keyword: "BEGIN", Kind: StatementOther, Session: true}},
+		{name: "SQL Server 开启事务", query: "begin tran", expected: StatementInfo{Keyword: "BEGIN", Kind: StatementOther, Session: true}},
+		{name: "PL/SQL 语句块", query: "BEGIN UPDATE emp SET sal = sal * 2; END;", expected: StatementInfo{Keyword: "BEGIN", Kind: StatementOther}},
+		{name: "START TRANSACTION", query: "START TRANSACTION READ ONLY", expected: StatementInfo{Keyword: "START", Kind: StatementOther, Session: true}},
+		{name: "USE", query: "USE `shop`", expected: StatementInfo{Keyword: "USE", Kind: StatementOther, Session: true}},
+		{name: "ALTER SESSION", query: "ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD'", expected: StatementInfo{Keyword: "ALTER", Kind: StatementDDL, Session: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifySQL(tt.dbType, tt.query); got != tt.expected {
+				t.Errorf("ClassifySQL() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatementTable(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"INSERT INTO users (name) VALUES ('a')", "users"},
+		{"insert or replace into `app`.`users` values (1)", "app.users"},
+		{"UPDATE \"public\".\"order items\" SET v = 1", "public.order items"},
+		{"DELETE FROM [dbo].[logs] WHERE id = 1", "dbo.logs"},
+		{"WITH old AS (SELECT id FROM t) DELETE FROM archive WHERE id IN (SELECT id FROM old)", "archive"},
+		{"MERGE INTO target USING source ON target.id = source.id WHEN MATCHED THEN DELETE", "target"},
+		{"CREATE TEMPORARY TABLE IF NOT EXISTS tmp_users (id int)", "tmp_users"},
+		{"ALTER TABLE ONLY accounts ADD COLUMN note text", "accounts"},
+		{"DROP TABLE IF EXISTS sales.q1", "sales.q1"},
+		{"TRUNCATE events", "events"},
+		{"INSERT INTO #tmp VALUES (1)", "#tmp"},
+		{"CREATE INDEX idx_name ON users (name)", ""},
+		{"GRANT SELECT ON users TO reader", ""},
+		{"SELECT * FROM users", ""},
+	}
+
+	for _, tt := range tests {
+		if got := StatementTable("", tt.query); got != tt.expected {
+			t.Errorf("StatementTable(%q) = %q, want %q", tt.query, got, tt.expected)
+		}
+	}
+}
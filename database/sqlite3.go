@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -92,7 +93,8 @@ func (s *SQLite3) GetTableSchema(tableName string) (string, error) {
 
 // GetTableColumns 获取表的列信息
 func (s *SQLite3) GetTableColumns(tableName string) ([]ColumnInfo, error) {
-	query := fmt.Sprintf("PRAGMA table_info(`%s`)", tableName)
+	// table_xinfo 比 table_info 多出 hidden 列，并且包含生成列（hidden 为 2 或 3）
+	query := fmt.Sprintf("PRAGMA table_xinfo(`%s`)", tableName)
 	rows, err := s.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query column information: %w", err)
@@ -103,29 +105,55 @@ func (s *SQLite3) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 	for rows.Next() {
 		var col ColumnInfo
 		var cid int
-		var notnull, pk int
+		var notnull, pk, hidden int
 		var defaultVal sql.NullString
 
-		if err := rows.Scan(&cid, &col.Name, &col.Type, &notnull, &defaultVal, &pk); err != nil {
+		if err := rows.Scan(&cid, &col.Name, &col.Type, &notnull, &defaultVal, &pk, &hidden); err != nil {
 			return nil, err
 		}
+		if hidden == 1 {
+			// 虚拟表的隐藏列
+			continue
+		}
 
 		col.Nullable = (notnull == 0)
 		if pk == 1 {
 			col.Key = "PRI"
 		}
+		col.Generated = hidden == 2 || hidden == 3
 		if defaultVal.Valid {
 			col.DefaultValue = defaultVal.String
 		}
 
 		columns = append(columns, col)
 	}
-	return columns, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 唯一的 INTEGER PRIMARY KEY 列是 rowid 的别名，插入时自动生成
+	primaryKeys := 0
+	for _, col := range columns {
+		if col.Key == "PRI" {
+			primaryKeys++
+		}
+	}
+	for i := range columns {
+		if primaryKeys == 1 && columns[i].Key == "PRI" && strings.EqualFold(columns[i].Type, "INTEGER") {
+			columns[i].AutoIncrement = true
+		}
+	}
+	return columns, nil
 }
 
 // ExecuteQuery 执行查询
 func (s *SQLite3) ExecuteQuery(query string) ([]map[string]interface{}, error) {
-	rows, err := s.db.Query(query)
+	return s.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (s *SQLite3) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -163,9 +191,225 @@ func (s *SQLite3) ExecuteQuery(query string) ([]map[string]interface{}, error) {
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (s *SQLite3) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (s *SQLite3) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, s.db, "sqlite")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (s *SQLite3) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, s.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (s *SQLite3) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, s.db, "sqlite", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (s *SQLite3) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, s.db, "sqlite", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (s *SQLite3) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, s.db, "sqlite", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (s *SQLite3) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, s.db, "sqlite", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (s *SQLite3) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, s.db, "sqlite", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (s *SQLite3) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, s.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (s *SQLite3) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, s.db, query, args)
+}
+
+// TruncateTable 清空表
+func (s *SQLite3) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, s.db, "sqlite", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (s *SQLite3) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, s.db, "sqlite", tableName, columns)
+}
+
+// GetTableMetadata 通过 pragma 表值函数读取表的索引和外键，检查约束从建表语句中解析（SQLite 不支持注释）
+func (s *SQLite3) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	columns, err := s.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+
+	// 表达式索引的列名为 NULL；INTEGER PRIMARY KEY 是 rowid 的别名，没有对应的索引，按列信息补充
+	var indexes []IndexInfo
+	err = queryMetadata(ctx, s.db, `
+		SELECT il.name, ii.name, il."unique", il.origin
+		FROM pragma_index_list(?) il
+		JOIN pragma_index_info(il.name) ii
+		ORDER BY il.origin = 'pk' DESC, il.name, ii.seqno`, func(rows *sql.Rows) error {
+		var name, origin string
+		var column sql.NullString
+		var unique bool
+		if err := rows.Scan(&name, &column, &unique, &origin); err != nil {
+			return err
+		}
+		if !column.Valid {
+			column.String = "(expression)"
+		}
+		indexes = addIndexColumn(indexes, name, column.String, unique, origin == "pk", "")
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+	if len(indexes) == 0 || !indexes[0].Primary {
+		var primary []string
+		for _, col := range columns {
+			if col.Key == "PRI" {
+				primary = append(primary, col.Name)
+			}
+		}
+		if len(primary) > 0 {
+			meta.Indexes = append(meta.Indexes, IndexInfo{Name: "PRIMARY", Columns: primary, Unique: true, Primary: true})
+		}
+	}
+	meta.Indexes = append(meta.Indexes, indexes...)
+
+	// SQLite 不保存外键名，以序号区分；"to" 为 NULL 时引用父表的主键
+	lastID := -1
+	err = queryMetadata(ctx, s.db, `SELECT id, "table", "from", "to", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`, func(rows *sql.Rows) error {
+		var id int
+		var refTable, column, onUpdate, onDelete string
+		var refColumn sql.NullString
+		if err := rows.Scan(&id, &refTable, &column, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		if id != lastID {
+			meta.ForeignKeys = append(meta.ForeignKeys, ForeignKeyInfo{RefTable: refTable, OnUpdate: onUpdate, OnDelete: onDelete})
+			lastID = id
+		}
+		fk := &meta.ForeignKeys[len(meta.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn.String)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+
+	err = queryMetadata(ctx, s.db, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", func(rows *sql.Rows) error {
+		var createSQL sql.NullString
+		if err := rows.Scan(&createSQL); err != nil {
+			return err
+		}
+		meta.Checks = parseCheckConstraints(createSQL.String)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check constraints: %w", err)
+	}
+	return meta, nil
+}
+
+// GetViews 获取数据库中的视图，定义为 CREATE VIEW 语句
+func (s *SQLite3) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	views := []ViewInfo{}
+	err := queryMetadata(ctx, s.db, "SELECT name, sql FROM sqlite_master WHERE type = 'view' ORDER BY name", func(rows *sql.Rows) error {
+		var view ViewInfo
+		if err := rows.Scan(&view.Name, &view.Definition); err != nil {
+			return err
+		}
+		views = append(views, view)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines SQLite 没有存储过程和函数，返回空列表
+func (s *SQLite3) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	return []RoutineInfo{}, nil
+}
+
+// GetTriggers 获取表的触发器，时机和事件从 CREATE TRIGGER 语句中解析
+func (s *SQLite3) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	triggers := []TriggerInfo{}
+	err := queryMetadata(ctx, s.db, `
+		SELECT name, tbl_name, sql FROM sqlite_master
+		WHERE type = 'trigger' AND (? = '' OR tbl_name = ?)
+		ORDER BY tbl_name, name`, func(rows *sql.Rows) error {
+		var trigger TriggerInfo
+		if err := rows.Scan(&trigger.Name, &trigger.Table, &trigger.Definition); err != nil {
+			return err
+		}
+		trigger.Timing, trigger.Event = parseTriggerHeader(trigger.Definition)
+		triggers = append(triggers, trigger)
+		return nil
+	}, tableName, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// ExplainQuery 使用 EXPLAIN QUERY PLAN 获取执行计划（SQLite 不提供行数和代价，不支持 analyze）
+func (s *SQLite3) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	var rows []planRow
+	var lines []string
+	depth := map[int]int{}
+	err := queryMetadata(ctx, s.db, "EXPLAIN QUERY PLAN "+query, func(r *sql.Rows) error {
+		var id, parent, notUsed int
+		var detail string
+		if err := r.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			return err
+		}
+		rows = append(rows, planRow{id: id, parent: parent, node: sqlitePlanNode(detail)})
+		depth[id] = depth[parent] + 1
+		lines = append(lines, strings.Repeat("  ", depth[id]-1)+detail)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return &QueryPlan{Nodes: buildPlanTree(rows), Format: PlanFormatText, Raw: strings.Join(lines, "\n")}, nil
+}
+
 // ExecuteUpdate 执行更新
 func (s *SQLite3) ExecuteUpdate(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (s *SQLite3) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -174,7 +418,12 @@ func (s *SQLite3) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (s *SQLite3) ExecuteDelete(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (s *SQLite3) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -183,7 +432,12 @@ func (s *SQLite3) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (s *SQLite3) ExecuteInsert(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (s *SQLite3) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -192,6 +446,11 @@ func (s *SQLite3) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (s *SQLite3) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return s.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (s *SQLite3) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("sqlite", tableName, filters)
 	if err != nil {
@@ -204,7 +463,7 @@ func (s *SQLite3) GetTableData(tableName string, page, pageSize int, filters *Fi
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := s.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := s.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -216,7 +475,7 @@ func (s *SQLite3) GetTableData(tableName string, page, pageSize int, filters *Fi
 	}
 	query += fmt.Sprintf(" LIMIT %d OFFSET %d", pageSize, offset)
 
-	rows, err := s.db.Query(query, whereArgs...)
+	rows, err := s.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -258,6 +517,11 @@ func (s *SQLite3) GetTableData(tableName string, page, pageSize int, filters *Fi
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (s *SQLite3) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return s.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (s *SQLite3) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("sqlite", tableName, filters)
 	if err != nil {
@@ -270,7 +534,7 @@ func (s *SQLite3) GetTableDataByID(tableName string, primaryKey string, lastId i
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := s.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := s.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -321,7 +585,7 @@ func (s *SQLite3) GetTableDataByID(tableName string, primaryKey string, lastId i
 		query += fmt.Sprintf(" ORDER BY `%s` ASC LIMIT %d", primaryKey, pageSize)
 	}
 
-	rows, err = s.db.Query(query, queryArgs...)
+	rows, err = s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -382,6 +646,21 @@ func (s *SQLite3) GetTableDataByID(tableName string, primaryKey string, lastId i
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (s *SQLite3) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return querySortedTableData(ctx, s.db, "sqlite", fmt.Sprintf("`%s`", tableName), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (s *SQLite3) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return queryTableDataByCursor(ctx, s.db, "sqlite", fmt.Sprintf("`%s`", tableName), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (s *SQLite3) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, s.db, "sqlite", fmt.Sprintf("`%s`", tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (s *SQLite3) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if page <= 1 {
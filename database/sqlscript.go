@@ -0,0 +1,149 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SplitSQLScript 将多语句脚本拆分为可逐条执行的语句
+// 按 ; 拆分（字符串、引用标识符、注释和 PostgreSQL 的 $tag$ 字符串中的 ; 不拆分），
+// SQL Server 额外以单独一行的 GO [n] 分隔批处理，Oracle 以单独一行的 / 结束 PL/SQL 块；
+// 过程块（如 CREATE PROCEDURE、BEGIN ... END）内部的 ; 不拆分，直到遇到批处理分隔符。
+// 只包含注释或空白的语句会被丢弃
+func SplitSQLScript(dbType, script string) []string {
+	tokens := tokenizeSQL(dbType, script)
+	var statements []string
+
+	start := 0   // 当前语句在 script 中的起始位置
+	stmtTok := 0 // 当前语句第一个词法单元的下标
+	flush := func(end, repeat int) {
+		// 语句从第一个词法单元开始，去掉前导注释
+		begin := start
+		if stmtTok < len(tokens) && tokens[stmtTok].pos > begin && tokens[stmtTok].pos < end {
+			begin = tokens[stmtTok].pos
+		}
+		stmt := strings.TrimSpace(script[begin:end])
+		if ClassifySQL(dbType, stmt).Keyword == "" {
+			return
+		}
+		for n := 0; n < repeat; n++ {
+			statements = append(statements, stmt)
+		}
+	}
+
+	for i, tok := range tokens {
+		if tok.pos < start {
+			// 分隔符所在行的剩余部分（如 GO 的重复次数）
+			stmtTok = i + 1
+			continue
+		}
+		switch {
+		case tok.text == ";":
+			if isProceduralBlock(dbType, tokens[stmtTok:i]) {
+				continue
+			}
+			flush(tok.pos, 1)
+			start, stmtTok = tok.pos+1, i+1
+		case dbType == "sqlserver" && tok.kind == sqlTokenWord && strings.EqualFold(tok.text, "GO"):
+			rest, lineEnd, ok := standaloneLine(script, tok.pos, len(tok.text))
+			if !ok {
+				continue
+			}
+			repeat := 1
+			if rest != "" {
+				n, err := strconv.Atoi(rest)
+				if err != nil || n < 1 {
+					continue
+				}
+				repeat = n
+			}
+			flush(tok.pos, repeat)
+			start, stmtTok = lineEnd, i+1
+		case dbType == "oracle" && tok.text == "/":
+			rest, lineEnd, ok := standaloneLine(script, tok.pos, 1)
+			if !ok || rest != "" {
+				continue
+			}
+			flush(tok.pos, 1)
+			start, stmtTok = lineEnd, i+1
+		}
+	}
+	flush(len(script), 1)
+	return statements
+}
+
+// standaloneLine 判断从 pos 开始、长度为 length 的词法单元是否位于行首（前面只有空白），
+// 返回该行剩余的内容（已去除空白）和下一行的起始位置
+func standaloneLine(script string, pos, length int) (string, int, bool) {
+	lineStart := strings.LastIndexByte(script[:pos], '\n') + 1
+	if strings.TrimSpace(script[lineStart:pos]) != "" {
+		return "", 0, false
+	}
+	lineEnd := len(script)
+	if n := strings.IndexByte(script[pos:], '\n'); n >= 0 {
+		lineEnd = pos + n + 1
+	}
+	return strings.TrimSpace(script[pos+length : lineEnd]), lineEnd, true
+}
+
+// isProceduralBlock 判断语句是否为过程块，过程块内部的 ; 不作为语句分隔符
+// Oracle：匿名块（DECLARE/BEGIN）以及 CREATE [OR REPLACE] PROCEDURE/FUNCTION/PACKAGE/TRIGGER/TYPE；
+// SQL Server：DECLARE、IF、WHILE、BEGIN（BEGIN TRAN 除外）以及 CREATE/ALTER PROCEDURE/FUNCTION/TRIGGER/VIEW，
+// 这些语句需要作为一个批处理整体执行
+func isProceduralBlock(dbType string, tokens []sqlToken) bool {
+	var words []string
+	for _, tok := range tokens {
+		if tok.kind != sqlTokenWord {
+			break
+		}
+		words = append(words, strings.ToUpper(tok.text))
+		if len(words) == 4 {
+			break
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+
+	switch dbType {
+	case "oracle":
+		switch words[0] {
+		case "DECLARE", "BEGIN":
+			return true
+		case "CREATE":
+			for _, w := range words[1:] {
+				switch w {
+				case "PROCEDURE", "FUNCTION", "PACKAGE", "TRIGGER", "TYPE", "LIBRARY":
+					return true
+				case "OR", "REPLACE", "EDITIONABLE", "NONEDITIONABLE":
+					continue
+				}
+				return false
+			}
+		}
+	case "sqlserver":
+		switch words[0] {
+		case "DECLARE", "IF", "WHILE":
+			return true
+		case "BEGIN":
+			if len(words) > 1 {
+				switch words[1] {
+				case "TRAN", "TRANSACTION", "DISTRIBUTED":
+					return false
+				}
+			}
+			return true
+		case "CREATE", "ALTER":
+			for _, w := range words[1:] {
+				switch w {
+				case "PROCEDURE", "PROC", "FUNCTION", "TRIGGER", "VIEW":
+					return true
+				case "OR", "ALTER":
+					continue
+				}
+				return false
+			}
+		}
+	}
+	return false
+}
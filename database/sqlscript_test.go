@@ -0,0 +1,104 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSQLScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		dbType   string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "分号拆分并丢弃空语句和注释",
+			dbType:   "mysql",
+			script:   "INSERT INTO t VALUES ('a;b');\n;\n-- done;\nUPDATE t SET v = \"x;\" WHERE id = 1; /* tail; */",
+			expected: []string{"INSERT INTO t VALUES ('a;b')", "UPDATE t SET v = \"x;\" WHERE id = 1"},
+		},
+		{
+			name:   "PostgreSQL 美元符号字符串",
+			dbType: "postgresql",
+			script: "CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql;\nSELECT f();",
+			expected: []string{
+				"CREATE FUNCTION f() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql",
+				"SELECT f()",
+			},
+		},
+		{
+			name:   "SQL Server GO 批处理",
+			dbType: "sqlserver",
+			script: "CREATE PROCEDURE p AS\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND\nGO\nINSERT INTO t VALUES (1); INSERT INTO t VALUES (2)\ngo 2\nSELECT 'GO'",
+			expected: []string{
+				"CREATE PROCEDURE p AS\nBEGIN\n  SELECT 1;\n  SELECT 2;\nEND",
+				"INSERT INTO t VALUES (1)",
+				"INSERT INTO t VALUES (2)",
+				"INSERT INTO t VALUES (2)",
+				"SELECT 'GO'",
+			},
+		},
+		{
+			name:   "Oracle PL/SQL 块",
+			dbType: "oracle",
+			script: "CREATE OR REPLACE PROCEDURE p IS\nBEGIN\n  UPDATE t SET v = v / 2;\nEND;\n/\nSELECT 1 FROM dual;\nBEGIN p; END;\n/",
+			expected: []string{
+				"CREATE OR REPLACE PROCEDURE p IS\nBEGIN\n  UPDATE t SET v = v / 2;\nEND;",
+				"SELECT 1 FROM dual",
+				"BEGIN p; END;",
+			},
+		},
+		{
+			name:     "MySQL 反斜杠转义和 # 注释",
+			dbType:   "mysql",
+			script:   "SELECT 'it\\'s; fine'; # note;\nSELECT 2",
+			expected: []string{`SELECT 'it\'s; fine'`, "SELECT 2"},
+		},
+		{
+			name:     "ClickHouse 反斜杠转义",
+			dbType:   "clickhouse",
+			script:   `SELECT 'a\';b'; SELECT 1`,
+			expected: []string{`SELECT 'a\';b'`, "SELECT 1"},
+		},
+		{
+			name:     "PostgreSQL 反斜杠是普通字符",
+			dbType:   "postgresql",
+			script:   `SELECT 'C:\'; SET search_path = app`,
+			expected: []string{`SELECT 'C:\'`, "SET search_path = app"},
+		},
+		{
+			name:     "PostgreSQL E 字符串",
+			dbType:   "postgresql",
+			script:   `SELECT E'it\'s; x'; SELECT 2`,
+			expected: []string{`SELECT E'it\'s; x'`, "SELECT 2"},
+		},
+		{
+			name:     "SQL Server 反斜杠和临时表",
+			dbType:   "sqlserver",
+			script:   `INSERT INTO #tmp VALUES ('C:\'); USE shop`,
+			expected: []string{`INSERT INTO #tmp VALUES ('C:\')`, "USE shop"},
+		},
+		{
+			name:     "Oracle 反斜杠是普通字符",
+			dbType:   "oracle",
+			script:   "SELECT 'C:\\' FROM dual;\nCOMMIT;",
+			expected: []string{`SELECT 'C:\' FROM dual`, "COMMIT"},
+		},
+		{
+			name:     "SQLite 反斜杠是普通字符",
+			dbType:   "sqlite",
+			script:   `SELECT 'C:\'; PRAGMA foreign_keys = ON`,
+			expected: []string{`SELECT 'C:\'`, "PRAGMA foreign_keys = ON"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitSQLScript(tt.dbType, tt.script)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("SplitSQLScript() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
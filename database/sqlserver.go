@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"net/url"
 	"strings"
@@ -85,6 +87,10 @@ func (s *SQLServer) GetTableSchema(tableName string) (string, error) {
 					WHEN NUMERIC_PRECISION IS NOT NULL AND NUMERIC_SCALE IS NOT NULL THEN '(' + CAST(NUMERIC_PRECISION AS VARCHAR) + ',' + CAST(NUMERIC_SCALE AS VARCHAR) + ')'
 					ELSE ''
 				END +
+				CASE 
+					WHEN COLUMNPROPERTY(OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME)), COLUMN_NAME, 'IsIdentity') = 1 THEN ' IDENTITY(1,1)'
+					ELSE ''
+				END +
 				CASE 
 					WHEN IS_NULLABLE = 'NO' THEN ' NOT NULL'
 					ELSE ''
@@ -133,6 +139,8 @@ func (s *SQLServer) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 			END as DATA_TYPE,
 			IS_NULLABLE,
 			COLUMN_DEFAULT,
+			COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'IsIdentity') as IS_IDENTITY,
+			COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'IsComputed') as IS_COMPUTED,
 			CASE 
 				WHEN EXISTS (
 					SELECT 1 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
@@ -160,12 +168,16 @@ func (s *SQLServer) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 		var col ColumnInfo
 		var nullable string
 		var defaultVal sql.NullString
+		var isIdentity, isComputed sql.NullInt64
 
-		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &col.Key); err != nil {
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultVal, &isIdentity, &isComputed, &col.Key); err != nil {
 			return nil, err
 		}
 
 		col.Nullable = (nullable == "YES")
+		col.AutoIncrement = isIdentity.Int64 == 1
+		// rowversion 列的类型显示为 timestamp，与计算列一样不能写入
+		col.Generated = isComputed.Int64 == 1 || strings.EqualFold(col.Type, "timestamp")
 		if defaultVal.Valid {
 			col.DefaultValue = defaultVal.String
 		}
@@ -177,7 +189,12 @@ func (s *SQLServer) GetTableColumns(tableName string) ([]ColumnInfo, error) {
 
 // ExecuteQuery 执行查询
 func (s *SQLServer) ExecuteQuery(query string) ([]map[string]interface{}, error) {
-	rows, err := s.db.Query(query)
+	return s.ExecuteQueryContext(context.Background(), query)
+}
+
+// ExecuteQueryContext 执行查询，ctx 取消或超时时中止执行
+func (s *SQLServer) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -215,9 +232,274 @@ func (s *SQLServer) ExecuteQuery(query string) ([]map[string]interface{}, error)
 	return results, rows.Err()
 }
 
+// QueryRows 流式执行查询，返回行迭代器
+func (s *SQLServer) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return NewSQLRowIterator(rows)
+}
+
+// BeginTx 开启固定在单个连接上的显式事务
+func (s *SQLServer) BeginTx(ctx context.Context) (Transaction, error) {
+	return beginSQLTransaction(ctx, s.db, "sqlserver")
+}
+
+// PinConn 从连接池取出一个独占连接，用于需要共享会话状态的多条语句
+func (s *SQLServer) PinConn(ctx context.Context) (PinnedConn, error) {
+	return pinSQLConn(ctx, s.db)
+}
+
+// UpdateRows 使用参数化语句按键更新行
+func (s *SQLServer) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateSQLRows(ctx, s.db, "sqlserver", tableName, keys, values)
+}
+
+// DeleteRows 使用参数化语句按键删除行
+func (s *SQLServer) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteSQLRows(ctx, s.db, "sqlserver", tableName, keys)
+}
+
+// SnapshotRows 使用参数化语句按键读取整行，用于撤销行修改
+func (s *SQLServer) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	return snapshotSQLRows(ctx, s.db, "sqlserver", tableName, keys)
+}
+
+// InsertRow 使用参数化语句插入一行，返回新行的主键
+func (s *SQLServer) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertSQLRow(ctx, s.db, "sqlserver", tableName, columns, values)
+}
+
+// InsertRows 使用多行 INSERT 语句批量插入，keys 不为空时 upsert
+func (s *SQLServer) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertSQLRows(ctx, s.db, "sqlserver", tableName, columns, rows, keys)
+}
+
+// QueryArgsContext 执行带绑定参数的查询
+func (s *SQLServer) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return querySQLArgs(ctx, s.db, query, args)
+}
+
+// ExecArgsContext 执行带绑定参数的更新类语句
+func (s *SQLServer) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	return execSQLArgs(ctx, s.db, query, args)
+}
+
+// TruncateTable 清空表
+func (s *SQLServer) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateSQLTable(ctx, s.db, "sqlserver", tableName, false)
+}
+
+// CreateTable 按列信息建表
+func (s *SQLServer) CreateTable(ctx context.Context, tableName string, columns []ColumnInfo) error {
+	return createSQLTable(ctx, s.db, "sqlserver", tableName, columns)
+}
+
+// GetTableMetadata 从 sys 目录视图读取表的注释（MS_Description 扩展属性）、索引、外键和检查约束
+func (s *SQLServer) GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error) {
+	columns, err := s.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	meta := newTableMetadata(tableName, columns)
+
+	err = queryMetadata(ctx, s.db, `
+		SELECT c.name, CAST(ep.value AS NVARCHAR(MAX))
+		FROM sys.extended_properties ep
+		LEFT JOIN sys.columns c ON c.object_id = ep.major_id AND c.column_id = ep.minor_id
+		WHERE ep.class = 1 AND ep.name = 'MS_Description' AND ep.major_id = OBJECT_ID(@p1)`, func(rows *sql.Rows) error {
+		var column sql.NullString
+		var comment string
+		if err := rows.Scan(&column, &comment); err != nil {
+			return err
+		}
+		// minor_id 为 0 的是表的注释
+		if column.Valid {
+			meta.ColumnComments[column.String] = comment
+		} else {
+			meta.Comment = comment
+		}
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+
+	err = queryMetadata(ctx, s.db, `
+		SELECT i.name, c.name, i.is_unique, i.is_primary_key, i.type_desc
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(@p1) AND i.name IS NOT NULL AND ic.is_included_column = 0
+		ORDER BY i.is_primary_key DESC, i.name, ic.key_ordinal`, func(rows *sql.Rows) error {
+		var name, column, indexType string
+		var unique, primary bool
+		if err := rows.Scan(&name, &column, &unique, &primary, &indexType); err != nil {
+			return err
+		}
+		meta.Indexes = addIndexColumn(meta.Indexes, name, column, unique, primary, indexType)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexes: %w", err)
+	}
+
+	err = queryMetadata(ctx, s.db, `
+		SELECT fk.name, pc.name, rt.name, rc.name, fk.update_referential_action_desc, fk.delete_referential_action_desc
+		FROM sys.foreign_keys fk
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE fk.parent_object_id = OBJECT_ID(@p1)
+		ORDER BY fk.name, fkc.constraint_column_id`, func(rows *sql.Rows) error {
+		var name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		// 动作名称形如 NO_ACTION、SET_NULL
+		onUpdate, onDelete = strings.ReplaceAll(onUpdate, "_", " "), strings.ReplaceAll(onDelete, "_", " ")
+		meta.ForeignKeys = addForeignKeyColumn(meta.ForeignKeys, name, column, refTable, refColumn, onUpdate, onDelete)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query foreign keys: %w", err)
+	}
+
+	err = queryMetadata(ctx, s.db, "SELECT name, definition FROM sys.check_constraints WHERE parent_object_id = OBJECT_ID(@p1) ORDER BY name", func(rows *sql.Rows) error {
+		var check CheckConstraintInfo
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return err
+		}
+		meta.Checks = append(meta.Checks, check)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check constraints: %w", err)
+	}
+	return meta, nil
+}
+
+// GetViews 获取当前数据库中的视图
+func (s *SQLServer) GetViews(ctx context.Context) ([]ViewInfo, error) {
+	views := []ViewInfo{}
+	err := queryMetadata(ctx, s.db, "SELECT v.name, m.definition FROM sys.views v JOIN sys.sql_modules m ON m.object_id = v.object_id ORDER BY v.name", func(rows *sql.Rows) error {
+		var view ViewInfo
+		var definition sql.NullString
+		if err := rows.Scan(&view.Name, &definition); err != nil {
+			return err
+		}
+		// 加密的视图没有定义
+		view.Definition = definition.String
+		views = append(views, view)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	return views, nil
+}
+
+// GetRoutines 获取当前数据库中的存储过程和函数，定义为完整的 CREATE 语句
+func (s *SQLServer) GetRoutines(ctx context.Context) ([]RoutineInfo, error) {
+	routines := []RoutineInfo{}
+	err := queryMetadata(ctx, s.db, `
+		SELECT ROUTINE_NAME, ROUTINE_TYPE, DATA_TYPE,
+			OBJECT_DEFINITION(OBJECT_ID(QUOTENAME(ROUTINE_SCHEMA) + '.' + QUOTENAME(ROUTINE_NAME)))
+		FROM INFORMATION_SCHEMA.ROUTINES
+		ORDER BY ROUTINE_TYPE, ROUTINE_NAME`, func(rows *sql.Rows) error {
+		var routine RoutineInfo
+		var returnType, definition sql.NullString
+		if err := rows.Scan(&routine.Name, &routine.Type, &returnType, &definition); err != nil {
+			return err
+		}
+		routine.ReturnType, routine.Definition = returnType.String, definition.String
+		routines = append(routines, routine)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query routines: %w", err)
+	}
+	return routines, nil
+}
+
+// GetTriggers 获取表的触发器，tableName 为空时返回所有表的触发器
+func (s *SQLServer) GetTriggers(ctx context.Context, tableName string) ([]TriggerInfo, error) {
+	triggers := []TriggerInfo{}
+	err := queryMetadata(ctx, s.db, `
+		SELECT tr.name, t.name,
+			CASE WHEN tr.is_instead_of_trigger = 1 THEN 'INSTEAD OF' ELSE 'AFTER' END,
+			STUFF((SELECT ' OR ' + te.type_desc FROM sys.trigger_events te WHERE te.object_id = tr.object_id FOR XML PATH('')), 1, 4, ''),
+			m.definition
+		FROM sys.triggers tr
+		JOIN sys.tables t ON t.object_id = tr.parent_id
+		LEFT JOIN sys.sql_modules m ON m.object_id = tr.object_id
+		WHERE @p1 = '' OR t.name = @p1
+		ORDER BY t.name, tr.name`, func(rows *sql.Rows) error {
+		var trigger TriggerInfo
+		var event, definition sql.NullString
+		if err := rows.Scan(&trigger.Name, &trigger.Table, &trigger.Timing, &event, &definition); err != nil {
+			return err
+		}
+		trigger.Event, trigger.Definition = event.String, definition.String
+		triggers = append(triggers, trigger)
+		return nil
+	}, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query triggers: %w", err)
+	}
+	return triggers, nil
+}
+
+// ExplainQuery 使用 SET SHOWPLAN_XML 获取预估的执行计划（不执行查询，不支持 analyze）
+// SHOWPLAN_XML 是会话级设置，因此在单独的连接上执行；无法恢复设置时丢弃该连接
+func (s *SQLServer) ExplainQuery(ctx context.Context, query string, analyze bool) (*QueryPlan, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, fmt.Errorf("failed to enable showplan: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SET SHOWPLAN_XML OFF"); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+	// 每个批处理返回一行 XML
+	var raw string
+	for rows.Next() {
+		var part string
+		if err := rows.Scan(&part); err != nil {
+			return nil, err
+		}
+		raw += part
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	return parseSQLServerShowplan(raw)
+}
+
 // ExecuteUpdate 执行更新
 func (s *SQLServer) ExecuteUpdate(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteUpdateContext(context.Background(), query)
+}
+
+// ExecuteUpdateContext 执行更新，ctx 取消或超时时中止执行
+func (s *SQLServer) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute update: %w", err)
 	}
@@ -226,7 +508,12 @@ func (s *SQLServer) ExecuteUpdate(query string) (int64, error) {
 
 // ExecuteDelete 执行删除
 func (s *SQLServer) ExecuteDelete(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteDeleteContext(context.Background(), query)
+}
+
+// ExecuteDeleteContext 执行删除，ctx 取消或超时时中止执行
+func (s *SQLServer) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute delete: %w", err)
 	}
@@ -235,7 +522,12 @@ func (s *SQLServer) ExecuteDelete(query string) (int64, error) {
 
 // ExecuteInsert 执行插入
 func (s *SQLServer) ExecuteInsert(query string) (int64, error) {
-	result, err := s.db.Exec(query)
+	return s.ExecuteInsertContext(context.Background(), query)
+}
+
+// ExecuteInsertContext 执行插入，ctx 取消或超时时中止执行
+func (s *SQLServer) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, query)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
 	}
@@ -244,6 +536,11 @@ func (s *SQLServer) ExecuteInsert(query string) (int64, error) {
 
 // GetTableData 获取表数据（分页）
 func (s *SQLServer) GetTableData(tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
+	return s.GetTableDataContext(context.Background(), tableName, page, pageSize, filters)
+}
+
+// GetTableDataContext 获取表数据（分页），ctx 取消或超时时中止执行
+func (s *SQLServer) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup) ([]map[string]interface{}, int64, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("sqlserver", tableName, filters)
 	if err != nil {
@@ -256,7 +553,7 @@ func (s *SQLServer) GetTableData(tableName string, page, pageSize int, filters *
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := s.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := s.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -272,7 +569,7 @@ func (s *SQLServer) GetTableData(tableName string, page, pageSize int, filters *
 		FETCH NEXT %d ROWS ONLY
 	`, offset, pageSize)
 
-	rows, err := s.db.Query(query, whereArgs...)
+	rows, err := s.db.QueryContext(ctx, query, whereArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query data: %w", err)
 	}
@@ -314,6 +611,11 @@ func (s *SQLServer) GetTableData(tableName string, page, pageSize int, filters *
 
 // GetTableDataByID 基于主键ID获取表数据（高性能分页）
 func (s *SQLServer) GetTableDataByID(tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return s.GetTableDataByIDContext(context.Background(), tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+// GetTableDataByIDContext 基于主键ID获取表数据（高性能分页），ctx 取消或超时时中止执行
+func (s *SQLServer) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
 	// 构建 WHERE 子句
 	whereClause, whereArgs, err := BuildWhereClause("sqlserver", tableName, filters)
 	if err != nil {
@@ -326,7 +628,7 @@ func (s *SQLServer) GetTableDataByID(tableName string, primaryKey string, lastId
 	if whereClause != "" {
 		countQuery += " WHERE " + whereClause
 	}
-	if err := s.db.QueryRow(countQuery, whereArgs...).Scan(&total); err != nil {
+	if err := s.db.QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
 		return nil, 0, nil, fmt.Errorf("failed to query total count: %w", err)
 	}
 
@@ -341,14 +643,14 @@ func (s *SQLServer) GetTableDataByID(tableName string, primaryKey string, lastId
 		if lastId == nil {
 			return nil, 0, nil, fmt.Errorf("lastId is required for previous page")
 		}
-		idCondition = fmt.Sprintf("[%s] < ?", primaryKey)
+		idCondition = buildIDCursorCondition("sqlserver", "["+primaryKey+"]", "<", len(whereArgs))
 		queryArgs = append(whereArgs, lastId)
 	} else {
 		if lastId == nil {
 			idCondition = ""
 			queryArgs = whereArgs
 		} else {
-			idCondition = fmt.Sprintf("[%s] > ?", primaryKey)
+			idCondition = buildIDCursorCondition("sqlserver", "["+primaryKey+"]", ">", len(whereArgs))
 			queryArgs = append(whereArgs, lastId)
 		}
 	}
@@ -379,7 +681,7 @@ func (s *SQLServer) GetTableDataByID(tableName string, primaryKey string, lastId
 		`, pageSize, tableName, wherePart, primaryKey)
 	}
 	
-	rows, err = s.db.Query(query, queryArgs...)
+	rows, err = s.db.QueryContext(ctx, query, queryArgs...)
 
 	if err != nil {
 		return nil, 0, nil, fmt.Errorf("查询数据失败: %w", err)
@@ -437,6 +739,21 @@ func (s *SQLServer) GetTableDataByID(tableName string, primaryKey string, lastId
 	return results, total, nextId, rows.Err()
 }
 
+// GetTableDataSorted 获取表数据（分页 + 多列排序）
+func (s *SQLServer) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return querySortedTableData(ctx, s.db, "sqlserver", fmt.Sprintf("[%s]", tableName), tableName, page, pageSize, filters, sorts)
+}
+
+// GetTableDataByCursor 基于游标获取表数据（keyset 分页，支持非主键排序）
+func (s *SQLServer) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *FilterGroup, sorts []SortSpec) ([]map[string]interface{}, int64, error) {
+	return queryTableDataByCursor(ctx, s.db, "sqlserver", fmt.Sprintf("[%s]", tableName), tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+}
+
+// StreamTableRows 流式读取表数据（用于导出）
+func (s *SQLServer) StreamTableRows(ctx context.Context, tableName string, filters *FilterGroup, sorts []SortSpec) (RowIterator, error) {
+	return streamSQLTableRows(ctx, s.db, "sqlserver", fmt.Sprintf("[%s]", tableName), tableName, filters, sorts)
+}
+
 // GetPageIdByPageNumber 根据页码计算该页的起始ID（用于页码跳转）
 func (s *SQLServer) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	if page <= 1 {
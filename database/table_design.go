@@ -0,0 +1,530 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableDesign 表设计器提交的表定义
+type TableDesign struct {
+	Name    string         `json:"name"`
+	Columns []DesignColumn `json:"columns"`
+	Indexes []DesignIndex  `json:"indexes"` // 主键以外的索引，主键由列的 PrimaryKey 表示
+}
+
+// DesignColumn 表设计器中的列。Type 和 Default 为本数据库的类型和 SQL 表达式（字符串默认值需要带引号），按原样写入语句
+// 修改已有表时 OriginalName 为列的原名，与 Name 不同表示重命名；为空时按 Name 匹配已有的列，没有匹配的列为新增的列
+type DesignColumn struct {
+	Name          string `json:"name"`
+	OriginalName  string `json:"originalName,omitempty"`
+	Type          string `json:"type"`
+	Nullable      bool   `json:"nullable"`
+	Default       string `json:"default,omitempty"`
+	PrimaryKey    bool   `json:"primaryKey"`
+	AutoIncrement bool   `json:"autoIncrement"`
+}
+
+// DesignIndex 表设计器中的索引
+type DesignIndex struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+// DesignFromMetadata 由已有表的元数据生成表定义，作为修改表时的初始值（默认值转换为 SQL 表达式）
+func DesignFromMetadata(dbType string, meta *TableMetadata) TableDesign {
+	design := TableDesign{Name: meta.Name, Columns: []DesignColumn{}, Indexes: []DesignIndex{}}
+	primary := map[string]bool{}
+	for _, name := range primaryKeyColumns(meta) {
+		primary[name] = true
+	}
+	for _, col := range meta.Columns {
+		column := DesignColumn{
+			Name:          col.Name,
+			OriginalName:  col.Name,
+			Type:          col.Type,
+			Nullable:      col.Nullable,
+			PrimaryKey:    primary[col.Name],
+			AutoIncrement: col.AutoIncrement,
+		}
+		if dbType == "clickhouse" {
+			// 可空性在设计器中单独表示
+			if inner, ok := unwrapNullable(col.Type); ok {
+				column.Type, column.Nullable = inner, true
+			}
+		}
+		if value := strings.TrimSpace(col.DefaultValue); value != "" {
+			column.Default = defaultExpression(dbType, value)
+		}
+		design.Columns = append(design.Columns, column)
+	}
+	if dbType != "clickhouse" {
+		for _, idx := range meta.Indexes {
+			if !idx.Primary {
+				design.Indexes = append(design.Indexes, DesignIndex{Name: idx.Name, Columns: idx.Columns, Unique: idx.Unique})
+			}
+		}
+	}
+	return design
+}
+
+// BuildTableDesignSQL 生成使表符合 design 的语句（dbType 的方言）：current 为 nil 时建表，否则修改 current 对应的已有表
+// 返回的 warnings 为无法自动生成的变更（如 SQLite 的列修改、SQL Server 的默认值），需要手动处理
+func BuildTableDesignSQL(dbType string, current *TableMetadata, design TableDesign) (statements []string, warnings []string, err error) {
+	if !SupportsCreateTable(dbType) {
+		return nil, nil, fmt.Errorf("table design is not supported for %s", dbType)
+	}
+	if err := validateTableDesign(&design); err != nil {
+		return nil, nil, err
+	}
+	if dbType == "clickhouse" && len(design.Indexes) > 0 {
+		warnings = append(warnings, "ClickHouse data skipping indexes must be managed manually")
+	}
+	for _, col := range design.Columns {
+		if col.AutoIncrement && (dbType == "clickhouse" || dbType == "sqlite") {
+			warnings = append(warnings, fmt.Sprintf("%s: auto-increment is not supported for %s", col.Name, dbType))
+		}
+	}
+
+	if current == nil {
+		columns := make([]ColumnInfo, len(design.Columns))
+		for i, col := range design.Columns {
+			columns[i] = designColumnInfo(dbType, col)
+		}
+		query, err := BuildCreateTableSQL(dbType, design.Name, columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		statements = append(statements, query)
+		if dbType != "clickhouse" {
+			for _, idx := range design.Indexes {
+				statements = append(statements, createIndexSQL(dbType, design.Name, idx))
+			}
+		}
+		return statements, warnings, nil
+	}
+
+	alter, alterWarnings, err := alterTableDesignSQL(dbType, current, design)
+	if err != nil {
+		return nil, nil, err
+	}
+	return alter, append(warnings, alterWarnings...), nil
+}
+
+// validateTableDesign 校验表定义：名称合法、列名不重复、索引引用的列存在（索引列名统一为列定义中的写法）
+func validateTableDesign(design *TableDesign) error {
+	if err := validateIdentifier(design.Name); err != nil {
+		return err
+	}
+	if len(design.Columns) == 0 {
+		return fmt.Errorf("at least one column is required")
+	}
+	names := make([]string, 0, len(design.Columns))
+	for _, col := range design.Columns {
+		if err := validateIdentifier(col.Name); err != nil {
+			return err
+		}
+		if containsFold(names, col.Name) {
+			return fmt.Errorf("duplicate column: %s", col.Name)
+		}
+		names = append(names, col.Name)
+		if strings.TrimSpace(col.Type) == "" {
+			return fmt.Errorf("column %s has no type", col.Name)
+		}
+		if err := validateDesignFragment(col.Type); err != nil {
+			return fmt.Errorf("column %s type: %w", col.Name, err)
+		}
+		if err := validateDesignFragment(col.Default); err != nil {
+			return fmt.Errorf("column %s default: %w", col.Name, err)
+		}
+	}
+
+	var indexNames []string
+	for i, idx := range design.Indexes {
+		if err := validateIdentifier(idx.Name); err != nil {
+			return err
+		}
+		if containsFold(indexNames, idx.Name) {
+			return fmt.Errorf("duplicate index: %s", idx.Name)
+		}
+		indexNames = append(indexNames, idx.Name)
+		if len(idx.Columns) == 0 {
+			return fmt.Errorf("index %s has no columns", idx.Name)
+		}
+		columns := make([]string, len(idx.Columns))
+		for j, name := range idx.Columns {
+			col, ok := findTableFold(names, name)
+			if !ok {
+				return fmt.Errorf("index %s: unknown column %s", idx.Name, name)
+			}
+			columns[j] = col
+		}
+		design.Indexes[i].Columns = columns
+	}
+	return nil
+}
+
+// validateDesignFragment 类型和默认值原样写入语句，禁止其中包含语句分隔符和注释
+func validateDesignFragment(s string) error {
+	if strings.Contains(s, ";") || strings.Contains(s, "--") || strings.Contains(s, "/*") {
+		return fmt.Errorf("must not contain ';' or comments")
+	}
+	return nil
+}
+
+// designColumnInfo 把设计器中的列转换为列信息：主键列不可为空，ClickHouse 的可空列使用 Nullable(...)
+func designColumnInfo(dbType string, col DesignColumn) ColumnInfo {
+	info := ColumnInfo{
+		Name:          col.Name,
+		Type:          strings.TrimSpace(col.Type),
+		Nullable:      col.Nullable && !col.PrimaryKey,
+		DefaultValue:  strings.TrimSpace(col.Default),
+		AutoIncrement: col.AutoIncrement,
+	}
+	if col.PrimaryKey {
+		info.Key = "PRI"
+	}
+	if dbType == "clickhouse" && info.Nullable {
+		if _, ok := unwrapNullable(info.Type); !ok {
+			info.Type = "Nullable(" + info.Type + ")"
+		}
+	}
+	return info
+}
+
+// unwrapNullable 返回 ClickHouse Nullable(...) 类型中的类型
+func unwrapNullable(columnType string) (string, bool) {
+	if strings.HasPrefix(columnType, "Nullable(") && strings.HasSuffix(columnType, ")") {
+		return columnType[len("Nullable(") : len(columnType)-1], true
+	}
+	return columnType, false
+}
+
+// primaryKeyColumns 返回表的主键列，没有主键索引信息时按列的 Key 判断
+func primaryKeyColumns(meta *TableMetadata) []string {
+	for _, idx := range meta.Indexes {
+		if idx.Primary {
+			return idx.Columns
+		}
+	}
+	var columns []string
+	for _, col := range meta.Columns {
+		if col.Key == "PRI" {
+			columns = append(columns, col.Name)
+		}
+	}
+	return columns
+}
+
+// alterTableDesignSQL 生成修改已有表的语句。语句按以下顺序生成，保证每一步引用的列和索引都存在：
+// 删除索引、删除主键、删除列、重命名列、添加列、修改列、添加主键、创建索引，最后重命名表
+func alterTableDesignSQL(dbType string, current *TableMetadata, design TableDesign) ([]string, []string, error) {
+	if err := validateIdentifier(current.Name); err != nil {
+		return nil, nil, err
+	}
+	quote := getQuoteFunc(dbType)
+	ref := sqlTableRef(dbType, current.Name)
+	var statements, warnings []string
+
+	// 设计中的列对应的已有列，以及已有列在设计中的新名称
+	existing := make([]*ColumnInfo, len(design.Columns))
+	renamed := map[string]string{}
+	for i, col := range design.Columns {
+		name := col.OriginalName
+		if name == "" {
+			name = col.Name
+		}
+		for j := range current.Columns {
+			if strings.EqualFold(current.Columns[j].Name, name) {
+				existing[i] = &current.Columns[j]
+				break
+			}
+		}
+		if existing[i] == nil {
+			if col.OriginalName != "" {
+				return nil, nil, fmt.Errorf("column %s does not exist", col.OriginalName)
+			}
+			continue
+		}
+		if _, ok := renamed[existing[i].Name]; ok {
+			return nil, nil, fmt.Errorf("column %s is used more than once", existing[i].Name)
+		}
+		renamed[existing[i].Name] = col.Name
+	}
+	// renamedColumns 把已有的列名转换为设计中的列名，已删除的列返回 false
+	renamedColumns := func(columns []string) ([]string, bool) {
+		result := make([]string, len(columns))
+		for i, name := range columns {
+			newName, ok := renamed[name]
+			if !ok {
+				return nil, false
+			}
+			result[i] = newName
+		}
+		return result, true
+	}
+
+	var designKey []string
+	for _, col := range design.Columns {
+		if col.PrimaryKey {
+			designKey = append(designKey, col.Name)
+		}
+	}
+	currentKey := primaryKeyColumns(current)
+	mappedKey, ok := renamedColumns(currentKey)
+	keyChanged := !ok || !equalFold(mappedKey, designKey)
+	if keyChanged && (dbType == "sqlite" || dbType == "clickhouse") {
+		warnings = append(warnings, fmt.Sprintf("%s: primary key changes are not supported for %s, the table must be rebuilt", current.Name, dbType))
+		keyChanged = false
+	}
+
+	// 删除已删除或定义改变的索引
+	var createIndexes []DesignIndex
+	if dbType != "clickhouse" {
+		for _, idx := range current.Indexes {
+			if idx.Primary {
+				continue
+			}
+			columns, ok := renamedColumns(idx.Columns)
+			keep := false
+			for _, want := range design.Indexes {
+				if strings.EqualFold(want.Name, idx.Name) {
+					keep = ok && want.Unique == idx.Unique && equalFold(columns, want.Columns)
+					break
+				}
+			}
+			if !keep {
+				if err := validateIdentifier(idx.Name); err != nil {
+					return nil, nil, err
+				}
+				statements = append(statements, dropIndexSQL(dbType, ref, quote(idx.Name)))
+			}
+		}
+		for _, want := range design.Indexes {
+			keep := false
+			for _, idx := range current.Indexes {
+				if !idx.Primary && strings.EqualFold(want.Name, idx.Name) {
+					columns, ok := renamedColumns(idx.Columns)
+					keep = ok && want.Unique == idx.Unique && equalFold(columns, want.Columns)
+					break
+				}
+			}
+			if !keep {
+				createIndexes = append(createIndexes, want)
+			}
+		}
+	}
+
+	if keyChanged && len(currentKey) > 0 {
+		switch dbType {
+		case "postgresql", "sqlserver":
+			name := ""
+			for _, idx := range current.Indexes {
+				if idx.Primary {
+					name = idx.Name
+				}
+			}
+			if err := validateIdentifier(name); err != nil {
+				return nil, nil, fmt.Errorf("primary key constraint: %w", err)
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", ref, quote(name)))
+		default:
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", ref))
+		}
+	}
+
+	for _, col := range current.Columns {
+		if _, ok := renamed[col.Name]; !ok {
+			if err := validateIdentifier(col.Name); err != nil {
+				return nil, nil, err
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", ref, quote(col.Name)))
+		}
+	}
+
+	for i, col := range design.Columns {
+		if existing[i] == nil || existing[i].Name == col.Name {
+			continue
+		}
+		if err := validateIdentifier(existing[i].Name); err != nil {
+			return nil, nil, err
+		}
+		statements = append(statements, renameColumnSQL(dbType, current.Name, ref, existing[i].Name, col.Name))
+	}
+
+	for i, col := range design.Columns {
+		if existing[i] == nil {
+			statements = append(statements, addColumnSQL(dbType, ref, fullColumnDefinition(dbType, designColumnInfo(dbType, col))))
+		}
+	}
+
+	for i, col := range design.Columns {
+		if existing[i] == nil {
+			continue
+		}
+		stmts, warns := modifyDesignColumnSQL(dbType, ref, current.Name, *existing[i], designColumnInfo(dbType, col))
+		statements = append(statements, stmts...)
+		warnings = append(warnings, warns...)
+	}
+
+	if keyChanged && len(designKey) > 0 {
+		keys := make([]string, len(designKey))
+		for i, name := range designKey {
+			keys[i] = quote(name)
+		}
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", ref, strings.Join(keys, ", ")))
+	}
+
+	for _, idx := range createIndexes {
+		statements = append(statements, createIndexSQL(dbType, current.Name, idx))
+	}
+
+	if design.Name != current.Name {
+		switch dbType {
+		case "sqlserver":
+			statements = append(statements, fmt.Sprintf("EXEC sp_rename %s, %s", FormatSQLLiteral(dbType, current.Name), FormatSQLLiteral(dbType, design.Name)))
+		case "clickhouse":
+			statements = append(statements, fmt.Sprintf("RENAME TABLE %s TO %s", ref, sqlTableRef(dbType, design.Name)))
+		default:
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s RENAME TO %s", ref, sqlTableRef(dbType, design.Name)))
+		}
+	}
+	return statements, warnings, nil
+}
+
+// modifyDesignColumnSQL 修改已有列的类型、可空性、默认值和自增属性的语句（列已经重命名为 col.Name）
+// 默认值按 SQL 表达式比较，MySQL 的已有默认值先按 defaultExpression 转换
+func modifyDesignColumnSQL(dbType, ref, table string, current, col ColumnInfo) ([]string, []string) {
+	typeChanged := normalizeColumnType(current.Type) != normalizeColumnType(col.Type)
+	nullableChanged := current.Nullable != col.Nullable && dbType != "clickhouse"
+	currentDefault := strings.TrimSpace(current.DefaultValue)
+	if currentDefault != "" {
+		currentDefault = defaultExpression(dbType, currentDefault)
+	}
+	defaultChanged := currentDefault != col.DefaultValue
+	autoChanged := current.AutoIncrement != col.AutoIncrement
+	if !typeChanged && !nullableChanged && !defaultChanged && !autoChanged {
+		return nil, nil
+	}
+
+	var statements, warnings []string
+	name := getQuoteFunc(dbType)(col.Name)
+	if autoChanged && dbType != "mysql" {
+		warnings = append(warnings, fmt.Sprintf("%s.%s: auto-increment changes must be applied manually", table, col.Name))
+	}
+	switch dbType {
+	case "sqlite":
+		if typeChanged || nullableChanged || defaultChanged {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: SQLite cannot alter columns, the table must be rebuilt", table, col.Name))
+		}
+	case "mysql":
+		// MODIFY COLUMN 使用完整的列定义，省略的默认值和自增属性会被移除
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", ref, fullColumnDefinition(dbType, col)))
+	case "clickhouse":
+		if typeChanged || (defaultChanged && col.DefaultValue != "") {
+			definition := name + " " + col.Type
+			if col.DefaultValue != "" {
+				definition += " DEFAULT " + col.DefaultValue
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", ref, definition))
+		}
+		if defaultChanged && col.DefaultValue == "" {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s REMOVE DEFAULT", ref, name))
+		}
+	case "postgresql", "h2":
+		if typeChanged {
+			keyword := "TYPE"
+			if dbType == "h2" {
+				keyword = "SET DATA TYPE"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s %s", ref, name, keyword, col.Type))
+		}
+		if nullableChanged {
+			action := "SET NOT NULL"
+			if col.Nullable {
+				action = "DROP NOT NULL"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s", ref, name, action))
+		}
+		if defaultChanged {
+			if col.DefaultValue == "" {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", ref, name))
+			} else {
+				statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", ref, name, col.DefaultValue))
+			}
+		}
+	case "sqlserver":
+		if typeChanged || nullableChanged {
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", ref, columnDefinition(dbType, col)+nullSuffix(col)))
+		}
+		if defaultChanged {
+			// 默认值是单独命名的约束
+			warnings = append(warnings, fmt.Sprintf("%s.%s: default value changes must be applied manually", table, col.Name))
+		}
+	case "oracle":
+		if typeChanged || nullableChanged {
+			// Oracle 修改为已有的可空性时报错（ORA-01442），只在可空性变化时指定
+			definition := name + " " + col.Type
+			if nullableChanged {
+				definition = columnDefinition(dbType, col) + nullSuffix(col)
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY (%s)", ref, definition))
+		}
+		if defaultChanged {
+			value := col.DefaultValue
+			if value == "" {
+				value = "NULL"
+			}
+			statements = append(statements, fmt.Sprintf("ALTER TABLE %s MODIFY (%s DEFAULT %s)", ref, name, value))
+		}
+	}
+	return statements, warnings
+}
+
+// renameColumnSQL 重命名列的语句
+func renameColumnSQL(dbType, table, ref, oldName, newName string) string {
+	quote := getQuoteFunc(dbType)
+	switch dbType {
+	case "sqlserver":
+		return fmt.Sprintf("EXEC sp_rename %s, %s, 'COLUMN'", FormatSQLLiteral(dbType, table+"."+oldName), FormatSQLLiteral(dbType, newName))
+	case "h2":
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s RENAME TO %s", ref, quote(oldName), quote(newName))
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", ref, quote(oldName), quote(newName))
+}
+
+// createIndexSQL 创建索引的语句
+func createIndexSQL(dbType, table string, idx DesignIndex) string {
+	quote := getQuoteFunc(dbType)
+	columns := make([]string, len(idx.Columns))
+	for i, name := range idx.Columns {
+		columns[i] = quote(name)
+	}
+	keyword := "INDEX"
+	if idx.Unique {
+		keyword = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s %s ON %s (%s)", keyword, quote(idx.Name), sqlTableRef(dbType, table), strings.Join(columns, ", "))
+}
+
+// dropIndexSQL 删除索引的语句，MySQL 和 SQL Server 的索引名只在表内唯一
+func dropIndexSQL(dbType, ref, name string) string {
+	switch dbType {
+	case "mysql", "sqlserver":
+		return fmt.Sprintf("DROP INDEX %s ON %s", name, ref)
+	}
+	return "DROP INDEX " + name
+}
+
+// equalFold 判断两个名称列表是否相同（不区分大小写）
+func equalFold(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildTableDesignSQLCreate(t *testing.T) {
+	design := TableDesign{
+		Name: "orders",
+		Columns: []DesignColumn{
+			{Name: "id", Type: "BIGINT", PrimaryKey: true, AutoIncrement: true},
+			{Name: "status", Type: "VARCHAR(16)", Default: "'new'"},
+			{Name: "note", Type: "TEXT", Nullable: true},
+		},
+		Indexes: []DesignIndex{{Name: "idx_status", Columns: []string{"STATUS"}}},
+	}
+
+	tests := []struct {
+		dbType   string
+		expected []string
+	}{
+		{"mysql", []string{
+			"CREATE TABLE `orders` (\n  `id` BIGINT AUTO_INCREMENT NOT NULL,\n  `status` VARCHAR(16) DEFAULT 'new' NOT NULL,\n  `note` TEXT,\n  PRIMARY KEY (`id`)\n)",
+			"CREATE INDEX `idx_status` ON `orders` (`status`)",
+		}},
+		{"oracle", []string{
+			"CREATE TABLE \"ORDERS\" (\n  \"ID\" BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,\n  \"STATUS\" VARCHAR(16) DEFAULT 'new' NOT NULL,\n  \"NOTE\" TEXT,\n  PRIMARY KEY (\"ID\")\n)",
+			"CREATE INDEX \"IDX_STATUS\" ON \"ORDERS\" (\"STATUS\")",
+		}},
+	}
+	for _, tt := range tests {
+		statements, warnings, err := BuildTableDesignSQL(tt.dbType, nil, design)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dbType, err)
+		}
+		if len(warnings) > 0 {
+			t.Errorf("%s: unexpected warnings %v", tt.dbType, warnings)
+		}
+		if !reflect.DeepEqual(statements, tt.expected) {
+			t.Errorf("%s: statements = %q, want %q", tt.dbType, statements, tt.expected)
+		}
+	}
+}
+
+func TestBuildTableDesignSQLAlter(t *testing.T) {
+	current := newTableMetadata("users", []ColumnInfo{
+		{Name: "id", Type: "integer", Key: "PRI"},
+		{Name: "name", Type: "varchar(32)"},
+		{Name: "email", Type: "text", Nullable: true},
+		{Name: "legacy", Type: "text", Nullable: true},
+	})
+	current.Indexes = []IndexInfo{
+		{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true},
+		{Name: "idx_email", Columns: []string{"email"}, Unique: true},
+		{Name: "idx_name", Columns: []string{"name"}},
+	}
+	design := TableDesign{
+		Name: "members",
+		Columns: []DesignColumn{
+			{Name: "id", OriginalName: "id", Type: "integer", PrimaryKey: true},
+			{Name: "full_name", OriginalName: "name", Type: "varchar(64)", Default: "''"},
+			{Name: "email", OriginalName: "email", Type: "TEXT", Nullable: true},
+			{Name: "created_at", Type: "timestamp", Default: "now()"},
+		},
+		Indexes: []DesignIndex{
+			{Name: "idx_email", Columns: []string{"email"}, Unique: true},
+			{Name: "idx_name", Columns: []string{"full_name", "created_at"}},
+		},
+	}
+
+	statements, warnings, err := BuildTableDesignSQL("postgresql", current, design)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		`DROP INDEX "idx_name"`,
+		`ALTER TABLE "users" DROP COLUMN "legacy"`,
+		`ALTER TABLE "users" RENAME COLUMN "name" TO "full_name"`,
+		`ALTER TABLE "users" ADD COLUMN "created_at" timestamp DEFAULT now() NOT NULL`,
+		`ALTER TABLE "users" ALTER COLUMN "full_name" TYPE varchar(64)`,
+		`ALTER TABLE "users" ALTER COLUMN "full_name" SET DEFAULT ''`,
+		`CREATE INDEX "idx_name" ON "users" ("full_name", "created_at")`,
+		`ALTER TABLE "users" RENAME TO "members"`,
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("statements =\n%q\nwant\n%q", statements, expected)
+	}
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	// 主键变化：先删除原主键约束，修改列后再添加
+	design = DesignFromMetadata("postgresql", current)
+	design.Columns[1].PrimaryKey = true
+	statements, _, err = BuildTableDesignSQL("postgresql", current, design)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		`ALTER TABLE "users" DROP CONSTRAINT "users_pkey"`,
+		`ALTER TABLE "users" ADD PRIMARY KEY ("id", "name")`,
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("statements =\n%q\nwant\n%q", statements, expected)
+	}
+
+	// SQLite 不能修改列和主键，只返回提示
+	design.Columns[2].Type = "varchar(255)"
+	statements, warnings, err = BuildTableDesignSQL("sqlite", current, design)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 0 || len(warnings) != 2 {
+		t.Errorf("sqlite: statements = %q, warnings = %q", statements, warnings)
+	}
+}
+
+func TestValidateTableDesign(t *testing.T) {
+	tests := []struct {
+		name   string
+		design TableDesign
+	}{
+		{"no columns", TableDesign{Name: "t"}},
+		{"duplicate column", TableDesign{Name: "t", Columns: []DesignColumn{{Name: "a", Type: "int"}, {Name: "A", Type: "int"}}}},
+		{"missing type", TableDesign{Name: "t", Columns: []DesignColumn{{Name: "a"}}}},
+		{"injected default", TableDesign{Name: "t", Columns: []DesignColumn{{Name: "a", Type: "int", Default: "1; DROP TABLE x"}}}},
+		{"unknown index column", TableDesign{Name: "t", Columns: []DesignColumn{{Name: "a", Type: "int"}}, Indexes: []DesignIndex{{Name: "i", Columns: []string{"b"}}}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := BuildTableDesignSQL("mysql", nil, tt.design); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Transaction 固定在单个连接上的显式事务
+// 同一事务上的语句按顺序执行；流式查询在迭代器关闭前会阻塞后续语句
+type Transaction interface {
+	// ExecuteQueryContext 在事务中执行查询
+	ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error)
+
+	// QueryRows 在事务中流式执行查询
+	QueryRows(ctx context.Context, query string) (RowIterator, error)
+
+	// ExecContext 在事务中执行更新类语句，返回影响行数
+	ExecContext(ctx context.Context, query string) (int64, error)
+
+	// Commit 提交事务
+	Commit() error
+
+	// Rollback 回滚事务
+	Rollback() error
+}
+
+// TransactionalDatabase 支持显式事务的数据库接口扩展
+type TransactionalDatabase interface {
+	// BeginTx 开启事务
+	// ctx 只用于开启事务；事务的生命周期由 Commit/Rollback 控制，不随 ctx 结束
+	BeginTx(ctx context.Context) (Transaction, error)
+}
+
+// PinnedConn 从连接池中独占的单个连接
+// 上面执行的语句共享会话状态（SET、USE、临时表、BEGIN/COMMIT 等），按顺序执行
+type PinnedConn interface {
+	// ExecuteQueryContext 在连接上执行查询
+	ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error)
+
+	// QueryRows 在连接上流式执行查询
+	QueryRows(ctx context.Context, query string) (RowIterator, error)
+
+	// ExecContext 在连接上执行更新类语句，返回影响行数
+	ExecContext(ctx context.Context, query string) (int64, error)
+
+	// Release 归还连接；discard 为 true 时关闭连接而不放回连接池，避免会话状态留给之后的请求
+	Release(discard bool) error
+}
+
+// ConnPinningDatabase 支持独占单个连接的数据库接口扩展
+type ConnPinningDatabase interface {
+	// PinConn 从连接池取出一个连接，用完后必须调用 Release
+	PinConn(ctx context.Context) (PinnedConn, error)
+}
+
+// statementRunner 在固定连接上执行语句（事务或独占连接）
+type statementRunner interface {
+	ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error)
+	QueryRows(ctx context.Context, query string) (RowIterator, error)
+	ExecContext(ctx context.Context, query string) (int64, error)
+}
+
+// beginSQLTransaction 在 *sql.DB 上开启事务
+// database/sql 会在 BeginTx 的 ctx 结束时自动回滚事务，因此这里只用 ctx 检查是否已取消
+// dbType 用于事务中按键修改行时生成对应方言的语句
+func beginSQLTransaction(ctx context.Context, db *sql.DB, dbType string) (Transaction, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &sqlTransaction{tx: tx, dbType: dbType}, nil
+}
+
+// sqlTransaction 基于 *sql.Tx 的事务实现
+type sqlTransaction struct {
+	tx     *sql.Tx
+	dbType string
+	mu     sync.Mutex // *sql.Tx 上同一时间只能有一个活动语句
+}
+
+func (t *sqlTransaction) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rows, err := t.tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return scanRowMaps(rows)
+}
+
+func (t *sqlTransaction) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	t.mu.Lock()
+	rows, err := t.tx.QueryContext(ctx, query)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	iter, err := NewSQLRowIterator(rows)
+	if err != nil {
+		t.mu.Unlock()
+		return nil, err
+	}
+	return &lockedRowIterator{RowIterator: iter, unlock: t.mu.Unlock}, nil
+}
+
+func (t *sqlTransaction) ExecContext(ctx context.Context, query string) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result, err := t.tx.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute statement: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (t *sqlTransaction) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return updateSQLRows(ctx, t.tx, t.dbType, tableName, keys, values)
+}
+
+func (t *sqlTransaction) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return deleteSQLRows(ctx, t.tx, t.dbType, tableName, keys)
+}
+
+func (t *sqlTransaction) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return snapshotSQLRows(ctx, t.tx, t.dbType, tableName, keys)
+}
+
+func (t *sqlTransaction) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return insertSQLRow(ctx, t.tx, t.dbType, tableName, columns, values)
+}
+
+func (t *sqlTransaction) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return insertSQLRows(ctx, t.tx, t.dbType, tableName, columns, rows, keys)
+}
+
+func (t *sqlTransaction) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return querySQLArgs(ctx, t.tx, query, args)
+}
+
+func (t *sqlTransaction) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return execSQLArgs(ctx, t.tx, query, args)
+}
+
+func (t *sqlTransaction) TruncateTable(ctx context.Context, tableName string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return truncateSQLTable(ctx, t.tx, t.dbType, tableName, true)
+}
+
+func (t *sqlTransaction) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tx.Commit()
+}
+
+func (t *sqlTransaction) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.tx.Rollback()
+}
+
+// pinSQLConn 从 *sql.DB 的连接池中取出一个连接
+func pinSQLConn(ctx context.Context, db *sql.DB) (PinnedConn, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return &sqlPinnedConn{conn: conn}, nil
+}
+
+// sqlPinnedConn 基于 *sql.Conn 的独占连接
+type sqlPinnedConn struct {
+	conn *sql.Conn
+	mu   sync.Mutex // *sql.Conn 上同一时间只能有一个活动语句
+}
+
+func (c *sqlPinnedConn) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	return scanRowMaps(rows)
+}
+
+func (c *sqlPinnedConn) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	c.mu.Lock()
+	rows, err := c.conn.QueryContext(ctx, query)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	iter, err := NewSQLRowIterator(rows)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	return &lockedRowIterator{RowIterator: iter, unlock: c.mu.Unlock}, nil
+}
+
+func (c *sqlPinnedConn) ExecContext(ctx context.Context, query string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, err := c.conn.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute statement: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (c *sqlPinnedConn) Release(discard bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if discard {
+		// Raw 返回 driver.ErrBadConn 时 database/sql 会关闭底层连接，而不是放回连接池
+		err := c.conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		if errors.Is(err, driver.ErrBadConn) {
+			return nil
+		}
+		return err
+	}
+	return c.conn.Close()
+}
+
+// lockedRowIterator 关闭时释放事务锁的行迭代器
+type lockedRowIterator struct {
+	RowIterator
+	unlock func()
+	once   sync.Once
+}
+
+func (it *lockedRowIterator) Close() error {
+	err := it.RowIterator.Close()
+	it.once.Do(it.unlock)
+	return err
+}
+
+// WithTransaction 返回在事务中执行语句的数据库包装
+// 查询和增删改语句在事务连接上执行，其余方法（表结构、表数据等）仍使用原始连接
+func WithTransaction(db Database, tx Transaction) ContextDatabase {
+	return &txDatabase{ContextDatabase: AsContextDatabase(db), tx: tx}
+}
+
+// WithPinnedConn 返回在独占连接上执行语句的数据库包装
+// 查询和增删改语句在该连接上执行，其余方法仍使用原始连接
+func WithPinnedConn(db Database, conn PinnedConn) ContextDatabase {
+	return &txDatabase{ContextDatabase: AsContextDatabase(db), tx: conn}
+}
+
+// txDatabase 将语句执行转发到事务或独占连接的数据库包装
+type txDatabase struct {
+	ContextDatabase
+	tx statementRunner
+}
+
+func (d *txDatabase) ExecuteQuery(query string) ([]map[string]interface{}, error) {
+	return d.tx.ExecuteQueryContext(context.Background(), query)
+}
+
+func (d *txDatabase) ExecuteUpdate(query string) (int64, error) {
+	return d.tx.ExecContext(context.Background(), query)
+}
+
+func (d *txDatabase) ExecuteDelete(query string) (int64, error) {
+	return d.tx.ExecContext(context.Background(), query)
+}
+
+func (d *txDatabase) ExecuteInsert(query string) (int64, error) {
+	return d.tx.ExecContext(context.Background(), query)
+}
+
+func (d *txDatabase) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	return d.tx.ExecuteQueryContext(ctx, query)
+}
+
+func (d *txDatabase) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	return d.tx.ExecContext(ctx, query)
+}
+
+func (d *txDatabase) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	return d.tx.ExecContext(ctx, query)
+}
+
+func (d *txDatabase) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	return d.tx.ExecContext(ctx, query)
+}
+
+func (d *txDatabase) QueryRows(ctx context.Context, query string) (RowIterator, error) {
+	return d.tx.QueryRows(ctx, query)
+}
+
+func (d *txDatabase) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	rm, ok := d.tx.(RowMutationDatabase)
+	if !ok {
+		return 0, fmt.Errorf("row mutation is not supported in this transaction")
+	}
+	return rm.UpdateRows(ctx, tableName, keys, values)
+}
+
+func (d *txDatabase) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	rm, ok := d.tx.(RowMutationDatabase)
+	if !ok {
+		return 0, fmt.Errorf("row mutation is not supported in this transaction")
+	}
+	return rm.DeleteRows(ctx, tableName, keys)
+}
+
+func (d *txDatabase) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	rs, ok := d.tx.(RowSnapshotDatabase)
+	if !ok {
+		return nil, fmt.Errorf("row snapshot is not supported in this transaction")
+	}
+	return rs.SnapshotRows(ctx, tableName, keys)
+}
+
+func (d *txDatabase) InsertRow(ctx context.Context, tableName string, columns []ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	ri, ok := d.tx.(RowInsertDatabase)
+	if !ok {
+		return nil, fmt.Errorf("row insert is not supported in this transaction")
+	}
+	return ri.InsertRow(ctx, tableName, columns, values)
+}
+
+func (d *txDatabase) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	bi, ok := d.tx.(BulkInsertDatabase)
+	if !ok {
+		return 0, fmt.Errorf("bulk insert is not supported in this transaction")
+	}
+	return bi.InsertRows(ctx, tableName, columns, rows, keys)
+}
+
+func (d *txDatabase) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	pd, ok := d.tx.(ParameterizedDatabase)
+	if !ok {
+		return nil, fmt.Errorf("parameterized statements are not supported in this transaction")
+	}
+	return pd.QueryArgsContext(ctx, query, args...)
+}
+
+func (d *txDatabase) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	pd, ok := d.tx.(ParameterizedDatabase)
+	if !ok {
+		return 0, fmt.Errorf("parameterized statements are not supported in this transaction")
+	}
+	return pd.ExecArgsContext(ctx, query, args...)
+}
+
+func (d *txDatabase) TruncateTable(ctx context.Context, tableName string) error {
+	tt, ok := d.tx.(TableTruncateDatabase)
+	if !ok {
+		return fmt.Errorf("truncate is not supported in this transaction")
+	}
+	return tt.TruncateTable(ctx, tableName)
+}
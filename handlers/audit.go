@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// maxAuditStatement 审计日志中保存的语句最大长度（字节），超出部分截断
+const maxAuditStatement = 64 * 1024
+
+// 审计记录的操作来源
+const (
+	AuditSourceQuery       = "query"       // /api/query、/api/query/script 和 /api/query/saved/run 执行的语句
+	AuditSourceRow         = "row"         // /api/row/insert、/api/row/update、/api/row/delete、/api/row/undo
+	AuditSourceChangeset   = "changeset"   // /api/row/changeset 中的一项修改
+	AuditSourceRestore     = "restore"     // /api/database/restore 执行的语句
+	AuditSourceTableDesign = "tableDesign" // /api/table/design/apply 执行的语句
+	AuditSourceImport      = "import"      // /api/table/import 清空表和导入的行
+	AuditSourceCopy        = "copy"        // /api/copy/start 在目标连接上建表、清空表和写入的行
+)
+
+// AuditEntry 一条数据修改操作（INSERT/UPDATE/DELETE/DDL 等）的审计记录
+type AuditEntry struct {
+	ID             int64         `json:"id"`
+	User           string        `json:"user,omitempty"` // 执行操作的用户（见 SetUserResolver），未设置时为空
+	ConnectionName string        `json:"connectionName"`
+	DbType         string        `json:"dbType"`
+	Database       string        `json:"database"`
+	Table          string        `json:"table,omitempty"` // 目标表，语句无法识别时为空
+	Operation      string        `json:"operation"`       // 语句关键字，如 UPDATE、DELETE、CREATE
+	Source         string        `json:"source"`          // 操作来源，见 AuditSource* 常量
+	Statement      string        `json:"statement"`
+	Args           []interface{} `json:"args,omitempty"` // 参数化语句的参数
+	AffectedRows   int64         `json:"affectedRows"`
+	Success        bool          `json:"success"`
+	Error          string        `json:"error,omitempty"`
+	ExecutedAt     time.Time     `json:"executedAt"`
+}
+
+// AuditFilter 审计日志的搜索条件，字段为空时不限制
+type AuditFilter struct {
+	User           string // 用户（精确匹配）
+	ConnectionName string // 连接名称（精确匹配）
+	Table          string // 表名（精确匹配）
+	Operation      string // 语句关键字（不区分大小写）
+	Search         string // 语句包含的文本（不区分大小写）
+	Status         string // success 或 error
+	Offset         int
+	Limit          int
+}
+
+// Match 判断记录是否符合搜索条件（不考虑分页）
+func (f AuditFilter) Match(entry AuditEntry) bool {
+	if f.User != "" && entry.User != f.User {
+		return false
+	}
+	if f.ConnectionName != "" && entry.ConnectionName != f.ConnectionName {
+		return false
+	}
+	if f.Table != "" && entry.Table != f.Table {
+		return false
+	}
+	if f.Operation != "" && !strings.EqualFold(entry.Operation, f.Operation) {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(entry.Statement), strings.ToLower(f.Search)) {
+		return false
+	}
+	switch f.Status {
+	case "success":
+		return entry.Success
+	case "error":
+		return !entry.Success
+	}
+	return true
+}
+
+// AuditSink 审计日志的写入接口
+// 允许外部项目把审计记录写入自己的存储；写入失败只记录警告，不影响操作本身
+type AuditSink interface {
+	// Record 保存一条记录，ID 由实现分配
+	Record(entry AuditEntry) error
+}
+
+// AuditReader 可浏览的审计日志，AuditSink 实现该接口时才能通过 GET /api/audit 查看
+type AuditReader interface {
+	// Search 按执行时间倒序返回符合条件的一页记录，以及符合条件的总数
+	Search(filter AuditFilter) ([]AuditEntry, int, error)
+}
+
+// AdminResolver 判断请求的用户是否为管理员，用于限制审计日志的查看
+type AdminResolver func(r *http.Request) bool
+
+// FileAuditSink 以 JSON Lines 格式追加写入文件的审计日志（默认实现）
+type FileAuditSink struct {
+	path   string
+	file   *os.File
+	nextID int64
+	mutex  sync.Mutex
+}
+
+// NewFileAuditSink 打开（不存在时创建）审计日志文件，新记录追加到文件末尾
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	sink := &FileAuditSink{path: path, file: file}
+	// 记录 ID 接着已有记录编号
+	err = sink.scan(func(entry AuditEntry) {
+		if entry.ID > sink.nextID {
+			sink.nextID = entry.ID
+		}
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// Record 追加一条记录
+func (f *FileAuditSink) Record(entry AuditEntry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.nextID++
+	entry.ID = f.nextID
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if _, err := f.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Search 读取整个文件，按执行时间倒序返回符合条件的一页记录
+func (f *FileAuditSink) Search(filter AuditFilter) ([]AuditEntry, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var matched []AuditEntry
+	err := f.scan(func(entry AuditEntry) {
+		if filter.Match(entry) {
+			matched = append(matched, entry)
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(matched)
+	entries := make([]AuditEntry, 0)
+	for i := total - 1 - filter.Offset; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) >= filter.Limit {
+			break
+		}
+		entries = append(entries, matched[i])
+	}
+	return entries, total, nil
+}
+
+// scan 按写入顺序读取所有记录，跳过无法解析的行
+func (f *FileAuditSink) scan(fn func(entry AuditEntry)) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*maxAuditStatement)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		fn(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭审计日志文件
+func (f *FileAuditSink) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.file.Close()
+}
+
+// SetAuditSink 设置审计日志，传入 nil 时不记录（默认）
+// 示例：
+//
+//	sink, err := handlers.NewFileAuditSink("audit.log")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	server.SetAuditSink(sink)
+func (s *Server) SetAuditSink(sink AuditSink) {
+	s.auditSinkMutex.Lock()
+	defer s.auditSinkMutex.Unlock()
+	s.auditSink = sink
+}
+
+// getAuditSink 获取审计日志（线程安全），未启用时返回 nil
+func (s *Server) getAuditSink() AuditSink {
+	s.auditSinkMutex.RLock()
+	defer s.auditSinkMutex.RUnlock()
+	return s.auditSink
+}
+
+// SetAdminResolver 设置判断请求用户是否为管理员的函数
+// 只有管理员可以查看审计日志；未设置时任何请求都不能查看
+func (s *Server) SetAdminResolver(resolver AdminResolver) {
+	s.adminResolverMutex.Lock()
+	defer s.adminResolverMutex.Unlock()
+	s.adminResolver = resolver
+}
+
+// requestIsAdmin 判断发起请求的用户是否为管理员（线程安全）
+func (s *Server) requestIsAdmin(r *http.Request) bool {
+	s.adminResolverMutex.RLock()
+	resolver := s.adminResolver
+	s.adminResolverMutex.RUnlock()
+	return resolver != nil && resolver(r)
+}
+
+// isAuditedStatement 判断语句是否需要写入审计日志
+// SQL 数据库记录查询以外的所有语句（包括 PL/SQL 语句块、DO、存储过程调用等可能修改数据的语句）；
+// Redis 和 Elasticsearch 的命令不是 SQL，只记录能识别为修改数据或结构的命令
+func isAuditedStatement(dbType string, stmt database.StatementInfo) bool {
+	switch dbType {
+	case "redis", "elasticsearch":
+		return stmt.Kind == database.StatementWrite || stmt.Kind == database.StatementDDL
+	}
+	return stmt.Kind != database.StatementRead
+}
+
+// recordAudit 补充发起请求的用户后写入审计日志，未设置 AuditSink 时忽略
+func (s *Server) recordAudit(r *http.Request, session *ConnectionSession, entry AuditEntry, execErr error) {
+	if s.getAuditSink() == nil {
+		return
+	}
+	entry.User = s.requestUser(r)
+	s.recordAuditEntry(r.Context(), session, entry, execErr)
+}
+
+// recordAuditEntry 补充连接和结果后写入审计日志，entry.User 由调用方设置
+// 用于请求结束后仍在执行的后台任务（如跨连接复制表）
+func (s *Server) recordAuditEntry(ctx context.Context, session *ConnectionSession, entry AuditEntry, execErr error) {
+	sink := s.getAuditSink()
+	if sink == nil {
+		return
+	}
+	entry.ConnectionName = connectionDisplayName(session)
+	entry.DbType = session.dbType
+	entry.Database = session.currentDatabase
+	entry.Statement = truncateText(entry.Statement, maxAuditStatement)
+	entry.Success = execErr == nil
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+	if entry.ExecutedAt.IsZero() {
+		entry.ExecutedAt = time.Now()
+	}
+	if err := sink.Record(entry); err != nil {
+		s.getLogger().Warn(ctx, "Failed to record audit entry: %v", err)
+	}
+}
+
+// auditStatement 记录一条语句的执行，查询语句不记录（见 isAuditedStatement）；args 为参数化语句的参数
+func (s *Server) auditStatement(r *http.Request, session *ConnectionSession, source string, stmt database.StatementInfo, query string, args []interface{}, started time.Time, affected int64, execErr error) {
+	if !isAuditedStatement(session.dbType, stmt) {
+		return
+	}
+	s.recordAudit(r, session, AuditEntry{
+		Table:        database.StatementTable(session.dbType, query),
+		Operation:    stmt.Keyword,
+		Source:       source,
+		Statement:    query,
+		Args:         args,
+		AffectedRows: affected,
+		ExecutedAt:   started,
+	}, execErr)
+}
+
+// auditRowChange 记录一次按键修改行的操作，语句使用 DescribeRowChange 生成的描述
+func (s *Server) auditRowChange(r *http.Request, session *ConnectionSession, source, table, op string, keys, values map[string]interface{}, affected int64, execErr error) {
+	if s.getAuditSink() == nil {
+		return
+	}
+	statement, args, err := database.DescribeRowChange(session.dbType, table, op, keys, values)
+	if err != nil {
+		statement = op
+	}
+	s.recordAudit(r, session, AuditEntry{
+		Table:        table,
+		Operation:    strings.ToUpper(op),
+		Source:       source,
+		Statement:    statement,
+		Args:         args,
+		AffectedRows: affected,
+	}, execErr)
+}
+
+// GetAuditLog 分页浏览审计日志（仅管理员）
+// 查询参数：user、connection、table、operation、search（语句包含的文本）、status（success/error）、page、pageSize
+func (s *Server) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !s.requestIsAdmin(r) {
+		writeJSONError(w, http.StatusForbidden, ErrCodeAuditForbidden)
+		return
+	}
+	reader, ok := s.getAuditSink().(AuditReader)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeAuditDisabled)
+		return
+	}
+
+	params := r.URL.Query()
+	page, _ := strconv.Atoi(params.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(params.Get("pageSize"))
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	entries, total, err := reader.Search(AuditFilter{
+		User:           strings.TrimSpace(params.Get("user")),
+		ConnectionName: strings.TrimSpace(params.Get("connection")),
+		Table:          strings.TrimSpace(params.Get("table")),
+		Operation:      strings.TrimSpace(params.Get("operation")),
+		Search:         strings.TrimSpace(params.Get("search")),
+		Status:         params.Get("status"),
+		Offset:         (page - 1) * pageSize,
+		Limit:          pageSize,
+	})
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeAuditFailed, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"entries":  entries,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// 变更集中每项修改的状态
+const (
+	changeStatusPending    = "pending"    // 预览，未执行
+	changeStatusApplied    = "applied"    // 已执行
+	changeStatusFailed     = "failed"     // 校验或执行失败
+	changeStatusRolledBack = "rolledBack" // 执行成功，但因其他修改失败被回滚
+	changeStatusSkipped    = "skipped"    // 因其他修改失败未执行
+)
+
+// rowChange 变更集中的一项修改
+type rowChange struct {
+	Op    string                 `json:"op"`    // insert、update、delete
+	Data  map[string]interface{} `json:"data"`  // insert、update 的列和值
+	Where map[string]interface{} `json:"where"` // update、delete 定位行的键
+}
+
+// ApplyChangeset 在一张表上批量执行新增、修改和删除
+// preview 为 true 时只校验并返回每项修改生成的语句，不执行。
+// 会话中有打开的事务时在该事务中执行（由用户提交或回滚），遇到失败即停止；
+// 否则驱动支持事务时在新事务中执行，任一修改失败则全部回滚；不支持事务的数据库逐项执行，失败的修改不影响其他修改。
+// 返回每项修改的状态，失败的修改可以在修正后单独重试
+func (s *Server) ApplyChangeset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	var req struct {
+		Table   string      `json:"table"`
+		Changes []rowChange `json:"changes"`
+		Preview bool        `json:"preview"`
+	}
+	if err := decodeJSONWithNumbers(r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
+		return
+	}
+	if len(req.Changes) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptyChangeset)
+		return
+	}
+
+	columns, err := session.db.GetTableColumns(req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+
+	// 执行前校验所有修改，并生成预览语句
+	results := make([]map[string]interface{}, len(req.Changes))
+	invalid := 0
+	for i := range req.Changes {
+		change := &req.Changes[i]
+		change.Data = normalizeJSONNumbers(change.Data)
+		change.Where = normalizeJSONNumbers(change.Where)
+		result := map[string]interface{}{"index": i, "op": change.Op, "status": changeStatusPending}
+		results[i] = result
+
+		if errCode, param := checkRowChange(session.dbType, columns, change); errCode != "" {
+			result["status"] = changeStatusFailed
+			result["errorCode"] = errCode
+			if param != "" {
+				result["error"] = param
+			}
+			invalid++
+			continue
+		}
+		statement, args, err := database.DescribeRowChange(session.dbType, req.Table, change.Op, change.Where, change.Data)
+		if err != nil {
+			result["status"] = changeStatusFailed
+			result["errorCode"] = ErrCodeInvalidRowChange
+			result["error"] = err.Error()
+			invalid++
+			continue
+		}
+		result["statement"] = statement
+		if len(args) > 0 {
+			result["args"] = args
+		}
+	}
+
+	if req.Preview || invalid > 0 {
+		if invalid > 0 {
+			markChanges(results, changeStatusPending, changeStatusSkipped)
+		}
+		writeChangesetResponse(w, results, false, false)
+		return
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+
+	switch {
+	case session.hasTransaction():
+		// 在会话的事务中执行，失败后停止，已执行的修改由用户决定提交或回滚
+		db, done := s.sessionDB(session, true)
+		runChanges(ctx, db, req.Table, columns, req.Changes, results, true)
+		done()
+		s.auditChangeset(r, session, req.Table, req.Changes, results)
+		writeChangesetResponse(w, results, true, false)
+	case supportsChangesetTransaction(session):
+		tx, err := session.db.(database.TransactionalDatabase).BeginTx(ctx)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+			return
+		}
+		db := database.WithTransaction(session.db, tx)
+		if runChanges(ctx, db, req.Table, columns, req.Changes, results, true) {
+			if err := tx.Commit(); err != nil {
+				markChanges(results, changeStatusApplied, changeStatusRolledBack)
+				s.auditChangeset(r, session, req.Table, req.Changes, results)
+				writeJSONError(w, http.StatusInternalServerError, ErrCodeCommitTransactionFailed, err)
+				return
+			}
+			s.auditChangeset(r, session, req.Table, req.Changes, results)
+			writeChangesetResponse(w, results, true, true)
+			return
+		}
+		if err := tx.Rollback(); err != nil {
+			s.getLogger().Warn(ctx, "Failed to roll back changeset: %v", err)
+		}
+		markChanges(results, changeStatusApplied, changeStatusRolledBack)
+		s.auditChangeset(r, session, req.Table, req.Changes, results)
+		writeChangesetResponse(w, results, true, false)
+	default:
+		runChanges(ctx, database.AsContextDatabase(session.db), req.Table, columns, req.Changes, results, false)
+		s.auditChangeset(r, session, req.Table, req.Changes, results)
+		writeChangesetResponse(w, results, false, true)
+	}
+}
+
+// checkRowChange 校验一项修改，返回错误代码和附加参数
+func checkRowChange(dbType string, columns []database.ColumnInfo, change *rowChange) (string, string) {
+	switch change.Op {
+	case "insert":
+		if change.Data == nil {
+			change.Data = map[string]interface{}{}
+		}
+		return checkInsertValues(dbType, columns, change.Data)
+	case "update":
+		if dbType == "clickhouse" {
+			return ErrCodeClickHouseNoUpdate, ""
+		}
+		if len(change.Where) == 0 {
+			return ErrCodeRowKeyRequired, ""
+		}
+		if len(change.Data) == 0 {
+			return ErrCodeEmptyRowChange, ""
+		}
+	case "delete":
+		if dbType == "clickhouse" {
+			return ErrCodeClickHouseNoDelete, ""
+		}
+		if len(change.Where) == 0 {
+			return ErrCodeRowKeyRequired, ""
+		}
+	default:
+		return ErrCodeInvalidRowChange, change.Op
+	}
+	return "", ""
+}
+
+// supportsChangesetTransaction 判断是否可以在新事务中原子地执行变更集
+// 代理包装总是实现 TransactionalDatabase，需要按被代理的数据库类型判断
+func supportsChangesetTransaction(session *ConnectionSession) bool {
+	switch session.dbType {
+	case "mongodb", "elasticsearch", "redis", "clickhouse":
+		return false
+	}
+	_, ok := session.db.(database.TransactionalDatabase)
+	return ok
+}
+
+// runChanges 按顺序执行修改，stopOnError 为 true 时遇到失败即停止，其余修改标记为 skipped
+// 返回是否全部执行成功
+func runChanges(ctx context.Context, db database.ContextDatabase, table string, columns []database.ColumnInfo, changes []rowChange, results []map[string]interface{}, stopOnError bool) bool {
+	ok := true
+	for i, change := range changes {
+		if !ok && stopOnError {
+			results[i]["status"] = changeStatusSkipped
+			continue
+		}
+
+		var err error
+		var errCode string
+		switch change.Op {
+		case "insert":
+			var key map[string]interface{}
+			key, err = insertRow(ctx, db, table, columns, change.Data)
+			errCode = ErrCodeInsertFailed
+			if err == nil {
+				results[i]["key"] = key
+			}
+		case "update":
+			var affected int64
+			affected, err = updateRows(ctx, db, table, change.Where, change.Data)
+			errCode = ErrCodeUpdateFailed
+			results[i]["affected"] = affected
+		case "delete":
+			var affected int64
+			affected, err = deleteRows(ctx, db, table, change.Where)
+			errCode = ErrCodeDeleteFailed
+			results[i]["affected"] = affected
+		}
+
+		if err != nil {
+			ok = false
+			results[i]["status"] = changeStatusFailed
+			setScriptError(ctx, results[i], errCode, err)
+			continue
+		}
+		results[i]["status"] = changeStatusApplied
+	}
+	return ok
+}
+
+// errChangeRolledBack 执行成功但随变更集回滚的修改在审计日志中的错误
+var errChangeRolledBack = errors.New("rolled back with the changeset")
+
+// auditChangeset 把执行过的修改写入审计日志，被回滚的修改记录为失败
+func (s *Server) auditChangeset(r *http.Request, session *ConnectionSession, table string, changes []rowChange, results []map[string]interface{}) {
+	for i, change := range changes {
+		var execErr error
+		switch results[i]["status"] {
+		case changeStatusApplied:
+		case changeStatusFailed:
+			execErr = fmt.Errorf("%v", results[i]["error"])
+		case changeStatusRolledBack:
+			execErr = errChangeRolledBack
+		default:
+			continue
+		}
+		affected, _ := results[i]["affected"].(int64)
+		if change.Op == "insert" && execErr == nil {
+			affected = 1
+		}
+		s.auditRowChange(r, session, AuditSourceChangeset, table, change.Op, change.Where, change.Data, affected, execErr)
+	}
+}
+
+// markChanges 将状态为 from 的修改改为 to
+func markChanges(results []map[string]interface{}, from, to string) {
+	for _, result := range results {
+		if result["status"] == from {
+			result["status"] = to
+		}
+	}
+}
+
+// writeChangesetResponse 返回变更集的执行结果
+// atomic 表示修改是否在事务中执行，committed 表示修改是否已经生效（会话事务中执行的修改需要用户提交）
+func writeChangesetResponse(w http.ResponseWriter, results []map[string]interface{}, atomic, committed bool) {
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result["status"].(string)]++
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":   true,
+		"results":   results,
+		"total":     len(results),
+		"applied":   counts[changeStatusApplied],
+		"failed":    counts[changeStatusFailed],
+		"atomic":    atomic,
+		"committed": committed,
+	})
+}
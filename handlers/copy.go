@@ -0,0 +1,545 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// copyJobRetention 已结束的复制任务保留的时间，之后在创建新任务时清理
+const copyJobRetention = time.Hour
+
+// 复制任务的状态
+const (
+	copyStateRunning   = "running"
+	copyStateCompleted = "completed"
+	copyStateFailed    = "failed"
+	copyStateCancelled = "cancelled"
+)
+
+// copyJobStatus 复制任务的状态信息（即 /api/copy/status 返回的内容）
+type copyJobStatus struct {
+	ID                 string     `json:"id"`
+	SourceConnectionID string     `json:"sourceConnectionId"`
+	TargetConnectionID string     `json:"targetConnectionId"`
+	SourceType         string     `json:"sourceType"`
+	TargetType         string     `json:"targetType"`
+	SourceTable        string     `json:"sourceTable"`
+	TargetTable        string     `json:"targetTable"`
+	Columns            []string   `json:"columns"` // 复制的列（目标表中的列名）
+	Created            bool       `json:"created"` // 是否创建了目标表
+	State              string     `json:"state"`   // running、completed、failed、cancelled
+	Total              int64      `json:"total"`   // 源表中满足过滤条件的行数，-1 表示未知
+	Copied             int64      `json:"copied"`  // 已写入目标表的行数
+	ErrorCode          string     `json:"errorCode,omitempty"`
+	Error              string     `json:"error,omitempty"`
+	StartedAt          time.Time  `json:"startedAt"`
+	FinishedAt         *time.Time `json:"finishedAt,omitempty"`
+}
+
+// copyJob 跨连接复制表数据的后台任务
+type copyJob struct {
+	mu     sync.Mutex
+	status copyJobStatus
+	cancel context.CancelFunc
+
+	source        database.Database
+	target        database.ContextDatabase
+	targetSession *ConnectionSession // 用于审计日志
+	user          string             // 发起复制的用户，用于审计日志
+	sourceName    string             // 源连接名称，用于审计日志
+	filters       *database.FilterGroup
+	truncate      bool
+	truncateQuery string                // 清空目标表的语句，用于审计日志
+	targetColumns []database.ColumnInfo // 与 sourceNames 一一对应
+	sourceNames   []string
+}
+
+// snapshot 返回任务状态的副本
+func (j *copyJob) snapshot() copyJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// update 在锁内修改任务状态
+func (j *copyJob) update(fn func(status *copyJobStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.status)
+}
+
+// finish 结束任务，err 为空时任务完成；上下文被取消时任务为已取消
+func (j *copyJob) finish(ctx context.Context, errCode string, err error) {
+	j.update(func(status *copyJobStatus) {
+		now := time.Now()
+		status.FinishedAt = &now
+		switch {
+		case ctx.Err() != nil:
+			status.State = copyStateCancelled
+		case err != nil:
+			status.State = copyStateFailed
+			status.ErrorCode = errCode
+			status.Error = err.Error()
+		default:
+			status.State = copyStateCompleted
+		}
+	})
+}
+
+// StartCopyTable 将当前连接（X-Connection-ID）中的表复制到另一个连接，在后台执行并立即返回任务状态
+// 请求体：{"targetConnectionId": "...", "table": "users", "targetTable": "users", "createTable": true, "truncate": false, "filters": {...}}
+// targetTable 为空时与源表同名；目标表不存在且 createTable 不为 false 时按源表的列信息建表（列类型转换为目标数据库的类型），
+// 目标表已存在时按列名（不区分大小写）复制两表共有的列。truncate 为 true 时复制前清空目标表，filters 只复制满足条件的行。
+// 复制直接使用两个连接，不在会话事务中执行；进度通过 GetCopyStatus 查询
+func (s *Server) StartCopyTable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		TargetConnectionID string                `json:"targetConnectionId"`
+		Table              string                `json:"table"`
+		TargetTable        string                `json:"targetTable"`
+		CreateTable        *bool                 `json:"createTable"`
+		Truncate           bool                  `json:"truncate"`
+		Filters            *database.FilterGroup `json:"filters"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
+		return
+	}
+	if req.TargetConnectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingTargetConnection)
+		return
+	}
+	if req.TargetTable == "" {
+		req.TargetTable = req.Table
+	}
+	createAllowed := req.CreateTable == nil || *req.CreateTable
+
+	source, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	target, err := s.getSession(req.TargetConnectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTargetConnectionNotExists, err)
+		return
+	}
+	if req.TargetConnectionID == connectionID && req.TargetTable == req.Table {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeCopyTableFailed, "source and target are the same table")
+		return
+	}
+
+	sourceColumns, err := source.db.GetTableColumns(req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+
+	job := &copyJob{
+		source:        source.db,
+		target:        database.AsContextDatabase(target.db),
+		targetSession: target,
+		user:          s.requestUser(r),
+		sourceName:    connectionDisplayName(source),
+		filters:       req.Filters,
+		truncate:      req.Truncate,
+		status: copyJobStatus{
+			SourceConnectionID: connectionID,
+			TargetConnectionID: req.TargetConnectionID,
+			SourceType:         source.dbType,
+			TargetType:         target.dbType,
+			SourceTable:        req.Table,
+			TargetTable:        req.TargetTable,
+			State:              copyStateRunning,
+			Total:              -1,
+			StartedAt:          time.Now(),
+		},
+	}
+
+	targetTables, err := target.db.GetTables()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTablesFailed, err)
+		return
+	}
+	targetTable, exists := findTable(targetTables, req.TargetTable)
+	switch {
+	case exists:
+		job.status.TargetTable = targetTable
+		targetColumns, err := target.db.GetTableColumns(targetTable)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+			return
+		}
+		for _, col := range sourceColumns {
+			if match, ok := findColumn(targetColumns, col.Name); ok {
+				job.sourceNames = append(job.sourceNames, col.Name)
+				job.targetColumns = append(job.targetColumns, match)
+			}
+		}
+		if len(job.targetColumns) == 0 {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeNoCommonColumns, targetTable)
+			return
+		}
+	case isSchemalessDatabase(target.dbType):
+		// 文档数据库和键值数据库插入时自动创建集合，按源表的列写入
+		for _, col := range sourceColumns {
+			job.sourceNames = append(job.sourceNames, col.Name)
+			job.targetColumns = append(job.targetColumns, database.ColumnInfo{Name: col.Name})
+		}
+	case !createAllowed:
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTargetTableNotExists, req.TargetTable)
+		return
+	case !database.SupportsCreateTable(target.dbType):
+		writeJSONError(w, http.StatusBadRequest, ErrCodeCreateTableNotSupported, target.dbType)
+		return
+	default:
+		columns, err := database.TranslateColumns(source.dbType, target.dbType, sourceColumns)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeCreateTableFailed, err)
+			return
+		}
+		query, err := database.BuildCreateTableSQL(target.dbType, req.TargetTable, columns)
+		if err == nil {
+			err = s.validateSQL(query, "CREATE")
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+			return
+		}
+		started := time.Now()
+		err = createTable(r.Context(), job.target, target.dbType, req.TargetTable, columns)
+		s.auditStatement(r, target, AuditSourceCopy, database.ClassifySQL(target.dbType, query), query, nil, started, 0, err)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(r.Context(), err, ErrCodeCreateTableFailed), err)
+			return
+		}
+		job.status.Created = true
+		job.targetColumns = columns
+		for _, col := range sourceColumns {
+			job.sourceNames = append(job.sourceNames, col.Name)
+		}
+	}
+
+	if req.Truncate && !job.status.Created {
+		query, err := database.BuildTruncateTableSQL(target.dbType, job.status.TargetTable, false)
+		if err == nil {
+			err = s.validateSQL(query, strings.Fields(query)[0])
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+			return
+		}
+		job.truncateQuery = query
+	}
+
+	for _, col := range job.targetColumns {
+		job.status.Columns = append(job.status.Columns, col.Name)
+	}
+	job.status.ID, err = generateConnectionID()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGenerateConnectionIDFailed, err)
+		return
+	}
+
+	// 任务不受请求的生命周期和语句超时限制，只能通过 CancelCopyTable 取消
+	ctx, cancel := context.WithCancel(context.Background())
+	job.cancel = cancel
+	s.copyJobsMutex.Lock()
+	s.pruneCopyJobs()
+	s.copyJobs[job.status.ID] = job
+	s.copyJobsMutex.Unlock()
+
+	go s.runCopyJob(ctx, job)
+
+	s.getLogger().Info(r.Context(), "Copy job %s started: %s.%s -> %s.%s", job.status.ID, source.dbType, req.Table, target.dbType, job.status.TargetTable)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"job":     job.snapshot(),
+	})
+}
+
+// runCopyJob 执行复制任务：统计行数、按需清空目标表，然后流式读取源表并按批写入目标表
+func (s *Server) runCopyJob(ctx context.Context, job *copyJob) {
+	defer job.cancel()
+	status := job.snapshot()
+	sourceTable, targetTable := status.SourceTable, status.TargetTable
+
+	if _, total, err := database.AsContextDatabase(job.source).GetTableDataContext(ctx, sourceTable, 1, 1, job.filters); err == nil {
+		job.update(func(status *copyJobStatus) { status.Total = total })
+	}
+
+	if job.truncate && !status.Created {
+		err := truncateTable(ctx, job.target, targetTable)
+		s.recordAuditEntry(ctx, job.targetSession, AuditEntry{
+			User:      job.user,
+			Table:     targetTable,
+			Operation: database.ClassifySQL(job.targetSession.dbType, job.truncateQuery).Keyword,
+			Source:    AuditSourceCopy,
+			Statement: job.truncateQuery,
+		}, err)
+		if err != nil {
+			job.finish(ctx, queryErrorCode(ctx, err, ErrCodeCopyTableFailed), err)
+			return
+		}
+	}
+	// 写入的行在任务结束时记录为一条审计记录
+	defer s.auditCopyJob(ctx, job)
+
+	iter, err := database.StreamTableRows(ctx, job.source, sourceTable, job.sourceNames, job.filters, nil)
+	if err != nil {
+		job.finish(ctx, queryErrorCode(ctx, err, ErrCodeCopyTableFailed), err)
+		return
+	}
+	defer iter.Close()
+
+	// 迭代器的列顺序可能与 sourceNames 不同，按列名定位
+	positions := make(map[string]int, len(iter.Columns()))
+	for i, name := range iter.Columns() {
+		positions[name] = i
+	}
+	names := make([]string, len(job.targetColumns))
+	for i, col := range job.targetColumns {
+		names[i] = col.Name
+	}
+
+	schemaless := isSchemalessDatabase(status.TargetType)
+	batchSize := database.ImportBatchSize(status.TargetType, len(names))
+	batch := make([][]interface{}, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := insertRows(ctx, job.target, targetTable, job.targetColumns, names, batch, nil)
+		job.update(func(status *copyJobStatus) { status.Copied += n })
+		batch = batch[:0]
+		return err
+	}
+
+	var row int64
+	for iter.Next() {
+		values := iter.Values()
+		row++
+		record := make([]interface{}, len(names))
+		for i, col := range job.targetColumns {
+			pos, ok := positions[job.sourceNames[i]]
+			if !ok {
+				continue
+			}
+			if schemaless {
+				record[i] = values[pos]
+				continue
+			}
+			value, err := database.ConvertCopyValue(status.TargetType, col, values[pos])
+			if err != nil {
+				job.finish(ctx, ErrCodeImportInvalidValue, fmt.Errorf("row %d column %s: %w", row, col.Name, err))
+				return
+			}
+			record[i] = value
+		}
+		batch = append(batch, record)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				job.finish(ctx, queryErrorCode(ctx, err, ErrCodeCopyTableFailed), err)
+				return
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		job.finish(ctx, queryErrorCode(ctx, err, ErrCodeCopyTableFailed), err)
+		return
+	}
+	if err := flush(); err != nil {
+		job.finish(ctx, queryErrorCode(ctx, err, ErrCodeCopyTableFailed), err)
+		return
+	}
+	job.finish(ctx, "", nil)
+
+	final := job.snapshot()
+	s.getLogger().Info(ctx, "Copy job %s %s: %d rows copied to %s", final.ID, final.State, final.Copied, targetTable)
+}
+
+// auditCopyJob 把复制任务写入目标表的行记录为一条审计记录，任务失败或取消时记录为失败
+func (s *Server) auditCopyJob(ctx context.Context, job *copyJob) {
+	status := job.snapshot()
+	var err error
+	switch {
+	case status.State == copyStateCancelled:
+		err = context.Canceled
+	case status.Error != "":
+		err = errors.New(status.Error)
+	}
+	s.recordAuditEntry(ctx, job.targetSession, AuditEntry{
+		User:         job.user,
+		Table:        status.TargetTable,
+		Operation:    "INSERT",
+		Source:       AuditSourceCopy,
+		Statement:    fmt.Sprintf("COPY %s.%s INTO %s", job.sourceName, status.SourceTable, status.TargetTable),
+		AffectedRows: status.Copied,
+		ExecutedAt:   status.StartedAt,
+	}, err)
+}
+
+// pruneCopyJobs 清理结束超过 copyJobRetention 的任务，调用方需持有 copyJobsMutex
+func (s *Server) pruneCopyJobs() {
+	for id, job := range s.copyJobs {
+		status := job.snapshot()
+		if status.FinishedAt != nil && time.Since(*status.FinishedAt) > copyJobRetention {
+			delete(s.copyJobs, id)
+		}
+	}
+}
+
+// getCopyJob 返回属于连接（源连接或目标连接）的复制任务
+func (s *Server) getCopyJob(connectionID, jobID string) (*copyJob, bool) {
+	s.copyJobsMutex.Lock()
+	defer s.copyJobsMutex.Unlock()
+	job, exists := s.copyJobs[jobID]
+	if !exists {
+		return nil, false
+	}
+	status := job.snapshot()
+	if status.SourceConnectionID != connectionID && status.TargetConnectionID != connectionID {
+		return nil, false
+	}
+	return job, true
+}
+
+// GetCopyStatus 查询复制任务的状态：指定 jobId 时返回 {"job": {...}}，
+// 否则返回 {"jobs": [...]}，包含当前连接作为源或目标的所有任务（按开始时间倒序）
+func (s *Server) GetCopyStatus(w http.ResponseWriter, r *http.Request) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	if jobID := r.URL.Query().Get("jobId"); jobID != "" {
+		job, exists := s.getCopyJob(connectionID, jobID)
+		if !exists {
+			writeJSONError(w, http.StatusNotFound, ErrCodeCopyJobNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"job":     job.snapshot(),
+		})
+		return
+	}
+
+	jobs := []copyJobStatus{}
+	s.copyJobsMutex.Lock()
+	for _, job := range s.copyJobs {
+		status := job.snapshot()
+		if status.SourceConnectionID == connectionID || status.TargetConnectionID == connectionID {
+			jobs = append(jobs, status)
+		}
+	}
+	s.copyJobsMutex.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"jobs":    jobs,
+	})
+}
+
+// CancelCopyTable 取消正在执行的复制任务，请求体：{"jobId": "..."}
+// 已写入目标表的行不会撤销
+func (s *Server) CancelCopyTable(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		JobID string `json:"jobId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	job, exists := s.getCopyJob(connectionID, req.JobID)
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, ErrCodeCopyJobNotFound)
+		return
+	}
+	job.cancel()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"job":     job.snapshot(),
+	})
+}
+
+// createTable 按列信息建表，驱动未实现 database.TableCreateDatabase 时执行 BuildCreateTableSQL 生成的语句
+func createTable(ctx context.Context, db database.ContextDatabase, dbType, table string, columns []database.ColumnInfo) error {
+	if tc, ok := db.(database.TableCreateDatabase); ok {
+		return tc.CreateTable(ctx, table, columns)
+	}
+	query, err := database.BuildCreateTableSQL(dbType, table, columns)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecuteUpdateContext(ctx, query)
+	return err
+}
+
+// findTable 按名称查找表，优先精确匹配，其次不区分大小写
+func findTable(tables []string, name string) (string, bool) {
+	for _, table := range tables {
+		if table == name {
+			return table, true
+		}
+	}
+	for _, table := range tables {
+		if strings.EqualFold(table, name) {
+			return table, true
+		}
+	}
+	return "", false
+}
+
+// findColumn 按名称查找列，优先精确匹配，其次不区分大小写
+func findColumn(columns []database.ColumnInfo, name string) (database.ColumnInfo, bool) {
+	for _, col := range columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	for _, col := range columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+	return database.ColumnInfo{}, false
+}
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// maxDataDiffRows 任务状态中保留的差异行数，完整的差异通过同步脚本获取
+const maxDataDiffRows = 200
+
+// dataDiffJobStatus 数据对比任务的状态信息（即 /api/datadiff/status 返回的内容）
+type dataDiffJobStatus struct {
+	ID                 string                 `json:"id"`
+	SourceConnectionID string                 `json:"sourceConnectionId"`
+	TargetConnectionID string                 `json:"targetConnectionId"`
+	SourceTable        string                 `json:"sourceTable"`
+	TargetTable        string                 `json:"targetTable"`
+	Key                string                 `json:"key"`
+	Columns            []string               `json:"columns"` // 参与比较的列（源表的列名）
+	State              string                 `json:"state"`   // running、completed、failed、cancelled，与复制任务相同
+	Stats              database.DataDiffStats `json:"stats"`
+	Rows               []database.DataDiffRow `json:"rows"`     // 前 maxDataDiffRows 行差异
+	MoreRows           bool                   `json:"moreRows"` // 差异超过 maxDataDiffRows 行
+	Script             bool                   `json:"script"`   // 是否生成了同步脚本（完成后通过 /api/datadiff/script 下载）
+	ErrorCode          string                 `json:"errorCode,omitempty"`
+	Error              string                 `json:"error,omitempty"`
+	StartedAt          time.Time              `json:"startedAt"`
+	FinishedAt         *time.Time             `json:"finishedAt,omitempty"`
+}
+
+// dataDiffJob 数据对比的后台任务
+type dataDiffJob struct {
+	mu         sync.Mutex
+	status     dataDiffJobStatus
+	cancel     context.CancelFunc
+	scriptPath string // 同步脚本的临时文件
+
+	source  database.DataDiffTable
+	target  database.DataDiffTable
+	filters *database.FilterGroup
+}
+
+func (j *dataDiffJob) snapshot() dataDiffJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := j.status
+	status.Rows = append([]database.DataDiffRow{}, j.status.Rows...)
+	return status
+}
+
+func (j *dataDiffJob) update(fn func(status *dataDiffJobStatus)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.status)
+}
+
+// finish 结束任务，规则与 copyJob.finish 相同
+func (j *dataDiffJob) finish(ctx context.Context, errCode string, err error) {
+	j.update(func(status *dataDiffJobStatus) {
+		now := time.Now()
+		status.FinishedAt = &now
+		switch {
+		case ctx.Err() != nil:
+			status.State = copyStateCancelled
+		case err != nil:
+			status.State = copyStateFailed
+			status.ErrorCode = errCode
+			status.Error = err.Error()
+		default:
+			status.State = copyStateCompleted
+		}
+	})
+}
+
+// StartDataDiff 按主键对比当前连接（X-Connection-ID）中的表与另一张表（可以在另一个连接中）的数据，在后台执行并立即返回任务状态
+// 请求体：{"targetConnectionId": "...", "table": "users", "targetTable": "users", "key": "id", "columns": [...], "filters": {...}, "script": true}
+// targetConnectionId 为空时与当前连接相同，targetTable 为空时与源表同名；key 为空时使用源表的单列主键；
+// columns 为空时比较两表共有的所有列（按列名不区分大小写匹配）。两侧都按主键分页读取，不会把整张表读入内存。
+// script 为 true 时把使目标表与源表一致的 INSERT/UPDATE/DELETE 语句写入临时文件，完成后通过 DownloadDataDiffScript 下载
+func (s *Server) StartDataDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		TargetConnectionID string                `json:"targetConnectionId"`
+		Table              string                `json:"table"`
+		TargetTable        string                `json:"targetTable"`
+		Key                string                `json:"key"`
+		Columns            []string              `json:"columns"`
+		Filters            *database.FilterGroup `json:"filters"`
+		Script             bool                  `json:"script"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
+		return
+	}
+	if req.TargetConnectionID == "" {
+		req.TargetConnectionID = connectionID
+	}
+	if req.TargetTable == "" {
+		req.TargetTable = req.Table
+	}
+
+	source, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	target, err := s.getSession(req.TargetConnectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTargetConnectionNotExists, err)
+		return
+	}
+	if req.TargetConnectionID == connectionID && req.TargetTable == req.Table {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeDataDiffFailed, "source and target are the same table")
+		return
+	}
+
+	sourceColumns, err := source.db.GetTableColumns(req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+	targetTables, err := target.db.GetTables()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTablesFailed, err)
+		return
+	}
+	targetTable, exists := findTable(targetTables, req.TargetTable)
+	if !exists {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTargetTableNotExists, req.TargetTable)
+		return
+	}
+	targetColumns, err := target.db.GetTableColumns(targetTable)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+
+	// 主键：未指定时使用源表的单列主键
+	key := req.Key
+	if key == "" {
+		var keys []string
+		for _, col := range sourceColumns {
+			if col.Key == "PRI" {
+				keys = append(keys, col.Name)
+			}
+		}
+		if len(keys) != 1 {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeDataDiffKeyRequired)
+			return
+		}
+		key = keys[0]
+	}
+	sourceKey, ok := findColumn(sourceColumns, key)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownColumn, key)
+		return
+	}
+	targetKey, ok := findColumn(targetColumns, key)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownColumn, key)
+		return
+	}
+
+	job := &dataDiffJob{
+		source:  database.DataDiffTable{DB: database.AsContextDatabase(source.db), DBType: source.dbType, Table: req.Table, Key: sourceKey.Name},
+		target:  database.DataDiffTable{DB: database.AsContextDatabase(target.db), DBType: target.dbType, Table: targetTable, Key: targetKey.Name},
+		filters: req.Filters,
+		status: dataDiffJobStatus{
+			SourceConnectionID: connectionID,
+			TargetConnectionID: req.TargetConnectionID,
+			SourceTable:        req.Table,
+			TargetTable:        targetTable,
+			Key:                sourceKey.Name,
+			State:              copyStateRunning,
+			Rows:               []database.DataDiffRow{},
+			Script:             req.Script,
+			StartedAt:          time.Now(),
+		},
+	}
+
+	// 比较的列：主键在前，其余按请求的顺序，未指定时为两表共有的所有列（按源表的列顺序）
+	job.source.Columns = []database.ColumnInfo{sourceKey}
+	job.target.Columns = []database.ColumnInfo{targetKey}
+	if len(req.Columns) == 0 {
+		for _, col := range sourceColumns {
+			if match, ok := findColumn(targetColumns, col.Name); ok && col.Name != sourceKey.Name {
+				job.source.Columns = append(job.source.Columns, col)
+				job.target.Columns = append(job.target.Columns, match)
+			}
+		}
+	}
+	for _, name := range req.Columns {
+		col, ok := findColumn(sourceColumns, name)
+		if !ok {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownColumn, name)
+			return
+		}
+		match, ok := findColumn(targetColumns, col.Name)
+		if !ok {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownColumn, name)
+			return
+		}
+		if col.Name != sourceKey.Name {
+			job.source.Columns = append(job.source.Columns, col)
+			job.target.Columns = append(job.target.Columns, match)
+		}
+	}
+	for _, col := range job.source.Columns {
+		job.status.Columns = append(job.status.Columns, col.Name)
+	}
+
+	job.status.ID, err = generateConnectionID()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGenerateConnectionIDFailed, err)
+		return
+	}
+
+	var script *os.File
+	if req.Script {
+		if script, err = os.CreateTemp("", "datadiff-*.sql"); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, ErrCodeDataDiffFailed, err)
+			return
+		}
+		job.scriptPath = script.Name()
+	}
+
+	// 任务不受请求的生命周期和语句超时限制，只能通过 CancelDataDiff 取消
+	ctx, cancel := context.WithCancel(context.Background())
+	job.cancel = cancel
+	s.dataDiffJobsMutex.Lock()
+	s.pruneDataDiffJobs()
+	s.dataDiffJobs[job.status.ID] = job
+	s.dataDiffJobsMutex.Unlock()
+
+	go s.runDataDiffJob(ctx, job, script)
+
+	s.getLogger().Info(r.Context(), "Data diff job %s started: %s.%s -> %s.%s", job.status.ID, source.dbType, req.Table, target.dbType, targetTable)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"job":     job.snapshot(),
+	})
+}
+
+// runDataDiffJob 执行数据对比，差异行保留在任务状态中，同步语句写入脚本文件
+func (s *Server) runDataDiffJob(ctx context.Context, job *dataDiffJob, script *os.File) {
+	defer job.cancel()
+
+	var buf *bufio.Writer
+	if script != nil {
+		defer script.Close()
+		buf = bufio.NewWriter(script)
+		fmt.Fprintf(buf, "-- SimpleDBWeb data sync script\n-- Source: %s (%s)\n-- Target: %s (%s)\n-- Generated at: %s\n\n",
+			job.source.Table, job.source.DBType, job.target.Table, job.target.DBType, time.Now().Format("2006-01-02 15:04:05"))
+	}
+
+	emit := func(row database.DataDiffRow) error {
+		job.update(func(status *dataDiffJobStatus) {
+			if len(status.Rows) < maxDataDiffRows {
+				status.Rows = append(status.Rows, row)
+			} else {
+				status.MoreRows = true
+			}
+		})
+		if buf == nil {
+			return nil
+		}
+		stmt, err := database.BuildDataSyncSQL(job.source, job.target, row)
+		if err != nil {
+			return err
+		}
+		_, err = buf.WriteString(stmt + ";\n")
+		return err
+	}
+	progress := func(stats database.DataDiffStats) {
+		job.update(func(status *dataDiffJobStatus) { status.Stats = stats })
+	}
+
+	stats, err := database.CompareTableData(ctx, job.source, job.target, job.filters, emit, progress)
+	job.update(func(status *dataDiffJobStatus) { status.Stats = stats })
+	if err == nil && buf != nil {
+		err = buf.Flush()
+	}
+	job.finish(ctx, queryErrorCode(ctx, err, ErrCodeDataDiffFailed), err)
+
+	final := job.snapshot()
+	s.getLogger().Info(ctx, "Data diff job %s %s: %d inserted, %d deleted, %d changed", final.ID, final.State,
+		stats.Inserted, stats.Deleted, stats.Changed)
+}
+
+// pruneDataDiffJobs 清理结束超过 copyJobRetention 的任务及其脚本文件，调用方需持有 dataDiffJobsMutex
+func (s *Server) pruneDataDiffJobs() {
+	for id, job := range s.dataDiffJobs {
+		status := job.snapshot()
+		if status.FinishedAt != nil && time.Since(*status.FinishedAt) > copyJobRetention {
+			if job.scriptPath != "" {
+				os.Remove(job.scriptPath)
+			}
+			delete(s.dataDiffJobs, id)
+		}
+	}
+}
+
+// getDataDiffJob 返回属于连接（源连接或目标连接）的数据对比任务
+func (s *Server) getDataDiffJob(connectionID, jobID string) (*dataDiffJob, bool) {
+	s.dataDiffJobsMutex.Lock()
+	defer s.dataDiffJobsMutex.Unlock()
+	job, exists := s.dataDiffJobs[jobID]
+	if !exists {
+		return nil, false
+	}
+	status := job.snapshot()
+	if status.SourceConnectionID != connectionID && status.TargetConnectionID != connectionID {
+		return nil, false
+	}
+	return job, true
+}
+
+// GetDataDiffStatus 查询数据对比任务的状态：指定 jobId 时返回 {"job": {...}}，
+// 否则返回 {"jobs": [...]}，包含当前连接作为源或目标的所有任务（按开始时间倒序，不含差异行）
+func (s *Server) GetDataDiffStatus(w http.ResponseWriter, r *http.Request) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	if jobID := r.URL.Query().Get("jobId"); jobID != "" {
+		job, exists := s.getDataDiffJob(connectionID, jobID)
+		if !exists {
+			writeJSONError(w, http.StatusNotFound, ErrCodeDataDiffJobNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"job":     job.snapshot(),
+		})
+		return
+	}
+
+	jobs := []dataDiffJobStatus{}
+	s.dataDiffJobsMutex.Lock()
+	for _, job := range s.dataDiffJobs {
+		status := job.snapshot()
+		if status.SourceConnectionID == connectionID || status.TargetConnectionID == connectionID {
+			status.Rows = nil
+			jobs = append(jobs, status)
+		}
+	}
+	s.dataDiffJobsMutex.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"jobs":    jobs,
+	})
+}
+
+// DownloadDataDiffScript 下载已完成的数据对比任务生成的同步脚本（jobId 查询参数）
+func (s *Server) DownloadDataDiffScript(w http.ResponseWriter, r *http.Request) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	job, exists := s.getDataDiffJob(connectionID, r.URL.Query().Get("jobId"))
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, ErrCodeDataDiffJobNotFound)
+		return
+	}
+	status := job.snapshot()
+	if job.scriptPath == "" || status.State != copyStateCompleted {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeDataDiffScriptNotReady)
+		return
+	}
+	file, err := os.Open(job.scriptPath)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeDataDiffFailed, err)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/sql; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s_sync_%s.sql", status.TargetTable, status.StartedAt.Format("20060102_150405")))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", *status.FinishedAt, file)
+}
+
+// CancelDataDiff 取消正在执行的数据对比任务，请求体：{"jobId": "..."}
+func (s *Server) CancelDataDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		JobID string `json:"jobId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	job, exists := s.getDataDiffJob(connectionID, req.JobID)
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, ErrCodeDataDiffJobNotFound)
+		return
+	}
+	job.cancel()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"job":     job.snapshot(),
+	})
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+const (
+	maxRestoreFileSize      = 256 << 20              // 恢复脚本的最大大小，脚本需要整体读入内存后拆分
+	maxRestoreErrors        = 100                    // 最多输出的失败语句数
+	restoreProgressInterval = 500 * time.Millisecond // 输出进度的最小间隔
+	restoreStatementPreview = 200                    // 失败信息中语句的最大长度
+)
+
+// DumpDatabase 将当前数据库转储为 .sql 文件（建表语句和批量 INSERT），支持 MySQL、PostgreSQL、SQLite、SQL Server 和 Oracle
+// POST 请求体为 {"tables": [...], "noSchema": false, "noData": false, "dropTables": false}，tables 为空时转储所有表；
+// GET 请求使用同名的查询参数，tables 为逗号分隔的表名。数据边读取边写入响应，开始写入后发生的错误只能记录日志
+func (s *Server) DumpDatabase(w http.ResponseWriter, r *http.Request) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var options database.DumpOptions
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+			return
+		}
+	} else {
+		query := r.URL.Query()
+		if tables := query.Get("tables"); tables != "" {
+			options.Tables = strings.Split(tables, ",")
+		}
+		options.NoSchema = query.Get("noSchema") == "true"
+		options.NoData = query.Get("noData") == "true"
+		options.DropTables = query.Get("dropTables") == "true"
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	if !database.SupportsSQLDump(session.dbType) {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeDumpNotSupported, session.dbType)
+		return
+	}
+
+	// 开始写入前检查表是否存在，使错误能以 JSON 返回
+	tables, err := session.db.GetTables()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTablesFailed, err)
+		return
+	}
+	if len(options.Tables) == 0 {
+		options.Tables = tables
+	} else {
+		exists := make(map[string]bool, len(tables))
+		for _, table := range tables {
+			exists[table] = true
+		}
+		for _, table := range options.Tables {
+			if !exists[table] {
+				writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownTable, table)
+				return
+			}
+		}
+	}
+
+	name := fmt.Sprintf("%s_%s.sql", dumpFileName(session), time.Now().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/sql; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", name))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// 转储不受语句超时限制，客户端断开时停止读取
+	ctx := r.Context()
+	count, err := database.DumpSQL(ctx, session.db, session.dbType, w, options)
+	if err != nil {
+		s.getLogger().Error(ctx, "Dump stopped after %d rows: %v", count, err)
+	}
+}
+
+// dumpFileName 返回转储文件名的前缀：当前数据库名，没有时使用数据库类型
+func dumpFileName(session *ConnectionSession) string {
+	name := session.dbType
+	if session.currentDatabase != "" {
+		name = session.currentDatabase
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|; `, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// RestoreDatabase 执行上传的 .sql 脚本（如 DumpDatabase 生成的转储文件），以 NDJSON 格式流式输出进度：
+//
+//	{"type":"start","total":120}
+//	{"type":"progress","executed":50,"failed":0,"total":120}
+//	{"type":"error","index":51,"statement":"INSERT ...","errorCode":"...","message":"..."}
+//	{"type":"end","executed":120,"failed":1,"total":120,"stopped":false}
+//
+// 表单字段：file 为脚本文件，continueOnError 为 true 时出错后继续执行后续语句。
+// 执行前校验所有语句；会话中有打开的事务时在该事务中执行。恢复不受语句超时限制，客户端断开时停止
+func (s *Server) RestoreDatabase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	switch session.dbType {
+	case "redis", "mongodb", "elasticsearch":
+		writeJSONError(w, http.StatusBadRequest, ErrCodeScriptNotSupported)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxRestoreFileSize)
+	if err := r.ParseMultipartForm(importMemoryLimit); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingImportFile)
+		return
+	}
+	defer file.Close()
+	script, err := io.ReadAll(file)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeRestoreFailed, err)
+		return
+	}
+	continueOnError := r.FormValue("continueOnError") == "true"
+
+	statements := database.SplitSQLScript(session.dbType, strings.TrimPrefix(string(script), "\ufeff"))
+	if len(statements) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptySQLQuery)
+		return
+	}
+	infos := make([]database.StatementInfo, len(statements))
+	for i, stmt := range statements {
+		infos[i] = database.ClassifySQL(session.dbType, stmt)
+		if err := s.validateSQL(stmt, infos[i].Keyword); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, fmt.Sprintf("#%d %v", i+1, err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(message map[string]interface{}) error {
+		if err := encoder.Encode(message); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	total := len(statements)
+	if err := send(map[string]interface{}{"type": "start", "total": total}); err != nil {
+		return
+	}
+
+	ctx := r.Context()
+	executed, failed := 0, 0
+	lastProgress := time.Now()
+	for i, stmt := range statements {
+		if ctx.Err() != nil {
+			break
+		}
+		// 每条语句结束后重新开始会话事务的空闲计时
+		db, done := s.sessionDB(session, infos[i].Kind != database.StatementRead)
+		var errCode string
+		var affected int64
+		started := time.Now()
+		if infos[i].ReturnsRows() {
+			errCode = ErrCodeExecuteQueryFailed
+			var rows []map[string]interface{}
+			rows, err = db.ExecuteQueryContext(ctx, stmt)
+			affected = int64(len(rows))
+		} else {
+			affected, errCode, err = executeStatement(ctx, db, infos[i], stmt)
+		}
+		done()
+		s.auditStatement(r, session, AuditSourceRestore, infos[i], stmt, nil, started, affected, err)
+		executed++
+
+		if err != nil {
+			failed++
+			if failed <= maxRestoreErrors {
+				preview := stmt
+				if len([]rune(preview)) > restoreStatementPreview {
+					preview = string([]rune(preview)[:restoreStatementPreview]) + "..."
+				}
+				if send(map[string]interface{}{
+					"type":      "error",
+					"index":     i,
+					"statement": preview,
+					"errorCode": queryErrorCode(ctx, err, errCode),
+					"message":   err.Error(),
+				}) != nil {
+					return
+				}
+			}
+			if !continueOnError {
+				break
+			}
+		}
+
+		if time.Since(lastProgress) >= restoreProgressInterval {
+			lastProgress = time.Now()
+			if send(map[string]interface{}{"type": "progress", "executed": executed, "failed": failed, "total": total}) != nil {
+				// 客户端已断开，停止执行
+				return
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.getLogger().Warn(ctx, "Restore stopped after %d of %d statements: %v", executed, total, err)
+		return
+	}
+	send(map[string]interface{}{
+		"type":     "end",
+		"executed": executed,
+		"failed":   failed,
+		"total":    total,
+		"stopped":  executed < total,
+	})
+}
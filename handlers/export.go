@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// exportFormatXLSX Excel 格式，其他格式由 database.NewExportWriter 生成
+const exportFormatXLSX = "xlsx"
+
+// exportFormat 导出格式的响应类型和文件扩展名
+type exportFormat struct {
+	contentType string
+	extension   string
+}
+
+var exportFormats = map[string]exportFormat{
+	exportFormatXLSX:              {"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"},
+	database.ExportFormatCSV:      {"text/csv; charset=utf-8", "csv"},
+	database.ExportFormatTSV:      {"text/tab-separated-values; charset=utf-8", "tsv"},
+	database.ExportFormatJSON:     {"application/json; charset=utf-8", "json"},
+	database.ExportFormatNDJSON:   {"application/x-ndjson; charset=utf-8", "ndjson"},
+	database.ExportFormatMarkdown: {"text/markdown; charset=utf-8", "md"},
+	database.ExportFormatSQL:      {"application/sql; charset=utf-8", "sql"},
+}
+
+// exportRequest 导出参数
+type exportRequest struct {
+	Table    string                `json:"table"`
+	Query    string                `json:"query"`
+	Format   string                `json:"format"`   // 默认为 xlsx
+	Columns  []string              `json:"columns"`  // 导出的列及顺序，为空时导出所有列
+	Filters  *database.FilterGroup `json:"filters"`  // 过滤条件，为空时导出整张表
+	Sort     []database.SortSpec   `json:"sort"`     // 排序条件
+	Dialect  string                `json:"dialect"`  // INSERT 语句的方言，默认为当前连接的数据库类型
+	Page     int                   `json:"page"`     // 大于 0 时只导出该页
+	PageSize int                   `json:"pageSize"` // 只导出一页时的每页行数
+}
+
+// parseExportRequest 解析导出参数：POST 请求从请求体中解析，GET 请求从查询参数中解析
+// columns 为逗号分隔的列名，filters 和 sort 为 JSON 字符串
+func parseExportRequest(r *http.Request) (*exportRequest, error) {
+	req := &exportRequest{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, err
+		}
+	} else {
+		query := r.URL.Query()
+		req.Table = query.Get("table")
+		req.Format = query.Get("format")
+		req.Dialect = query.Get("dialect")
+		req.Page, _ = strconv.Atoi(query.Get("page"))
+		req.PageSize, _ = strconv.Atoi(query.Get("pageSize"))
+		if columns := query.Get("columns"); columns != "" {
+			req.Columns = strings.Split(columns, ",")
+		}
+		if filters := query.Get("filters"); filters != "" {
+			var f database.FilterGroup
+			if err := json.Unmarshal([]byte(filters), &f); err != nil {
+				return nil, err
+			}
+			req.Filters = &f
+		}
+		var err error
+		if req.Sort, err = parseSortParam(r); err != nil {
+			return nil, err
+		}
+	}
+	if req.Format == "" {
+		req.Format = exportFormatXLSX
+	}
+	req.Format = strings.ToLower(req.Format)
+	return req, nil
+}
+
+// ExportTableData 导出表数据
+// 默认流式导出整张表或满足过滤条件的行，支持 xlsx、csv、tsv、json、ndjson、markdown 和 sql（INSERT 语句）格式；
+// 指定 page 时只导出该页。数据边读取边写入响应，导出大表时服务器内存占用与表的大小无关
+func (s *Server) ExportTableData(w http.ResponseWriter, r *http.Request) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	req, err := parseExportRequest(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingTableName)
+		return
+	}
+	if _, ok := exportFormats[req.Format]; !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedExportFormat, req.Format)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	columns, err := session.db.GetTableColumns(req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+	if err := validateSortColumns(session.dbType, req.Sort, columns); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSort, err)
+		return
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+
+	// 导出不受语句超时限制，客户端断开时停止读取
+	ctx := r.Context()
+	var iter database.RowIterator
+	filename := req.Table
+	if req.Page > 0 {
+		if req.PageSize < 1 {
+			req.PageSize = 50
+		}
+		var rows []map[string]interface{}
+		if sdb, ok := session.db.(database.SortableDatabase); ok && len(req.Sort) > 0 {
+			rows, _, err = sdb.GetTableDataSorted(ctx, req.Table, req.Page, req.PageSize, req.Filters, req.Sort)
+		} else {
+			rows, _, err = database.AsContextDatabase(session.db).GetTableDataContext(ctx, req.Table, req.Page, req.PageSize, req.Filters)
+		}
+		if err == nil {
+			iter = database.NewMapRowIterator(names, rows)
+		}
+		filename = fmt.Sprintf("%s_page%d", req.Table, req.Page)
+	} else {
+		iter, err = database.StreamTableRows(ctx, session.db, req.Table, names, req.Filters, req.Sort)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetTableDataFailed), err)
+		return
+	}
+	defer iter.Close()
+
+	s.writeExport(w, r, iter, req, session.dbType, filename, false)
+}
+
+// ExportQueryResults 导出只读查询的结果，格式与 ExportTableData 相同
+func (s *Server) ExportQueryResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	req, err := parseExportRequest(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Query == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptySQLQuery)
+		return
+	}
+	if _, ok := exportFormats[req.Format]; !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedExportFormat, req.Format)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	// 只支持返回结果集的只读查询（SELECT、WITH ... SELECT、SHOW 等）
+	stmt := database.ClassifySQL(session.dbType, req.Query)
+	if stmt.Kind != database.StatementRead {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeOnlySelectQueryAllowed)
+		return
+	}
+	// 与 /api/query 相同，Redis、MongoDB 和 Elasticsearch 使用自己的命令语法，跳过 SQL 校验
+	if session.dbType != "redis" && session.dbType != "mongodb" && session.dbType != "elasticsearch" {
+		if err := s.validateSQL(req.Query, stmt.Keyword); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+			return
+		}
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+
+	// 会话中有打开的事务时在该事务中查询，可以看到未提交的修改
+	db, done := s.sessionDB(session, false)
+	defer done()
+	iter, err := database.StreamQuery(ctx, db, req.Query)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
+		return
+	}
+	defer iter.Close()
+
+	if req.Table == "" {
+		// INSERT 语句的目标表，未指定时使用占位名称
+		req.Table = "query_result"
+	}
+	s.writeExport(w, r, iter, req, session.dbType, "query_result", true)
+}
+
+// writeExport 将迭代器中的行按请求的格式和列写入响应
+// 读取第一行之前发生的错误以 JSON 返回；开始写入后发生的错误只能记录日志，客户端收到的文件不完整
+func (s *Server) writeExport(w http.ResponseWriter, r *http.Request, iter database.RowIterator, req *exportRequest, dbType, filename string, requireRows bool) {
+	// 按请求的列选择并排序
+	all := iter.Columns()
+	indexes := make([]int, 0, len(all))
+	columns := req.Columns
+	if len(columns) == 0 {
+		columns = all
+		for i := range all {
+			indexes = append(indexes, i)
+		}
+	} else {
+		position := make(map[string]int, len(all))
+		for i, name := range all {
+			if _, ok := position[name]; !ok {
+				position[name] = i
+			}
+		}
+		for _, name := range columns {
+			i, ok := position[name]
+			if !ok {
+				writeJSONError(w, http.StatusBadRequest, ErrCodeUnknownColumn, name)
+				return
+			}
+			indexes = append(indexes, i)
+		}
+	}
+
+	hasRow := iter.Next()
+	if err := iter.Err(); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(r.Context(), err, ErrCodeExportFailed), err)
+		return
+	}
+	if !hasRow && requireRows {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeQueryResultEmpty)
+		return
+	}
+
+	var writer database.ExportWriter
+	var err error
+	if req.Format == exportFormatXLSX {
+		writer, err = newExcelExportWriter(w)
+	} else {
+		dialect := req.Dialect
+		if dialect == "" {
+			dialect = dbType
+		}
+		writer, err = database.NewExportWriter(req.Format, w, database.ExportOptions{TableName: req.Table, Dialect: dialect})
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeExportFailed, err)
+		return
+	}
+
+	format := exportFormats[req.Format]
+	name := fmt.Sprintf("%s_%s.%s", filename, time.Now().Format("20060102_150405"), format.extension)
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", name))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	err = writer.WriteHeader(columns)
+	var count int64
+	for err == nil && hasRow {
+		values := iter.Values()
+		row := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			row[i] = values[index]
+		}
+		if err = writer.WriteRow(row); err != nil {
+			break
+		}
+		count++
+		hasRow = iter.Next()
+	}
+	if err == nil {
+		err = iter.Err()
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		s.getLogger().Error(r.Context(), "Export stopped after %d rows: %v", count, err)
+	}
+}
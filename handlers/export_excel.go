@@ -1,90 +1,38 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"strconv"
+	"io"
 	"time"
 
+	"github.com/gotoailab/simple-db-web/database"
 	"github.com/xuri/excelize/v2"
 )
 
-// ExportTableDataToExcel 导出表数据为Excel
-func (s *Server) ExportTableDataToExcel(w http.ResponseWriter, r *http.Request) {
-	connectionID := getConnectionID(r)
-	if connectionID == "" {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
-		return
-	}
-
-	tableName := r.URL.Query().Get("table")
-	if tableName == "" {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingTableName)
-		return
-	}
-
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
-
-	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	if pageSize < 1 {
-		pageSize = 50
-	}
-
-	session, err := s.getSession(connectionID)
-	if err != nil {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
-		return
-	}
-
-	// 获取数据（导出时不使用过滤条件）
-	data, _, err := session.db.GetTableData(tableName, page, pageSize, nil)
-	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableDataFailed, err)
-		return
-	}
+// excelSheetName 导出的工作表名称
+const excelSheetName = "Sheet1"
 
-	// 获取列信息
-	columns, err := session.db.GetTableColumns(tableName)
-	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
-		return
-	}
+// excelExportWriter 使用 excelize 流式写入器生成 Excel 文件，实现 database.ExportWriter
+// 行数据由 excelize 写入临时文件，Close 时输出完整的文件；超过工作表最大行数时报错
+type excelExportWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
 
-	// 创建Excel文件
+func newExcelExportWriter(w io.Writer) (*excelExportWriter, error) {
 	f := excelize.NewFile()
-	defer func() {
-		if err := f.Close(); err != nil {
-			s.getLogger().Error(r.Context(), "Failed to close Excel file: %v", err)
-		}
-	}()
-
-	sheetName := "Sheet1"
-	// 删除默认的Sheet1（如果存在）
-	val, _ := f.GetSheetIndex("Sheet1")
-	if val != -1 {
-		f.DeleteSheet("Sheet1")
-	}
-	index, err := f.NewSheet(sheetName)
+	stream, err := f.NewStreamWriter(excelSheetName)
 	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeCreateExcelSheetFailed, err)
-		return
-	}
-	f.SetActiveSheet(index)
-
-	// 写入表头
-	colNames := make([]string, len(columns))
-	for i, col := range columns {
-		colNames[i] = col.Name
-		cellName, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheetName, cellName, col.Name)
+		f.Close()
+		return nil, err
 	}
+	return &excelExportWriter{w: w, file: f, stream: stream}, nil
+}
 
-	// 设置表头样式
-	headerStyle, err := f.NewStyle(&excelize.Style{
+func (e *excelExportWriter) WriteHeader(columns []string) error {
+	headerStyle, err := e.file.NewStyle(&excelize.Style{
 		Font: &excelize.Font{
 			Bold: true,
 		},
@@ -94,159 +42,50 @@ func (s *Server) ExportTableDataToExcel(w http.ResponseWriter, r *http.Request)
 			Pattern: 1,
 		},
 	})
-	if err == nil && len(columns) > 0 {
-		lastCol, _ := excelize.CoordinatesToCellName(len(columns), 1)
-		f.SetCellStyle(sheetName, "A1", lastCol, headerStyle)
-	}
-
-	// 写入数据
-	for rowIdx, row := range data {
-		for colIdx, colName := range colNames {
-			cellName, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
-			value := row[colName]
-			if value != nil {
-				f.SetCellValue(sheetName, cellName, value)
-			}
-		}
-	}
-
-	// 设置响应头
-	filename := fmt.Sprintf("%s_page%d_%s.xlsx", tableName, page, time.Now().Format("20060102_150405"))
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	w.Header().Set("Content-Transfer-Encoding", "binary")
-
-	// 写入响应
-	if err := f.Write(w); err != nil {
-		s.getLogger().Error(r.Context(), "Failed to write Excel file: %v", err)
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeExportExcelFailed, err)
-		return
-	}
-}
-
-// ExportQueryResultsToExcel 导出查询结果为Excel
-func (s *Server) ExportQueryResultsToExcel(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
-		return
-	}
-
-	connectionID := getConnectionID(r)
-	if connectionID == "" {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
-		return
-	}
-
-	var req struct {
-		Query string `json:"query"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
-		return
-	}
-
-	if req.Query == "" {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptySQLQuery)
-		return
-	}
-
-	session, err := s.getSession(connectionID)
 	if err != nil {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
-		return
-	}
-
-	// 只支持SELECT查询
-	queryUpper := fmt.Sprintf("%.6s", req.Query)
-	if queryUpper != "SELECT" && queryUpper != "select" {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeOnlySelectQueryAllowed)
-		return
+		return err
 	}
 
-	// 执行查询
-	results, err := session.db.ExecuteQuery(req.Query)
-	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteQueryFailed, err)
-		return
+	cells := make([]interface{}, len(columns))
+	for i, name := range columns {
+		cells[i] = excelize.Cell{StyleID: headerStyle, Value: name}
 	}
+	e.row = 1
+	return e.stream.SetRow("A1", cells)
+}
 
-	if len(results) == 0 {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeQueryResultEmpty)
-		return
+func (e *excelExportWriter) WriteRow(values []interface{}) error {
+	if e.row >= excelize.TotalRows {
+		return fmt.Errorf("too many rows: Excel supports at most %d rows per sheet", excelize.TotalRows)
 	}
+	e.row++
 
-	// 创建Excel文件
-	f := excelize.NewFile()
-	defer func() {
-		if err := f.Close(); err != nil {
-			s.getLogger().Error(r.Context(), "Failed to close Excel file: %v", err)
-		}
-	}()
-
-	sheetName := "Sheet1"
-	// 删除默认的Sheet1（如果存在）
-	val, _ := f.GetSheetIndex("Sheet1")
-	if val != -1 {
-		f.DeleteSheet("Sheet1")
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = excelCellValue(v)
 	}
-	index, err := f.NewSheet(sheetName)
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
 	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeCreateExcelSheetFailed, err)
-		return
-	}
-	f.SetActiveSheet(index)
-
-	// 获取列名（从第一行数据中提取）
-	colNames := make([]string, 0)
-	if len(results) > 0 {
-		for colName := range results[0] {
-			colNames = append(colNames, colName)
-		}
-	}
-
-	// 写入表头
-	for i, colName := range colNames {
-		cellName, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheetName, cellName, colName)
-	}
-
-	// 设置表头样式
-	headerStyle, err := f.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Bold: true,
-		},
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Color:   []string{"#E0E0E0"},
-			Pattern: 1,
-		},
-	})
-	if err == nil && len(colNames) > 0 {
-		lastCol, _ := excelize.CoordinatesToCellName(len(colNames), 1)
-		f.SetCellStyle(sheetName, "A1", lastCol, headerStyle)
+		return err
 	}
+	return e.stream.SetRow(cell, cells)
+}
 
-	// 写入数据
-	for rowIdx, row := range results {
-		for colIdx, colName := range colNames {
-			cellName, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
-			value := row[colName]
-			if value != nil {
-				f.SetCellValue(sheetName, cellName, value)
-			}
-		}
+func (e *excelExportWriter) Close() error {
+	defer e.file.Close()
+	if err := e.stream.Flush(); err != nil {
+		return err
 	}
+	return e.file.Write(e.w)
+}
 
-	// 设置响应头
-	filename := fmt.Sprintf("query_result_%s.xlsx", time.Now().Format("20060102_150405"))
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	w.Header().Set("Content-Transfer-Encoding", "binary")
-
-	// 写入响应
-	if err := f.Write(w); err != nil {
-		s.getLogger().Error(r.Context(), "Failed to write Excel file: %v", err)
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeExportExcelFailed, err)
-		return
+// excelCellValue 数字、布尔和时间保留原始类型，其他值转换为文本
+func excelCellValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil, string, bool, time.Time,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	default:
+		return database.ExportText(value)
 	}
 }
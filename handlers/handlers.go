@@ -39,7 +39,9 @@ type ConnectionSession struct {
 	currentDatabase string
 	currentTable    string
 	createdAt       time.Time
-	sessionData     *SessionData // 保存原始数据，用于持久化
+	sessionData     *SessionData        // 保存原始数据，用于持久化
+	tx              *sessionTransaction // 打开的显式事务（可选）
+	txMutex         sync.Mutex          // 保护tx的互斥锁
 }
 
 // SessionStorage 会话存储接口
@@ -120,6 +122,10 @@ func (l *DefaultLogger) Error(ctx context.Context, format string, args ...interf
 	log.Printf("[ERROR] "+format, args...)
 }
 
+// UserResolver 返回发起请求的用户标识（如登录用户名），用于按用户区分查询历史等数据
+// 返回空字符串表示匿名用户
+type UserResolver func(r *http.Request) string
+
 // SQLValidator SQL校验器接口
 // 允许外部项目实现自定义的SQL校验规则
 type SQLValidator interface {
@@ -215,15 +221,37 @@ type Server struct {
 	customDbMutex          sync.RWMutex
 	customProxies          map[string]ProxyFactory // 自定义代理类型
 	customProxyMutex       sync.RWMutex
-	builtinTypes           map[string]string         // 内置数据库类型及其显示名称
-	customScript           string                    // 自定义JavaScript脚本，会在页面加载后执行
-	customScriptMutex      sync.RWMutex              // 保护customScript的读写锁
-	validators             []SQLValidator            // SQL校验器列表
-	validatorsMutex        sync.RWMutex              // 保护validators的读写锁
-	logger                 Logger                    // 日志记录器
-	loggerMutex            sync.RWMutex              // 保护logger的读写锁
-	presetConnections      []database.ConnectionInfo // 预设连接列表
-	presetConnectionsMutex sync.RWMutex              // 保护presetConnections的读写锁
+	builtinTypes           map[string]string                 // 内置数据库类型及其显示名称
+	customScript           string                            // 自定义JavaScript脚本，会在页面加载后执行
+	customScriptMutex      sync.RWMutex                      // 保护customScript的读写锁
+	validators             []SQLValidator                    // SQL校验器列表
+	validatorsMutex        sync.RWMutex                      // 保护validators的读写锁
+	logger                 Logger                            // 日志记录器
+	loggerMutex            sync.RWMutex                      // 保护logger的读写锁
+	presetConnections      []database.ConnectionInfo         // 预设连接列表
+	presetConnectionsMutex sync.RWMutex                      // 保护presetConnections的读写锁
+	queryTimeout           time.Duration                     // 默认语句超时时间（0表示不限制）
+	queryTimeoutMutex      sync.RWMutex                      // 保护queryTimeout的读写锁
+	runningQueries         map[runningQueryKey]*runningQuery // 正在执行的查询（用于取消），按连接和查询ID区分
+	runningQueriesMutex    sync.Mutex                        // 保护runningQueries的互斥锁
+	txIdleTimeout          time.Duration                     // 空闲事务自动回滚的超时时间（0表示不自动回滚）
+	txIdleTimeoutMutex     sync.RWMutex                      // 保护txIdleTimeout的读写锁
+	copyJobs               map[string]*copyJob               // 跨连接复制表的任务
+	copyJobsMutex          sync.Mutex                        // 保护copyJobs的互斥锁
+	dataDiffJobs           map[string]*dataDiffJob           // 数据对比任务
+	dataDiffJobsMutex      sync.Mutex                        // 保护dataDiffJobs的互斥锁
+	queryHistory           QueryHistoryStore                 // 查询历史存储（nil表示不记录）
+	queryHistoryMutex      sync.RWMutex                      // 保护queryHistory的读写锁
+	savedQueries           SavedQueryStore                   // 保存的查询（nil表示禁用）
+	savedQueriesMutex      sync.RWMutex                      // 保护savedQueries的读写锁
+	userResolver           UserResolver                      // 识别请求用户（可选）
+	userResolverMutex      sync.RWMutex                      // 保护userResolver的读写锁
+	auditSink              AuditSink                         // 数据修改操作的审计日志（nil表示不记录）
+	auditSinkMutex         sync.RWMutex                      // 保护auditSink的读写锁
+	adminResolver          AdminResolver                     // 判断请求用户是否为管理员（可选）
+	adminResolverMutex     sync.RWMutex                      // 保护adminResolver的读写锁
+	rowUndos               map[string]*rowUndo               // 行修改的撤销信息
+	rowUndosMutex          sync.Mutex                        // 保护rowUndos的互斥锁
 }
 
 // NewServer 创建新的服务器实例
@@ -252,6 +280,13 @@ func NewServer() (*Server, error) {
 		validators:           make([]SQLValidator, 0),
 		logger:               &DefaultLogger{}, // 默认使用标准库log
 		presetConnections:    make([]database.ConnectionInfo, 0),
+		runningQueries:       make(map[runningQueryKey]*runningQuery),
+		copyJobs:             make(map[string]*copyJob),
+		dataDiffJobs:         make(map[string]*dataDiffJob),
+		rowUndos:             make(map[string]*rowUndo),
+		queryHistory:         NewMemoryQueryHistoryStore(defaultQueryHistoryRetention),
+		savedQueries:         NewMemorySavedQueryStore(),
+		txIdleTimeout:        defaultTransactionIdleTimeout,
 	}
 
 	// 注册默认的SSH代理
@@ -460,6 +495,7 @@ const (
 	ErrCodeMissingDatabaseName        = "error.missingDatabaseName"
 	ErrCodeEmptySQLQuery              = "error.emptySQLQuery"
 	ErrCodeUnsupportedSQLType         = "error.unsupportedSQLType"
+	ErrCodeSessionStatement           = "error.sessionStatement"
 	ErrCodeUnsupportedDatabaseType    = "error.unsupportedDatabaseType"
 	ErrCodeUnsupportedProxyType       = "error.unsupportedProxyType"
 	ErrCodeParseRequestFailed         = "error.parseRequestFailed"
@@ -487,9 +523,70 @@ const (
 	ErrCodeTableNameEmpty             = "error.tableNameEmpty"
 	ErrCodeClickHouseNoUpdate         = "error.clickHouseNoUpdate"
 	ErrCodeClickHouseNoDelete         = "error.clickHouseNoDelete"
+	ErrCodeRowKeyRequired             = "error.rowKeyRequired"
+	ErrCodeInsertFailed               = "error.insertFailed"
+	ErrCodeUnknownColumn              = "error.unknownColumn"
+	ErrCodeColumnNotNullable          = "error.columnNotNullable"
+	ErrCodeEmptyChangeset             = "error.emptyChangeset"
+	ErrCodeEmptyRowChange             = "error.emptyRowChange"
+	ErrCodeInvalidRowChange           = "error.invalidRowChange"
 	ErrCodeConnectionNotExists        = "error.connectionNotExists"
 	ErrCodeCreateExcelSheetFailed     = "error.createExcelSheetFailed"
 	ErrCodeExportExcelFailed          = "error.exportExcelFailed"
+	ErrCodeExportFailed               = "error.exportFailed"
+	ErrCodeUnsupportedExportFormat    = "error.unsupportedExportFormat"
+	ErrCodeMissingImportFile          = "error.missingImportFile"
+	ErrCodeUnsupportedImportFormat    = "error.unsupportedImportFormat"
+	ErrCodeReadImportFileFailed       = "error.readImportFileFailed"
+	ErrCodeInvalidImportMode          = "error.invalidImportMode"
+	ErrCodeImportModeNotSupported     = "error.importModeNotSupported"
+	ErrCodeInvalidImportMapping       = "error.invalidImportMapping"
+	ErrCodeEmptyImportMapping         = "error.emptyImportMapping"
+	ErrCodeImportKeyRequired          = "error.importKeyRequired"
+	ErrCodeImportInvalidValue         = "error.importInvalidValue"
+	ErrCodeEmptyImportRow             = "error.emptyImportRow"
+	ErrCodeImportFailed               = "error.importFailed"
+	ErrCodeUnknownTable               = "error.unknownTable"
+	ErrCodeDumpNotSupported           = "error.dumpNotSupported"
+	ErrCodeRestoreFailed              = "error.restoreFailed"
+	ErrCodeMissingTargetConnection    = "error.missingTargetConnection"
+	ErrCodeTargetConnectionNotExists  = "error.targetConnectionNotExists"
+	ErrCodeTargetTableNotExists       = "error.targetTableNotExists"
+	ErrCodeCreateTableNotSupported    = "error.createTableNotSupported"
+	ErrCodeCreateTableFailed          = "error.createTableFailed"
+	ErrCodeNoCommonColumns            = "error.noCommonColumns"
+	ErrCodeCopyJobNotFound            = "error.copyJobNotFound"
+	ErrCodeCopyTableFailed            = "error.copyTableFailed"
+	ErrCodeSchemaSyncNotSupported     = "error.schemaSyncNotSupported"
+	ErrCodeSchemaDiffFailed           = "error.schemaDiffFailed"
+	ErrCodeDataDiffKeyRequired        = "error.dataDiffKeyRequired"
+	ErrCodeDataDiffJobNotFound        = "error.dataDiffJobNotFound"
+	ErrCodeDataDiffScriptNotReady     = "error.dataDiffScriptNotReady"
+	ErrCodeDataDiffFailed             = "error.dataDiffFailed"
+	ErrCodeMetadataNotSupported       = "error.metadataNotSupported"
+	ErrCodeGetMetadataFailed          = "error.getMetadataFailed"
+	ErrCodeTableDesignNotSupported    = "error.tableDesignNotSupported"
+	ErrCodeInvalidTableDesign         = "error.invalidTableDesign"
+	ErrCodeTableDesignFailed          = "error.tableDesignFailed"
+	ErrCodeExplainNotSupported        = "error.explainNotSupported"
+	ErrCodeExplainMultipleStatements  = "error.explainMultipleStatements"
+	ErrCodeExplainAnalyzeReadOnly     = "error.explainAnalyzeReadOnly"
+	ErrCodeExplainFailed              = "error.explainFailed"
+	ErrCodeQueryHistoryDisabled       = "error.queryHistoryDisabled"
+	ErrCodeQueryHistoryFailed         = "error.queryHistoryFailed"
+	ErrCodeSavedQueriesDisabled       = "error.savedQueriesDisabled"
+	ErrCodeSavedQueryNotFound         = "error.savedQueryNotFound"
+	ErrCodeSavedQueryForbidden        = "error.savedQueryForbidden"
+	ErrCodeInvalidSavedQuery          = "error.invalidSavedQuery"
+	ErrCodeInvalidSavedQueryParams    = "error.invalidSavedQueryParams"
+	ErrCodeSavedQueryNotSupported     = "error.savedQueryNotSupported"
+	ErrCodeSavedQueryFailed           = "error.savedQueryFailed"
+	ErrCodeAuditDisabled              = "error.auditDisabled"
+	ErrCodeAuditForbidden             = "error.auditForbidden"
+	ErrCodeAuditFailed                = "error.auditFailed"
+	ErrCodeRowUndoNotFound            = "error.rowUndoNotFound"
+	ErrCodeRowChangedSinceEdit        = "error.rowChangedSinceEdit"
+	ErrCodeRowUndoFailed              = "error.rowUndoFailed"
 	ErrCodeOnlySelectQueryAllowed     = "error.onlySelectQueryAllowed"
 	ErrCodeQueryResultEmpty           = "error.queryResultEmpty"
 	ErrCodeRequireLimit               = "error.requireLimit"
@@ -498,6 +595,21 @@ const (
 	ErrCodeNoTruncateTable            = "error.noTruncateTable"
 	ErrCodeNoDropDatabase             = "error.noDropDatabase"
 	ErrCodeQueryTooLong               = "error.queryTooLong"
+	ErrCodeQueryCancelled             = "error.queryCancelled"
+	ErrCodeQueryTimeout               = "error.queryTimeout"
+	ErrCodeQueryNotFound              = "error.queryNotFound"
+	ErrCodeMissingQueryID             = "error.missingQueryID"
+	ErrCodeQueryIDInUse               = "error.queryIdInUse"
+	ErrCodeInvalidSort                = "error.invalidSort"
+	ErrCodeInvalidCursor              = "error.invalidCursor"
+	ErrCodeSortNotSupported           = "error.sortNotSupported"
+	ErrCodeScriptNotSupported         = "error.scriptNotSupported"
+	ErrCodeTransactionNotSupported    = "error.transactionNotSupported"
+	ErrCodeTransactionActive          = "error.transactionActive"
+	ErrCodeNoActiveTransaction        = "error.noActiveTransaction"
+	ErrCodeBeginTransactionFailed     = "error.beginTransactionFailed"
+	ErrCodeCommitTransactionFailed    = "error.commitTransactionFailed"
+	ErrCodeRollbackTransactionFailed  = "error.rollbackTransactionFailed"
 )
 
 // writeJSONError 写入JSON格式的错误响应
@@ -756,6 +868,30 @@ func (s *Server) getLogger() Logger {
 	return s.logger
 }
 
+// SetUserResolver 设置识别请求用户的函数
+// 未设置时所有请求都视为同一个匿名用户
+// 示例：
+//
+//	server.SetUserResolver(func(r *http.Request) string {
+//	    return r.Header.Get("X-Forwarded-User")
+//	})
+func (s *Server) SetUserResolver(resolver UserResolver) {
+	s.userResolverMutex.Lock()
+	defer s.userResolverMutex.Unlock()
+	s.userResolver = resolver
+}
+
+// requestUser 返回发起请求的用户标识（线程安全）
+func (s *Server) requestUser(r *http.Request) string {
+	s.userResolverMutex.RLock()
+	resolver := s.userResolver
+	s.userResolverMutex.RUnlock()
+	if resolver == nil {
+		return ""
+	}
+	return resolver(r)
+}
+
 // SetCustomScript 设置自定义JavaScript脚本
 // 这个脚本会在页面加载后执行，可以用于配置请求拦截器等
 // 示例：
@@ -1230,15 +1366,18 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 		pageSize = 50
 	}
 
-	// 解析过滤条件（从请求体或查询参数中获取）
+	// 解析过滤条件和排序条件（从请求体或查询参数中获取）
 	var filters *database.FilterGroup = nil
+	var sorts []database.SortSpec
 	if r.Method == "POST" {
 		// POST 请求：从请求体中解析 JSON
 		var reqBody struct {
 			Filters *database.FilterGroup `json:"filters"`
+			Sort    []database.SortSpec   `json:"sort"`
 		}
-		if err := json.NewDecoder(r.Body).Decode(&reqBody); err == nil && reqBody.Filters != nil {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err == nil {
 			filters = reqBody.Filters
+			sorts = reqBody.Sort
 		}
 	} else {
 		// GET 请求：从查询参数中解析 JSON 字符串
@@ -1249,6 +1388,11 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 				filters = &f
 			}
 		}
+		var err error
+		if sorts, err = parseSortParam(r); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSort, err)
+			return
+		}
 	}
 
 	// 获取lastId参数（用于基于ID的分页）
@@ -1300,7 +1444,8 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 	// 判断是否可以使用基于ID的分页
 	useIdBasedPagination := false
 	var primaryKeyName string = ""
-	if primaryKeyCount == 1 && primaryKeyColumn != nil {
+	// Redis 列表的 index 列只用于定位元素，不支持基于ID的分页
+	if primaryKeyCount == 1 && primaryKeyColumn != nil && session.dbType != "redis" {
 		// 检查主键类型是否为整数类型
 		typeLower := strings.ToLower(primaryKeyColumn.Type)
 		if strings.Contains(typeLower, "int") || strings.Contains(typeLower, "serial") ||
@@ -1315,27 +1460,68 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 	var total int64
 	var nextId interface{} = nil
 
-	if useIdBasedPagination {
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	db := database.AsContextDatabase(session.db)
+
+	// 指定了排序条件时使用服务端排序
+	// 基于ID的分页改为游标分页，lastId/nextId/firstId 为编码后的游标（排序列 + 主键）
+	var sortable database.SortableDatabase
+	if len(sorts) > 0 {
+		if err := validateSortColumns(session.dbType, sorts, columns); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSort, err)
+			return
+		}
+		var ok bool
+		if sortable, ok = session.db.(database.SortableDatabase); !ok {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSortNotSupported)
+			return
+		}
+	}
+
+	if sortable != nil && useIdBasedPagination {
+		var cursor []interface{}
+		if lastIdStr != "" {
+			if cursor, err = database.DecodeCursor(lastIdStr); err != nil {
+				writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidCursor, err)
+				return
+			}
+		}
+		data, total, err = sortable.GetTableDataByCursor(ctx, tableName, primaryKeyName, cursor, pageSize, direction, filters, sorts)
+		if err != nil && ctx.Err() == nil {
+			// 如果游标分页失败，回退到传统分页
+			data, total, err = sortable.GetTableDataSorted(ctx, tableName, page, pageSize, filters, sorts)
+			useIdBasedPagination = false
+		} else if err == nil && len(data) > 0 {
+			if direction == "prev" {
+				nextId = rowCursor(data[0], sorts, primaryKeyName)
+			} else {
+				nextId = rowCursor(data[len(data)-1], sorts, primaryKeyName)
+			}
+		}
+	} else if sortable != nil {
+		data, total, err = sortable.GetTableDataSorted(ctx, tableName, page, pageSize, filters, sorts)
+	} else if useIdBasedPagination {
 		// 使用基于ID的分页
 		// direction: "next"表示下一页（id > lastId），"prev"表示上一页（id < lastId）
-		data, total, nextId, err = session.db.GetTableDataByID(tableName, primaryKeyName, lastId, pageSize, direction, filters)
-		if err != nil {
+		data, total, nextId, err = db.GetTableDataByIDContext(ctx, tableName, primaryKeyName, lastId, pageSize, direction, filters)
+		if err != nil && ctx.Err() == nil {
 			// 如果基于ID的分页失败，回退到传统分页
-			data, total, err = session.db.GetTableData(tableName, page, pageSize, filters)
+			data, total, err = db.GetTableDataContext(ctx, tableName, page, pageSize, filters)
 			useIdBasedPagination = false
 		}
 	} else {
 		// 使用传统OFFSET/LIMIT分页
-		data, total, err = session.db.GetTableData(tableName, page, pageSize, filters)
+		data, total, err = db.GetTableDataContext(ctx, tableName, page, pageSize, filters)
 	}
 
 	if err != nil {
-		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableDataFailed, err)
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetTableDataFailed), err)
 		return
 	}
 
 	// 如果使用基于ID的分页，从结果中提取ID
-	if useIdBasedPagination && nextId == nil && len(data) > 0 {
+	if useIdBasedPagination && sortable == nil && nextId == nil && len(data) > 0 {
 		if direction == "prev" {
 			// 上一页：nextId应该是当前页的第一个ID（用于继续向前翻页）
 			if idVal, ok := data[0][primaryKeyName]; ok {
@@ -1361,12 +1547,21 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 	isElasticsearch := session.dbType == "elasticsearch"
 	noPagination := isClickHouse || isRedis || isElasticsearch
 
+	tableData := map[string]interface{}{
+		"data":    data,
+		"columns": columns,
+	}
+	// format=resultset 时返回按列顺序排列、二进制安全的结果集
+	if r.URL.Query().Get("format") == "resultset" {
+		tableData = map[string]interface{}{
+			"resultSet": database.NewResultSetFromMaps(columns, data, r.URL.Query().Get("binaryEncoding")),
+			"columns":   columns,
+		}
+	}
+
 	response := map[string]interface{}{
-		"success": true,
-		"data": map[string]interface{}{
-			"data":    data,
-			"columns": columns,
-		},
+		"success":      true,
+		"data":         tableData,
 		"total":        total,
 		"page":         page,
 		"pageSize":     pageSize,
@@ -1382,12 +1577,18 @@ func (s *Server) GetTableData(w http.ResponseWriter, r *http.Request) {
 		}
 		response["hasNextPage"] = hasNextPage
 		// 如果是上一页，还需要返回当前页的第一个ID（用于继续向前翻页）
-		if direction == "prev" && len(data) > 0 {
+		if sortable != nil && len(data) > 0 {
+			// 游标分页时始终返回第一行的游标，前端无法从数据中自行提取
+			response["firstId"] = rowCursor(data[0], sorts, primaryKeyName)
+		} else if direction == "prev" && len(data) > 0 {
 			if firstId, ok := data[0][primaryKeyName]; ok {
 				response["firstId"] = firstId
 			}
 		}
 	}
+	if len(sorts) > 0 {
+		response["sort"] = sorts
+	}
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -1454,6 +1655,55 @@ func (s *Server) GetPageId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	sorts, err := parseSortParam(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSort, err)
+		return
+	}
+
+	// 指定了排序条件时，返回上一页最后一行的游标（与 /api/table/data 的 lastId 对应）
+	if len(sorts) > 0 {
+		if err := validateSortColumns(session.dbType, sorts, columns); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSort, err)
+			return
+		}
+		sortable, ok := session.db.(database.SortableDatabase)
+		if !ok {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSortNotSupported)
+			return
+		}
+
+		var pageId interface{} = nil
+		if page > 1 {
+			var filters *database.FilterGroup = nil
+			if filtersStr := r.URL.Query().Get("filters"); filtersStr != "" {
+				var f database.FilterGroup
+				if err := json.Unmarshal([]byte(filtersStr), &f); err == nil {
+					filters = &f
+				}
+			}
+
+			ctx, cancel := s.queryContext(r, session)
+			defer cancel()
+			// 每页1行时第 (page-1)*pageSize 页即为上一页的最后一行
+			rows, _, err := sortable.GetTableDataSorted(ctx, tableName, (page-1)*pageSize, 1, filters, sorts)
+			if err != nil {
+				writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetPageIDFailed), err)
+				return
+			}
+			if len(rows) > 0 {
+				pageId = rowCursor(rows[0], sorts, primaryKeyColumn.Name)
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"pageId":  pageId,
+			"page":    page,
+		})
+		return
+	}
+
 	// 获取指定页码的ID
 	pageId, err := session.db.GetPageIdByPageNumber(tableName, primaryKeyColumn.Name, page, pageSize)
 	if err != nil {
@@ -1482,7 +1732,12 @@ func (s *Server) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Query string `json:"query"`
+		Query   string `json:"query"`
+		QueryID string `json:"queryId"` // 查询ID（可选），用于取消正在执行的查询
+		Stream  bool   `json:"stream"`  // 是否以 NDJSON 流式返回查询结果
+		// Format 为 resultset 时返回有序、带列类型的结果集，而不是 map 数组
+		Format         string `json:"format"`
+		BinaryEncoding string `json:"binaryEncoding"` // 二进制值编码方式：hex（默认）或 base64
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
@@ -1500,83 +1755,117 @@ func (s *Server) ExecuteQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 判断SQL类型
-	queryUpper := strings.ToUpper(strings.TrimSpace(req.Query))
-	queryType := ""
-	if len(queryUpper) >= 6 {
-		queryType = queryUpper[:6]
-	}
+	// 判断SQL类型（跳过注释，WITH 语句按主语句分类）
+	stmt := database.ClassifySQL(session.dbType, req.Query)
 
 	// 执行SQL校验（Redis、MongoDB 和 Elasticsearch 跳过 SQL 验证，因为它们使用自己的命令语法）
 	if session.dbType != "redis" && session.dbType != "mongodb" && session.dbType != "elasticsearch" {
-		if err := s.validateSQL(req.Query, queryType); err != nil {
+		if err := s.validateSQL(req.Query, stmt.Keyword); err != nil {
 			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
 			return
 		}
 	}
 
+	// MongoDB 使用自己的命令语法，只支持查询和增删改
+	if session.dbType == "mongodb" && stmt.Kind != database.StatementRead && stmt.Kind != database.StatementWrite {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedSQLType)
+		return
+	}
+	if stmt.Keyword == "" && session.dbType != "redis" && session.dbType != "elasticsearch" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedSQLType)
+		return
+	}
+	// 事务控制和修改连接状态的语句会留在连接池中的某个连接上，影响之后的其他请求
+	// 事务使用 /api/tx，切换数据库使用 /api/database/switch
+	if stmt.Session && session.dbType != "redis" && session.dbType != "elasticsearch" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSessionStatement, stmt.Keyword)
+		return
+	}
+
+	// 注册可取消的查询，执行结束后自动注销
+	ctx, queryID, finish, err := s.startQuery(r, connectionID, req.QueryID, session)
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, ErrCodeQueryIDInUse)
+		return
+	}
+	defer finish()
+	// 执行结束后记录查询历史，修改数据或结构的语句同时写入审计日志
+	started := time.Now()
+	var rowCount int64
+	var execErr error
+	defer func() {
+		s.recordQueryHistory(r, session, req.Query, started, rowCount, execErr)
+		s.auditStatement(r, session, AuditSourceQuery, stmt, req.Query, nil, started, rowCount, execErr)
+	}()
+	// 会话中有打开的事务时，语句在事务连接上执行；结果输出完之前事务不会因空闲而回滚
+	db, done := s.sessionDB(session, stmt.Kind != database.StatementRead)
+	defer done()
+
 	// 判断SQL类型（兼容旧代码）
 	// 对于 Redis 和 Elasticsearch，直接执行查询（它们使用自己的命令语法）
 	if session.dbType == "redis" || session.dbType == "elasticsearch" {
-		results, err := session.db.ExecuteQuery(req.Query)
+		if req.Format == "resultset" {
+			rowCount, execErr = s.writeResultSet(ctx, w, db, req.Query, req.BinaryEncoding, queryID)
+			return
+		}
+		results, err := db.ExecuteQueryContext(ctx, req.Query)
 		if err != nil {
-			writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteQueryFailed, err)
+			execErr = err
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
 			return
 		}
+		rowCount = int64(len(results))
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": true,
 			"data":    results,
+			"queryId": queryID,
 		})
 		return
 	}
 
-	queryUpperPrefix := fmt.Sprintf("%.6s", req.Query)
-	if queryType == "SELECT" || queryUpperPrefix == "SELECT" || queryUpperPrefix == "select" {
-		results, err := session.db.ExecuteQuery(req.Query)
-		if err != nil {
-			writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteQueryFailed, err)
+	// 返回结果集的语句（SELECT、SHOW、EXPLAIN、带 RETURNING 的写语句等）按查询执行
+	if stmt.ReturnsRows() {
+		if req.Stream {
+			rowCount, execErr = s.streamQuery(ctx, w, db, req.Query, req.BinaryEncoding, queryID)
 			return
 		}
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"data":    results,
-		})
-	} else if queryType == "UPDATE" || queryUpperPrefix == "UPDATE" || queryUpperPrefix == "update" {
-		affected, err := session.db.ExecuteUpdate(req.Query)
-		if err != nil {
-			writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteUpdateFailed, err)
+		if req.Format == "resultset" {
+			rowCount, execErr = s.writeResultSet(ctx, w, db, req.Query, req.BinaryEncoding, queryID)
 			return
 		}
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":  true,
-			"affected": affected,
-		})
-	} else if queryType == "DELETE" || queryUpperPrefix == "DELETE" || queryUpperPrefix == "delete" {
-		affected, err := session.db.ExecuteDelete(req.Query)
+		results, err := db.ExecuteQueryContext(ctx, req.Query)
 		if err != nil {
-			writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteDeleteFailed, err)
+			execErr = err
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
 			return
 		}
+		rowCount = int64(len(results))
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":  true,
-			"affected": affected,
-		})
-	} else if queryType == "INSERT" || queryUpperPrefix == "INSERT" || queryUpperPrefix == "insert" {
-		affected, err := session.db.ExecuteInsert(req.Query)
-		if err != nil {
-			writeJSONError(w, http.StatusInternalServerError, ErrCodeExecuteInsertFailed, err)
-			return
-		}
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success":  true,
-			"affected": affected,
+			"success": true,
+			"data":    results,
+			"queryId": queryID,
 		})
-	} else {
-		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedSQLType)
+		return
 	}
+
+	// 其余语句按关键字分派执行，DDL 和其他语句统一按更新执行
+	affected, errCode, err := executeStatement(ctx, db, stmt, req.Query)
+	if err != nil {
+		execErr = err
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, errCode), err)
+		return
+	}
+	rowCount = affected
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"affected": affected,
+		"kind":     stmt.Kind,
+		"queryId":  queryID,
+	})
 }
 
 // UpdateRow 更新行数据
+// 驱动支持事务时，修改前后读取旧值（用于撤销）和修改本身在同一事务中执行
 func (s *Server) UpdateRow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
@@ -1607,7 +1896,7 @@ func (s *Server) UpdateRow(w http.ResponseWriter, r *http.Request) {
 		Where map[string]interface{} `json:"where"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONWithNumbers(r, &req); err != nil {
 		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
 		return
 	}
@@ -1616,55 +1905,50 @@ func (s *Server) UpdateRow(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
 		return
 	}
-
-	// 构建UPDATE SQL
-	query := fmt.Sprintf("UPDATE `%s` SET ", req.Table)
-	first := true
-	for k, v := range req.Data {
-		if !first {
-			query += ", "
-		}
-		if v == nil {
-			query += fmt.Sprintf("`%s` = NULL", k)
-		} else {
-			// 转义单引号防止SQL注入
-			valStr := fmt.Sprintf("%v", v)
-			valStr = strings.ReplaceAll(valStr, "'", "''")
-			query += fmt.Sprintf("`%s` = '%s'", k, valStr)
-		}
-		first = false
+	// 没有定位条件时会更新整张表
+	if len(req.Where) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeRowKeyRequired)
+		return
 	}
 
-	query += " WHERE "
-	first = true
-	for k, v := range req.Where {
-		if !first {
-			query += " AND "
-		}
-		if v == nil {
-			query += fmt.Sprintf("`%s` IS NULL", k)
-		} else {
-			// 转义单引号防止SQL注入
-			valStr := fmt.Sprintf("%v", v)
-			valStr = strings.ReplaceAll(valStr, "'", "''")
-			query += fmt.Sprintf("`%s` = '%s'", k, valStr)
-		}
-		first = false
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	keys, values := normalizeJSONNumbers(req.Where), normalizeJSONNumbers(req.Data)
+	db, finish, err := s.beginRowChange(ctx, session)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+		return
 	}
-
-	affected, err := session.db.ExecuteUpdate(query)
+	// 修改前后读取整行，用于撤销
+	before := s.snapshotRows(ctx, db, req.Table, keys)
+	affected, err := updateRows(ctx, db, req.Table, keys, values)
+	var after []map[string]interface{}
+	if err == nil && before != nil && affected > 0 {
+		after = s.snapshotRows(ctx, db, req.Table, updatedKeys(keys, values))
+	}
+	err = finish(err)
+	s.auditRowChange(r, session, AuditSourceRow, req.Table, "update", keys, values, affected, err)
 	if err != nil {
 		writeJSONError(w, http.StatusInternalServerError, ErrCodeUpdateFailed, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	resp := map[string]interface{}{
 		"success":  true,
 		"affected": affected,
-	})
+	}
+	if after != nil {
+		if undo := rowUndoForUpdate(req.Table, keys, values, before, after); undo != nil {
+			if undoID := s.saveRowUndo(r, connectionID, undo); undoID != "" {
+				resp["undoId"] = undoID
+			}
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
 }
 
 // DeleteRow 删除行数据
+// 驱动支持事务时，修改前后读取旧值（用于撤销）和修改本身在同一事务中执行
 func (s *Server) DeleteRow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
@@ -1694,7 +1978,7 @@ func (s *Server) DeleteRow(w http.ResponseWriter, r *http.Request) {
 		Where map[string]interface{} `json:"where"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeJSONWithNumbers(r, &req); err != nil {
 		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
 		return
 	}
@@ -1703,35 +1987,41 @@ func (s *Server) DeleteRow(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
 		return
 	}
-
-	// 构建DELETE SQL
-	query := fmt.Sprintf("DELETE FROM `%s` WHERE ", req.Table)
-	first := true
-	for k, v := range req.Where {
-		if !first {
-			query += " AND "
-		}
-		if v == nil {
-			query += fmt.Sprintf("`%s` IS NULL", k)
-		} else {
-			// 转义单引号防止SQL注入
-			valStr := fmt.Sprintf("%v", v)
-			valStr = strings.ReplaceAll(valStr, "'", "''")
-			query += fmt.Sprintf("`%s` = '%s'", k, valStr)
-		}
-		first = false
+	// 没有定位条件时会删除整张表的数据
+	if len(req.Where) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeRowKeyRequired)
+		return
 	}
 
-	affected, err := session.db.ExecuteDelete(query)
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	keys := normalizeJSONNumbers(req.Where)
+	db, finish, err := s.beginRowChange(ctx, session)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+		return
+	}
+	// 删除前读取整行，用于撤销
+	before := s.snapshotRows(ctx, db, req.Table, keys)
+	affected, err := deleteRows(ctx, db, req.Table, keys)
+	err = finish(err)
+	s.auditRowChange(r, session, AuditSourceRow, req.Table, "delete", keys, nil, affected, err)
 	if err != nil {
 		writeJSONError(w, http.StatusInternalServerError, ErrCodeDeleteFailed, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	resp := map[string]interface{}{
 		"success":  true,
 		"affected": affected,
-	})
+	}
+	if before != nil && affected == int64(len(before)) {
+		undo := &rowUndo{table: req.Table, op: "delete", keys: keys, before: before}
+		if undoID := s.saveRowUndo(r, connectionID, undo); undoID != "" {
+			resp["undoId"] = undoID
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
 }
 
 // GetDatabases 获取数据库列表
@@ -1792,6 +2082,12 @@ func (s *Server) SwitchDatabase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// 事务固定在当前数据库的连接上，提交或回滚前不允许切换
+	if session.hasTransaction() {
+		writeJSONError(w, http.StatusConflict, ErrCodeTransactionActive)
+		return
+	}
+
 	if err := session.db.SwitchDatabase(req.Database); err != nil {
 		writeJSONError(w, http.StatusInternalServerError, ErrCodeSwitchDatabaseFailed, err)
 		return
@@ -1832,6 +2128,8 @@ func (s *Server) Disconnect(w http.ResponseWriter, r *http.Request) {
 	s.sessionsMutex.Lock()
 	session, exists := s.sessions[connectionID]
 	if exists {
+		// 断开连接前回滚未提交的事务
+		s.rollbackSessionTransaction(r.Context(), session)
 		if session.db != nil {
 			session.db.Close()
 		}
@@ -1888,6 +2186,7 @@ func (s *Server) GetStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	response["currentDatabase"] = currentDatabase
 	response["currentTable"] = currentTable
+	response["transaction"] = session.transactionStatus()
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -1916,11 +2215,47 @@ func (s *Server) RegisterRoutes(router Router) {
 	router.HandleFunc("/api/table/columns", s.GetTableColumns)
 	router.HandleFunc("/api/table/data", s.GetTableData)
 	router.HandleFunc("/api/table/page-id", s.GetPageId)
-	router.HandleFunc("/api/table/export", s.ExportTableDataToExcel)
+	router.HandleFunc("/api/table/export", s.ExportTableData)
+	router.POST("/api/table/import", s.ImportTableData)
+	router.GET("/api/database/dump", s.DumpDatabase)
+	router.POST("/api/database/dump", s.DumpDatabase)
+	router.POST("/api/database/restore", s.RestoreDatabase)
+	router.POST("/api/copy/start", s.StartCopyTable)
+	router.GET("/api/copy/status", s.GetCopyStatus)
+	router.POST("/api/copy/cancel", s.CancelCopyTable)
+	router.POST("/api/schema/diff", s.CompareSchemas)
+	router.GET("/api/schema/table", s.GetTableMetadata)
+	router.GET("/api/schema/views", s.GetViews)
+	router.GET("/api/schema/routines", s.GetRoutines)
+	router.GET("/api/schema/triggers", s.GetTriggers)
+	router.GET("/api/schema/er", s.GetERDiagram)
+	router.GET("/api/table/design", s.GetTableDesign)
+	router.POST("/api/table/design/preview", s.PreviewTableDesign)
+	router.POST("/api/table/design/apply", s.ApplyTableDesign)
+	router.POST("/api/datadiff/start", s.StartDataDiff)
+	router.GET("/api/datadiff/status", s.GetDataDiffStatus)
+	router.GET("/api/datadiff/script", s.DownloadDataDiffScript)
+	router.POST("/api/datadiff/cancel", s.CancelDataDiff)
 	router.POST("/api/query", s.ExecuteQuery)
-	router.POST("/api/query/export", s.ExportQueryResultsToExcel)
+	router.POST("/api/query/export", s.ExportQueryResults)
+	router.POST("/api/query/cancel", s.CancelQuery)
+	router.POST("/api/query/script", s.ExecuteScript)
+	router.POST("/api/query/explain", s.ExplainQuery)
+	router.GET("/api/query/history", s.GetQueryHistory)
+	router.POST("/api/query/history/delete", s.DeleteQueryHistory)
+	router.GET("/api/query/saved", s.ListSavedQueries)
+	router.POST("/api/query/saved/save", s.SaveSavedQuery)
+	router.POST("/api/query/saved/delete", s.DeleteSavedQuery)
+	router.POST("/api/query/saved/run", s.RunSavedQuery)
+	router.GET("/api/audit", s.GetAuditLog)
+	router.POST("/api/tx/begin", s.BeginTransaction)
+	router.POST("/api/tx/commit", s.CommitTransaction)
+	router.POST("/api/tx/rollback", s.RollbackTransaction)
+	router.POST("/api/row/insert", s.InsertRow)
 	router.POST("/api/row/update", s.UpdateRow)
 	router.POST("/api/row/delete", s.DeleteRow)
+	router.POST("/api/row/changeset", s.ApplyChangeset)
+	router.POST("/api/row/undo", s.UndoRowChange)
 
 	// 静态文件 - 使用 embed.FS
 	router.StaticFS("/static/", staticFS)
@@ -0,0 +1,608 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// 导入模式
+const (
+	importModeInsert  = "insert"  // 只插入新行
+	importModeUpsert  = "upsert"  // 按主键插入或更新
+	importModeReplace = "replace" // 清空表后插入
+)
+
+const (
+	maxImportFileSize = 512 << 20 // 上传文件的最大大小
+	importMemoryLimit = 32 << 20  // 解析上传时保存在内存中的大小，超出部分写入临时文件
+	maxImportErrors   = 100       // 响应中最多返回的被拒绝行数
+	importPreviewRows = 10        // 预览返回的记录数
+)
+
+// importFormatExtensions 文件扩展名对应的导入格式
+var importFormatExtensions = map[string]string{
+	".csv":    database.ExportFormatCSV,
+	".tsv":    database.ExportFormatTSV,
+	".tab":    database.ExportFormatTSV,
+	".json":   database.ExportFormatJSON,
+	".ndjson": database.ExportFormatNDJSON,
+	".jsonl":  database.ExportFormatNDJSON,
+	".xlsx":   exportFormatXLSX,
+}
+
+// ImportTableData 将上传的 CSV、TSV、JSON/NDJSON 或 Excel 文件导入到表中
+// 表单字段：file 为上传的文件，table 为目标表，format 为文件格式（为空时按扩展名判断），
+// mode 为 insert、upsert（按主键插入或更新）或 replace（清空表后插入），
+// mapping 为文件列到表列的 JSON 对象（为空时按列名匹配，忽略大小写；映射到空字符串的列不导入），
+// emptyAsNull 为 false 时空单元格按空字符串导入，atomic 为 true 时全部成功才提交，sheet 为 Excel 的工作表名称，
+// preview 为 true 时只返回文件的列、建议的映射和前几条记录。
+// 每行按列类型校验和转换后分批插入；不合法的行和插入失败的行被拒绝并返回原因，其他行照常导入。
+// replace 模式以及 atomic 导入在事务中执行，任一行被拒绝则全部回滚；会话中有打开的事务时在该事务中执行
+func (s *Server) ImportTableData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	if err := r.ParseMultipartForm(importMemoryLimit); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	table := r.FormValue("table")
+	if table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingImportFile)
+		return
+	}
+	defer file.Close()
+
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = importFormatExtensions[strings.ToLower(filepath.Ext(header.Filename))]
+	}
+	mode := r.FormValue("mode")
+	if mode == "" {
+		mode = importModeInsert
+	}
+	if mode != importModeInsert && mode != importModeUpsert && mode != importModeReplace {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidImportMode, mode)
+		return
+	}
+	if !supportsImportMode(session.dbType, mode) {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeImportModeNotSupported, mode)
+		return
+	}
+
+	var reader database.ImportReader
+	switch format {
+	case exportFormatXLSX:
+		reader, err = newExcelImportReader(file, r.FormValue("sheet"))
+	case database.ExportFormatCSV, database.ExportFormatTSV, database.ExportFormatJSON, database.ExportFormatNDJSON:
+		reader, err = database.NewImportReader(format, file)
+	default:
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedImportFormat, format)
+		return
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeReadImportFileFailed, err)
+		return
+	}
+	defer reader.Close()
+
+	columns, err := session.db.GetTableColumns(table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+
+	mapping, errCode, param := parseImportMapping(session.dbType, r.FormValue("mapping"), reader.Columns(), columns)
+	if errCode != "" {
+		writeJSONError(w, http.StatusBadRequest, errCode, param)
+		return
+	}
+
+	if r.FormValue("preview") == "true" {
+		writeImportPreview(w, reader, format, columns, mapping)
+		return
+	}
+	if len(mapping) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptyImportMapping)
+		return
+	}
+
+	job := &importJob{
+		dbType:      session.dbType,
+		table:       table,
+		columns:     columns,
+		mapping:     mapping,
+		emptyAsNull: r.FormValue("emptyAsNull") != "false",
+		schemaless:  isSchemalessDatabase(session.dbType),
+		batches:     map[string]*importBatch{},
+		errors:      []map[string]interface{}{},
+	}
+	if mode == importModeUpsert {
+		if job.keys, param = importKeys(columns, mapping); len(job.keys) == 0 {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeImportKeyRequired, param)
+			return
+		}
+	}
+
+	// 导入不受语句超时限制，客户端断开时停止
+	ctx := r.Context()
+	job.ctx = ctx
+	var tx database.Transaction
+	switch {
+	case session.hasTransaction():
+		// 在会话的事务中执行，失败后停止写入，已导入的行由用户决定提交或回滚；导入期间事务不会因空闲而回滚
+		db, done := s.sessionDB(session, true)
+		defer done()
+		job.atomic = true
+		job.db = func() database.ContextDatabase { return db }
+	case (mode == importModeReplace || r.FormValue("atomic") == "true") && supportsChangesetTransaction(session):
+		tx, err = session.db.(database.TransactionalDatabase).BeginTx(ctx)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+			return
+		}
+		txDB := database.WithTransaction(session.db, tx)
+		job.atomic = true
+		job.db = func() database.ContextDatabase { return txDB }
+	default:
+		db := database.AsContextDatabase(session.db)
+		job.db = func() database.ContextDatabase { return db }
+	}
+	// rollback 回滚新开启的事务
+	rollback := func() {
+		if tx != nil {
+			if err := tx.Rollback(); err != nil {
+				s.getLogger().Warn(ctx, "Failed to roll back import: %v", err)
+			}
+		}
+	}
+
+	if mode == importModeReplace {
+		// 事务中使用 DELETE 清空表，不支持事务的数据库使用 TRUNCATE；按实际执行的语句校验
+		query, err := database.BuildTruncateTableSQL(session.dbType, table, job.atomic)
+		if err == nil {
+			err = s.validateSQL(query, strings.Fields(query)[0])
+		}
+		if err != nil {
+			rollback()
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+			return
+		}
+		started := time.Now()
+		err = truncateTable(ctx, job.db(), table)
+		s.auditStatement(r, session, AuditSourceImport, database.ClassifySQL(session.dbType, query), query, nil, started, 0, err)
+		if err != nil {
+			rollback()
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeImportFailed), err)
+			return
+		}
+	}
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			job.reject(reader.Line(), "", ErrCodeReadImportFileFailed, err)
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		job.add(record, reader.Line())
+	}
+	job.flushAll()
+
+	committed := !job.atomic
+	if tx != nil {
+		if job.failed || ctx.Err() != nil {
+			rollback()
+		} else if err := tx.Commit(); err != nil {
+			job.imported = 0
+			s.auditImport(r, session, job, mode, header.Filename, err)
+			writeJSONError(w, http.StatusInternalServerError, ErrCodeCommitTransactionFailed, err)
+			return
+		} else {
+			committed = true
+		}
+	}
+	if tx != nil && !committed {
+		// 事务回滚后没有行生效
+		job.imported = 0
+	}
+	s.auditImport(r, session, job, mode, header.Filename, ctx.Err())
+	if err := ctx.Err(); err != nil {
+		s.getLogger().Warn(ctx, "Import of %s stopped after %d rows: %v", table, job.total, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"mode":       mode,
+		"total":      job.total,
+		"imported":   job.imported,
+		"rejected":   job.rejected,
+		"errors":     job.errors,
+		"moreErrors": job.moreErrors,
+		"atomic":     job.atomic,
+		"committed":  committed,
+	})
+}
+
+// auditImport 把一次导入记录为一条审计记录，影响行数为实际生效的行数（replace 模式的清空表单独记录）
+// 有行被拒绝时记录为失败
+func (s *Server) auditImport(r *http.Request, session *ConnectionSession, job *importJob, mode, filename string, err error) {
+	if err == nil && job.rejected > 0 {
+		err = fmt.Errorf("%d of %d rows rejected", job.rejected, job.total)
+	}
+	operation := "INSERT"
+	if mode == importModeUpsert {
+		operation = "UPSERT"
+	}
+	s.recordAudit(r, session, AuditEntry{
+		Table:        job.table,
+		Operation:    operation,
+		Source:       AuditSourceImport,
+		Statement:    fmt.Sprintf("IMPORT %s INTO %s (mode %s)", filename, job.table, mode),
+		AffectedRows: int64(job.imported),
+	}, err)
+}
+
+// supportsImportMode 判断数据库是否支持导入模式
+// 文档数据库和键值数据库只支持插入，ClickHouse 不支持 upsert
+func supportsImportMode(dbType, mode string) bool {
+	switch mode {
+	case importModeUpsert:
+		return !isSchemalessDatabase(dbType) && dbType != "clickhouse"
+	case importModeReplace:
+		return !isSchemalessDatabase(dbType)
+	}
+	return true
+}
+
+// isSchemalessDatabase 判断数据库的字段是否不固定（不按列信息校验和转换导入的值）
+func isSchemalessDatabase(dbType string) bool {
+	switch dbType {
+	case "mongodb", "elasticsearch", "redis":
+		return true
+	}
+	return false
+}
+
+// importColumn 文件列到表列的映射
+type importColumn struct {
+	Source string              `json:"source"`
+	Target database.ColumnInfo `json:"target"`
+}
+
+// parseImportMapping 解析文件列到表列的映射，返回按文件列顺序排列的映射，出错时返回错误代码和附加参数
+// 没有提供映射时按列名匹配（先精确匹配，再忽略大小写）；字段不固定的数据库没有匹配的列按原名导入
+func parseImportMapping(dbType, raw string, fileColumns []string, columns []database.ColumnInfo) ([]importColumn, string, string) {
+	byName := make(map[string]database.ColumnInfo, len(columns))
+	byLower := make(map[string]database.ColumnInfo, len(columns))
+	for _, col := range columns {
+		byName[col.Name] = col
+		if _, ok := byLower[strings.ToLower(col.Name)]; !ok {
+			byLower[strings.ToLower(col.Name)] = col
+		}
+	}
+
+	targets := map[string]string{}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &targets); err != nil {
+			return nil, ErrCodeInvalidImportMapping, err.Error()
+		}
+	} else {
+		for _, name := range fileColumns {
+			if col, ok := byName[name]; ok {
+				targets[name] = col.Name
+			} else if col, ok := byLower[strings.ToLower(name)]; ok {
+				targets[name] = col.Name
+			} else if isSchemalessDatabase(dbType) {
+				targets[name] = name
+			}
+		}
+	}
+
+	var mapping []importColumn
+	used := map[string]bool{}
+	for _, name := range fileColumns {
+		target, ok := targets[name]
+		if !ok || target == "" {
+			continue
+		}
+		col, ok := byName[target]
+		if !ok {
+			if !isSchemalessDatabase(dbType) {
+				return nil, ErrCodeUnknownColumn, target
+			}
+			col = database.ColumnInfo{Name: target, Nullable: true}
+		}
+		if used[target] {
+			return nil, ErrCodeInvalidImportMapping, target
+		}
+		used[target] = true
+		mapping = append(mapping, importColumn{Source: name, Target: col})
+	}
+	return mapping, "", ""
+}
+
+// importKeys 返回 upsert 使用的主键列，表没有主键或主键列没有全部映射时返回 nil 和缺少的列
+func importKeys(columns []database.ColumnInfo, mapping []importColumn) ([]string, string) {
+	mapped := map[string]bool{}
+	for _, m := range mapping {
+		mapped[m.Target.Name] = true
+	}
+	var keys []string
+	for _, col := range columns {
+		if col.Key != "PRI" {
+			continue
+		}
+		if !mapped[col.Name] {
+			return nil, col.Name
+		}
+		keys = append(keys, col.Name)
+	}
+	return keys, ""
+}
+
+// writeImportPreview 返回文件的列、列映射和前几条记录
+func writeImportPreview(w http.ResponseWriter, reader database.ImportReader, format string, columns []database.ColumnInfo, mapping []importColumn) {
+	rows := []map[string]interface{}{}
+	for len(rows) < importPreviewRows {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeReadImportFileFailed, fmt.Sprintf("line %d: %v", reader.Line(), err))
+			return
+		}
+		rows = append(rows, record)
+	}
+	targets := make(map[string]string, len(mapping))
+	for _, m := range mapping {
+		targets[m.Source] = m.Target.Name
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		"format":      format,
+		"fileColumns": reader.Columns(),
+		"columns":     columns,
+		"mapping":     targets,
+		"rows":        rows,
+	})
+}
+
+// importBatch 列相同的一批待插入行
+type importBatch struct {
+	columns []string
+	rows    [][]interface{}
+	lines   []int
+}
+
+// importJob 一次导入的状态
+type importJob struct {
+	ctx         context.Context
+	db          func() database.ContextDatabase
+	dbType      string
+	table       string
+	columns     []database.ColumnInfo
+	mapping     []importColumn
+	keys        []string
+	emptyAsNull bool
+	schemaless  bool
+	atomic      bool // 任一行失败即停止写入（由调用方回滚）
+
+	batches  map[string]*importBatch
+	total    int
+	imported int
+	rejected int
+	failed   bool
+	errors   []map[string]interface{}
+
+	moreErrors bool // 被拒绝的行超过 maxImportErrors 条，部分原因没有返回
+}
+
+// add 校验并转换一条记录，加入列相同的批次，批次满时插入
+// 值为 NULL 的不可为空列有默认值（或自增）时不插入该列，使用数据库默认值
+func (j *importJob) add(record map[string]interface{}, line int) {
+	j.total++
+	names := make([]string, 0, len(j.mapping))
+	values := make([]interface{}, 0, len(j.mapping))
+	for _, m := range j.mapping {
+		raw, ok := record[m.Source]
+		if !ok {
+			// 记录中没有该字段（JSON）时使用默认值
+			continue
+		}
+		if str, isString := raw.(string); isString && str == "" && j.emptyAsNull {
+			raw = nil
+		}
+
+		var value interface{}
+		if j.schemaless {
+			value = normalizeJSONNumbers(map[string]interface{}{"v": raw})["v"]
+		} else {
+			var err error
+			if value, err = database.CoerceImportValue(m.Target, raw); err != nil {
+				j.reject(line, m.Target.Name, ErrCodeImportInvalidValue, err)
+				return
+			}
+			if value == nil && !m.Target.Nullable {
+				if m.Target.AutoIncrement || m.Target.DefaultValue != "" {
+					continue
+				}
+				j.reject(line, m.Target.Name, ErrCodeColumnNotNullable, nil)
+				return
+			}
+		}
+		names = append(names, m.Target.Name)
+		values = append(values, value)
+	}
+	if len(names) == 0 {
+		j.reject(line, "", ErrCodeEmptyImportRow, nil)
+		return
+	}
+	if len(j.keys) > 0 {
+		present := map[string]bool{}
+		for _, name := range names {
+			present[name] = true
+		}
+		for _, key := range j.keys {
+			if !present[key] {
+				j.reject(line, key, ErrCodeRowKeyRequired, nil)
+				return
+			}
+		}
+	}
+
+	signature := strings.Join(names, "\x00")
+	batch, ok := j.batches[signature]
+	if !ok {
+		batch = &importBatch{columns: names}
+		j.batches[signature] = batch
+	}
+	batch.rows = append(batch.rows, values)
+	batch.lines = append(batch.lines, line)
+	if len(batch.rows) >= database.ImportBatchSize(j.dbType, len(names)) {
+		j.flush(batch)
+	}
+}
+
+// flushAll 插入所有未满的批次
+func (j *importJob) flushAll() {
+	for _, batch := range j.batches {
+		j.flush(batch)
+	}
+}
+
+// flush 插入一个批次
+// 驱动不支持批量插入时逐行插入；非原子导入时批量插入失败则逐行重试，找出出错的行
+func (j *importJob) flush(batch *importBatch) {
+	rows, lines := batch.rows, batch.lines
+	batch.rows, batch.lines = nil, nil
+	if len(rows) == 0 || (j.atomic && j.failed) || j.ctx.Err() != nil {
+		// 原子导入已失败时后面的行只校验不写入
+		return
+	}
+
+	db := j.db()
+	_, bulk := db.(database.BulkInsertDatabase)
+	if bulk || j.atomic {
+		n, err := insertRows(j.ctx, db, j.table, j.columns, batch.columns, rows, j.keys)
+		if err == nil {
+			j.imported += len(rows)
+			return
+		}
+		errCode := queryErrorCode(j.ctx, err, ErrCodeImportFailed)
+		switch {
+		case !bulk:
+			// 逐行插入在第 n 行失败
+			j.reject(lines[n], "", errCode, err)
+			return
+		case j.atomic:
+			// 事务中出错后（如 PostgreSQL）不能继续执行语句，整批报告为一条失败
+			j.rejectBatch(lines, errCode, err)
+			return
+		case len(rows) == 1:
+			j.reject(lines[0], "", errCode, err)
+			return
+		}
+	}
+
+	for i, row := range rows {
+		if _, err := insertRows(j.ctx, db, j.table, j.columns, batch.columns, [][]interface{}{row}, j.keys); err != nil {
+			j.reject(lines[i], "", queryErrorCode(j.ctx, err, ErrCodeImportFailed), err)
+			continue
+		}
+		j.imported++
+	}
+}
+
+// rejectBatch 记录整批插入失败，rowEnd 为批次最后一行的位置
+func (j *importJob) rejectBatch(lines []int, errCode string, err error) {
+	j.reject(lines[0], "", errCode, err)
+	j.rejected += len(lines) - 1
+	if last := len(j.errors) - 1; last >= 0 && j.errors[last]["row"] == lines[0] && len(lines) > 1 {
+		j.errors[last]["rowEnd"] = lines[len(lines)-1]
+	}
+}
+
+// reject 记录被拒绝的行，最多保留 maxImportErrors 条原因
+func (j *importJob) reject(line int, column, errCode string, err error) {
+	j.rejected++
+	j.failed = true
+	if len(j.errors) >= maxImportErrors {
+		j.moreErrors = true
+		return
+	}
+	result := map[string]interface{}{"row": line, "errorCode": errCode}
+	if column != "" {
+		result["column"] = column
+	}
+	if err != nil {
+		result["error"] = err.Error()
+	}
+	j.errors = append(j.errors, result)
+}
+
+// insertRows 批量插入行，驱动未实现 database.BulkInsertDatabase 时逐行插入（不支持 upsert）
+func insertRows(ctx context.Context, db database.ContextDatabase, table string, columns []database.ColumnInfo, names []string, rows [][]interface{}, keys []string) (int64, error) {
+	if bi, ok := db.(database.BulkInsertDatabase); ok {
+		return bi.InsertRows(ctx, table, names, rows, keys)
+	}
+	if len(keys) > 0 {
+		return 0, fmt.Errorf("upsert is not supported")
+	}
+	for i, row := range rows {
+		values := make(map[string]interface{}, len(names))
+		for j, name := range names {
+			values[name] = row[j]
+		}
+		if _, err := insertRow(ctx, db, table, columns, values); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(rows)), nil
+}
+
+// truncateTable 清空表，驱动未实现 database.TableTruncateDatabase 时退化为 DELETE 语句
+func truncateTable(ctx context.Context, db database.ContextDatabase, table string) error {
+	if tt, ok := db.(database.TableTruncateDatabase); ok {
+		return tt.TruncateTable(ctx, table)
+	}
+	_, err := db.ExecuteDeleteContext(ctx, fmt.Sprintf("DELETE FROM `%s`", table))
+	return err
+}
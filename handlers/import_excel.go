@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gotoailab/simple-db-web/database"
+	"github.com/xuri/excelize/v2"
+)
+
+// excelImportReader 逐行读取 Excel 工作表，实现 database.ImportReader
+// 第一行为列名；单元格读取原始值（不应用数字格式），日期为 Excel 序列号，按列类型转换
+type excelImportReader struct {
+	file    *excelize.File
+	rows    *excelize.Rows
+	columns []string
+	line    int
+}
+
+// newExcelImportReader 打开工作表，sheet 为空时读取第一个工作表
+func newExcelImportReader(r io.Reader, sheet string) (*excelImportReader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if sheet == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			f.Close()
+			return nil, fmt.Errorf("workbook has no sheets")
+		}
+		sheet = sheets[0]
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	e := &excelImportReader{file: f, rows: rows}
+	if !rows.Next() {
+		e.Close()
+		return nil, fmt.Errorf("sheet %s is empty", sheet)
+	}
+	e.line = 1
+	header, err := rows.Columns(excelize.Options{RawCellValue: true})
+	if err != nil {
+		e.Close()
+		return nil, err
+	}
+	e.columns = make([]string, len(header))
+	for i, name := range header {
+		e.columns[i] = strings.TrimSpace(name)
+	}
+	return e, nil
+}
+
+func (e *excelImportReader) Columns() []string {
+	return e.columns
+}
+
+func (e *excelImportReader) Next() (map[string]interface{}, error) {
+	for e.rows.Next() {
+		e.line++
+		cells, err := e.rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+		if row := database.ImportRecord(e.columns, cells); row != nil {
+			return row, nil
+		}
+	}
+	if err := e.rows.Error(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (e *excelImportReader) Line() int {
+	return e.line
+}
+
+func (e *excelImportReader) Close() error {
+	e.rows.Close()
+	return e.file.Close()
+}
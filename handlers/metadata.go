@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// metadataDatabase 返回支持结构化元数据的 Database；经代理连接时使用被代理的驱动（其连接已经通过代理建立）
+func metadataDatabase(db database.Database) (database.MetadataDatabase, bool) {
+	if proxy, ok := db.(*ProxyDatabaseWrapper); ok {
+		db = proxy.db
+	}
+	mdb, ok := db.(database.MetadataDatabase)
+	return mdb, ok
+}
+
+// serveMetadata 元数据接口的公共部分：校验连接、取得 MetadataDatabase 并在语句超时内执行 fn，
+// 成功时把 fn 的返回值以 key 写入响应
+func (s *Server) serveMetadata(w http.ResponseWriter, r *http.Request, key string, fn func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error)) {
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	mdb, ok := metadataDatabase(session.db)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMetadataNotSupported, session.dbType)
+		return
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	result, err := fn(ctx, session, mdb)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetMetadataFailed), err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		key:       result,
+	})
+}
+
+// GetTableMetadata 获取表的结构化元数据：列、注释、索引、外键和检查约束（table 查询参数）
+func (s *Server) GetTableMetadata(w http.ResponseWriter, r *http.Request) {
+	tableName := r.URL.Query().Get("table")
+	if tableName == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingTableName)
+		return
+	}
+	s.serveMetadata(w, r, "table", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		return mdb.GetTableMetadata(ctx, tableName)
+	})
+}
+
+// GetViews 获取当前数据库中的视图及其定义
+func (s *Server) GetViews(w http.ResponseWriter, r *http.Request) {
+	s.serveMetadata(w, r, "views", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		return mdb.GetViews(ctx)
+	})
+}
+
+// GetRoutines 获取当前数据库中的存储过程和函数
+func (s *Server) GetRoutines(w http.ResponseWriter, r *http.Request) {
+	s.serveMetadata(w, r, "routines", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		return mdb.GetRoutines(ctx)
+	})
+}
+
+// GetTriggers 获取触发器，指定 table 查询参数时只返回该表的触发器
+func (s *Server) GetTriggers(w http.ResponseWriter, r *http.Request) {
+	tableName := r.URL.Query().Get("table")
+	s.serveMetadata(w, r, "triggers", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		return mdb.GetTriggers(ctx, tableName)
+	})
+}
+
+// GetERDiagram 由外键生成当前数据库的实体关系图，返回图的 JSON 以及 Mermaid 和 Graphviz DOT 文本
+// 查询参数：tables 为逗号分隔的表名，指定时只包含这些表以及 depth 跳以内（默认 1）与它们有外键关系的表
+func (s *Server) GetERDiagram(w http.ResponseWriter, r *http.Request) {
+	var focus []string
+	for _, name := range strings.Split(r.URL.Query().Get("tables"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			focus = append(focus, name)
+		}
+	}
+	depth := 1
+	if value := r.URL.Query().Get("depth"); value != "" {
+		depth, _ = strconv.Atoi(value)
+	}
+
+	s.serveMetadata(w, r, "diagram", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		tables, err := session.db.GetTables()
+		if err != nil {
+			return nil, err
+		}
+		diagram, err := database.LoadERDiagram(ctx, mdb, tables, focus, depth)
+		if err != nil {
+			return nil, err
+		}
+		return struct {
+			*database.ERDiagram
+			Mermaid string `json:"mermaid"`
+			DOT     string `json:"dot"`
+		}{diagram, diagram.Mermaid(), diagram.DOT()}, nil
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"sync"
@@ -119,6 +120,126 @@ func (p *ProxyDatabaseWrapper) GetTableDataByID(tableName string, primaryKey str
 	return p.db.GetTableDataByID(tableName, primaryKey, lastId, pageSize, direction, filters)
 }
 
+func (p *ProxyDatabaseWrapper) ExecuteQueryContext(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	return database.AsContextDatabase(p.db).ExecuteQueryContext(ctx, query)
+}
+
+func (p *ProxyDatabaseWrapper) ExecuteUpdateContext(ctx context.Context, query string) (int64, error) {
+	return database.AsContextDatabase(p.db).ExecuteUpdateContext(ctx, query)
+}
+
+func (p *ProxyDatabaseWrapper) ExecuteDeleteContext(ctx context.Context, query string) (int64, error) {
+	return database.AsContextDatabase(p.db).ExecuteDeleteContext(ctx, query)
+}
+
+func (p *ProxyDatabaseWrapper) ExecuteInsertContext(ctx context.Context, query string) (int64, error) {
+	return database.AsContextDatabase(p.db).ExecuteInsertContext(ctx, query)
+}
+
+func (p *ProxyDatabaseWrapper) GetTableDataContext(ctx context.Context, tableName string, page, pageSize int, filters *database.FilterGroup) ([]map[string]interface{}, int64, error) {
+	return database.AsContextDatabase(p.db).GetTableDataContext(ctx, tableName, page, pageSize, filters)
+}
+
+func (p *ProxyDatabaseWrapper) GetTableDataByIDContext(ctx context.Context, tableName string, primaryKey string, lastId interface{}, pageSize int, direction string, filters *database.FilterGroup) ([]map[string]interface{}, int64, interface{}, error) {
+	return database.AsContextDatabase(p.db).GetTableDataByIDContext(ctx, tableName, primaryKey, lastId, pageSize, direction, filters)
+}
+
+func (p *ProxyDatabaseWrapper) QueryRows(ctx context.Context, query string) (database.RowIterator, error) {
+	return database.StreamQuery(ctx, p.db, query)
+}
+
+func (p *ProxyDatabaseWrapper) StreamTableRows(ctx context.Context, tableName string, filters *database.FilterGroup, sorts []database.SortSpec) (database.RowIterator, error) {
+	if tdb, ok := p.db.(database.TableStreamingDatabase); ok {
+		return tdb.StreamTableRows(ctx, tableName, filters, sorts)
+	}
+	// 被代理的数据库不支持流式读取时按页读取，列顺序与 GetTableColumns 一致
+	columns, err := p.db.GetTableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return database.StreamTableRows(ctx, p.db, tableName, names, filters, sorts)
+}
+
+func (p *ProxyDatabaseWrapper) BeginTx(ctx context.Context) (database.Transaction, error) {
+	if tdb, ok := p.db.(database.TransactionalDatabase); ok {
+		return tdb.BeginTx(ctx)
+	}
+	return nil, fmt.Errorf("%s does not support transactions", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) PinConn(ctx context.Context) (database.PinnedConn, error) {
+	if cdb, ok := p.db.(database.ConnPinningDatabase); ok {
+		return cdb.PinConn(ctx)
+	}
+	return nil, fmt.Errorf("%s does not support pinned connections", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) QueryArgsContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if pdb, ok := p.db.(database.ParameterizedDatabase); ok {
+		return pdb.QueryArgsContext(ctx, query, args...)
+	}
+	return nil, fmt.Errorf("%s does not support parameterized statements", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) ExecArgsContext(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	if pdb, ok := p.db.(database.ParameterizedDatabase); ok {
+		return pdb.ExecArgsContext(ctx, query, args...)
+	}
+	return 0, fmt.Errorf("%s does not support parameterized statements", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) UpdateRows(ctx context.Context, tableName string, keys, values map[string]interface{}) (int64, error) {
+	return updateRows(ctx, database.AsContextDatabase(p.db), tableName, keys, values)
+}
+
+func (p *ProxyDatabaseWrapper) DeleteRows(ctx context.Context, tableName string, keys map[string]interface{}) (int64, error) {
+	return deleteRows(ctx, database.AsContextDatabase(p.db), tableName, keys)
+}
+
+func (p *ProxyDatabaseWrapper) SnapshotRows(ctx context.Context, tableName string, keys map[string]interface{}) ([]map[string]interface{}, error) {
+	if rs, ok := p.db.(database.RowSnapshotDatabase); ok {
+		return rs.SnapshotRows(ctx, tableName, keys)
+	}
+	return nil, fmt.Errorf("%s does not support row snapshots", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) InsertRow(ctx context.Context, tableName string, columns []database.ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	return insertRow(ctx, database.AsContextDatabase(p.db), tableName, columns, values)
+}
+
+func (p *ProxyDatabaseWrapper) InsertRows(ctx context.Context, tableName string, columns []string, rows [][]interface{}, keys []string) (int64, error) {
+	return insertRows(ctx, database.AsContextDatabase(p.db), tableName, nil, columns, rows, keys)
+}
+
+func (p *ProxyDatabaseWrapper) TruncateTable(ctx context.Context, tableName string) error {
+	return truncateTable(ctx, database.AsContextDatabase(p.db), tableName)
+}
+
+func (p *ProxyDatabaseWrapper) CreateTable(ctx context.Context, tableName string, columns []database.ColumnInfo) error {
+	return createTable(ctx, database.AsContextDatabase(p.db), p.db.GetTypeName(), tableName, columns)
+}
+
+func (p *ProxyDatabaseWrapper) GetTableDataSorted(ctx context.Context, tableName string, page, pageSize int, filters *database.FilterGroup, sorts []database.SortSpec) ([]map[string]interface{}, int64, error) {
+	if sdb, ok := p.db.(database.SortableDatabase); ok {
+		return sdb.GetTableDataSorted(ctx, tableName, page, pageSize, filters, sorts)
+	}
+	if len(sorts) == 0 {
+		return database.AsContextDatabase(p.db).GetTableDataContext(ctx, tableName, page, pageSize, filters)
+	}
+	return nil, 0, fmt.Errorf("%s does not support sorting", p.db.GetDisplayName())
+}
+
+func (p *ProxyDatabaseWrapper) GetTableDataByCursor(ctx context.Context, tableName string, primaryKey string, cursor []interface{}, pageSize int, direction string, filters *database.FilterGroup, sorts []database.SortSpec) ([]map[string]interface{}, int64, error) {
+	if sdb, ok := p.db.(database.SortableDatabase); ok {
+		return sdb.GetTableDataByCursor(ctx, tableName, primaryKey, cursor, pageSize, direction, filters, sorts)
+	}
+	return nil, 0, fmt.Errorf("%s does not support cursor-based pagination", p.db.GetDisplayName())
+}
+
 func (p *ProxyDatabaseWrapper) GetPageIdByPageNumber(tableName string, primaryKey string, page, pageSize int) (interface{}, error) {
 	return p.db.GetPageIdByPageNumber(tableName, primaryKey, page, pageSize)
 }
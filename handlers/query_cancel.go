@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// runningQueryKey 正在执行的查询的键：查询ID由客户端提供，只在同一连接内唯一
+type runningQueryKey struct {
+	connectionID string
+	queryID      string
+}
+
+// runningQuery 正在执行的查询
+type runningQuery struct {
+	startedAt time.Time
+	cancel    context.CancelFunc
+}
+
+// errQueryIDInUse 客户端提供的查询ID在该连接上已有正在执行的查询
+var errQueryIDInUse = errors.New("query id is already in use")
+
+// SetQueryTimeout 设置默认的语句超时时间
+// 连接信息中的 query_timeout 优先于该值，0 表示不限制
+// 示例：
+//
+//	server.SetQueryTimeout(30 * time.Second)
+func (s *Server) SetQueryTimeout(timeout time.Duration) {
+	s.queryTimeoutMutex.Lock()
+	defer s.queryTimeoutMutex.Unlock()
+	s.queryTimeout = timeout
+}
+
+// getQueryTimeout 获取会话的语句超时时间（连接级配置优先）
+func (s *Server) getQueryTimeout(session *ConnectionSession) time.Duration {
+	if session != nil && session.sessionData != nil && session.sessionData.ConnectionInfo.QueryTimeout > 0 {
+		return time.Duration(session.sessionData.ConnectionInfo.QueryTimeout) * time.Second
+	}
+	s.queryTimeoutMutex.RLock()
+	defer s.queryTimeoutMutex.RUnlock()
+	return s.queryTimeout
+}
+
+// queryContext 基于请求上下文创建带语句超时的执行上下文
+// 客户端断开连接时请求上下文会被取消，正在执行的语句随之中止
+func (s *Server) queryContext(r *http.Request, session *ConnectionSession) (context.Context, context.CancelFunc) {
+	if timeout := s.getQueryTimeout(session); timeout > 0 {
+		return context.WithTimeout(r.Context(), timeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+// startQuery 注册一个可通过 /api/query/cancel 取消的查询
+// queryID 为空时自动生成，返回执行上下文、最终使用的查询ID和结束函数；
+// 客户端提供的 queryID 在该连接上已有正在执行的查询时返回 errQueryIDInUse，不替换为其他ID，
+// 否则客户端会用自己的ID取消到别的查询或者取消不到
+func (s *Server) startQuery(r *http.Request, connectionID, queryID string, session *ConnectionSession) (context.Context, string, func(), error) {
+	s.runningQueriesMutex.Lock()
+	defer s.runningQueriesMutex.Unlock()
+
+	key := runningQueryKey{connectionID: connectionID, queryID: queryID}
+	if queryID == "" {
+		for {
+			// 复用连接ID的生成方式，生成失败时退化为时间戳
+			if id, err := generateConnectionID(); err == nil {
+				key.queryID = id
+			} else {
+				key.queryID = time.Now().Format("20060102150405.000000000")
+			}
+			if _, exists := s.runningQueries[key]; !exists {
+				break
+			}
+		}
+	} else if _, exists := s.runningQueries[key]; exists {
+		return nil, "", nil, errQueryIDInUse
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	s.runningQueries[key] = &runningQuery{
+		startedAt: time.Now(),
+		cancel:    cancel,
+	}
+
+	finish := func() {
+		s.runningQueriesMutex.Lock()
+		delete(s.runningQueries, key)
+		s.runningQueriesMutex.Unlock()
+		cancel()
+	}
+	return ctx, key.queryID, finish, nil
+}
+
+// queryErrorCode 根据上下文状态返回错误代码
+// 查询被取消或超时时返回对应的错误代码，否则返回 fallback
+func queryErrorCode(ctx context.Context, err error, fallback string) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrCodeQueryTimeout
+	}
+	if errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled) {
+		return ErrCodeQueryCancelled
+	}
+	return fallback
+}
+
+// CancelQuery 取消正在执行的查询
+func (s *Server) CancelQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		QueryID string `json:"queryId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	if req.QueryID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingQueryID)
+		return
+	}
+
+	// 只能取消属于当前连接的查询
+	s.runningQueriesMutex.Lock()
+	running, exists := s.runningQueries[runningQueryKey{connectionID: connectionID, queryID: req.QueryID}]
+	s.runningQueriesMutex.Unlock()
+
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, ErrCodeQueryNotFound)
+		return
+	}
+
+	running.cancel()
+	s.getLogger().Info(r.Context(), "Query %s cancelled after %v", req.QueryID, time.Since(running.startedAt))
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"queryId": req.QueryID,
+	})
+}
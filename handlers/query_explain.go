@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// explainDatabase 返回支持执行计划的 Database；经代理连接时使用被代理的驱动
+func explainDatabase(db database.Database) (database.ExplainDatabase, bool) {
+	if proxy, ok := db.(*ProxyDatabaseWrapper); ok {
+		db = proxy.db
+	}
+	edb, ok := db.(database.ExplainDatabase)
+	return edb, ok
+}
+
+// ExplainQuery 获取单条查询的执行计划，各数据库的输出统一为算子树（见 database.QueryPlan）
+// 请求体：{"query": "...", "analyze": false, "queryId": ""}
+// analyze 为 true 时实际执行查询以得到实际行数和耗时，只允许用于只读语句；语句超时和 /api/query/cancel 同样适用
+func (s *Server) ExplainQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		Query   string `json:"query"`
+		Analyze bool   `json:"analyze"`
+		QueryID string `json:"queryId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	edb, ok := explainDatabase(session.db)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeExplainNotSupported, session.dbType)
+		return
+	}
+
+	query := strings.TrimSpace(req.Query)
+	// MongoDB 使用自己的查询语法，不按 SQL 拆分和校验
+	if session.dbType != "mongodb" {
+		statements := database.SplitSQLScript(session.dbType, query)
+		if len(statements) > 1 {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeExplainMultipleStatements)
+			return
+		}
+		if len(statements) == 1 {
+			query = statements[0]
+		}
+	}
+	if query == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptySQLQuery)
+		return
+	}
+	if session.dbType != "mongodb" {
+		stmt := database.ClassifySQL(session.dbType, query)
+		if err := s.validateSQL(query, stmt.Keyword); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+			return
+		}
+		if req.Analyze && stmt.Kind != database.StatementRead {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeExplainAnalyzeReadOnly)
+			return
+		}
+	}
+
+	ctx, queryID, finish, err := s.startQuery(r, connectionID, req.QueryID, session)
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, ErrCodeQueryIDInUse)
+		return
+	}
+	defer finish()
+	plan, err := edb.ExplainQuery(ctx, query, req.Analyze)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExplainFailed), err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"plan":    plan,
+		"queryId": queryID,
+	})
+}
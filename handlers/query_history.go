@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxQueryHistoryText 查询历史中保存的语句最大长度（字节），超出部分截断
+const maxQueryHistoryText = 64 * 1024
+
+// defaultQueryHistoryRetention 默认的查询历史保留策略
+var defaultQueryHistoryRetention = QueryHistoryRetention{MaxEntries: 500, MaxAge: 30 * 24 * time.Hour}
+
+// QueryHistoryEntry 一条查询历史（/api/query 的一次执行）
+type QueryHistoryEntry struct {
+	ID             int64     `json:"id"`
+	User           string    `json:"user,omitempty"` // 执行查询的用户（见 SetUserResolver），未设置时为空
+	ConnectionName string    `json:"connectionName"`
+	DbType         string    `json:"dbType"`
+	Database       string    `json:"database"`
+	Query          string    `json:"query"`
+	DurationMs     int64     `json:"durationMs"`
+	RowCount       int64     `json:"rowCount"` // 返回的行数，写语句为影响的行数
+	Success        bool      `json:"success"`
+	Error          string    `json:"error,omitempty"`
+	ExecutedAt     time.Time `json:"executedAt"`
+}
+
+// QueryHistoryFilter 查询历史的搜索条件
+type QueryHistoryFilter struct {
+	User           string // 只返回该用户的记录
+	ConnectionName string // 连接名称（可选，精确匹配）
+	Search         string // 语句包含的文本（可选，不区分大小写）
+	Status         string // success 或 error（可选）
+	Offset         int
+	Limit          int
+}
+
+// Match 判断记录是否符合搜索条件（不考虑分页）
+func (f QueryHistoryFilter) Match(entry QueryHistoryEntry) bool {
+	if entry.User != f.User {
+		return false
+	}
+	if f.ConnectionName != "" && entry.ConnectionName != f.ConnectionName {
+		return false
+	}
+	if f.Search != "" && !strings.Contains(strings.ToLower(entry.Query), strings.ToLower(f.Search)) {
+		return false
+	}
+	switch f.Status {
+	case "success":
+		return entry.Success
+	case "error":
+		return !entry.Success
+	}
+	return true
+}
+
+// QueryHistoryRetention 查询历史的保留策略，0 表示不限制
+type QueryHistoryRetention struct {
+	MaxEntries int           // 每个用户最多保留的条数
+	MaxAge     time.Duration // 最长保留时间
+}
+
+// QueryHistoryStore 查询历史存储接口
+// 允许外部项目实现自定义的持久化存储，保留策略由存储自行执行
+type QueryHistoryStore interface {
+	// Add 保存一条记录，ID 由存储分配
+	Add(entry QueryHistoryEntry) error
+
+	// Search 按执行时间倒序返回符合条件的一页记录，以及符合条件的总数
+	Search(filter QueryHistoryFilter) ([]QueryHistoryEntry, int, error)
+
+	// Delete 删除用户的记录，ids 为空时删除该用户的全部记录
+	Delete(user string, ids []int64) error
+}
+
+// MemoryQueryHistoryStore 内存查询历史存储（默认实现），服务重启后丢失
+type MemoryQueryHistoryStore struct {
+	entries   []QueryHistoryEntry // 按执行时间升序
+	nextID    int64
+	retention QueryHistoryRetention
+	mutex     sync.RWMutex
+}
+
+// NewMemoryQueryHistoryStore 创建内存查询历史存储
+func NewMemoryQueryHistoryStore(retention QueryHistoryRetention) *MemoryQueryHistoryStore {
+	return &MemoryQueryHistoryStore{retention: retention}
+}
+
+// Add 保存一条记录，并按保留策略清理旧记录
+func (m *MemoryQueryHistoryStore) Add(entry QueryHistoryEntry) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.nextID++
+	entry.ID = m.nextID
+	m.entries = append(m.entries, entry)
+	m.prune(time.Now())
+	return nil
+}
+
+// prune 删除过期的记录和每个用户超出条数限制的最旧记录
+func (m *MemoryQueryHistoryStore) prune(now time.Time) {
+	keep := make([]bool, len(m.entries))
+	counts := make(map[string]int)
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		entry := m.entries[i]
+		if m.retention.MaxAge > 0 && now.Sub(entry.ExecutedAt) > m.retention.MaxAge {
+			continue
+		}
+		counts[entry.User]++
+		keep[i] = m.retention.MaxEntries <= 0 || counts[entry.User] <= m.retention.MaxEntries
+	}
+	kept := m.entries[:0]
+	for i, entry := range m.entries {
+		if keep[i] {
+			kept = append(kept, entry)
+		}
+	}
+	m.entries = kept
+}
+
+// Search 按执行时间倒序返回符合条件的一页记录
+func (m *MemoryQueryHistoryStore) Search(filter QueryHistoryFilter) ([]QueryHistoryEntry, int, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	result := make([]QueryHistoryEntry, 0)
+	total := 0
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if !filter.Match(m.entries[i]) {
+			continue
+		}
+		if total >= filter.Offset && (filter.Limit <= 0 || len(result) < filter.Limit) {
+			result = append(result, m.entries[i])
+		}
+		total++
+	}
+	return result, total, nil
+}
+
+// Delete 删除用户的记录
+func (m *MemoryQueryHistoryStore) Delete(user string, ids []int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	remove := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+	kept := m.entries[:0]
+	for _, entry := range m.entries {
+		if entry.User == user && (len(ids) == 0 || remove[entry.ID]) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	m.entries = kept
+	return nil
+}
+
+// SetQueryHistoryStore 设置查询历史存储，传入 nil 时不再记录查询历史
+// 示例：
+//
+//	server.SetQueryHistoryStore(handlers.NewMemoryQueryHistoryStore(handlers.QueryHistoryRetention{MaxEntries: 1000}))
+func (s *Server) SetQueryHistoryStore(store QueryHistoryStore) {
+	s.queryHistoryMutex.Lock()
+	defer s.queryHistoryMutex.Unlock()
+	s.queryHistory = store
+}
+
+// getQueryHistoryStore 获取查询历史存储（线程安全），未启用时返回 nil
+func (s *Server) getQueryHistoryStore() QueryHistoryStore {
+	s.queryHistoryMutex.RLock()
+	defer s.queryHistoryMutex.RUnlock()
+	return s.queryHistory
+}
+
+// connectionDisplayName 返回会话的连接名称，未命名时使用 用户@主机:端口 或数据库类型
+func connectionDisplayName(session *ConnectionSession) string {
+	if session.sessionData == nil {
+		return session.dbType
+	}
+	info := session.sessionData.ConnectionInfo
+	if info.Name != "" {
+		return info.Name
+	}
+	if info.Host == "" {
+		return session.dbType
+	}
+	name := info.Host
+	if info.Port != "" {
+		name += ":" + info.Port
+	}
+	if info.User != "" {
+		name = info.User + "@" + name
+	}
+	return name
+}
+
+// recordQueryHistory 把一次查询的执行结果写入查询历史，写入失败只记录日志
+func (s *Server) recordQueryHistory(r *http.Request, session *ConnectionSession, query string, started time.Time, rowCount int64, execErr error) {
+	store := s.getQueryHistoryStore()
+	if store == nil {
+		return
+	}
+	query = truncateText(query, maxQueryHistoryText)
+	entry := QueryHistoryEntry{
+		User:           s.requestUser(r),
+		ConnectionName: connectionDisplayName(session),
+		DbType:         session.dbType,
+		Database:       session.currentDatabase,
+		Query:          query,
+		DurationMs:     time.Since(started).Milliseconds(),
+		RowCount:       rowCount,
+		Success:        execErr == nil,
+		ExecutedAt:     started,
+	}
+	if execErr != nil {
+		entry.Error = execErr.Error()
+	}
+	if err := store.Add(entry); err != nil {
+		s.getLogger().Warn(r.Context(), "Failed to record query history: %v", err)
+	}
+}
+
+// truncateText 把文本截断到 max 字节以内，不截断多字节字符
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max]
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// GetQueryHistory 分页搜索当前用户的查询历史
+// 查询参数：search（语句包含的文本）、status（success/error）、current（true 时只返回当前连接的记录）、page、pageSize
+func (s *Server) GetQueryHistory(w http.ResponseWriter, r *http.Request) {
+	store := s.getQueryHistoryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeQueryHistoryDisabled)
+		return
+	}
+
+	params := r.URL.Query()
+	page, _ := strconv.Atoi(params.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(params.Get("pageSize"))
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	if pageSize > 200 {
+		pageSize = 200
+	}
+
+	filter := QueryHistoryFilter{
+		User:   s.requestUser(r),
+		Search: strings.TrimSpace(params.Get("search")),
+		Status: params.Get("status"),
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	}
+	if params.Get("current") == "true" {
+		connectionID := getConnectionID(r)
+		if connectionID == "" {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+			return
+		}
+		session, err := s.getSession(connectionID)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+			return
+		}
+		filter.ConnectionName = connectionDisplayName(session)
+	}
+
+	entries, total, err := store.Search(filter)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeQueryHistoryFailed, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"entries":  entries,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// DeleteQueryHistory 删除当前用户的查询历史
+// 请求体：{"ids": [1, 2]}，ids 为空时清空全部记录
+func (s *Server) DeleteQueryHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+	store := s.getQueryHistoryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeQueryHistoryDisabled)
+		return
+	}
+
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if err := store.Delete(s.requestUser(r), req.IDs); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeQueryHistoryFailed, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// streamQueryBatchSize 流式输出时每批发送的行数
+const streamQueryBatchSize = 500
+
+// streamQuery 以 NDJSON 格式流式输出查询结果
+// 第一行为列信息，之后每行为一批数据，最后一行为结束信息或错误信息：
+//
+//	{"type":"columns","columns":["id","name"],"columnTypes":[...],"queryId":"..."}
+//	{"type":"rows","rows":[[1,"a"],[2,"b"]]}
+//	{"type":"end","count":2}
+//
+// 服务器只保留当前批次的数据，内存占用与结果集大小无关
+// 返回已输出的行数和查询错误（客户端断开不算查询错误）
+func (s *Server) streamQuery(ctx context.Context, w http.ResponseWriter, db database.Database, query, binaryEncoding, queryID string) (int64, error) {
+	iter, err := database.StreamQuery(ctx, db, query)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
+		return 0, err
+	}
+	defer iter.Close()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	send := func(message map[string]interface{}) error {
+		if err := encoder.Encode(message); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	binaryEncoding = database.NormalizeBinaryEncoding(binaryEncoding)
+	if err := send(map[string]interface{}{
+		"type":           "columns",
+		"columns":        iter.Columns(),
+		"columnTypes":    iter.ColumnTypes(),
+		"binaryEncoding": binaryEncoding,
+		"queryId":        queryID,
+	}); err != nil {
+		return 0, nil
+	}
+
+	var count int64
+	batch := make([][]interface{}, 0, streamQueryBatchSize)
+	for iter.Next() {
+		values := iter.Values()
+		database.SetBinaryEncoding(values, binaryEncoding)
+		batch = append(batch, values)
+		count++
+		if len(batch) >= streamQueryBatchSize {
+			if err := send(map[string]interface{}{"type": "rows", "rows": batch}); err != nil {
+				// 客户端已断开，停止读取
+				return count, nil
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := send(map[string]interface{}{"type": "rows", "rows": batch}); err != nil {
+			return count, nil
+		}
+	}
+
+	// 响应头已发送，错误只能作为最后一条消息输出
+	if err := iter.Err(); err != nil {
+		send(map[string]interface{}{
+			"type":      "error",
+			"success":   false,
+			"errorCode": queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed),
+			"message":   err.Error(),
+			"params":    []interface{}{err.Error()},
+		})
+		return count, err
+	}
+
+	send(map[string]interface{}{
+		"type":  "end",
+		"count": count,
+	})
+	return count, nil
+}
+
+// writeResultSet 执行查询并以有序结果集的形式返回，返回结果行数和查询错误
+func (s *Server) writeResultSet(ctx context.Context, w http.ResponseWriter, db database.Database, query, binaryEncoding, queryID string) (int64, error) {
+	rs, err := database.QueryResultSet(ctx, db, query, binaryEncoding)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
+		return 0, err
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":   true,
+		"resultSet": rs,
+		"queryId":   queryID,
+	})
+	return int64(len(rs.Rows)), nil
+}
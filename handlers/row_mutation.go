@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// InsertRow 插入行数据
+// 根据 GetTableColumns 返回的列信息校验提交的列：请求中未出现的列使用数据库默认值（自增/标识列由数据库生成），
+// 不可为 NULL 的列提交 null 时，有默认值则使用默认值，否则报错；返回新行的主键（驱动能够获取时）
+func (s *Server) InsertRow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	var req struct {
+		Table string                 `json:"table"`
+		Data  map[string]interface{} `json:"data"`
+	}
+	if err := decodeJSONWithNumbers(r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.Table == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableNameEmpty)
+		return
+	}
+	values := normalizeJSONNumbers(req.Data)
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	columns, err := session.db.GetTableColumns(req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeGetTableColumnsFailed, err)
+		return
+	}
+
+	if errCode, column := checkInsertValues(session.dbType, columns, values); errCode != "" {
+		writeJSONError(w, http.StatusBadRequest, errCode, column)
+		return
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	db, done := s.sessionDB(session, true)
+	key, err := insertRow(ctx, db, req.Table, columns, values)
+	done()
+	s.auditRowChange(r, session, AuditSourceRow, req.Table, "insert", nil, values, 1, err)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeInsertFailed, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"affected": 1,
+		"key":      key,
+	})
+}
+
+// checkInsertValues 按列信息校验插入的值，返回错误代码和出错的列名
+// 不可为 NULL 但有默认值（或自增）的列提交 null 时从 values 中移除，使用数据库默认值
+func checkInsertValues(dbType string, columns []database.ColumnInfo, values map[string]interface{}) (string, string) {
+	// 文档数据库和键值数据库的字段不固定，不按列信息校验
+	switch dbType {
+	case "mongodb", "elasticsearch", "redis":
+		return "", ""
+	}
+
+	byName := make(map[string]database.ColumnInfo, len(columns))
+	for _, col := range columns {
+		byName[col.Name] = col
+	}
+	for _, name := range sortedColumns(values) {
+		col, ok := byName[name]
+		if !ok {
+			return ErrCodeUnknownColumn, name
+		}
+		if values[name] == nil && !col.Nullable {
+			if !col.AutoIncrement && col.DefaultValue == "" {
+				return ErrCodeColumnNotNullable, name
+			}
+			delete(values, name)
+		}
+	}
+	return "", ""
+}
+
+// decodeJSONWithNumbers 解析请求体，数字保留为 json.Number，避免大整数主键丢失精度
+func decodeJSONWithNumbers(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// normalizeJSONNumbers 将 json.Number 转换为 int64 或 float64
+func normalizeJSONNumbers(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				m[k] = i
+			} else if f, err := n.Float64(); err == nil {
+				m[k] = f
+			} else {
+				m[k] = n.String()
+			}
+		}
+	}
+	return m
+}
+
+// updateRows 按键更新行，驱动需要实现 RowMutationDatabase，使用各自的标识符引用和绑定参数
+func updateRows(ctx context.Context, db database.ContextDatabase, table string, keys, values map[string]interface{}) (int64, error) {
+	rm, ok := db.(database.RowMutationDatabase)
+	if !ok {
+		return 0, fmt.Errorf("%s does not support row updates", db.GetDisplayName())
+	}
+	return rm.UpdateRows(ctx, table, keys, values)
+}
+
+// deleteRows 按键删除行，驱动需要实现 RowMutationDatabase
+func deleteRows(ctx context.Context, db database.ContextDatabase, table string, keys map[string]interface{}) (int64, error) {
+	rm, ok := db.(database.RowMutationDatabase)
+	if !ok {
+		return 0, fmt.Errorf("%s does not support row deletes", db.GetDisplayName())
+	}
+	return rm.DeleteRows(ctx, table, keys)
+}
+
+// insertRow 插入一行，驱动需要实现 RowInsertDatabase
+func insertRow(ctx context.Context, db database.ContextDatabase, table string, columns []database.ColumnInfo, values map[string]interface{}) (map[string]interface{}, error) {
+	ri, ok := db.(database.RowInsertDatabase)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support row inserts", db.GetDisplayName())
+	}
+	return ri.InsertRow(ctx, table, columns, values)
+}
+
+// sortedColumns 返回按名称排序的列名
+func sortedColumns(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+const (
+	rowUndoRetention = time.Hour // 撤销信息的保留时间
+	maxRowUndos      = 200       // 最多保留的撤销信息条数，超出时丢弃最旧的
+	maxRowUndoRows   = 100       // 一次修改超过该行数时不保存旧值，不能撤销
+)
+
+// errRowChanged 行在修改之后又被改动，不能撤销
+var errRowChanged = errors.New("row has changed since the edit")
+
+// rowUndo 一次按键修改行的撤销信息
+type rowUndo struct {
+	connectionID string
+	user         string
+	table        string
+	op           string                   // update 或 delete
+	keys         map[string]interface{}   // 撤销时定位行的键（update 为修改后的键）
+	columns      []string                 // update 修改的列
+	before       []map[string]interface{} // 修改前的行
+	after        []map[string]interface{} // update 修改后的行，用于判断行是否又被改动
+	createdAt    time.Time
+}
+
+// snapshotRows 读取满足 keys 的整行，驱动不支持或行数超过 maxRowUndoRows 时返回 nil
+func (s *Server) snapshotRows(ctx context.Context, db database.ContextDatabase, table string, keys map[string]interface{}) []map[string]interface{} {
+	rs, ok := db.(database.RowSnapshotDatabase)
+	if !ok {
+		return nil
+	}
+	rows, err := rs.SnapshotRows(ctx, table, keys)
+	if err != nil {
+		s.getLogger().Warn(ctx, "Failed to snapshot rows of %s: %v", table, err)
+		return nil
+	}
+	if len(rows) == 0 || len(rows) > maxRowUndoRows {
+		return nil
+	}
+	return rows
+}
+
+// beginRowChange 返回按键修改行使用的数据库：会话中有打开的事务时使用该事务，否则驱动支持事务时开启新事务，
+// 使修改前后读取的行和修改本身在同一事务中完成；finish 根据执行结果提交或回滚新事务，返回最终的错误
+func (s *Server) beginRowChange(ctx context.Context, session *ConnectionSession) (database.ContextDatabase, func(error) error, error) {
+	if session.hasTransaction() || !supportsChangesetTransaction(session) {
+		db, done := s.sessionDB(session, true)
+		return db, func(err error) error { done(); return err }, nil
+	}
+	tx, err := session.db.(database.TransactionalDatabase).BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	finish := func(err error) error {
+		if err == nil {
+			return tx.Commit()
+		}
+		if rbErr := tx.Rollback(); rbErr != nil {
+			s.getLogger().Warn(ctx, "Failed to roll back row change: %v", rbErr)
+		}
+		return err
+	}
+	return database.WithTransaction(session.db, tx), finish, nil
+}
+
+// rowUndoForUpdate 根据修改前后的行生成更新的撤销信息
+// 多行匹配时要求这些行在修改的列上旧值相同，才能用一条 UPDATE 还原
+func rowUndoForUpdate(table string, keys, values map[string]interface{}, before, after []map[string]interface{}) *rowUndo {
+	if before == nil || len(after) != len(before) {
+		return nil
+	}
+	columns := sortedColumns(values)
+	for _, row := range before[1:] {
+		for _, col := range columns {
+			if !reflect.DeepEqual(row[col], before[0][col]) {
+				return nil
+			}
+		}
+	}
+	return &rowUndo{table: table, op: "update", keys: updatedKeys(keys, values), columns: columns, before: before, after: after}
+}
+
+// updatedKeys 返回修改后定位行的键：被修改的键列使用新值
+func updatedKeys(keys, values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(keys))
+	for k, v := range keys {
+		if nv, ok := values[k]; ok {
+			v = nv
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// saveRowUndo 保存撤销信息并返回撤销 ID，同时清理过期和超出条数的记录
+func (s *Server) saveRowUndo(r *http.Request, connectionID string, undo *rowUndo) string {
+	id, err := generateConnectionID()
+	if err != nil {
+		s.getLogger().Warn(r.Context(), "Failed to generate undo ID: %v", err)
+		return ""
+	}
+	undo.connectionID = connectionID
+	undo.user = s.requestUser(r)
+	undo.createdAt = time.Now()
+
+	s.rowUndosMutex.Lock()
+	defer s.rowUndosMutex.Unlock()
+	var oldestID string
+	for uid, u := range s.rowUndos {
+		if time.Since(u.createdAt) > rowUndoRetention {
+			delete(s.rowUndos, uid)
+		} else if oldestID == "" || u.createdAt.Before(s.rowUndos[oldestID].createdAt) {
+			oldestID = uid
+		}
+	}
+	if len(s.rowUndos) >= maxRowUndos && oldestID != "" {
+		delete(s.rowUndos, oldestID)
+	}
+	s.rowUndos[id] = undo
+	return id
+}
+
+// takeRowUndo 取出属于该连接和用户且未过期的撤销信息
+func (s *Server) takeRowUndo(r *http.Request, connectionID, id string) *rowUndo {
+	s.rowUndosMutex.Lock()
+	defer s.rowUndosMutex.Unlock()
+	undo, ok := s.rowUndos[id]
+	if !ok || undo.connectionID != connectionID || undo.user != s.requestUser(r) || time.Since(undo.createdAt) > rowUndoRetention {
+		return nil
+	}
+	delete(s.rowUndos, id)
+	return undo
+}
+
+// putBackRowUndo 撤销失败时放回撤销信息，以便稍后重试
+func (s *Server) putBackRowUndo(id string, undo *rowUndo) {
+	s.rowUndosMutex.Lock()
+	defer s.rowUndosMutex.Unlock()
+	s.rowUndos[id] = undo
+}
+
+// sameRows 判断两组行是否相同（不考虑顺序）
+func sameRows(a, b []map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+	for _, row := range a {
+		found := false
+		for i, other := range b {
+			if !used[i] && reflect.DeepEqual(row, other) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRowUndo 确认行在修改后没有再被改动，然后还原旧值，返回影响行数
+// 更新按修改后的键把修改的列改回旧值；删除要求键对应的行仍不存在，再重新插入旧行（跳过生成列，标识列写回原值）
+func (s *Server) applyRowUndo(ctx context.Context, r *http.Request, session *ConnectionSession, db database.ContextDatabase, undo *rowUndo) (int64, error) {
+	rs, ok := db.(database.RowSnapshotDatabase)
+	if !ok {
+		return 0, errors.New("row snapshot is not supported")
+	}
+	current, err := rs.SnapshotRows(ctx, undo.table, undo.keys)
+	if err != nil {
+		return 0, err
+	}
+
+	if undo.op == "update" {
+		if !sameRows(current, undo.after) {
+			return 0, errRowChanged
+		}
+		values := make(map[string]interface{}, len(undo.columns))
+		for _, col := range undo.columns {
+			values[col] = undo.before[0][col]
+		}
+		affected, err := updateRows(ctx, db, undo.table, undo.keys, values)
+		s.auditRowChange(r, session, AuditSourceRow, undo.table, "update", undo.keys, values, affected, err)
+		return affected, err
+	}
+
+	if len(current) > 0 {
+		return 0, errRowChanged
+	}
+	tableColumns, err := session.db.GetTableColumns(undo.table)
+	if err != nil {
+		return 0, err
+	}
+	columns := generatedColumns(tableColumns)
+	var affected int64
+	for _, row := range undo.before {
+		_, err := insertRow(ctx, db, undo.table, columns, row)
+		s.auditRowChange(r, session, AuditSourceRow, undo.table, "insert", nil, row, 1, err)
+		if err != nil {
+			return affected, err
+		}
+		affected++
+	}
+	return affected, nil
+}
+
+// generatedColumns 返回标识列和生成列，重新插入旧行时据此跳过生成列、按原值写回标识列
+// 只传入这些列：文档数据库会按列类型转换字符串值，而快照中的值已经是驱动读取到的类型
+func generatedColumns(columns []database.ColumnInfo) []database.ColumnInfo {
+	var result []database.ColumnInfo
+	for _, col := range columns {
+		if col.AutoIncrement || col.Generated {
+			result = append(result, col)
+		}
+	}
+	return result
+}
+
+// UndoRowChange 撤销 /api/row/update 或 /api/row/delete 的修改
+// 请求体：{"undoId": "..."}，undoId 由修改接口返回；只能由同一连接的同一用户在一小时内撤销一次。
+// 行在修改后又被改动（或删除的行已被重新插入）时返回 409，不做修改。
+// 会话中有打开的事务时在该事务中执行，否则驱动支持事务时在新事务中执行
+func (s *Server) UndoRowChange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	var req struct {
+		UndoID string `json:"undoId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	undo := s.takeRowUndo(r, connectionID, req.UndoID)
+	if undo == nil {
+		writeJSONError(w, http.StatusNotFound, ErrCodeRowUndoNotFound)
+		return
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+
+	db, finish, err := s.beginRowChange(ctx, session)
+	if err != nil {
+		s.putBackRowUndo(req.UndoID, undo)
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+		return
+	}
+	affected, err := s.applyRowUndo(ctx, r, session, db, undo)
+	if err = finish(err); err != nil {
+		s.putBackRowUndo(req.UndoID, undo)
+		if err == errRowChanged {
+			writeJSONError(w, http.StatusConflict, ErrCodeRowChangedSinceEdit)
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeRowUndoFailed, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"affected": affected,
+	})
+}
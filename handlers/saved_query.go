@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// SavedQuery 保存的查询（片段），语句中可以使用 :name 形式的参数
+type SavedQuery struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Query       string    `json:"query"`
+	Tags        []string  `json:"tags"`
+	Owner       string    `json:"owner"`  // 创建者（见 SetUserResolver）
+	Shared      bool      `json:"shared"` // 是否共享给所有用户
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// SavedQueryFilter 保存的查询的搜索条件
+type SavedQueryFilter struct {
+	User   string // 返回该用户创建的和共享的查询
+	Search string // 名称、描述或语句包含的文本（可选，不区分大小写）
+	Tag    string // 标签（可选）
+}
+
+// Match 判断保存的查询是否符合搜索条件
+func (f SavedQueryFilter) Match(query SavedQuery) bool {
+	if query.Owner != f.User && !query.Shared {
+		return false
+	}
+	if f.Tag != "" && !containsString(query.Tags, f.Tag) {
+		return false
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		return strings.Contains(strings.ToLower(query.Name), search) ||
+			strings.Contains(strings.ToLower(query.Description), search) ||
+			strings.Contains(strings.ToLower(query.Query), search)
+	}
+	return true
+}
+
+// SavedQueryStore 保存的查询的存储接口
+// 允许外部项目实现自定义的持久化存储；权限检查（只有创建者可以修改和删除）由 Server 负责
+type SavedQueryStore interface {
+	// List 按名称顺序返回符合条件的查询
+	List(filter SavedQueryFilter) ([]SavedQuery, error)
+
+	// Get 获取查询，不存在时返回 nil 和 nil
+	Get(id int64) (*SavedQuery, error)
+
+	// Save 保存查询：ID 为 0 时新建并分配 ID，否则更新已有的查询；返回保存后的查询
+	Save(query SavedQuery) (*SavedQuery, error)
+
+	// Delete 删除查询
+	Delete(id int64) error
+}
+
+// MemorySavedQueryStore 内存保存的查询存储（默认实现），服务重启后丢失
+type MemorySavedQueryStore struct {
+	queries map[int64]SavedQuery
+	nextID  int64
+	mutex   sync.RWMutex
+}
+
+// NewMemorySavedQueryStore 创建内存保存的查询存储
+func NewMemorySavedQueryStore() *MemorySavedQueryStore {
+	return &MemorySavedQueryStore{queries: make(map[int64]SavedQuery)}
+}
+
+// List 按名称顺序返回符合条件的查询
+func (m *MemorySavedQueryStore) List(filter SavedQueryFilter) ([]SavedQuery, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	result := make([]SavedQuery, 0)
+	for _, query := range m.queries {
+		if filter.Match(query) {
+			result = append(result, query)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// Get 获取查询
+func (m *MemorySavedQueryStore) Get(id int64) (*SavedQuery, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	query, exists := m.queries[id]
+	if !exists {
+		return nil, nil
+	}
+	return &query, nil
+}
+
+// Save 新建或更新查询
+func (m *MemorySavedQueryStore) Save(query SavedQuery) (*SavedQuery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if query.ID == 0 {
+		m.nextID++
+		query.ID = m.nextID
+	}
+	m.queries[query.ID] = query
+	return &query, nil
+}
+
+// Delete 删除查询
+func (m *MemorySavedQueryStore) Delete(id int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.queries, id)
+	return nil
+}
+
+// SetSavedQueryStore 设置保存的查询的存储，传入 nil 时禁用保存的查询
+func (s *Server) SetSavedQueryStore(store SavedQueryStore) {
+	s.savedQueriesMutex.Lock()
+	defer s.savedQueriesMutex.Unlock()
+	s.savedQueries = store
+}
+
+// getSavedQueryStore 获取保存的查询的存储（线程安全），未启用时返回 nil
+func (s *Server) getSavedQueryStore() SavedQueryStore {
+	s.savedQueriesMutex.RLock()
+	defer s.savedQueriesMutex.RUnlock()
+	return s.savedQueries
+}
+
+// containsString 判断切片中是否包含字符串
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// savedQueryView 返回给前端的保存的查询，附带参数名和当前用户能否修改
+type savedQueryView struct {
+	SavedQuery
+	Parameters []string `json:"parameters"`
+	Editable   bool     `json:"editable"`
+}
+
+// ListSavedQueries 返回当前用户创建的和共享的查询
+// 查询参数：search（名称、描述或语句包含的文本）、tag
+func (s *Server) ListSavedQueries(w http.ResponseWriter, r *http.Request) {
+	store := s.getSavedQueryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSavedQueriesDisabled)
+		return
+	}
+
+	user := s.requestUser(r)
+	queries, err := store.List(SavedQueryFilter{
+		User:   user,
+		Search: strings.TrimSpace(r.URL.Query().Get("search")),
+		Tag:    r.URL.Query().Get("tag"),
+	})
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeSavedQueryFailed, err)
+		return
+	}
+
+	views := make([]savedQueryView, len(queries))
+	for i, query := range queries {
+		views[i] = savedQueryView{
+			SavedQuery: query,
+			Parameters: database.SQLParameterNames(query.Query),
+			Editable:   query.Owner == user,
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"queries": views,
+	})
+}
+
+// SaveSavedQuery 新建或修改保存的查询，只有创建者可以修改
+// 请求体：{"id": 0, "name": "...", "description": "", "query": "...", "tags": [], "shared": false}
+func (s *Server) SaveSavedQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+	store := s.getSavedQueryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSavedQueriesDisabled)
+		return
+	}
+
+	var req SavedQuery
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || strings.TrimSpace(req.Query) == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSavedQuery)
+		return
+	}
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	req.Tags = tags
+
+	user := s.requestUser(r)
+	now := time.Now()
+	req.Owner = user
+	req.CreatedAt = now
+	req.UpdatedAt = now
+	if req.ID != 0 {
+		existing, ok := s.ownedSavedQuery(w, store, req.ID, user)
+		if !ok {
+			return
+		}
+		req.CreatedAt = existing.CreatedAt
+	}
+
+	saved, err := store.Save(req)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeSavedQueryFailed, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"query": savedQueryView{
+			SavedQuery: *saved,
+			Parameters: database.SQLParameterNames(saved.Query),
+			Editable:   true,
+		},
+	})
+}
+
+// DeleteSavedQuery 删除保存的查询，只有创建者可以删除
+// 请求体：{"id": 1}
+func (s *Server) DeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+	store := s.getSavedQueryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSavedQueriesDisabled)
+		return
+	}
+
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if _, ok := s.ownedSavedQuery(w, store, req.ID, s.requestUser(r)); !ok {
+		return
+	}
+	if err := store.Delete(req.ID); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeSavedQueryFailed, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// ownedSavedQuery 获取当前用户创建的查询。失败时已写入错误响应
+func (s *Server) ownedSavedQuery(w http.ResponseWriter, store SavedQueryStore, id int64, user string) (*SavedQuery, bool) {
+	query, err := store.Get(id)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeSavedQueryFailed, err)
+		return nil, false
+	}
+	if query == nil {
+		writeJSONError(w, http.StatusNotFound, ErrCodeSavedQueryNotFound)
+		return nil, false
+	}
+	if query.Owner != user {
+		writeJSONError(w, http.StatusForbidden, ErrCodeSavedQueryForbidden)
+		return nil, false
+	}
+	return query, true
+}
+
+// RunSavedQuery 在当前连接上执行保存的查询，:name 参数通过驱动绑定，不会拼接到语句中
+// 请求体：{"id": 1, "params": {"user_id": 42}, "queryId": ""}
+// 返回格式与 /api/query 相同（data 或 affected），执行记录写入查询历史
+func (s *Server) RunSavedQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+	store := s.getSavedQueryStore()
+	if store == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSavedQueriesDisabled)
+		return
+	}
+
+	var req struct {
+		ID      int64                  `json:"id"`
+		Params  map[string]interface{} `json:"params"`
+		QueryID string                 `json:"queryId"`
+	}
+	if err := decodeJSONWithNumbers(r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	saved, err := store.Get(req.ID)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeSavedQueryFailed, err)
+		return
+	}
+	if saved == nil || (saved.Owner != s.requestUser(r) && !saved.Shared) {
+		writeJSONError(w, http.StatusNotFound, ErrCodeSavedQueryNotFound)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	stmt := database.ClassifySQL(session.dbType, saved.Query)
+	if stmt.Keyword == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeUnsupportedSQLType)
+		return
+	}
+	if stmt.Session {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSessionStatement, stmt.Keyword)
+		return
+	}
+	if err := s.validateSQL(saved.Query, stmt.Keyword); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, err)
+		return
+	}
+	query, args, err := database.BindNamedParameters(session.dbType, saved.Query, normalizeJSONNumbers(req.Params))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidSavedQueryParams, err)
+		return
+	}
+
+	// 先在原始连接上检查驱动能力，请求被拒绝时不触碰会话中的事务
+	db, ok := session.db.(database.ParameterizedDatabase)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSavedQueryNotSupported, session.dbType)
+		return
+	}
+
+	ctx, queryID, finish, err := s.startQuery(r, connectionID, req.QueryID, session)
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, ErrCodeQueryIDInUse)
+		return
+	}
+	defer finish()
+	// 会话中有打开的事务时，语句在事务连接上执行
+	sdb, done := s.sessionDB(session, stmt.Kind != database.StatementRead)
+	defer done()
+	if tdb, ok := sdb.(database.ParameterizedDatabase); ok {
+		db = tdb
+	}
+	started := time.Now()
+	var rowCount int64
+	var execErr error
+	defer func() {
+		s.recordQueryHistory(r, session, saved.Query, started, rowCount, execErr)
+		s.auditStatement(r, session, AuditSourceQuery, stmt, query, args, started, rowCount, execErr)
+	}()
+	if stmt.ReturnsRows() {
+		results, err := db.QueryArgsContext(ctx, query, args...)
+		if err != nil {
+			execErr = err
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
+			return
+		}
+		rowCount = int64(len(results))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data":    results,
+			"queryId": queryID,
+		})
+		return
+	}
+
+	affected, err := db.ExecArgsContext(ctx, query, args...)
+	if err != nil {
+		execErr = err
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteUpdateFailed), err)
+		return
+	}
+	rowCount = affected
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"affected": affected,
+		"kind":     stmt.Kind,
+		"queryId":  queryID,
+	})
+}
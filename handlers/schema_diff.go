@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// CompareSchemas 比较两个库的表结构（表、列、类型、可空性、默认值、主键和自增属性）
+// 请求体：{"sourceConnectionId": "...", "targetConnectionId": "...", "sourceDatabase": "", "targetDatabase": "",
+// "tables": [...], "script": true, "dropTables": false, "dropColumns": false}
+// 连接ID为空时使用当前连接（X-Connection-ID），数据库为空时使用连接的当前数据库，因此可以比较两个连接，也可以比较同一连接中的两个库。
+// 结果以源库为准：added 为目标库缺少的表或列，removed 为目标库多出的表或列。
+// script 为 true 时同时生成使目标库与源库一致的 ALTER 脚本（目标库的方言），删除表和列的语句只在 dropTables、dropColumns 为 true 时生成
+func (s *Server) CompareSchemas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		SourceConnectionID string   `json:"sourceConnectionId"`
+		TargetConnectionID string   `json:"targetConnectionId"`
+		SourceDatabase     string   `json:"sourceDatabase"`
+		TargetDatabase     string   `json:"targetDatabase"`
+		Tables             []string `json:"tables"`
+		Script             bool     `json:"script"`
+		database.SchemaSyncOptions
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+	if req.SourceConnectionID == "" {
+		req.SourceConnectionID = connectionID
+	}
+	if req.TargetConnectionID == "" {
+		req.TargetConnectionID = connectionID
+	}
+
+	source, err := s.getSession(req.SourceConnectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+	target, err := s.getSession(req.TargetConnectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTargetConnectionNotExists, err)
+		return
+	}
+	if req.Script && !database.SupportsCreateTable(target.dbType) {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSchemaSyncNotSupported, target.dbType)
+		return
+	}
+
+	ctx := r.Context()
+	sourceDB, closeSource, err := s.openSessionDatabase(source, req.SourceDatabase)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSwitchDatabaseFailed, err)
+		return
+	}
+	defer closeSource()
+	targetDB, closeTarget, err := s.openSessionDatabase(target, req.TargetDatabase)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeSwitchDatabaseFailed, err)
+		return
+	}
+	defer closeTarget()
+
+	sourceSchema, err := database.LoadSchema(ctx, sourceDB, req.Tables)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetTableColumnsFailed), err)
+		return
+	}
+	targetSchema, err := database.LoadSchema(ctx, targetDB, req.Tables)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetTableColumnsFailed), err)
+		return
+	}
+
+	diff := database.CompareSchemas(source.dbType, sourceSchema, target.dbType, targetSchema)
+	response := map[string]interface{}{
+		"success": true,
+		"diff":    diff,
+	}
+	if req.Script {
+		statements, warnings, err := database.BuildSchemaSyncSQL(diff, req.SchemaSyncOptions)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSchemaDiffFailed, err)
+			return
+		}
+		var script strings.Builder
+		for _, warning := range warnings {
+			script.WriteString("-- " + warning + "\n")
+		}
+		for _, stmt := range statements {
+			script.WriteString(stmt + ";\n")
+		}
+		response["statements"] = statements
+		response["warnings"] = warnings
+		response["script"] = script.String()
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// openSessionDatabase 返回连接中指定数据库的 Database，数据库为空或为当前数据库时使用会话的连接；
+// 否则按会话的连接信息另建一个临时连接，避免切换会话的当前数据库。返回的函数用于关闭临时连接
+func (s *Server) openSessionDatabase(session *ConnectionSession, name string) (database.Database, func(), error) {
+	if name == "" || name == session.currentDatabase {
+		return session.db, func() {}, nil
+	}
+	if session.sessionData == nil {
+		return nil, nil, fmt.Errorf("connection information is not available")
+	}
+	data := *session.sessionData
+	data.CurrentDatabase = name
+	db, err := s.createDatabaseFromSessionData(&data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() { db.Close() }, nil
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// ExecuteScript 执行多语句脚本，按顺序逐条执行并返回每条语句的结果
+// 没有打开的事务时整个脚本在同一个连接上执行，脚本中的 SET、USE、临时表和 BEGIN/COMMIT 只作用于该连接
+// continueOnError 为 false（默认）时遇到第一个错误即停止，否则继续执行后续语句
+// 语句超时和 /api/query/cancel 作用于整个脚本
+func (s *Server) ExecuteScript(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	var req struct {
+		Script          string `json:"script"`
+		QueryID         string `json:"queryId"`
+		ContinueOnError bool   `json:"continueOnError"`
+		BinaryEncoding  string `json:"binaryEncoding"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	// Redis、MongoDB 和 Elasticsearch 使用自己的命令语法，不支持 SQL 脚本
+	switch session.dbType {
+	case "redis", "mongodb", "elasticsearch":
+		writeJSONError(w, http.StatusBadRequest, ErrCodeScriptNotSupported)
+		return
+	}
+
+	statements := database.SplitSQLScript(session.dbType, req.Script)
+	if len(statements) == 0 {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeEmptySQLQuery)
+		return
+	}
+
+	// 会话中有打开的事务时，脚本在事务连接上执行；否则独占一个连接，
+	// 使脚本中的 SET、USE、临时表和 BEGIN/COMMIT 作用于同一个连接
+	inTx := session.hasTransaction()
+	pinner, canPin := session.db.(database.ConnPinningDatabase)
+	pinned := canPin && !inTx
+
+	// 执行前先校验所有语句，避免脚本执行到一半被校验拒绝
+	infos := make([]database.StatementInfo, len(statements))
+	write := false
+	for i, stmt := range statements {
+		infos[i] = database.ClassifySQL(session.dbType, stmt)
+		if err := s.validateSQL(stmt, infos[i].Keyword); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, fmt.Sprintf("#%d %v", i+1, err))
+			return
+		}
+		// 不能独占连接时，事务控制和修改连接状态的语句会影响打开的事务或连接池中的其他请求
+		if infos[i].Session && !pinned {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSessionStatement, fmt.Sprintf("#%d %s", i+1, infos[i].Keyword))
+			return
+		}
+		write = write || infos[i].Kind != database.StatementRead
+	}
+
+	ctx, queryID, finish, err := s.startQuery(r, connectionID, req.QueryID, session)
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, ErrCodeQueryIDInUse)
+		return
+	}
+	defer finish()
+	var db database.ContextDatabase
+	if pinned {
+		conn, err := pinner.PinConn(ctx)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeExecuteQueryFailed), err)
+			return
+		}
+		// 执行过非查询语句的连接可能留有会话状态（SET、USE、临时表、未结束的事务），用完后关闭而不放回连接池
+		defer func() {
+			if err := conn.Release(write); err != nil {
+				s.getLogger().Warn(ctx, "Failed to release script connection: %v", err)
+			}
+		}()
+		db = database.WithPinnedConn(session.db, conn)
+	} else {
+		var done func()
+		db, done = s.sessionDB(session, write)
+		defer done()
+	}
+
+	results := make([]map[string]interface{}, 0, len(statements))
+	failed := 0
+	for i, stmt := range statements {
+		result := map[string]interface{}{
+			"index":     i,
+			"statement": stmt,
+			"keyword":   infos[i].Keyword,
+			"kind":      infos[i].Kind,
+		}
+
+		started := time.Now()
+		if infos[i].ReturnsRows() {
+			rs, err := database.QueryResultSet(ctx, db, stmt, req.BinaryEncoding)
+			if err != nil {
+				setScriptError(ctx, result, ErrCodeExecuteQueryFailed, err)
+				s.auditStatement(r, session, AuditSourceQuery, infos[i], stmt, nil, started, 0, err)
+			} else {
+				result["resultSet"] = rs
+				s.auditStatement(r, session, AuditSourceQuery, infos[i], stmt, nil, started, int64(len(rs.Rows)), nil)
+			}
+		} else {
+			affected, errCode, err := executeStatement(ctx, db, infos[i], stmt)
+			s.auditStatement(r, session, AuditSourceQuery, infos[i], stmt, nil, started, affected, err)
+			if err != nil {
+				setScriptError(ctx, result, errCode, err)
+			} else {
+				result["affected"] = affected
+			}
+		}
+		result["durationMs"] = time.Since(started).Milliseconds()
+		_, hasErr := result["error"]
+		result["success"] = !hasErr
+		results = append(results, result)
+
+		if hasErr {
+			failed++
+			// 被取消或超时后后续语句也无法执行
+			if !req.ContinueOnError || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"results":  results,
+		"total":    len(statements),
+		"executed": len(results),
+		"failed":   failed,
+		"queryId":  queryID,
+	})
+}
+
+// setScriptError 记录语句的执行错误
+func setScriptError(ctx context.Context, result map[string]interface{}, fallback string, err error) {
+	result["errorCode"] = queryErrorCode(ctx, err, fallback)
+	result["error"] = err.Error()
+}
+
+// executeStatement 执行不返回结果集的语句，按关键字分派到对应的执行方法
+// DDL 和其他语句统一按更新执行；返回影响行数和失败时使用的错误代码
+func executeStatement(ctx context.Context, db database.ContextDatabase, stmt database.StatementInfo, query string) (int64, string, error) {
+	switch stmt.Keyword {
+	case "DELETE":
+		affected, err := db.ExecuteDeleteContext(ctx, query)
+		return affected, ErrCodeExecuteDeleteFailed, err
+	case "INSERT":
+		affected, err := db.ExecuteInsertContext(ctx, query)
+		return affected, ErrCodeExecuteInsertFailed, err
+	default:
+		affected, err := db.ExecuteUpdateContext(ctx, query)
+		return affected, ErrCodeExecuteUpdateFailed, err
+	}
+}
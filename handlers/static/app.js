@@ -83,19 +83,200 @@ const i18n = {
             // 数据标签页
             'tab.data': 'Data',
             'tab.schema': 'Schema',
+            'schema.comment': 'Comment',
+            'schema.columnComments': 'Column comments',
+            'schema.column': 'Column',
+            'schema.indexes': 'Indexes',
+            'schema.name': 'Name',
+            'schema.columns': 'Columns',
+            'schema.indexType': 'Type',
+            'schema.foreignKeys': 'Foreign keys',
+            'schema.references': 'References',
+            'schema.actions': 'Actions',
+            'schema.checks': 'Check constraints',
+            'schema.expression': 'Expression',
+            'schema.triggers': 'Triggers',
+            'schema.timing': 'Timing',
+            'schema.event': 'Event',
             'tab.query': 'SQL Query',
             'data.perPage': 'Per Page:',
             'data.total': 'Total {total} records, Page {page}/{totalPages}',
             'data.clickhouseNoPagination': 'Showing first 10 records (ClickHouse does not support pagination)',
             'data.redisNoPagination': 'Showing current page data (Redis does not support pagination)',
             'data.elasticsearchNoPagination': 'Showing current page data (Elasticsearch does not support pagination)',
+            'data.sortHint': 'Click to sort, Shift+click to add a secondary sort column',
             'data.prevPage': 'Previous',
             'data.nextPage': 'Next',
             'data.copySchema': 'Copy',
             'data.copySchemaTitle': 'Copy Schema',
             'data.exportExcel': 'Export to Excel',
+            'data.export': 'Export',
             'data.exportSuccess': 'Export successful',
+            'export.title': 'Export Data',
+            'export.format': 'Format',
+            'export.formatSQL': 'SQL INSERT statements',
+            'export.dialect': 'SQL dialect',
+            'export.scope': 'Rows',
+            'export.scopeAll': 'Whole table',
+            'export.scopeFiltered': 'Rows matching the current filter',
+            'export.scopePage': 'Current page',
+            'export.columns': 'Columns',
+            'export.selectAll': 'Select all',
+            'export.selectNone': 'Select none',
+            'export.confirm': 'Export',
+            'export.noColumns': 'Select at least one column',
+            'import.title': 'Import Data',
+            'import.file': 'File (CSV, TSV, JSON, NDJSON or Excel)',
+            'import.mode': 'Mode',
+            'import.modeInsert': 'Insert new rows',
+            'import.modeUpsert': 'Insert or update by primary key',
+            'import.modeReplace': 'Empty the table, then insert',
+            'import.emptyAsNull': 'Import empty cells as NULL',
+            'import.atomic': 'Only commit if every row succeeds (roll back on any failure)',
+            'import.mapping': 'Column mapping',
+            'import.skipColumn': '(skip)',
+            'import.preview': 'File preview',
+            'import.confirm': 'Import',
+            'import.noFile': 'Choose a file to import',
+            'import.replaceConfirm': 'All existing rows in {table} will be deleted before the import. Continue?',
+            'import.result': '{imported} of {total} rows imported, {rejected} rejected',
+            'import.success': 'Imported {count} rows',
+            'import.rolledBack': 'The import was rolled back; no rows were written.',
+            'import.pendingInTransaction': 'Imported rows are in the open transaction; commit or roll back to finish.',
+            'import.row': 'Row {row}',
+            'import.rowRange': 'Rows {row}-{rowEnd}',
+            'import.moreErrors': 'More rows were rejected; only the first 100 are listed.',
+            'dump.backup': 'Backup',
+            'dump.restore': 'Restore',
+            'dump.title': 'Back Up Database',
+            'dump.scope': 'Scope',
+            'dump.scopeAll': 'All tables',
+            'dump.scopeTable': 'Current table',
+            'dump.schema': 'Table structure (CREATE TABLE)',
+            'dump.data': 'Data (INSERT)',
+            'dump.dropTables': 'Drop each table before creating it',
+            'dump.download': 'Download .sql file',
+            'dump.nothingSelected': 'Select structure, data or both',
+            'dump.success': 'Backup downloaded',
+            'dump.failed': 'Backup failed',
+            'restore.title': 'Restore from SQL File',
+            'restore.file': 'SQL file',
+            'restore.continueOnError': 'Continue with the next statements after an error',
+            'restore.confirm': 'Start restore',
+            'restore.confirmMessage': 'Run every statement in {file} against the current database? Existing tables may be dropped or changed.',
+            'restore.noFile': 'Choose a .sql file to restore',
+            'restore.progress': '{executed} of {total} statements executed, {failed} failed',
+            'restore.success': 'Restore finished: {total} statements executed',
+            'restore.doneWithErrors': 'Restore finished with {failed} failed statements',
+            'restore.stopped': 'Restore stopped at a failed statement after {executed} of {total} statements',
+            'restore.interrupted': 'The connection was interrupted before the restore finished',
+            'restore.failed': 'Restore failed',
+            'copy.title': 'Copy Table to Another Connection',
+            'copy.targetConnection': 'Target connection',
+            'copy.currentConnection': 'current',
+            'copy.targetTable': 'Target table',
+            'copy.createTable': 'Create the target table if it does not exist',
+            'copy.truncate': 'Empty the target table before copying',
+            'copy.truncateConfirm': 'All rows in {table} will be deleted before copying. Continue?',
+            'copy.filtered': 'Only copy rows matching the current filters',
+            'copy.start': 'Start copy',
+            'copy.cancelJob': 'Stop copy',
+            'copy.noTarget': 'Choose a target connection',
+            'copy.progress': '{copied} of {total} rows copied',
+            'copy.tableCreated': 'created table {table}',
+            'copy.success': 'Copied {copied} rows to {table}',
+            'copy.cancelled': 'Copy stopped after {copied} rows',
+            'copy.failed': 'Copy failed',
+            'schemaDiff.button': 'Compare',
+            'schemaDiff.title': 'Schema Compare',
+            'schemaDiff.source': 'Source (desired schema)',
+            'schemaDiff.target': 'Target (to be changed)',
+            'schemaDiff.currentDatabase': 'Current database',
+            'schemaDiff.dropTables': 'Drop tables that only exist in the target',
+            'schemaDiff.dropColumns': 'Drop columns that only exist in the target',
+            'schemaDiff.compare': 'Compare',
+            'schemaDiff.script': 'Sync script',
+            'schemaDiff.openScript': 'Open in query editor',
+            'schemaDiff.identical': 'Schemas are identical ({count} tables)',
+            'schemaDiff.summary': '{changed} tables differ, {unchanged} identical',
+            'schemaDiff.status.added': 'Missing',
+            'schemaDiff.status.removed': 'Extra',
+            'schemaDiff.status.changed': 'Changed',
+            'schemaDiff.failed': 'Schema compare failed',
+            'erDiagram.button': 'ER Diagram',
+            'erDiagram.title': 'ER Diagram',
+            'erDiagram.tables': 'Focus tables (comma-separated, empty for all)',
+            'erDiagram.depth': 'Neighbour depth',
+            'erDiagram.format': 'Format',
+            'erDiagram.generate': 'Generate',
+            'erDiagram.copy': 'Copy',
+            'erDiagram.download': 'Download',
+            'erDiagram.copied': 'Diagram copied to clipboard',
+            'tableDesign.title': 'Table Designer',
+            'tableDesign.create': 'New Table',
+            'tableDesign.edit': 'Design Table',
+            'tableDesign.createTitle': 'New Table',
+            'tableDesign.editTitle': 'Design Table: {table}',
+            'tableDesign.name': 'Table name',
+            'tableDesign.columns': 'Columns',
+            'tableDesign.columnName': 'Name',
+            'tableDesign.type': 'Type',
+            'tableDesign.nullable': 'Nullable',
+            'tableDesign.default': 'Default (SQL expression)',
+            'tableDesign.primaryKey': 'Primary key',
+            'tableDesign.autoIncrement': 'Auto increment',
+            'tableDesign.addColumn': 'Add Column',
+            'tableDesign.indexes': 'Indexes',
+            'tableDesign.indexName': 'Name',
+            'tableDesign.indexColumns': 'Columns (comma-separated)',
+            'tableDesign.unique': 'Unique',
+            'tableDesign.addIndex': 'Add Index',
+            'tableDesign.statements': 'Statements to execute',
+            'tableDesign.preview': 'Preview SQL',
+            'tableDesign.apply': 'Execute',
+            'tableDesign.noChanges': '-- No changes',
+            'tableDesign.applied': '{count} statements executed',
+            'tableDesign.failed': 'Table designer failed',
+            'explain.button': 'Explain',
+            'explain.hint': 'Show the execution plan',
+            'explain.analyze': 'Analyze',
+            'explain.analyzeHint': 'Run read-only queries to get actual rows and timing',
+            'explain.title': 'Estimated execution plan',
+            'explain.analyzedTitle': 'Actual execution plan',
+            'explain.estimatedRows': 'est. {rows} rows',
+            'explain.actualRows': 'actual {rows} rows',
+            'explain.cost': 'cost {cost}',
+            'explain.index': 'index {index}',
+            'explain.raw': 'Raw plan',
+            'explain.failed': 'Failed to get the execution plan',
+            'erDiagram.summary': '{tables} tables, {relations} relationships',
+            'erDiagram.failed': 'Failed to generate ER diagram',
+            'dataDiff.title': 'Data Compare',
+            'dataDiff.targetConnection': 'Target connection',
+            'dataDiff.targetTable': 'Target table',
+            'dataDiff.key': 'Key column',
+            'dataDiff.keyPlaceholder': 'Leave empty to use the table primary key',
+            'dataDiff.generateScript': 'Generate sync script',
+            'dataDiff.filtered': 'Only compare rows matching the current filters',
+            'dataDiff.start': 'Start Compare',
+            'dataDiff.cancelJob': 'Stop Compare',
+            'dataDiff.downloadScript': 'Download Sync Script',
+            'dataDiff.progress': 'Scanned {scanned} / {total} source rows · {inserted} missing, {deleted} extra, {changed} changed',
+            'dataDiff.identical': 'The data is identical ({count} rows)',
+            'dataDiff.summary': '{identical} rows are identical; differences:',
+            'dataDiff.moreRows': 'Only the first {count} differences are shown; download the sync script for all of them',
+            'dataDiff.status.inserted': 'Missing',
+            'dataDiff.status.deleted': 'Extra',
+            'dataDiff.status.changed': 'Changed',
+            'dataDiff.cancelled': 'Data compare stopped',
+            'dataDiff.failed': 'Data compare failed',
             'data.filter': 'Filter',
+            'data.addRow': 'Add Row',
+            'data.batchEdit': 'Batch Edit',
+            'data.import': 'Import',
+            'data.copyTo': 'Copy to...',
+            'data.compareTo': 'Compare data...',
+            'data.exitBatchEdit': 'Exit Batch Edit',
             'data.filterLogic': 'Logic',
             'data.filterAnd': 'AND (all conditions must be met)',
             'data.filterOr': 'OR (any condition can be met)',
@@ -106,6 +287,30 @@ const i18n = {
             'data.filterOperator': 'Operator',
             'data.filterValue': 'Value',
             'data.removeFilter': 'Remove',
+            'data.addFilterGroup': '+ Add Group',
+            'data.removeFilterGroup': 'Remove Group',
+            'data.filterGroup': 'Group',
+            'data.filterFrom': 'From',
+            'data.filterTo': 'To',
+            'data.opContains': 'contains',
+            'data.opNotContains': 'does not contain',
+            'data.opStartsWith': 'starts with',
+            'data.opEndsWith': 'ends with',
+            'data.opRegex': 'matches regex',
+            'data.opNotRegex': 'does not match regex',
+            'data.opLast': 'in the last',
+            'data.opNext': 'in the next',
+            'data.opToday': 'today',
+            'data.opYesterday': 'yesterday',
+            'data.opThisWeek': 'this week',
+            'data.opThisMonth': 'this month',
+            'data.opThisYear': 'this year',
+            'data.unit.minutes': 'minutes',
+            'data.unit.hours': 'hours',
+            'data.unit.days': 'days',
+            'data.unit.weeks': 'weeks',
+            'data.unit.months': 'months',
+            'data.unit.years': 'years',
             'data.filterActive': 'Filter Active',
             'data.clearFilter': 'Clear Filter',
             
@@ -118,13 +323,61 @@ const i18n = {
             'query.failed': 'Execution failed',
             'query.unsupported': 'Unsupported SQL type',
             'query.exportExcel': 'Export to Excel',
+            'query.export': 'Export',
             'query.exportSuccess': 'Export successful',
             'query.history': 'Query History',
             'query.showHistory': 'History',
             'query.noHistory': 'No query history',
-            'query.historyItem': 'History #{index}',
             'query.clearHistory': 'Clear History',
             'query.historyCleared': 'History cleared',
+            'query.historySearch': 'Search statements',
+            'query.historyAllStatus': 'All',
+            'query.historySuccess': 'Succeeded',
+            'query.historyFailed': 'Failed',
+            'query.historyCurrentConnection': 'Current connection only',
+            'query.historyRows': '{count} rows',
+            'query.historyPage': 'Page {page} of {pages} ({total} total)',
+            'savedQuery.button': 'Saved',
+            'savedQuery.save': 'Save Query',
+            'savedQuery.title': 'Saved Queries',
+            'savedQuery.search': 'Search name, description or statement',
+            'savedQuery.tagFilter': 'Tag',
+            'savedQuery.name': 'Name',
+            'savedQuery.description': 'Description',
+            'savedQuery.tags': 'Tags (comma separated)',
+            'savedQuery.query': 'Statement (use :name for parameters)',
+            'savedQuery.shared': 'Share with all users',
+            'savedQuery.sharedBadge': 'Shared',
+            'savedQuery.owner': 'By {owner}',
+            'savedQuery.parameters': 'Parameters: {names}',
+            'savedQuery.open': 'Open',
+            'savedQuery.empty': 'No saved queries',
+            'savedQuery.required': 'Name and statement are required',
+            'savedQuery.saved': 'Saved query "{name}"',
+            'savedQuery.confirmDelete': 'Delete saved query "{name}"?',
+            'query.cancel': 'Cancel',
+            'query.executeScript': 'Run Script',
+            'query.executeScriptHint': 'Split the editor content into statements and run them in order',
+            'query.continueOnError': 'Continue on error',
+            'tx.begin': 'Begin Transaction',
+            'tx.commit': 'Commit',
+            'tx.rollback': 'Rollback',
+            'tx.active': 'Transaction open',
+            'tx.uncommitted': 'Uncommitted changes ({count} statements)',
+            'tx.begun': 'Transaction started',
+            'tx.committed': 'Transaction committed',
+            'tx.rolledBack': 'Transaction rolled back',
+            'tx.confirmRollback': 'Discard all uncommitted changes?',
+            'query.scriptSummary': 'Executed {executed} of {total} statements, {failed} failed',
+            'query.scriptStatement': 'Statement',
+            'query.scriptStatus': 'Status',
+            'query.scriptDuration': 'Duration',
+            'query.scriptOutcome': 'Result',
+            'query.scriptRows': '{count} rows returned',
+            'query.scriptOk': 'Succeeded',
+            'query.scriptFailed': 'Failed',
+            'query.cancelled': 'Query cancelled',
+            'query.streaming': 'Receiving results... {count} rows',
             
             // Redis
             'redis.command': 'Command',
@@ -153,9 +406,31 @@ const i18n = {
             'edit.title': 'Edit Row Data',
             'edit.save': 'Update successful',
             'edit.failed': 'Update failed',
+            'edit.noChanges': 'No changes to save',
+            'insert.title': 'Add Row',
+            'insert.generated': 'Generated by the database',
+            'insert.defaultValue': 'Default',
+            'insert.required': 'Required column is empty',
+            'insert.success': 'Row added',
+            'insert.failed': 'Failed to add row',
+            'changeset.hint': 'Double-click a cell to edit it; changes are kept until you apply them',
+            'changeset.pending': '{count} pending changes',
+            'changeset.preview': 'Preview Statements',
+            'changeset.previewTitle': 'Pending Statements',
+            'changeset.apply': 'Apply Changes',
+            'changeset.discard': 'Discard Changes',
+            'changeset.discardConfirm': 'Discard all pending changes?',
+            'changeset.args': 'Parameters',
+            'changeset.skipped': 'Not executed because another change is invalid',
+            'changeset.result': 'Applied {applied}, failed {failed}',
+            'changeset.rolledBack': 'All changes were rolled back; fix the failed rows and apply again.',
+            'changeset.failed': 'Failed to apply changes',
             'delete.title': 'Confirm Delete',
             'delete.message': 'Are you sure you want to delete this row? This operation cannot be undone.',
             'delete.success': 'Delete successful',
+            'rowUndo.undo': 'Undo',
+            'rowUndo.success': 'Change undone',
+            'rowUndo.failed': 'Undo failed',
             'delete.failed': 'Delete failed',
             'delete.connection': 'Confirm Delete Connection',
             'delete.connectionMessage': 'Are you sure you want to delete this saved connection? This operation cannot be undone.',
@@ -179,6 +454,59 @@ const i18n = {
             'error.copySuccess': 'Schema copied to clipboard',
             'error.noContent': 'No content to copy',
             'error.exportFailed': 'Export failed',
+            'error.unsupportedExportFormat': 'Unsupported export format',
+            'error.importFailed': 'Import failed',
+            'error.unknownTable': 'Table does not exist',
+            'error.dumpNotSupported': 'SQL backup is only supported for MySQL, PostgreSQL, SQLite, SQL Server and Oracle',
+            'error.restoreFailed': 'Failed to read the SQL file',
+            'error.missingTargetConnection': 'Target connection is required',
+            'error.targetConnectionNotExists': 'Target connection does not exist or has been closed',
+            'error.targetTableNotExists': 'Target table does not exist',
+            'error.createTableNotSupported': 'Creating tables is not supported for this database type',
+            'error.createTableFailed': 'Failed to create the target table',
+            'error.noCommonColumns': 'The target table has no columns in common with the source table',
+            'error.copyJobNotFound': 'Copy job not found',
+            'error.copyTableFailed': 'Copy failed',
+            'error.schemaSyncNotSupported': 'Generating a sync script is not supported for the target database type',
+            'error.schemaDiffFailed': 'Failed to generate the sync script',
+            'error.dataDiffKeyRequired': 'The table has no single-column primary key; specify the key column',
+            'error.dataDiffJobNotFound': 'Data compare job not found',
+            'error.dataDiffScriptNotReady': 'The sync script is not available',
+            'error.dataDiffFailed': 'Data compare failed',
+            'error.metadataNotSupported': 'This database type does not provide structured metadata',
+            'error.getMetadataFailed': 'Failed to read metadata',
+            'error.tableDesignNotSupported': 'The table designer is not supported for this database type',
+            'error.invalidTableDesign': 'Invalid table definition',
+            'error.tableDesignFailed': 'Failed to apply the table definition',
+            'error.explainNotSupported': 'Execution plans are not supported for this database type',
+            'error.explainMultipleStatements': 'Only a single statement can be explained',
+            'error.explainAnalyzeReadOnly': 'Actual execution is only allowed for read-only statements',
+            'error.explainFailed': 'Failed to get the execution plan',
+            'error.queryHistoryDisabled': 'Query history is disabled',
+            'error.queryHistoryFailed': 'Failed to access query history',
+            'error.savedQueriesDisabled': 'Saved queries are not enabled',
+            'error.savedQueryNotFound': 'Saved query not found',
+            'error.savedQueryForbidden': 'Only the owner can modify this saved query',
+            'error.invalidSavedQuery': 'Name and statement are required',
+            'error.invalidSavedQueryParams': 'Invalid query parameters',
+            'error.savedQueryNotSupported': 'This database does not support parameterized statements',
+            'error.savedQueryFailed': 'Failed to access saved queries',
+            'error.auditDisabled': 'Audit log is not enabled',
+            'error.auditForbidden': 'Only administrators can view the audit log',
+            'error.auditFailed': 'Failed to read audit log',
+            'error.rowUndoNotFound': 'Nothing to undo: the change has expired or was already undone',
+            'error.rowChangedSinceEdit': 'Cannot undo: the row has been changed since the edit',
+            'error.rowUndoFailed': 'Failed to undo the change',
+            'error.missingImportFile': 'No file uploaded',
+            'error.unsupportedImportFormat': 'Unsupported import format; use CSV, TSV, JSON, NDJSON or XLSX',
+            'error.readImportFileFailed': 'Failed to read the import file',
+            'error.invalidImportMode': 'Invalid import mode',
+            'error.importModeNotSupported': 'This database does not support the selected import mode',
+            'error.invalidImportMapping': 'Invalid column mapping (each table column can only be mapped once)',
+            'error.emptyImportMapping': 'No file column is mapped to a table column',
+            'error.importKeyRequired': 'Upsert requires a primary key, and every primary key column must be mapped',
+            'error.importInvalidValue': 'Value does not match the column type',
+            'error.emptyImportRow': 'The row has no values for the mapped columns',
             'error.noTable': 'No table selected',
             'error.timeout': 'Request timeout, please try again later',
             // API错误代码翻译
@@ -188,6 +516,7 @@ const i18n = {
             'error.missingDatabaseName': 'Database name cannot be empty',
             'error.emptySQLQuery': 'SQL query cannot be empty',
             'error.unsupportedSQLType': 'Unsupported SQL type',
+            'error.sessionStatement': 'Transaction control and session statements (BEGIN, COMMIT, SET, USE, ...) are not allowed here: use the transaction buttons to manage transactions and the database selector to switch databases',
             'error.unsupportedDatabaseType': 'Unsupported database type',
             'error.sqliteFileRequired': 'Please enter SQLite database file path',
             'error.unsupportedProxyType': 'Unsupported proxy type',
@@ -222,7 +551,29 @@ const i18n = {
             'error.tableNameEmpty': 'Table name cannot be empty',
             'error.clickHouseNoUpdate': 'ClickHouse does not support UPDATE operations',
             'error.clickHouseNoDelete': 'ClickHouse does not support DELETE operations',
+            'error.rowKeyRequired': 'The row has no primary key to identify it',
+            'error.insertFailed': 'Insert failed',
+            'error.unknownColumn': 'Unknown column',
+            'error.columnNotNullable': 'Column cannot be NULL',
+            'error.emptyChangeset': 'No changes to apply',
+            'error.emptyRowChange': 'No column values to update',
+            'error.invalidRowChange': 'Invalid change',
             'error.connectionNotExists': 'Connection does not exist or has been disconnected',
+            'error.queryCancelled': 'Query was cancelled',
+            'error.queryTimeout': 'Query exceeded the statement timeout',
+            'error.queryNotFound': 'Query does not exist or has already finished',
+            'error.missingQueryID': 'Missing query ID',
+            'error.queryIdInUse': 'A query with this ID is already running on this connection',
+            'error.invalidSort': 'Invalid sort parameter',
+            'error.invalidCursor': 'Invalid pagination cursor',
+            'error.sortNotSupported': 'The current database does not support server-side sorting',
+            'error.scriptNotSupported': 'The current database does not support SQL scripts',
+            'error.transactionNotSupported': 'The current database does not support transactions',
+            'error.transactionActive': 'A transaction is already open, commit or roll it back first',
+            'error.noActiveTransaction': 'No open transaction (it may have been rolled back after being idle)',
+            'error.beginTransactionFailed': 'Failed to begin transaction',
+            'error.commitTransactionFailed': 'Failed to commit transaction',
+            'error.rollbackTransactionFailed': 'Failed to roll back transaction',
             
             // 语言切换
             'lang.en': 'English',
@@ -261,6 +612,12 @@ const i18n = {
             'user.passwordUpdated': 'Password updated successfully',
             'user.passwordUpdateFailed': 'Failed to update password',
             'user.loadFailed': 'Failed to load users',
+            'audit.title': 'Audit Log',
+            'audit.user': 'User',
+            'audit.table': 'Table',
+            'audit.search': 'Search statement',
+            'audit.empty': 'No audit entries',
+            'audit.loadFailed': 'Failed to load audit log',
             'user.deleteConfirm': 'Are you sure you want to delete this user?',
             'user.created': 'User created successfully',
             'user.createFailed': 'Failed to create user',
@@ -350,19 +707,200 @@ const i18n = {
             // 数据标签页
             'tab.data': '数据',
             'tab.schema': '结构',
+            'schema.comment': '注释',
+            'schema.columnComments': '列注释',
+            'schema.column': '列',
+            'schema.indexes': '索引',
+            'schema.name': '名称',
+            'schema.columns': '列',
+            'schema.indexType': '类型',
+            'schema.foreignKeys': '外键',
+            'schema.references': '引用',
+            'schema.actions': '动作',
+            'schema.checks': '检查约束',
+            'schema.expression': '表达式',
+            'schema.triggers': '触发器',
+            'schema.timing': '时机',
+            'schema.event': '事件',
             'tab.query': 'SQL查询',
             'data.perPage': '每页:',
             'data.total': '共 {total} 条，第 {page}/{totalPages} 页',
             'data.clickhouseNoPagination': '显示前 10 条数据（ClickHouse 不支持分页）',
             'data.redisNoPagination': '显示当前页数据（Redis 不支持分页）',
             'data.elasticsearchNoPagination': '显示当前页数据（Elasticsearch 不支持分页）',
+            'data.sortHint': '点击排序，按住 Shift 点击追加排序列',
             'data.prevPage': '上一页',
             'data.nextPage': '下一页',
             'data.copySchema': '复制',
             'data.copySchemaTitle': '复制结构',
             'data.exportExcel': '导出Excel',
+            'data.export': '导出',
             'data.exportSuccess': '导出成功',
+            'export.title': '导出数据',
+            'export.format': '格式',
+            'export.formatSQL': 'SQL INSERT 语句',
+            'export.dialect': 'SQL 方言',
+            'export.scope': '范围',
+            'export.scopeAll': '整张表',
+            'export.scopeFiltered': '满足筛选条件的行',
+            'export.scopePage': '当前页',
+            'export.columns': '列',
+            'export.selectAll': '全选',
+            'export.selectNone': '全不选',
+            'export.confirm': '导出',
+            'export.noColumns': '请至少选择一列',
+            'import.title': '导入数据',
+            'import.file': '文件（CSV、TSV、JSON、NDJSON 或 Excel）',
+            'import.mode': '导入方式',
+            'import.modeInsert': '插入新行',
+            'import.modeUpsert': '按主键插入或更新',
+            'import.modeReplace': '清空表后插入',
+            'import.emptyAsNull': '空单元格作为 NULL 导入',
+            'import.atomic': '全部成功才提交（任一行失败则回滚）',
+            'import.mapping': '列映射',
+            'import.skipColumn': '（不导入）',
+            'import.preview': '文件预览',
+            'import.confirm': '导入',
+            'import.noFile': '请选择要导入的文件',
+            'import.replaceConfirm': '导入前将删除 {table} 中的所有行，是否继续？',
+            'import.result': '共 {total} 行，已导入 {imported} 行，拒绝 {rejected} 行',
+            'import.success': '已导入 {count} 行',
+            'import.rolledBack': '导入已回滚，没有写入任何行。',
+            'import.pendingInTransaction': '已导入的行在当前事务中，请提交或回滚。',
+            'import.row': '第 {row} 行',
+            'import.rowRange': '第 {row}-{rowEnd} 行',
+            'import.moreErrors': '还有更多被拒绝的行，只列出前 100 行。',
+            'dump.backup': '备份',
+            'dump.restore': '恢复',
+            'dump.title': '备份数据库',
+            'dump.scope': '范围',
+            'dump.scopeAll': '所有表',
+            'dump.scopeTable': '当前表',
+            'dump.schema': '表结构（CREATE TABLE）',
+            'dump.data': '数据（INSERT）',
+            'dump.dropTables': '建表前删除同名表',
+            'dump.download': '下载 .sql 文件',
+            'dump.nothingSelected': '请选择表结构或数据',
+            'dump.success': '备份已下载',
+            'dump.failed': '备份失败',
+            'restore.title': '从 SQL 文件恢复',
+            'restore.file': 'SQL 文件',
+            'restore.continueOnError': '出错后继续执行后续语句',
+            'restore.confirm': '开始恢复',
+            'restore.confirmMessage': '是否对当前数据库执行 {file} 中的所有语句？现有的表可能会被删除或修改。',
+            'restore.noFile': '请选择要恢复的 .sql 文件',
+            'restore.progress': '已执行 {executed}/{total} 条语句，失败 {failed} 条',
+            'restore.success': '恢复完成，共执行 {total} 条语句',
+            'restore.doneWithErrors': '恢复完成，{failed} 条语句失败',
+            'restore.stopped': '恢复在失败的语句处停止（已执行 {executed}/{total} 条）',
+            'restore.interrupted': '恢复完成前连接已中断',
+            'restore.failed': '恢复失败',
+            'copy.title': '复制表到其他连接',
+            'copy.targetConnection': '目标连接',
+            'copy.currentConnection': '当前',
+            'copy.targetTable': '目标表',
+            'copy.createTable': '目标表不存在时创建',
+            'copy.truncate': '复制前清空目标表',
+            'copy.truncateConfirm': '复制前将删除 {table} 中的所有行，是否继续？',
+            'copy.filtered': '只复制满足当前过滤条件的行',
+            'copy.start': '开始复制',
+            'copy.cancelJob': '停止复制',
+            'copy.noTarget': '请选择目标连接',
+            'copy.progress': '已复制 {copied} / {total} 行',
+            'copy.tableCreated': '已创建表 {table}',
+            'copy.success': '已复制 {copied} 行到 {table}',
+            'copy.cancelled': '复制已停止，已复制 {copied} 行',
+            'copy.failed': '复制失败',
+            'schemaDiff.button': '结构对比',
+            'schemaDiff.title': '表结构对比',
+            'schemaDiff.source': '源（期望的结构）',
+            'schemaDiff.target': '目标（要修改的库）',
+            'schemaDiff.currentDatabase': '当前数据库',
+            'schemaDiff.dropTables': '脚本中删除目标库多出的表',
+            'schemaDiff.dropColumns': '脚本中删除目标库多出的列',
+            'schemaDiff.compare': '对比',
+            'schemaDiff.script': '同步脚本',
+            'schemaDiff.openScript': '在查询编辑器中打开',
+            'schemaDiff.identical': '表结构相同（{count} 张表）',
+            'schemaDiff.summary': '{changed} 张表有差异，{unchanged} 张相同',
+            'schemaDiff.status.added': '目标缺少',
+            'schemaDiff.status.removed': '目标多出',
+            'schemaDiff.status.changed': '已修改',
+            'schemaDiff.failed': '结构对比失败',
+            'erDiagram.button': 'ER 图',
+            'erDiagram.title': 'ER 图',
+            'erDiagram.tables': '关注的表（逗号分隔，留空表示全部）',
+            'erDiagram.depth': '包含的相邻层数',
+            'erDiagram.format': '格式',
+            'erDiagram.generate': '生成',
+            'erDiagram.copy': '复制',
+            'erDiagram.download': '下载',
+            'erDiagram.copied': 'ER 图已复制到剪贴板',
+            'tableDesign.title': '表设计器',
+            'tableDesign.create': '新建表',
+            'tableDesign.edit': '设计表',
+            'tableDesign.createTitle': '新建表',
+            'tableDesign.editTitle': '设计表：{table}',
+            'tableDesign.name': '表名',
+            'tableDesign.columns': '列',
+            'tableDesign.columnName': '名称',
+            'tableDesign.type': '类型',
+            'tableDesign.nullable': '可空',
+            'tableDesign.default': '默认值（SQL 表达式）',
+            'tableDesign.primaryKey': '主键',
+            'tableDesign.autoIncrement': '自增',
+            'tableDesign.addColumn': '添加列',
+            'tableDesign.indexes': '索引',
+            'tableDesign.indexName': '名称',
+            'tableDesign.indexColumns': '列（逗号分隔）',
+            'tableDesign.unique': '唯一',
+            'tableDesign.addIndex': '添加索引',
+            'tableDesign.statements': '将执行的语句',
+            'tableDesign.preview': '预览 SQL',
+            'tableDesign.apply': '执行',
+            'tableDesign.noChanges': '-- 没有变更',
+            'tableDesign.applied': '已执行 {count} 条语句',
+            'tableDesign.failed': '表设计器操作失败',
+            'explain.button': '执行计划',
+            'explain.hint': '查看执行计划',
+            'explain.analyze': '实际执行',
+            'explain.analyzeHint': '实际执行只读查询以获取实际行数和耗时',
+            'explain.title': '预估执行计划',
+            'explain.analyzedTitle': '实际执行计划',
+            'explain.estimatedRows': '预估 {rows} 行',
+            'explain.actualRows': '实际 {rows} 行',
+            'explain.cost': '代价 {cost}',
+            'explain.index': '索引 {index}',
+            'explain.raw': '原始计划',
+            'explain.failed': '获取执行计划失败',
+            'erDiagram.summary': '{tables} 个表，{relations} 个关系',
+            'erDiagram.failed': '生成 ER 图失败',
+            'dataDiff.title': '数据对比',
+            'dataDiff.targetConnection': '目标连接',
+            'dataDiff.targetTable': '目标表',
+            'dataDiff.key': '主键列',
+            'dataDiff.keyPlaceholder': '留空使用表的主键',
+            'dataDiff.generateScript': '生成同步脚本',
+            'dataDiff.filtered': '只对比满足当前过滤条件的行',
+            'dataDiff.start': '开始对比',
+            'dataDiff.cancelJob': '停止对比',
+            'dataDiff.downloadScript': '下载同步脚本',
+            'dataDiff.progress': '已读取源表 {scanned} / {total} 行 · 目标缺少 {inserted} 行，多出 {deleted} 行，不同 {changed} 行',
+            'dataDiff.identical': '数据一致（{count} 行）',
+            'dataDiff.summary': '{identical} 行一致，差异如下：',
+            'dataDiff.moreRows': '只显示前 {count} 行差异，完整差异请下载同步脚本',
+            'dataDiff.status.inserted': '目标缺少',
+            'dataDiff.status.deleted': '目标多出',
+            'dataDiff.status.changed': '不同',
+            'dataDiff.cancelled': '数据对比已停止',
+            'dataDiff.failed': '数据对比失败',
             'data.filter': '筛选',
+            'data.addRow': '新增行',
+            'data.batchEdit': '批量编辑',
+            'data.import': '导入',
+            'data.copyTo': '复制到...',
+            'data.compareTo': '数据对比...',
+            'data.exitBatchEdit': '退出批量编辑',
             'data.filterLogic': '逻辑关系',
             'data.filterAnd': 'AND（所有条件都满足）',
             'data.filterOr': 'OR（任一条件满足）',
@@ -373,6 +911,30 @@ const i18n = {
             'data.filterOperator': '操作符',
             'data.filterValue': '值',
             'data.removeFilter': '删除',
+            'data.addFilterGroup': '+ 添加条件组',
+            'data.removeFilterGroup': '删除条件组',
+            'data.filterGroup': '条件组',
+            'data.filterFrom': '起始值',
+            'data.filterTo': '结束值',
+            'data.opContains': '包含',
+            'data.opNotContains': '不包含',
+            'data.opStartsWith': '开头是',
+            'data.opEndsWith': '结尾是',
+            'data.opRegex': '匹配正则',
+            'data.opNotRegex': '不匹配正则',
+            'data.opLast': '最近',
+            'data.opNext': '未来',
+            'data.opToday': '今天',
+            'data.opYesterday': '昨天',
+            'data.opThisWeek': '本周',
+            'data.opThisMonth': '本月',
+            'data.opThisYear': '今年',
+            'data.unit.minutes': '分钟',
+            'data.unit.hours': '小时',
+            'data.unit.days': '天',
+            'data.unit.weeks': '周',
+            'data.unit.months': '个月',
+            'data.unit.years': '年',
             'data.filterActive': '筛选已启用',
             'data.clearFilter': '清除筛选',
             'data.noFilters': '暂无筛选条件',
@@ -387,13 +949,61 @@ const i18n = {
             'query.failed': '执行失败',
             'query.unsupported': '不支持的SQL类型',
             'query.exportExcel': '导出Excel',
+            'query.export': '导出',
             'query.exportSuccess': '导出成功',
             'query.history': '查询历史',
             'query.showHistory': '历史',
             'query.noHistory': '暂无查询历史',
-            'query.historyItem': '历史 #{index}',
             'query.clearHistory': '清空历史',
             'query.historyCleared': '历史记录已清空',
+            'query.historySearch': '搜索语句',
+            'query.historyAllStatus': '全部',
+            'query.historySuccess': '成功',
+            'query.historyFailed': '失败',
+            'query.historyCurrentConnection': '仅当前连接',
+            'query.historyRows': '{count} 行',
+            'query.historyPage': '第 {page} / {pages} 页，共 {total} 条',
+            'savedQuery.button': '已保存',
+            'savedQuery.save': '保存查询',
+            'savedQuery.title': '保存的查询',
+            'savedQuery.search': '搜索名称、描述或语句',
+            'savedQuery.tagFilter': '标签',
+            'savedQuery.name': '名称',
+            'savedQuery.description': '描述',
+            'savedQuery.tags': '标签（逗号分隔）',
+            'savedQuery.query': '语句（可以使用 :name 形式的参数）',
+            'savedQuery.shared': '共享给所有用户',
+            'savedQuery.sharedBadge': '共享',
+            'savedQuery.owner': '创建者 {owner}',
+            'savedQuery.parameters': '参数：{names}',
+            'savedQuery.open': '打开',
+            'savedQuery.empty': '暂无保存的查询',
+            'savedQuery.required': '名称和语句不能为空',
+            'savedQuery.saved': '已保存查询“{name}”',
+            'savedQuery.confirmDelete': '确定删除保存的查询“{name}”吗？',
+            'query.cancel': '取消执行',
+            'query.executeScript': '执行脚本',
+            'query.executeScriptHint': '按语句拆分编辑器内容并按顺序逐条执行',
+            'query.continueOnError': '出错后继续',
+            'tx.begin': '开始事务',
+            'tx.commit': '提交',
+            'tx.rollback': '回滚',
+            'tx.active': '事务进行中',
+            'tx.uncommitted': '有未提交的更改（{count} 条语句）',
+            'tx.begun': '事务已开启',
+            'tx.committed': '事务已提交',
+            'tx.rolledBack': '事务已回滚',
+            'tx.confirmRollback': '确定要放弃所有未提交的更改吗？',
+            'query.scriptSummary': '已执行 {executed}/{total} 条语句，失败 {failed} 条',
+            'query.scriptStatement': '语句',
+            'query.scriptStatus': '状态',
+            'query.scriptDuration': '耗时',
+            'query.scriptOutcome': '结果',
+            'query.scriptRows': '返回 {count} 行',
+            'query.scriptOk': '成功',
+            'query.scriptFailed': '失败',
+            'query.cancelled': '查询已取消',
+            'query.streaming': '正在接收结果... 已接收 {count} 行',
             'query.format': '格式化',
             'query.formatSuccess': 'SQL格式化成功',
             'query.formatFailed': '格式化失败',
@@ -430,9 +1040,31 @@ const i18n = {
             'edit.title': '编辑行数据',
             'edit.save': '更新成功',
             'edit.failed': '更新失败',
+            'edit.noChanges': '没有需要保存的修改',
+            'insert.title': '新增行数据',
+            'insert.generated': '由数据库生成',
+            'insert.defaultValue': '默认值',
+            'insert.required': '必填列为空',
+            'insert.success': '新增成功',
+            'insert.failed': '新增失败',
+            'changeset.hint': '双击单元格进行编辑，修改在应用前不会生效',
+            'changeset.pending': '{count} 项待提交的修改',
+            'changeset.preview': '预览语句',
+            'changeset.previewTitle': '待提交的语句',
+            'changeset.apply': '应用修改',
+            'changeset.discard': '放弃修改',
+            'changeset.discardConfirm': '放弃所有待提交的修改？',
+            'changeset.args': '参数',
+            'changeset.skipped': '因其他修改无效未执行',
+            'changeset.result': '已应用 {applied} 项，失败 {failed} 项',
+            'changeset.rolledBack': '所有修改已回滚，请修正失败的行后重新应用。',
+            'changeset.failed': '应用修改失败',
             'delete.title': '确认删除',
             'delete.message': '确定要删除这行数据吗？此操作无法撤销。',
             'delete.success': '删除成功',
+            'rowUndo.undo': '撤销',
+            'rowUndo.success': '已撤销修改',
+            'rowUndo.failed': '撤销失败',
             'delete.failed': '删除失败',
             'delete.connection': '确认删除连接',
             'delete.connectionMessage': '确定要删除这个保存的连接吗？此操作无法撤销。',
@@ -456,6 +1088,59 @@ const i18n = {
             'error.copySuccess': '表结构已复制到剪贴板',
             'error.noContent': '没有可复制的内容',
             'error.exportFailed': '导出失败',
+            'error.unsupportedExportFormat': '不支持的导出格式',
+            'error.importFailed': '导入失败',
+            'error.unknownTable': '表不存在',
+            'error.dumpNotSupported': 'SQL 备份只支持 MySQL、PostgreSQL、SQLite、SQL Server 和 Oracle',
+            'error.restoreFailed': '读取 SQL 文件失败',
+            'error.missingTargetConnection': '缺少目标连接',
+            'error.targetConnectionNotExists': '目标连接不存在或已断开',
+            'error.targetTableNotExists': '目标表不存在',
+            'error.createTableNotSupported': '该数据库类型不支持自动建表',
+            'error.createTableFailed': '创建目标表失败',
+            'error.noCommonColumns': '目标表与源表没有相同的列',
+            'error.copyJobNotFound': '复制任务不存在',
+            'error.copyTableFailed': '复制失败',
+            'error.schemaSyncNotSupported': '目标数据库类型不支持生成同步脚本',
+            'error.schemaDiffFailed': '生成同步脚本失败',
+            'error.dataDiffKeyRequired': '表没有单列主键，请指定主键列',
+            'error.dataDiffJobNotFound': '数据对比任务不存在',
+            'error.dataDiffScriptNotReady': '同步脚本不可用',
+            'error.dataDiffFailed': '数据对比失败',
+            'error.metadataNotSupported': '该数据库类型不支持结构化元数据',
+            'error.getMetadataFailed': '读取元数据失败',
+            'error.tableDesignNotSupported': '当前数据库类型不支持表设计器',
+            'error.invalidTableDesign': '表定义无效',
+            'error.tableDesignFailed': '执行表定义失败',
+            'error.explainNotSupported': '当前数据库类型不支持查看执行计划',
+            'error.explainMultipleStatements': '只能查看单条语句的执行计划',
+            'error.explainAnalyzeReadOnly': '只有只读语句可以实际执行',
+            'error.explainFailed': '获取执行计划失败',
+            'error.queryHistoryDisabled': '查询历史未启用',
+            'error.queryHistoryFailed': '访问查询历史失败',
+            'error.savedQueriesDisabled': '未启用保存的查询',
+            'error.savedQueryNotFound': '保存的查询不存在',
+            'error.savedQueryForbidden': '只有创建者可以修改该查询',
+            'error.invalidSavedQuery': '名称和语句不能为空',
+            'error.invalidSavedQueryParams': '查询参数无效',
+            'error.savedQueryNotSupported': '该数据库不支持参数化语句',
+            'error.savedQueryFailed': '访问保存的查询失败',
+            'error.auditDisabled': '未启用审计日志',
+            'error.auditForbidden': '只有管理员可以查看审计日志',
+            'error.auditFailed': '读取审计日志失败',
+            'error.rowUndoNotFound': '没有可撤销的修改：修改已过期或已撤销',
+            'error.rowChangedSinceEdit': '无法撤销：行在修改后又被改动',
+            'error.rowUndoFailed': '撤销修改失败',
+            'error.missingImportFile': '没有上传文件',
+            'error.unsupportedImportFormat': '不支持的导入格式，请使用 CSV、TSV、JSON、NDJSON 或 XLSX',
+            'error.readImportFileFailed': '读取导入文件失败',
+            'error.invalidImportMode': '无效的导入方式',
+            'error.importModeNotSupported': '该数据库不支持所选的导入方式',
+            'error.invalidImportMapping': '无效的列映射（每个表列只能映射一次）',
+            'error.emptyImportMapping': '没有文件列映射到表列',
+            'error.importKeyRequired': '按主键更新需要表有主键，并且所有主键列都已映射',
+            'error.importInvalidValue': '值与列类型不匹配',
+            'error.emptyImportRow': '该行在映射的列中没有值',
             'error.noTable': '未选择表',
             'error.timeout': '请求超时，请稍后重试',
             // API错误代码翻译
@@ -465,6 +1150,7 @@ const i18n = {
             'error.missingDatabaseName': '数据库名不能为空',
             'error.emptySQLQuery': 'SQL查询不能为空',
             'error.unsupportedSQLType': '不支持的SQL类型',
+            'error.sessionStatement': '此处不允许执行事务控制和修改连接状态的语句（BEGIN、COMMIT、SET、USE 等）：请使用事务按钮管理事务，使用数据库选择切换数据库',
             'error.unsupportedDatabaseType': '不支持的数据库类型',
             'error.unsupportedProxyType': '不支持的代理类型',
             'error.parseRequestFailed': '解析请求失败',
@@ -498,7 +1184,29 @@ const i18n = {
             'error.tableNameEmpty': '表名不能为空',
             'error.clickHouseNoUpdate': 'ClickHouse 不支持 UPDATE 操作',
             'error.clickHouseNoDelete': 'ClickHouse 不支持 DELETE 操作',
+            'error.rowKeyRequired': '该行没有可用于定位的主键',
+            'error.insertFailed': '插入失败',
+            'error.unknownColumn': '未知的列',
+            'error.columnNotNullable': '该列不能为 NULL',
+            'error.emptyChangeset': '没有要应用的修改',
+            'error.emptyRowChange': '没有要更新的列',
+            'error.invalidRowChange': '无效的修改',
             'error.connectionNotExists': '连接不存在或已断开',
+            'error.queryCancelled': '查询已被取消',
+            'error.queryTimeout': '查询超过语句超时时间',
+            'error.queryNotFound': '查询不存在或已结束',
+            'error.missingQueryID': '缺少查询ID',
+            'error.queryIdInUse': '该连接上已有使用此 ID 的查询正在执行',
+            'error.invalidSort': '排序参数无效',
+            'error.invalidCursor': '分页游标无效',
+            'error.sortNotSupported': '当前数据库不支持服务端排序',
+            'error.scriptNotSupported': '当前数据库不支持执行 SQL 脚本',
+            'error.transactionNotSupported': '当前数据库不支持事务',
+            'error.transactionActive': '已有打开的事务，请先提交或回滚',
+            'error.noActiveTransaction': '没有打开的事务（可能已因空闲超时被自动回滚）',
+            'error.beginTransactionFailed': '开启事务失败',
+            'error.commitTransactionFailed': '提交事务失败',
+            'error.rollbackTransactionFailed': '回滚事务失败',
             'error.sqliteFileRequired': '请输入 SQLite 数据库文件路径',
             
             // 语言切换
@@ -538,6 +1246,12 @@ const i18n = {
             'user.passwordUpdated': '密码更新成功',
             'user.passwordUpdateFailed': '密码更新失败',
             'user.loadFailed': '加载用户列表失败',
+            'audit.title': '审计日志',
+            'audit.user': '用户',
+            'audit.table': '表',
+            'audit.search': '搜索语句',
+            'audit.empty': '暂无审计记录',
+            'audit.loadFailed': '加载审计日志失败',
             'user.deleteConfirm': '确定要删除此用户吗？',
             'user.created': '用户创建成功',
             'user.createFailed': '创建用户失败',
@@ -634,19 +1348,200 @@ const i18n = {
             // 数据标签页
             'tab.data': '資料',
             'tab.schema': '結構',
+            'schema.comment': '註解',
+            'schema.columnComments': '欄位註解',
+            'schema.column': '欄位',
+            'schema.indexes': '索引',
+            'schema.name': '名稱',
+            'schema.columns': '欄位',
+            'schema.indexType': '類型',
+            'schema.foreignKeys': '外鍵',
+            'schema.references': '參照',
+            'schema.actions': '動作',
+            'schema.checks': '檢查約束',
+            'schema.expression': '運算式',
+            'schema.triggers': '觸發器',
+            'schema.timing': '時機',
+            'schema.event': '事件',
             'tab.query': 'SQL查詢',
             'data.perPage': '每頁:',
             'data.total': '共 {total} 筆，第 {page}/{totalPages} 頁',
             'data.clickhouseNoPagination': '顯示前 10 筆資料（ClickHouse 不支援分頁）',
             'data.redisNoPagination': '顯示當前頁資料（Redis 不支援分頁）',
             'data.elasticsearchNoPagination': '顯示當前頁資料（Elasticsearch 不支援分頁）',
+            'data.sortHint': '點擊排序，按住 Shift 點擊追加排序欄',
             'data.prevPage': '上一頁',
             'data.nextPage': '下一頁',
             'data.copySchema': '複製',
             'data.copySchemaTitle': '複製結構',
             'data.exportExcel': '匯出Excel',
+            'data.export': '匯出',
             'data.exportSuccess': '匯出成功',
+            'export.title': '匯出資料',
+            'export.format': '格式',
+            'export.formatSQL': 'SQL INSERT 語句',
+            'export.dialect': 'SQL 方言',
+            'export.scope': '範圍',
+            'export.scopeAll': '整張表',
+            'export.scopeFiltered': '符合篩選條件的列',
+            'export.scopePage': '目前頁面',
+            'export.columns': '欄位',
+            'export.selectAll': '全選',
+            'export.selectNone': '全不選',
+            'export.confirm': '匯出',
+            'export.noColumns': '請至少選擇一個欄位',
+            'import.title': '匯入資料',
+            'import.file': '檔案（CSV、TSV、JSON、NDJSON 或 Excel）',
+            'import.mode': '匯入方式',
+            'import.modeInsert': '插入新列',
+            'import.modeUpsert': '依主鍵插入或更新',
+            'import.modeReplace': '清空資料表後插入',
+            'import.emptyAsNull': '空白儲存格以 NULL 匯入',
+            'import.atomic': '全部成功才提交（任一列失敗則復原）',
+            'import.mapping': '欄位對應',
+            'import.skipColumn': '（不匯入）',
+            'import.preview': '檔案預覽',
+            'import.confirm': '匯入',
+            'import.noFile': '請選擇要匯入的檔案',
+            'import.replaceConfirm': '匯入前將刪除 {table} 中的所有資料列，是否繼續？',
+            'import.result': '共 {total} 列，已匯入 {imported} 列，拒絕 {rejected} 列',
+            'import.success': '已匯入 {count} 列',
+            'import.rolledBack': '匯入已復原，沒有寫入任何資料列。',
+            'import.pendingInTransaction': '已匯入的資料列在目前交易中，請提交或復原。',
+            'import.row': '第 {row} 列',
+            'import.rowRange': '第 {row}-{rowEnd} 列',
+            'import.moreErrors': '還有更多被拒絕的資料列，只列出前 100 列。',
+            'dump.backup': '備份',
+            'dump.restore': '還原',
+            'dump.title': '備份資料庫',
+            'dump.scope': '範圍',
+            'dump.scopeAll': '所有資料表',
+            'dump.scopeTable': '目前資料表',
+            'dump.schema': '資料表結構（CREATE TABLE）',
+            'dump.data': '資料（INSERT）',
+            'dump.dropTables': '建立資料表前刪除同名資料表',
+            'dump.download': '下載 .sql 檔案',
+            'dump.nothingSelected': '請選擇資料表結構或資料',
+            'dump.success': '備份已下載',
+            'dump.failed': '備份失敗',
+            'restore.title': '從 SQL 檔案還原',
+            'restore.file': 'SQL 檔案',
+            'restore.continueOnError': '發生錯誤後繼續執行後續陳述式',
+            'restore.confirm': '開始還原',
+            'restore.confirmMessage': '是否對目前資料庫執行 {file} 中的所有陳述式？現有的資料表可能會被刪除或修改。',
+            'restore.noFile': '請選擇要還原的 .sql 檔案',
+            'restore.progress': '已執行 {executed}/{total} 條陳述式，失敗 {failed} 條',
+            'restore.success': '還原完成，共執行 {total} 條陳述式',
+            'restore.doneWithErrors': '還原完成，{failed} 條陳述式失敗',
+            'restore.stopped': '還原在失敗的陳述式處停止（已執行 {executed}/{total} 條）',
+            'restore.interrupted': '還原完成前連線已中斷',
+            'restore.failed': '還原失敗',
+            'copy.title': '複製資料表到其他連線',
+            'copy.targetConnection': '目標連線',
+            'copy.currentConnection': '目前',
+            'copy.targetTable': '目標資料表',
+            'copy.createTable': '目標資料表不存在時建立',
+            'copy.truncate': '複製前清空目標資料表',
+            'copy.truncateConfirm': '複製前將刪除 {table} 中的所有資料列，是否繼續？',
+            'copy.filtered': '只複製符合目前篩選條件的資料列',
+            'copy.start': '開始複製',
+            'copy.cancelJob': '停止複製',
+            'copy.noTarget': '請選擇目標連線',
+            'copy.progress': '已複製 {copied} / {total} 列',
+            'copy.tableCreated': '已建立資料表 {table}',
+            'copy.success': '已複製 {copied} 列到 {table}',
+            'copy.cancelled': '複製已停止，已複製 {copied} 列',
+            'copy.failed': '複製失敗',
+            'schemaDiff.button': '結構比對',
+            'schemaDiff.title': '資料表結構比對',
+            'schemaDiff.source': '來源（期望的結構）',
+            'schemaDiff.target': '目標（要修改的資料庫）',
+            'schemaDiff.currentDatabase': '目前資料庫',
+            'schemaDiff.dropTables': '腳本中刪除目標資料庫多出的資料表',
+            'schemaDiff.dropColumns': '腳本中刪除目標資料庫多出的欄位',
+            'schemaDiff.compare': '比對',
+            'schemaDiff.script': '同步腳本',
+            'schemaDiff.openScript': '在查詢編輯器中開啟',
+            'schemaDiff.identical': '資料表結構相同（{count} 個資料表）',
+            'schemaDiff.summary': '{changed} 個資料表有差異，{unchanged} 個相同',
+            'schemaDiff.status.added': '目標缺少',
+            'schemaDiff.status.removed': '目標多出',
+            'schemaDiff.status.changed': '已修改',
+            'schemaDiff.failed': '結構比對失敗',
+            'erDiagram.button': 'ER 圖',
+            'erDiagram.title': 'ER 圖',
+            'erDiagram.tables': '關注的資料表（逗號分隔，留空表示全部）',
+            'erDiagram.depth': '包含的相鄰層數',
+            'erDiagram.format': '格式',
+            'erDiagram.generate': '產生',
+            'erDiagram.copy': '複製',
+            'erDiagram.download': '下載',
+            'erDiagram.copied': 'ER 圖已複製到剪貼簿',
+            'tableDesign.title': '資料表設計器',
+            'tableDesign.create': '新增資料表',
+            'tableDesign.edit': '設計資料表',
+            'tableDesign.createTitle': '新增資料表',
+            'tableDesign.editTitle': '設計資料表：{table}',
+            'tableDesign.name': '資料表名稱',
+            'tableDesign.columns': '欄位',
+            'tableDesign.columnName': '名稱',
+            'tableDesign.type': '類型',
+            'tableDesign.nullable': '可為空',
+            'tableDesign.default': '預設值（SQL 運算式）',
+            'tableDesign.primaryKey': '主鍵',
+            'tableDesign.autoIncrement': '自動遞增',
+            'tableDesign.addColumn': '新增欄位',
+            'tableDesign.indexes': '索引',
+            'tableDesign.indexName': '名稱',
+            'tableDesign.indexColumns': '欄位（逗號分隔）',
+            'tableDesign.unique': '唯一',
+            'tableDesign.addIndex': '新增索引',
+            'tableDesign.statements': '將執行的陳述式',
+            'tableDesign.preview': '預覽 SQL',
+            'tableDesign.apply': '執行',
+            'tableDesign.noChanges': '-- 沒有變更',
+            'tableDesign.applied': '已執行 {count} 條陳述式',
+            'tableDesign.failed': '資料表設計器操作失敗',
+            'explain.button': '執行計畫',
+            'explain.hint': '檢視執行計畫',
+            'explain.analyze': '實際執行',
+            'explain.analyzeHint': '實際執行唯讀查詢以取得實際列數和耗時',
+            'explain.title': '預估執行計畫',
+            'explain.analyzedTitle': '實際執行計畫',
+            'explain.estimatedRows': '預估 {rows} 列',
+            'explain.actualRows': '實際 {rows} 列',
+            'explain.cost': '成本 {cost}',
+            'explain.index': '索引 {index}',
+            'explain.raw': '原始計畫',
+            'explain.failed': '取得執行計畫失敗',
+            'erDiagram.summary': '{tables} 個資料表，{relations} 個關聯',
+            'erDiagram.failed': '產生 ER 圖失敗',
+            'dataDiff.title': '資料比對',
+            'dataDiff.targetConnection': '目標連線',
+            'dataDiff.targetTable': '目標表',
+            'dataDiff.key': '主鍵欄',
+            'dataDiff.keyPlaceholder': '留空使用表的主鍵',
+            'dataDiff.generateScript': '產生同步腳本',
+            'dataDiff.filtered': '只比對符合目前篩選條件的列',
+            'dataDiff.start': '開始比對',
+            'dataDiff.cancelJob': '停止比對',
+            'dataDiff.downloadScript': '下載同步腳本',
+            'dataDiff.progress': '已讀取來源表 {scanned} / {total} 列 · 目標缺少 {inserted} 列，多出 {deleted} 列，不同 {changed} 列',
+            'dataDiff.identical': '資料一致（{count} 列）',
+            'dataDiff.summary': '{identical} 列一致，差異如下：',
+            'dataDiff.moreRows': '只顯示前 {count} 列差異，完整差異請下載同步腳本',
+            'dataDiff.status.inserted': '目標缺少',
+            'dataDiff.status.deleted': '目標多出',
+            'dataDiff.status.changed': '不同',
+            'dataDiff.cancelled': '資料比對已停止',
+            'dataDiff.failed': '資料比對失敗',
             'data.filter': '篩選',
+            'data.addRow': '新增列',
+            'data.batchEdit': '批次編輯',
+            'data.import': '匯入',
+            'data.copyTo': '複製到...',
+            'data.compareTo': '資料比對...',
+            'data.exitBatchEdit': '結束批次編輯',
             'data.filterLogic': '邏輯關係',
             'data.filterAnd': 'AND（所有條件都滿足）',
             'data.filterOr': 'OR（任一條件滿足）',
@@ -657,6 +1552,30 @@ const i18n = {
             'data.filterOperator': '運算子',
             'data.filterValue': '值',
             'data.removeFilter': '刪除',
+            'data.addFilterGroup': '+ 新增條件組',
+            'data.removeFilterGroup': '刪除條件組',
+            'data.filterGroup': '條件組',
+            'data.filterFrom': '起始值',
+            'data.filterTo': '結束值',
+            'data.opContains': '包含',
+            'data.opNotContains': '不包含',
+            'data.opStartsWith': '開頭是',
+            'data.opEndsWith': '結尾是',
+            'data.opRegex': '匹配正則',
+            'data.opNotRegex': '不匹配正則',
+            'data.opLast': '最近',
+            'data.opNext': '未來',
+            'data.opToday': '今天',
+            'data.opYesterday': '昨天',
+            'data.opThisWeek': '本週',
+            'data.opThisMonth': '本月',
+            'data.opThisYear': '今年',
+            'data.unit.minutes': '分鐘',
+            'data.unit.hours': '小時',
+            'data.unit.days': '天',
+            'data.unit.weeks': '週',
+            'data.unit.months': '個月',
+            'data.unit.years': '年',
             'data.filterActive': '篩選已啟用',
             'data.clearFilter': '清除篩選',
             'data.noFilters': '暫無篩選條件',
@@ -671,13 +1590,61 @@ const i18n = {
             'query.failed': '執行失敗',
             'query.unsupported': '不支援的SQL類型',
             'query.exportExcel': '匯出Excel',
+            'query.export': '匯出',
             'query.exportSuccess': '匯出成功',
             'query.history': '查詢歷史',
             'query.showHistory': '歷史',
             'query.noHistory': '暫無查詢歷史',
-            'query.historyItem': '歷史 #{index}',
             'query.clearHistory': '清空歷史',
             'query.historyCleared': '歷史記錄已清空',
+            'query.historySearch': '搜尋語句',
+            'query.historyAllStatus': '全部',
+            'query.historySuccess': '成功',
+            'query.historyFailed': '失敗',
+            'query.historyCurrentConnection': '僅目前連線',
+            'query.historyRows': '{count} 行',
+            'query.historyPage': '第 {page} / {pages} 頁，共 {total} 筆',
+            'savedQuery.button': '已儲存',
+            'savedQuery.save': '儲存查詢',
+            'savedQuery.title': '儲存的查詢',
+            'savedQuery.search': '搜尋名稱、描述或語句',
+            'savedQuery.tagFilter': '標籤',
+            'savedQuery.name': '名稱',
+            'savedQuery.description': '描述',
+            'savedQuery.tags': '標籤（逗號分隔）',
+            'savedQuery.query': '語句（可以使用 :name 形式的參數）',
+            'savedQuery.shared': '共享給所有使用者',
+            'savedQuery.sharedBadge': '共享',
+            'savedQuery.owner': '建立者 {owner}',
+            'savedQuery.parameters': '參數：{names}',
+            'savedQuery.open': '開啟',
+            'savedQuery.empty': '暫無儲存的查詢',
+            'savedQuery.required': '名稱和語句不能為空',
+            'savedQuery.saved': '已儲存查詢「{name}」',
+            'savedQuery.confirmDelete': '確定刪除儲存的查詢「{name}」嗎？',
+            'query.cancel': '取消執行',
+            'query.executeScript': '執行腳本',
+            'query.executeScriptHint': '按語句拆分編輯器內容並按順序逐條執行',
+            'query.continueOnError': '出錯後繼續',
+            'tx.begin': '開始交易',
+            'tx.commit': '提交',
+            'tx.rollback': '回滾',
+            'tx.active': '交易進行中',
+            'tx.uncommitted': '有未提交的變更（{count} 條語句）',
+            'tx.begun': '交易已開啟',
+            'tx.committed': '交易已提交',
+            'tx.rolledBack': '交易已回滾',
+            'tx.confirmRollback': '確定要放棄所有未提交的變更嗎？',
+            'query.scriptSummary': '已執行 {executed}/{total} 條語句，失敗 {failed} 條',
+            'query.scriptStatement': '語句',
+            'query.scriptStatus': '狀態',
+            'query.scriptDuration': '耗時',
+            'query.scriptOutcome': '結果',
+            'query.scriptRows': '返回 {count} 行',
+            'query.scriptOk': '成功',
+            'query.scriptFailed': '失敗',
+            'query.cancelled': '查詢已取消',
+            'query.streaming': '正在接收結果... 已接收 {count} 行',
             'query.format': '格式化',
             'query.formatSuccess': 'SQL格式化成功',
             'query.formatFailed': '格式化失敗',
@@ -714,9 +1681,31 @@ const i18n = {
             'edit.title': '編輯行資料',
             'edit.save': '更新成功',
             'edit.failed': '更新失敗',
+            'edit.noChanges': '沒有需要儲存的修改',
+            'insert.title': '新增列資料',
+            'insert.generated': '由資料庫產生',
+            'insert.defaultValue': '預設值',
+            'insert.required': '必填欄位為空',
+            'insert.success': '新增成功',
+            'insert.failed': '新增失敗',
+            'changeset.hint': '雙擊儲存格進行編輯，修改在套用前不會生效',
+            'changeset.pending': '{count} 項待提交的修改',
+            'changeset.preview': '預覽語句',
+            'changeset.previewTitle': '待提交的語句',
+            'changeset.apply': '套用修改',
+            'changeset.discard': '放棄修改',
+            'changeset.discardConfirm': '放棄所有待提交的修改？',
+            'changeset.args': '參數',
+            'changeset.skipped': '因其他修改無效未執行',
+            'changeset.result': '已套用 {applied} 項，失敗 {failed} 項',
+            'changeset.rolledBack': '所有修改已回滾，請修正失敗的列後重新套用。',
+            'changeset.failed': '套用修改失敗',
             'delete.title': '確認刪除',
             'delete.message': '確定要刪除這行資料嗎？此操作無法復原。',
             'delete.success': '刪除成功',
+            'rowUndo.undo': '復原',
+            'rowUndo.success': '已復原修改',
+            'rowUndo.failed': '復原失敗',
             'delete.failed': '刪除失敗',
             'delete.connection': '確認刪除連接',
             'delete.connectionMessage': '確定要刪除這個儲存的連接嗎？此操作無法復原。',
@@ -740,6 +1729,59 @@ const i18n = {
             'error.copySuccess': '表結構已複製到剪貼簿',
             'error.noContent': '沒有可複製的內容',
             'error.exportFailed': '匯出失敗',
+            'error.unsupportedExportFormat': '不支援的匯出格式',
+            'error.importFailed': '匯入失敗',
+            'error.unknownTable': '資料表不存在',
+            'error.dumpNotSupported': 'SQL 備份只支援 MySQL、PostgreSQL、SQLite、SQL Server 和 Oracle',
+            'error.restoreFailed': '讀取 SQL 檔案失敗',
+            'error.missingTargetConnection': '缺少目標連線',
+            'error.targetConnectionNotExists': '目標連線不存在或已中斷',
+            'error.targetTableNotExists': '目標資料表不存在',
+            'error.createTableNotSupported': '該資料庫類型不支援自動建立資料表',
+            'error.createTableFailed': '建立目標資料表失敗',
+            'error.noCommonColumns': '目標資料表與來源資料表沒有相同的欄位',
+            'error.copyJobNotFound': '複製任務不存在',
+            'error.copyTableFailed': '複製失敗',
+            'error.schemaSyncNotSupported': '目標資料庫類型不支援產生同步腳本',
+            'error.schemaDiffFailed': '產生同步腳本失敗',
+            'error.dataDiffKeyRequired': '表沒有單欄主鍵，請指定主鍵欄',
+            'error.dataDiffJobNotFound': '資料比對任務不存在',
+            'error.dataDiffScriptNotReady': '同步腳本不可用',
+            'error.dataDiffFailed': '資料比對失敗',
+            'error.metadataNotSupported': '此資料庫類型不支援結構化中繼資料',
+            'error.getMetadataFailed': '讀取中繼資料失敗',
+            'error.tableDesignNotSupported': '目前資料庫類型不支援資料表設計器',
+            'error.invalidTableDesign': '資料表定義無效',
+            'error.tableDesignFailed': '套用資料表定義失敗',
+            'error.explainNotSupported': '目前資料庫類型不支援檢視執行計畫',
+            'error.explainMultipleStatements': '只能檢視單一陳述式的執行計畫',
+            'error.explainAnalyzeReadOnly': '只有唯讀陳述式可以實際執行',
+            'error.explainFailed': '取得執行計畫失敗',
+            'error.queryHistoryDisabled': '查詢歷史未啟用',
+            'error.queryHistoryFailed': '存取查詢歷史失敗',
+            'error.savedQueriesDisabled': '未啟用儲存的查詢',
+            'error.savedQueryNotFound': '儲存的查詢不存在',
+            'error.savedQueryForbidden': '只有建立者可以修改該查詢',
+            'error.invalidSavedQuery': '名稱和語句不能為空',
+            'error.invalidSavedQueryParams': '查詢參數無效',
+            'error.savedQueryNotSupported': '該資料庫不支援參數化語句',
+            'error.savedQueryFailed': '存取儲存的查詢失敗',
+            'error.auditDisabled': '未啟用稽核日誌',
+            'error.auditForbidden': '只有管理員可以查看稽核日誌',
+            'error.auditFailed': '讀取稽核日誌失敗',
+            'error.rowUndoNotFound': '沒有可復原的修改：修改已過期或已復原',
+            'error.rowChangedSinceEdit': '無法復原：資料列在修改後又被變更',
+            'error.rowUndoFailed': '復原修改失敗',
+            'error.missingImportFile': '沒有上傳檔案',
+            'error.unsupportedImportFormat': '不支援的匯入格式，請使用 CSV、TSV、JSON、NDJSON 或 XLSX',
+            'error.readImportFileFailed': '讀取匯入檔案失敗',
+            'error.invalidImportMode': '無效的匯入方式',
+            'error.importModeNotSupported': '該資料庫不支援所選的匯入方式',
+            'error.invalidImportMapping': '無效的欄位對應（每個資料表欄位只能對應一次）',
+            'error.emptyImportMapping': '沒有檔案欄位對應到資料表欄位',
+            'error.importKeyRequired': '依主鍵更新需要資料表有主鍵，且所有主鍵欄位都已對應',
+            'error.importInvalidValue': '值與欄位類型不符',
+            'error.emptyImportRow': '該列在對應的欄位中沒有值',
             'error.noTable': '未選擇表',
             'error.timeout': '請求超時，請稍後重試',
             // API错误代码翻译
@@ -749,6 +1791,7 @@ const i18n = {
             'error.missingDatabaseName': '資料庫名不能為空',
             'error.emptySQLQuery': 'SQL查詢不能為空',
             'error.unsupportedSQLType': '不支援的SQL類型',
+            'error.sessionStatement': '此處不允許執行交易控制和修改連線狀態的語句（BEGIN、COMMIT、SET、USE 等）：請使用交易按鈕管理交易，使用資料庫選擇切換資料庫',
             'error.unsupportedDatabaseType': '不支援的資料庫類型',
             'error.unsupportedProxyType': '不支援的代理類型',
             'error.parseRequestFailed': '解析請求失敗',
@@ -782,7 +1825,29 @@ const i18n = {
             'error.tableNameEmpty': '表名不能為空',
             'error.clickHouseNoUpdate': 'ClickHouse 不支援 UPDATE 操作',
             'error.clickHouseNoDelete': 'ClickHouse 不支援 DELETE 操作',
+            'error.rowKeyRequired': '該列沒有可用於定位的主鍵',
+            'error.insertFailed': '插入失敗',
+            'error.unknownColumn': '未知的欄位',
+            'error.columnNotNullable': '該欄位不能為 NULL',
+            'error.emptyChangeset': '沒有要套用的修改',
+            'error.emptyRowChange': '沒有要更新的欄位',
+            'error.invalidRowChange': '無效的修改',
             'error.connectionNotExists': '連接不存在或已斷開',
+            'error.queryCancelled': '查詢已被取消',
+            'error.queryTimeout': '查詢超過語句逾時時間',
+            'error.queryNotFound': '查詢不存在或已結束',
+            'error.missingQueryID': '缺少查詢ID',
+            'error.queryIdInUse': '該連線上已有使用此 ID 的查詢正在執行',
+            'error.invalidSort': '排序參數無效',
+            'error.invalidCursor': '分頁游標無效',
+            'error.sortNotSupported': '當前資料庫不支援伺服器端排序',
+            'error.scriptNotSupported': '當前資料庫不支援執行 SQL 腳本',
+            'error.transactionNotSupported': '當前資料庫不支援交易',
+            'error.transactionActive': '已有開啟的交易，請先提交或回滾',
+            'error.noActiveTransaction': '沒有開啟的交易（可能已因閒置逾時被自動回滾）',
+            'error.beginTransactionFailed': '開啟交易失敗',
+            'error.commitTransactionFailed': '提交交易失敗',
+            'error.rollbackTransactionFailed': '回滾交易失敗',
             'error.sqliteFileRequired': '請輸入 SQLite 資料庫檔案路徑',
             
             // 语言切换
@@ -822,6 +1887,12 @@ const i18n = {
             'user.passwordUpdated': '密碼更新成功',
             'user.passwordUpdateFailed': '密碼更新失敗',
             'user.loadFailed': '載入用戶列表失敗',
+            'audit.title': '稽核日誌',
+            'audit.user': '用戶',
+            'audit.table': '表',
+            'audit.search': '搜尋語句',
+            'audit.empty': '暫無稽核記錄',
+            'audit.loadFailed': '載入稽核日誌失敗',
             'user.deleteConfirm': '確定要刪除此用戶嗎？',
             'user.created': '用戶建立成功',
             'user.createFailed': '建立用戶失敗',
@@ -1128,6 +2199,8 @@ let currentTable = null;
 let currentPage = 1;
 let pageSize = 50;
 let currentRowData = null;
+let editMode = 'update'; // 编辑模态框的模式：update 编辑行，insert 新增行
+let currentInsertColumns = []; // 新增行表单的列信息
 let currentDeleteWhere = null;
 let connectionId = null; // 当前连接的ID
 let connectionInfo = null; // 当前连接信息
@@ -1227,6 +2300,7 @@ const sqlQuery = document.getElementById('sqlQuery');
 const executeQuery = document.getElementById('executeQuery');
 const clearQuery = document.getElementById('clearQuery');
 const exportQueryBtn = document.getElementById('exportQueryBtn');
+const cancelQueryBtn = document.getElementById('cancelQueryBtn');
 const showHistoryBtn = document.getElementById('showHistoryBtn');
 const formatQueryBtn = document.getElementById('formatQueryBtn');
 const queryHistoryModal = document.getElementById('queryHistoryModal');
@@ -1256,6 +2330,8 @@ const editForm = document.getElementById('editForm');
 const closeEditModal = document.getElementById('closeEditModal');
 const cancelEdit = document.getElementById('cancelEdit');
 const saveEdit = document.getElementById('saveEdit');
+const editModalTitle = document.getElementById('editModalTitle');
+const addRowBtn = document.getElementById('addRowBtn');
 const deleteModal = document.getElementById('deleteModal');
 const closeDeleteModal = document.getElementById('closeDeleteModal');
 const cancelDelete = document.getElementById('cancelDelete');
@@ -1494,10 +2570,10 @@ window.addEventListener('languageChanged', () => {
     }
     // 更新导出按钮的翻译
     if (exportDataBtn && exportDataBtn.style.display !== 'none') {
-        exportDataBtn.textContent = t('data.exportExcel');
+        exportDataBtn.textContent = t('data.export');
     }
     if (exportQueryBtn && exportQueryBtn.style.display !== 'none') {
-        exportQueryBtn.textContent = t('query.exportExcel');
+        exportQueryBtn.textContent = t('query.export');
     }
     // 更新数据库选择器的默认选项
     if (databaseSelect && databaseSelect.firstElementChild && databaseSelect.firstElementChild.hasAttribute('data-i18n')) {
@@ -3211,82 +4287,131 @@ const queryResultsHistory = {
     }
 };
 
-// 查询历史记录管理（SQL语句历史）
+// 查询历史记录管理（服务端记录的 /api/query 执行历史，支持搜索和分页）
 const queryHistory = {
-    // 保存查询历史（最多10条）
-    save(query) {
-        if (!query || !query.trim()) return;
-        
-        let history = this.load();
-        // 移除重复项
-        history = history.filter(item => item !== query.trim());
-        // 添加到开头
-        history.unshift(query.trim());
-        // 只保留最近10条
-        if (history.length > 10) {
-            history = history.slice(0, 10);
-        }
-        localStorage.setItem('sqlQueryHistory', JSON.stringify(history));
-    },
+    page: 1,
+    pageSize: 20,
+    total: 0,
+    searchTimer: null,
     
-    // 加载查询历史
-    load() {
+    // 加载并显示当前页
+    async display() {
+        const params = new URLSearchParams({ page: this.page, pageSize: this.pageSize });
+        const search = document.getElementById('queryHistorySearch').value.trim();
+        const status = document.getElementById('queryHistoryStatus').value;
+        if (search) {
+            params.set('search', search);
+        }
+        if (status) {
+            params.set('status', status);
+        }
+        if (document.getElementById('queryHistoryCurrent').checked && connectionId) {
+            params.set('current', 'true');
+        }
+        
         try {
-            const history = localStorage.getItem('sqlQueryHistory');
-            return history ? JSON.parse(history) : [];
-        } catch (e) {
-            console.error('加载查询历史失败:', e);
-            return [];
+            const response = await apiRequest(`${API_BASE}/query/history?${params}`);
+            const data = await response.json();
+            if (!response.ok || !data.success) {
+                throw new Error(translateApiError(data));
+            }
+            this.total = data.total;
+            this.render(data.entries || []);
+        } catch (error) {
+            this.total = 0;
+            queryHistoryList.innerHTML = `<div class="query-message error">${escapeHtml(error.message)}</div>`;
+            this.updatePager();
         }
     },
     
-    // 清空查询历史
-    clear() {
-        localStorage.removeItem('sqlQueryHistory');
-    },
-    
-    // 显示查询历史
-    display() {
-        const history = this.load();
+    // 渲染历史记录列表
+    render(entries) {
         queryHistoryList.innerHTML = '';
+        this.updatePager();
         
-        if (history.length === 0) {
+        if (entries.length === 0) {
             queryHistoryList.innerHTML = `<div style="padding: 2rem; color: var(--text-secondary); text-align: center; font-size: 0.875rem;" data-i18n="query.noHistory">暂无查询历史</div>`;
             updateI18nElements();
             return;
         }
         
-        history.forEach((query, index) => {
+        entries.forEach(entry => {
             const item = document.createElement('div');
             item.className = 'query-history-item';
-            item.style.cssText = 'padding: 1rem; border-bottom: 1px solid var(--border-color); cursor: pointer; transition: background 0.2s;';
+            const status = entry.success
+                ? `<span class="status-success">${t('query.historySuccess')}</span>`
+                : `<span class="status-error">${t('query.historyFailed')}</span>`;
             item.innerHTML = `
-                <div style="font-size: 0.875rem; color: var(--text-primary); margin-bottom: 0.5rem; word-break: break-all; white-space: pre-wrap; font-family: monospace; background: var(--surface-light); padding: 0.75rem; border-radius: 4px;">${escapeHtml(query)}</div>
-                <div style="font-size: 0.75rem; color: var(--text-secondary);">${t('query.historyItem', { index: index + 1 })}</div>
+                <div class="query-history-query">${escapeHtml(entry.query)}</div>
+                <div class="query-history-meta">
+                    ${status}
+                    <span>${escapeHtml(entry.connectionName)}${entry.database ? ' / ' + escapeHtml(entry.database) : ''}</span>
+                    <span>${escapeHtml(new Date(entry.executedAt).toLocaleString())}</span>
+                    <span>${entry.durationMs} ms</span>
+                    ${entry.success ? `<span>${t('query.historyRows', { count: entry.rowCount })}</span>` : ''}
+                </div>
+                ${entry.error ? `<div class="query-history-error">${escapeHtml(entry.error)}</div>` : ''}
+                <button class="query-history-delete" title="${t('common.delete')}">×</button>
             `;
             
             item.addEventListener('click', () => {
                 if (sqlEditor) {
-                    sqlEditor.setValue(query);
+                    sqlEditor.setValue(entry.query);
                     sqlEditor.focus();
                 } else {
-                    sqlQuery.value = query;
+                    sqlQuery.value = entry.query;
                 }
                 if (queryHistoryModal) {
                     queryHistoryModal.style.display = 'none';
                 }
             });
             
-            item.addEventListener('mouseenter', () => {
-                item.style.background = 'var(--surface-light)';
-            });
-            
-            item.addEventListener('mouseleave', () => {
-                item.style.background = 'transparent';
+            item.querySelector('.query-history-delete').addEventListener('click', async (e) => {
+                e.stopPropagation();
+                if (await this.remove([entry.id])) {
+                    this.display();
+                }
             });
             
             queryHistoryList.appendChild(item);
         });
+    },
+    
+    // 更新分页信息和按钮状态
+    updatePager() {
+        const pages = Math.max(1, Math.ceil(this.total / this.pageSize));
+        document.getElementById('queryHistoryPageInfo').textContent = t('query.historyPage', { page: this.page, pages, total: this.total });
+        document.getElementById('queryHistoryPrev').disabled = this.page <= 1;
+        document.getElementById('queryHistoryNext').disabled = this.page >= pages;
+    },
+    
+    // 删除指定记录，ids 为空时清空全部历史
+    async remove(ids) {
+        try {
+            const response = await apiRequest(`${API_BASE}/query/history/delete`, {
+                method: 'POST',
+                body: JSON.stringify({ ids })
+            });
+            const data = await response.json();
+            if (!response.ok || !data.success) {
+                throw new Error(translateApiError(data));
+            }
+            return true;
+        } catch (error) {
+            showNotification(error.message, 'error');
+            return false;
+        }
+    },
+    
+    // 清空查询历史
+    clear() {
+        return this.remove([]);
+    },
+    
+    // 从第一页重新加载
+    reload() {
+        this.page = 1;
+        this.display();
     }
 };
 
@@ -3332,6 +4457,7 @@ document.addEventListener('DOMContentLoaded', () => {
     const cancelFilter = document.getElementById('cancelFilter');
     const applyFilter = document.getElementById('applyFilter');
     const addFilterCondition = document.getElementById('addFilterCondition');
+    const addFilterGroup = document.getElementById('addFilterGroup');
     const clearFilters = document.getElementById('clearFilters');
     const filterLogic = document.getElementById('filterLogic');
     
@@ -3395,6 +4521,12 @@ document.addEventListener('DOMContentLoaded', () => {
         });
     }
     
+    if (addFilterGroup) {
+        addFilterGroup.addEventListener('click', () => {
+            filterManager.addGroup();
+        });
+    }
+    
     if (clearFilters) {
         clearFilters.addEventListener('click', () => {
             filterManager.clear();
@@ -3881,6 +5013,12 @@ function updateConnectionStatus(connected) {
         text.setAttribute('data-i18n', 'common.disconnected');
         text.textContent = t('common.disconnected');
     }
+    // 切换或断开连接后同步事务状态
+    if (connected) {
+        refreshTransactionStatus();
+    } else {
+        updateTransactionUI(null);
+    }
 }
 
 // 更新连接信息显示
@@ -4082,6 +5220,7 @@ function displayTables(tables) {
     allTables = tables;
     filterTables();
     tablesPanel.style.display = 'block';
+    updateDumpButtons();
     
     // 更新CodeMirror编辑器的自动补全表信息
     if (sqlEditor && allTables && allTables.length > 0) {
@@ -4191,7 +5330,7 @@ async function selectTable(tableName) {
         updateFilterButton();
     }
     // 重置排序状态（切换表时重置排序）
-    dataTableSortState = { field: null, direction: null };
+    dataTableSortState = { sorts: [] };
     
     // 切换到数据标签页
     switchTab('data');
@@ -4209,9 +5348,9 @@ let currentColumns = [];
 let currentFilters = null;
 
 // 排序状态管理
+// 表数据使用服务端多列排序：sorts 为 [{column, direction}]，按优先级排列
 let dataTableSortState = {
-    field: null,  // 当前排序的字段
-    direction: null  // 'asc', 'desc', null
+    sorts: []
 };
 
 let queryResultsSortState = {
@@ -4227,35 +5366,64 @@ let currentTableData = {
 };
 
 // 筛选条件管理
+// 条件组可以嵌套：每个条件组包含 conditions（条件列表）、groups（子条件组）和 logic（AND/OR）
 const filterManager = {
     conditions: [],
+    groups: [],
     logic: 'AND',
     
-    // 添加条件
-    addCondition() {
-        this.conditions.push({
-            field: '',
-            operator: '=',
-            value: '',
-            values: []
-        });
+    // 无需输入值的操作符
+    noValueOperators: ['IS NULL', 'IS NOT NULL', 'TODAY', 'YESTERDAY', 'THIS WEEK', 'THIS MONTH', 'THIS YEAR'],
+    
+    // 可选操作符（label 为空时直接显示操作符本身）
+    operators: [
+        { value: '=' }, { value: '!=' }, { value: '<' }, { value: '>' }, { value: '<=' }, { value: '>=' },
+        { value: 'LIKE' }, { value: 'NOT LIKE' },
+        { value: 'IN' }, { value: 'NOT IN' },
+        { value: 'BETWEEN' }, { value: 'NOT BETWEEN' },
+        { value: 'IS NULL' }, { value: 'IS NOT NULL' },
+        { value: 'CONTAINS', label: 'data.opContains' },
+        { value: 'NOT CONTAINS', label: 'data.opNotContains' },
+        { value: 'STARTS WITH', label: 'data.opStartsWith' },
+        { value: 'ENDS WITH', label: 'data.opEndsWith' },
+        { value: 'REGEX', label: 'data.opRegex' },
+        { value: 'NOT REGEX', label: 'data.opNotRegex' },
+        { value: 'LAST', label: 'data.opLast' },
+        { value: 'NEXT', label: 'data.opNext' },
+        { value: 'TODAY', label: 'data.opToday' },
+        { value: 'YESTERDAY', label: 'data.opYesterday' },
+        { value: 'THIS WEEK', label: 'data.opThisWeek' },
+        { value: 'THIS MONTH', label: 'data.opThisMonth' },
+        { value: 'THIS YEAR', label: 'data.opThisYear' }
+    ],
+    
+    // 创建空条件
+    newCondition() {
+        return { field: '', operator: '=', value: '', values: [] };
+    },
+    
+    // 添加条件（group 为空时添加到顶层）
+    addCondition(group = this) {
+        group.conditions.push(this.newCondition());
+        this.render();
+    },
+    
+    // 添加子条件组
+    addGroup(group = this) {
+        group.groups.push({ logic: 'AND', conditions: [this.newCondition()], groups: [] });
         this.render();
     },
     
     // 删除条件
-    removeCondition(index) {
-        this.conditions.splice(index, 1);
+    removeCondition(index, group = this) {
+        group.conditions.splice(index, 1);
         this.render();
     },
     
-    // 更新条件
-    updateCondition(index, field, operator, value, values) {
-        if (this.conditions[index]) {
-            this.conditions[index].field = field || '';
-            this.conditions[index].operator = operator || '=';
-            this.conditions[index].value = value || '';
-            this.conditions[index].values = values || [];
-        }
+    // 删除子条件组
+    removeGroup(index, group = this) {
+        group.groups.splice(index, 1);
+        this.render();
     },
     
     // 渲染条件列表
@@ -4271,7 +5439,7 @@ const filterManager = {
             filterLogic.value = this.logic;
         }
         
-        if (this.conditions.length === 0) {
+        if (this.conditions.length === 0 && this.groups.length === 0) {
             const emptyMsg = document.createElement('div');
             emptyMsg.style.cssText = 'padding: 1rem; color: var(--text-secondary); text-align: center; font-size: 0.875rem;';
             emptyMsg.textContent = t('data.noFilters') || '暂无筛选条件';
@@ -4279,132 +5447,238 @@ const filterManager = {
             return;
         }
         
-        this.conditions.forEach((condition, index) => {
-            const conditionDiv = document.createElement('div');
-            conditionDiv.style.cssText = 'display: flex; align-items: center; gap: 0.5rem; margin-bottom: 0.75rem; padding: 0.75rem; background: var(--surface); border-radius: 4px; border: 1px solid var(--border-color);';
-            
-            // 字段选择
-            const fieldSelect = document.createElement('select');
-            fieldSelect.className = 'form-control';
-            fieldSelect.style.cssText = 'flex: 1;';
-            fieldSelect.innerHTML = '<option value="">' + (t('data.filterField') || '字段') + '</option>';
-            currentColumns.forEach(col => {
-                const option = document.createElement('option');
-                option.value = col;
-                option.textContent = col;
-                if (condition.field === col) {
-                    option.selected = true;
-                }
-                fieldSelect.appendChild(option);
+        this.renderGroupItems(filterConditionsList, this);
+    },
+    
+    // 渲染条件组中的条件和子条件组
+    renderGroupItems(container, group) {
+        group.conditions.forEach((condition, index) => {
+            container.appendChild(this.createConditionRow(group, condition, index));
+        });
+        group.groups.forEach((subGroup, index) => {
+            container.appendChild(this.createGroupBox(group, subGroup, index));
+        });
+    },
+    
+    // 创建子条件组
+    createGroupBox(parent, group, index) {
+        const groupDiv = document.createElement('div');
+        groupDiv.style.cssText = 'margin-bottom: 0.75rem; padding: 0.75rem; border: 1px dashed var(--primary-color); border-radius: 4px;';
+        
+        const header = document.createElement('div');
+        header.style.cssText = 'display: flex; align-items: center; gap: 0.5rem; margin-bottom: 0.75rem;';
+        
+        const title = document.createElement('span');
+        title.style.cssText = 'font-size: 0.875rem; color: var(--text-secondary);';
+        title.textContent = t('data.filterGroup');
+        
+        const logicSelect = document.createElement('select');
+        logicSelect.className = 'form-control';
+        logicSelect.style.cssText = 'width: auto;';
+        [['AND', 'data.filterAnd'], ['OR', 'data.filterOr']].forEach(([value, key]) => {
+            const option = document.createElement('option');
+            option.value = value;
+            option.textContent = t(key);
+            option.selected = group.logic === value;
+            logicSelect.appendChild(option);
+        });
+        logicSelect.addEventListener('change', (e) => {
+            group.logic = e.target.value;
+        });
+        
+        const addConditionBtn = document.createElement('button');
+        addConditionBtn.className = 'btn btn-secondary';
+        addConditionBtn.style.cssText = 'padding: 0.25rem 0.5rem; font-size: 0.875rem;';
+        addConditionBtn.textContent = t('data.addFilter');
+        addConditionBtn.addEventListener('click', () => this.addCondition(group));
+        
+        const addGroupBtn = document.createElement('button');
+        addGroupBtn.className = 'btn btn-secondary';
+        addGroupBtn.style.cssText = 'padding: 0.25rem 0.5rem; font-size: 0.875rem;';
+        addGroupBtn.textContent = t('data.addFilterGroup');
+        addGroupBtn.addEventListener('click', () => this.addGroup(group));
+        
+        const removeBtn = document.createElement('button');
+        removeBtn.className = 'btn btn-danger';
+        removeBtn.style.cssText = 'margin-left: auto; padding: 0.25rem 0.5rem; font-size: 0.875rem;';
+        removeBtn.textContent = t('data.removeFilterGroup');
+        removeBtn.addEventListener('click', () => this.removeGroup(index, parent));
+        
+        header.appendChild(title);
+        header.appendChild(logicSelect);
+        header.appendChild(addConditionBtn);
+        header.appendChild(addGroupBtn);
+        header.appendChild(removeBtn);
+        groupDiv.appendChild(header);
+        
+        this.renderGroupItems(groupDiv, group);
+        return groupDiv;
+    },
+    
+    // 创建条件行
+    createConditionRow(group, condition, index) {
+        const conditionDiv = document.createElement('div');
+        conditionDiv.style.cssText = 'display: flex; align-items: center; gap: 0.5rem; margin-bottom: 0.75rem; padding: 0.75rem; background: var(--surface); border-radius: 4px; border: 1px solid var(--border-color);';
+        
+        // 字段选择
+        const fieldSelect = document.createElement('select');
+        fieldSelect.className = 'form-control';
+        fieldSelect.style.cssText = 'flex: 1;';
+        fieldSelect.innerHTML = '<option value="">' + (t('data.filterField') || '字段') + '</option>';
+        currentColumns.forEach(col => {
+            const option = document.createElement('option');
+            option.value = col;
+            option.textContent = col;
+            if (condition.field === col) {
+                option.selected = true;
+            }
+            fieldSelect.appendChild(option);
+        });
+        fieldSelect.addEventListener('change', (e) => {
+            condition.field = e.target.value;
+        });
+        
+        // 操作符选择
+        const operatorSelect = document.createElement('select');
+        operatorSelect.className = 'form-control';
+        operatorSelect.style.cssText = 'width: 140px;';
+        this.operators.forEach(op => {
+            const option = document.createElement('option');
+            option.value = op.value;
+            option.textContent = op.label ? t(op.label) : op.value;
+            if (condition.operator === op.value) {
+                option.selected = true;
+            }
+            operatorSelect.appendChild(option);
+        });
+        operatorSelect.addEventListener('change', (e) => {
+            condition.operator = e.target.value;
+            condition.value = '';
+            condition.values = [];
+            this.render(); // 重新渲染以更新值输入框
+        });
+        
+        // 删除按钮
+        const removeBtn = document.createElement('button');
+        removeBtn.className = 'btn btn-danger';
+        removeBtn.style.cssText = 'flex-shrink: 0; padding: 0.5rem 0.75rem; font-size: 0.875rem;';
+        removeBtn.textContent = t('data.removeFilter') || '删除';
+        removeBtn.addEventListener('click', () => {
+            this.removeCondition(index, group);
+        });
+        
+        conditionDiv.appendChild(fieldSelect);
+        conditionDiv.appendChild(operatorSelect);
+        conditionDiv.appendChild(this.createValueInput(condition));
+        conditionDiv.appendChild(removeBtn);
+        return conditionDiv;
+    },
+    
+    // 创建值输入框（根据操作符决定输入方式）
+    createValueInput(condition) {
+        const valueContainer = document.createElement('div');
+        valueContainer.style.cssText = 'flex: 1; display: flex; gap: 0.25rem;';
+        const operator = condition.operator || '=';
+        
+        if (this.noValueOperators.includes(operator)) {
+            valueContainer.innerHTML = '<span style="color: var(--text-secondary); font-size: 0.875rem;">' + (t('data.noValueNeeded') || '无需值') + '</span>';
+        } else if (operator === 'IN' || operator === 'NOT IN') {
+            // IN/NOT IN 使用多行输入
+            const valueTextarea = document.createElement('textarea');
+            valueTextarea.className = 'form-control';
+            valueTextarea.style.cssText = 'min-height: 60px; font-size: 0.875rem;';
+            valueTextarea.placeholder = (t('data.filterValue') || '值') + ' (每行一个值或逗号分隔)';
+            valueTextarea.value = condition.values.length > 0 ? condition.values.join('\n') : condition.value;
+            valueTextarea.addEventListener('change', (e) => {
+                condition.value = '';
+                condition.values = e.target.value.split(/[\n,]/).map(v => v.trim()).filter(v => v);
             });
-            fieldSelect.addEventListener('change', (e) => {
-                this.updateCondition(index, e.target.value, condition.operator, condition.value, condition.values);
+            valueContainer.appendChild(valueTextarea);
+        } else if (operator === 'BETWEEN' || operator === 'NOT BETWEEN') {
+            // BETWEEN 使用两个输入框
+            [0, 1].forEach(i => {
+                const input = document.createElement('input');
+                input.type = 'text';
+                input.className = 'form-control';
+                input.value = condition.values[i] || '';
+                input.placeholder = t(i === 0 ? 'data.filterFrom' : 'data.filterTo');
+                input.addEventListener('change', (e) => {
+                    const values = [condition.values[0] || '', condition.values[1] || ''];
+                    values[i] = e.target.value;
+                    condition.value = '';
+                    condition.values = values;
+                });
+                valueContainer.appendChild(input);
             });
-            
-            // 操作符选择
-            const operatorSelect = document.createElement('select');
-            operatorSelect.className = 'form-control';
-            operatorSelect.style.cssText = 'width: 120px;';
-            const operators = [
-                { value: '=', label: '=' },
-                { value: '!=', label: '!=' },
-                { value: '<', label: '<' },
-                { value: '>', label: '>' },
-                { value: '<=', label: '<=' },
-                { value: '>=', label: '>=' },
-                { value: 'LIKE', label: 'LIKE' },
-                { value: 'NOT LIKE', label: 'NOT LIKE' },
-                { value: 'IN', label: 'IN' },
-                { value: 'NOT IN', label: 'NOT IN' },
-                { value: 'IS NULL', label: 'IS NULL' },
-                { value: 'IS NOT NULL', label: 'IS NOT NULL' }
-            ];
-            operators.forEach(op => {
+        } else if (operator === 'LAST' || operator === 'NEXT') {
+            // 相对日期：数量 + 单位，如 "7 days"
+            const parts = (condition.value || '7 days').split(/\s+/);
+            const amountInput = document.createElement('input');
+            amountInput.type = 'number';
+            amountInput.min = '1';
+            amountInput.className = 'form-control';
+            amountInput.style.cssText = 'width: 80px;';
+            amountInput.value = parts[0];
+            const unitSelect = document.createElement('select');
+            unitSelect.className = 'form-control';
+            ['minutes', 'hours', 'days', 'weeks', 'months', 'years'].forEach(unit => {
                 const option = document.createElement('option');
-                option.value = op.value;
-                option.textContent = op.label;
-                if (condition.operator === op.value) {
-                    option.selected = true;
-                }
-                operatorSelect.appendChild(option);
+                option.value = unit;
+                option.textContent = t('data.unit.' + unit);
+                option.selected = (parts[1] || 'days') === unit;
+                unitSelect.appendChild(option);
             });
-            operatorSelect.addEventListener('change', (e) => {
-                this.updateCondition(index, condition.field, e.target.value, condition.value, condition.values);
-                this.render(); // 重新渲染以更新值输入框
-            });
-            
-            // 值输入（对于 IN/NOT IN 使用多行输入，对于 IS NULL/IS NOT NULL 不显示）
-            const valueContainer = document.createElement('div');
-            valueContainer.style.cssText = 'flex: 1;';
-            const operator = condition.operator || '=';
-            if (operator === 'IS NULL' || operator === 'IS NOT NULL') {
-                valueContainer.innerHTML = '<span style="color: var(--text-secondary); font-size: 0.875rem;">' + (t('data.noValueNeeded') || '无需值') + '</span>';
-            } else if (operator === 'IN' || operator === 'NOT IN') {
-                const valueTextarea = document.createElement('textarea');
-                valueTextarea.className = 'form-control';
-                valueTextarea.style.cssText = 'min-height: 60px; font-size: 0.875rem;';
-                valueTextarea.placeholder = (t('data.filterValue') || '值') + ' (每行一个值或逗号分隔)';
-                valueTextarea.value = condition.values.length > 0 ? condition.values.join('\n') : condition.value;
-                valueTextarea.addEventListener('change', (e) => {
-                    const values = e.target.value.split(/[\n,]/).map(v => v.trim()).filter(v => v);
-                    this.updateCondition(index, condition.field, condition.operator, '', values);
-                });
-                valueContainer.appendChild(valueTextarea);
-            } else {
-                const valueInput = document.createElement('input');
-                valueInput.type = 'text';
-                valueInput.className = 'form-control';
-                valueInput.value = condition.value || '';
-                valueInput.placeholder = t('data.filterValue') || '值';
-                valueInput.addEventListener('change', (e) => {
-                    this.updateCondition(index, condition.field, condition.operator, e.target.value, []);
-                });
-                valueContainer.appendChild(valueInput);
-            }
-            
-            // 删除按钮
-            const removeBtn = document.createElement('button');
-            removeBtn.className = 'btn btn-danger';
-            removeBtn.style.cssText = 'flex-shrink: 0; padding: 0.5rem 0.75rem; font-size: 0.875rem;';
-            removeBtn.textContent = t('data.removeFilter') || '删除';
-            removeBtn.addEventListener('click', () => {
-                this.removeCondition(index);
+            const update = () => {
+                condition.value = `${amountInput.value} ${unitSelect.value}`;
+            };
+            amountInput.addEventListener('change', update);
+            unitSelect.addEventListener('change', update);
+            update();
+            valueContainer.appendChild(amountInput);
+            valueContainer.appendChild(unitSelect);
+        } else {
+            const valueInput = document.createElement('input');
+            valueInput.type = 'text';
+            valueInput.className = 'form-control';
+            valueInput.value = condition.value || '';
+            valueInput.placeholder = t('data.filterValue') || '值';
+            valueInput.addEventListener('change', (e) => {
+                condition.value = e.target.value;
+                condition.values = [];
             });
-            
-            conditionDiv.appendChild(fieldSelect);
-            conditionDiv.appendChild(operatorSelect);
-            conditionDiv.appendChild(valueContainer);
-            conditionDiv.appendChild(removeBtn);
-            filterConditionsList.appendChild(conditionDiv);
-        });
+            valueContainer.appendChild(valueInput);
+        }
+        return valueContainer;
     },
     
-    // 获取过滤条件对象
-    getFilters() {
-        if (this.conditions.length === 0) {
-            return null;
-        }
-        
-        // 过滤掉空字段的条件
-        const validConditions = this.conditions.filter(c => c.field && c.field.trim() !== '');
-        if (validConditions.length === 0) {
-            return null;
-        }
-        
-        return {
-            conditions: validConditions.map(c => ({
+    // 序列化条件组，过滤掉空字段的条件和空的子条件组
+    serializeGroup(group) {
+        const conditions = group.conditions
+            .filter(c => c.field && c.field.trim() !== '')
+            .map(c => ({
                 field: c.field,
                 operator: c.operator,
                 value: c.value,
                 values: c.values
-            })),
-            logic: this.logic
-        };
+            }));
+        const groups = group.groups
+            .map(g => this.serializeGroup(g))
+            .filter(g => g !== null);
+        if (conditions.length === 0 && groups.length === 0) {
+            return null;
+        }
+        return { conditions, groups, logic: group.logic };
+    },
+    
+    // 获取过滤条件对象
+    getFilters() {
+        return this.serializeGroup(this);
     },
     
     // 清除所有条件
     clear() {
         this.conditions = [];
+        this.groups = [];
         this.logic = 'AND';
         const filterLogic = document.getElementById('filterLogic');
         if (filterLogic) {
@@ -4413,20 +5687,31 @@ const filterManager = {
         this.render();
     },
     
+    // 将过滤条件对象转换为可编辑的条件组
+    loadGroup(filters) {
+        return {
+            logic: filters.logic || 'AND',
+            conditions: (filters.conditions || []).map(c => ({
+                field: c.field || '',
+                operator: c.operator || '=',
+                value: c.value || '',
+                values: c.values || []
+            })),
+            groups: (filters.groups || []).map(g => this.loadGroup(g))
+        };
+    },
+    
     // 加载条件
     load(filters) {
-        if (!filters || !filters.conditions || filters.conditions.length === 0) {
+        if (!hasActiveFilters(filters)) {
             this.clear();
             return;
         }
         
-        this.conditions = filters.conditions.map(c => ({
-            field: c.field || '',
-            operator: c.operator || '=',
-            value: c.value || '',
-            values: c.values || []
-        }));
-        this.logic = filters.logic || 'AND';
+        const group = this.loadGroup(filters);
+        this.conditions = group.conditions;
+        this.groups = group.groups;
+        this.logic = group.logic;
         const filterLogic = document.getElementById('filterLogic');
         if (filterLogic) {
             filterLogic.value = this.logic;
@@ -4435,12 +5720,23 @@ const filterManager = {
     }
 };
 
+// 判断过滤条件是否包含有效条件（包括嵌套的子条件组）
+function hasActiveFilters(filters) {
+    if (!filters) {
+        return false;
+    }
+    if (filters.conditions && filters.conditions.some(c => c.field)) {
+        return true;
+    }
+    return !!(filters.groups && filters.groups.some(g => hasActiveFilters(g)));
+}
+
 // 更新筛选按钮状态
 function updateFilterButton() {
     const filterDataBtn = document.getElementById('filterDataBtn');
     if (!filterDataBtn) return;
     
-    const hasFilters = currentFilters && currentFilters.conditions && currentFilters.conditions.length > 0;
+    const hasFilters = hasActiveFilters(currentFilters);
     if (hasFilters) {
         filterDataBtn.classList.add('active');
         filterDataBtn.title = t('data.filterActive') || '筛选已激活';
@@ -4481,12 +5777,15 @@ async function loadTableData() {
         let url = `${API_BASE}/table/data?table=${currentTable}&page=${requestPage}&pageSize=${pageSize}`;
         
         // 添加过滤条件（如果有）
-        if (currentFilters && currentFilters.conditions && currentFilters.conditions.length > 0) {
+        if (hasActiveFilters(currentFilters)) {
             // 将过滤条件编码为 JSON 字符串
             const filtersStr = encodeURIComponent(JSON.stringify(currentFilters));
             url += `&filters=${filtersStr}`;
         }
         
+        // 添加排序条件（服务端排序）
+        url += getDataTableSortParam();
+        
         // 如果使用基于ID的分页，添加lastId和direction参数
         if (useIdPagination) {
             // 判断方向：
@@ -4618,8 +5917,8 @@ async function loadTableData() {
             // 显示导出按钮并更新翻译
             if (exportDataBtn) {
                 exportDataBtn.style.display = 'inline-block';
-                exportDataBtn.setAttribute('data-i18n', 'data.exportExcel');
-                exportDataBtn.textContent = t('data.exportExcel');
+                exportDataBtn.setAttribute('data-i18n', 'data.export');
+                exportDataBtn.textContent = t('data.export');
             }
         }
     } catch (error) {
@@ -4635,6 +5934,7 @@ async function loadTableData() {
 
 // 显示表数据
 function displayTableData(rows, total, isClickHouse = false) {
+    resetBatchEdit(rows);
     // 清空表格内容，避免DOM操作冲突
     while (dataTableHead.firstChild) {
         dataTableHead.removeChild(dataTableHead.firstChild);
@@ -4686,32 +5986,30 @@ function displayTableData(rows, total, isClickHouse = false) {
             sortIndicator.className = 'sort-indicator';
             sortIndicator.style.cssText = 'position: absolute; right: 0.5rem; top: 50%; transform: translateY(-50%); font-size: 0.75rem; color: var(--text-secondary);';
             
-            // 设置初始排序指示器状态
-            if (dataTableSortState.field === col) {
-                if (dataTableSortState.direction === 'asc') {
-                    sortIndicator.textContent = '↑';
-                    sortIndicator.style.color = 'var(--primary-color)';
-                } else if (dataTableSortState.direction === 'desc') {
-                    sortIndicator.textContent = '↓';
-                    sortIndicator.style.color = 'var(--primary-color)';
-                }
+            // 设置初始排序指示器状态（多列排序时显示优先级）
+            const sortIndex = dataTableSortState.sorts.findIndex(s => s.column === col);
+            if (sortIndex >= 0) {
+                const arrow = dataTableSortState.sorts[sortIndex].direction === 'desc' ? '↓' : '↑';
+                sortIndicator.textContent = dataTableSortState.sorts.length > 1 ? `${arrow}${sortIndex + 1}` : arrow;
+                sortIndicator.style.color = 'var(--primary-color)';
             }
             th.appendChild(sortIndicator);
+            th.title = t('data.sortHint');
             
-            // 添加点击事件
-            th.addEventListener('click', () => {
-                handleDataTableSort(col);
+            // 添加点击事件（按住 Shift 点击追加排序列）
+            th.addEventListener('click', (e) => {
+                handleDataTableSort(col, e.shiftKey);
             });
             
             // 添加悬停效果
             th.addEventListener('mouseenter', () => {
-                if (dataTableSortState.field !== col) {
+                if (sortIndex < 0) {
                     sortIndicator.textContent = '⇅';
                     sortIndicator.style.color = 'var(--text-secondary)';
                 }
             });
             th.addEventListener('mouseleave', () => {
-                if (dataTableSortState.field !== col) {
+                if (sortIndex < 0) {
                     sortIndicator.textContent = '';
                 }
             });
@@ -4743,19 +6041,15 @@ function displayTableData(rows, total, isClickHouse = false) {
         return;
     }
     
-    // 应用排序（如果有）
-    let sortedRows = [...rows];
-    if (dataTableSortState.field && dataTableSortState.direction) {
-        sortedRows = sortRows(rows, dataTableSortState.field, dataTableSortState.direction);
-    }
-    
-    // 创建表体
-    sortedRows.forEach((row, index) => {
+    // 创建表体（数据已由服务端排序）
+    rows.forEach((row, index) => {
         const bodyRow = document.createElement('tr');
+        bodyRow.dataset.rowIndex = index;
         
         // 按照列顺序添加单元格
         columns.forEach(col => {
             const td = document.createElement('td');
+            td.dataset.column = col;
             const value = row[col];
             if (value === null || value === undefined) {
                 const nullSpan = document.createElement('span');
@@ -4804,6 +6098,11 @@ function displayTableData(rows, total, isClickHouse = false) {
     
     dataTableBody.querySelectorAll('.delete-row-btn').forEach(btn => {
         btn.addEventListener('click', function() {
+            // 批量编辑模式下只标记删除，提交变更集时执行
+            if (batchEdit.active) {
+                togglePendingDelete(this.closest('tr'));
+                return;
+            }
             const rowData = JSON.parse(this.dataset.row);
             deleteRow(rowData);
         });
@@ -4907,7 +6206,7 @@ async function changePage(page) {
             } else {
                 // 如果历史栈中没有，需要从后端获取该页的ID
                 try {
-                    const response = await apiRequest(`${API_BASE}/table/page-id?table=${currentTable}&page=${page}&pageSize=${pageSize}`);
+                    const response = await apiRequest(`${API_BASE}/table/page-id?table=${currentTable}&page=${page}&pageSize=${pageSize}${getDataTableSortParam(true)}`);
                     const data = await response.json();
                     if (data.success && data.pageId !== null && data.pageId !== undefined) {
                         lastId = data.pageId;
@@ -4932,7 +6231,7 @@ async function changePage(page) {
             } else {
                 // 历史栈中没有，需要从后端获取该页的ID
                 try {
-                    const response = await apiRequest(`${API_BASE}/table/page-id?table=${currentTable}&page=${page}&pageSize=${pageSize}`);
+                    const response = await apiRequest(`${API_BASE}/table/page-id?table=${currentTable}&page=${page}&pageSize=${pageSize}${getDataTableSortParam(true)}`);
                     const data = await response.json();
                     if (data.success && data.pageId !== null && data.pageId !== undefined) {
                         lastId = data.pageId;
@@ -4993,6 +6292,8 @@ async function loadTableSchema() {
         
         if (data.success) {
             schemaContent.textContent = data.schema;
+            loadSchemaDetails(currentTable);
+            document.getElementById('designTableBtn').style.display = '';
             copySchemaBtn.style.display = 'block';
             copySchemaBtn.setAttribute('data-i18n', 'data.copySchema');
             copySchemaBtn.setAttribute('data-i18n-title', 'data.copySchemaTitle');
@@ -5010,6 +6311,66 @@ async function loadTableSchema() {
     }
 }
 
+// 结构详情：注释、索引、外键、检查约束和触发器，驱动不支持结构化元数据时不显示
+const schemaDetails = document.getElementById('schemaDetails');
+
+function renderMetadataTable(title, headers, rows) {
+    if (rows.length === 0) return '';
+    let html = `<h4>${escapeHtml(title)}</h4><table class="data-table"><thead><tr>`;
+    headers.forEach(h => { html += `<th>${escapeHtml(h)}</th>`; });
+    html += '</tr></thead><tbody>';
+    rows.forEach(row => {
+        html += '<tr>' + row.map(cell => `<td>${escapeHtml(cell || '')}</td>`).join('') + '</tr>';
+    });
+    return html + '</tbody></table>';
+}
+
+async function loadSchemaDetails(table) {
+    schemaDetails.style.display = 'none';
+    schemaDetails.innerHTML = '';
+    try {
+        const [metaResponse, triggerResponse] = await Promise.all([
+            apiRequest(`${API_BASE}/schema/table?table=${encodeURIComponent(table)}`),
+            apiRequest(`${API_BASE}/schema/triggers?table=${encodeURIComponent(table)}`)
+        ]);
+        const meta = await metaResponse.json();
+        const triggers = await triggerResponse.json();
+        if (!metaResponse.ok || !meta.success || table !== currentTable) {
+            return;
+        }
+        const info = meta.table;
+        let html = '';
+        if (info.comment) {
+            html += `<h4>${escapeHtml(t('schema.comment'))}</h4><div>${escapeHtml(info.comment)}</div>`;
+        }
+        html += renderMetadataTable(t('schema.columnComments'), [t('schema.column'), t('schema.comment')],
+            info.columns.filter(col => info.columnComments[col.name]).map(col => [col.name, info.columnComments[col.name]]));
+        html += renderMetadataTable(t('schema.indexes'), [t('schema.name'), t('schema.columns'), t('schema.indexType')],
+            info.indexes.map(idx => [
+                idx.name,
+                idx.columns.join(', '),
+                [idx.primary ? 'PRIMARY' : (idx.unique ? 'UNIQUE' : ''), idx.type].filter(Boolean).join(' ')
+            ]));
+        html += renderMetadataTable(t('schema.foreignKeys'), [t('schema.name'), t('schema.columns'), t('schema.references'), t('schema.actions')],
+            info.foreignKeys.map(fk => [
+                fk.name,
+                fk.columns.join(', '),
+                `${fk.refTable} (${fk.refColumns.join(', ')})`,
+                [fk.onUpdate && `ON UPDATE ${fk.onUpdate}`, fk.onDelete && `ON DELETE ${fk.onDelete}`].filter(Boolean).join(' ')
+            ]));
+        html += renderMetadataTable(t('schema.checks'), [t('schema.name'), t('schema.expression')],
+            info.checks.map(check => [check.name, check.expression]));
+        if (triggerResponse.ok && triggers.success) {
+            html += renderMetadataTable(t('schema.triggers'), [t('schema.name'), t('schema.timing'), t('schema.event')],
+                triggers.triggers.map(tr => [tr.name, tr.timing, tr.event]));
+        }
+        schemaDetails.innerHTML = html;
+        schemaDetails.style.display = html ? 'block' : 'none';
+    } catch (error) {
+        // 结构详情是附加信息，加载失败时只显示建表语句
+    }
+}
+
 // 复制表结构
 copySchemaBtn.addEventListener('click', async () => {
     const schemaText = schemaContent.textContent;
@@ -5398,6 +6759,9 @@ if (clearESQuery) {
     });
 }
 
+// 当前正在执行的查询ID
+let currentQueryId = null;
+
 // 执行SQL查询
 executeQuery.addEventListener('click', async () => {
     const query = sqlEditor ? sqlEditor.getValue().trim() : sqlQuery.value.trim();
@@ -5406,14 +6770,37 @@ executeQuery.addEventListener('click', async () => {
         return;
     }
     
+    // 生成查询ID，用于取消正在执行的查询
+    const queryId = 'q_' + Date.now() + '_' + Math.random().toString(36).substr(2, 9);
+    currentQueryId = queryId;
+    if (cancelQueryBtn) {
+        cancelQueryBtn.style.display = 'inline-block';
+    }
+    
     showLoading(queryLoading);
     setButtonLoading(executeQuery, true);
     try {
         const response = await apiRequest(`${API_BASE}/query`, {
             method: 'POST',
-            body: JSON.stringify({ query })
+            body: JSON.stringify({ query, queryId, stream: true }),
+            timeout: 10 * 60 * 1000 // 长查询由服务端语句超时控制，可通过取消按钮中止
         });
         
+        // SELECT 查询以 NDJSON 流式返回，边接收边渲染
+        const contentType = response.headers.get('Content-Type') || '';
+        if (response.ok && contentType.includes('application/x-ndjson')) {
+            const rows = await readQueryStream(response);
+            const resultId = queryResultsHistory.add(query, rows);
+            updateQueryResultsTabs();
+            displayQueryResult(resultId);
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'inline-block';
+                exportQueryBtn.setAttribute('data-i18n', 'query.export');
+                exportQueryBtn.textContent = t('query.export');
+            }
+            return;
+        }
+        
         const data = await response.json();
         
         if (!response.ok || !data.success) {
@@ -5426,9 +6813,6 @@ executeQuery.addEventListener('click', async () => {
         }
         
         if (response.ok && data.success) {
-            // 保存查询历史（SQL语句）
-            queryHistory.save(query);
-            
             if (data.data) {
                 // 查询结果 - 保存到历史记录
                 const resultId = queryResultsHistory.add(query, data.data);
@@ -5442,8 +6826,8 @@ executeQuery.addEventListener('click', async () => {
                 // 显示导出按钮并更新翻译
                 if (exportQueryBtn) {
                     exportQueryBtn.style.display = 'inline-block';
-                    exportQueryBtn.setAttribute('data-i18n', 'query.exportExcel');
-                    exportQueryBtn.textContent = t('query.exportExcel');
+                    exportQueryBtn.setAttribute('data-i18n', 'query.export');
+                    exportQueryBtn.textContent = t('query.export');
                 }
             } else if (data.affected !== undefined) {
                 // 更新/删除/插入结果（不保存到历史）
@@ -5468,91 +6852,530 @@ executeQuery.addEventListener('click', async () => {
     } finally {
         hideLoading(queryLoading);
         setButtonLoading(executeQuery, false);
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+        if (currentQueryId === queryId) {
+            currentQueryId = null;
+            if (cancelQueryBtn) {
+                cancelQueryBtn.style.display = 'none';
+            }
+        }
     }
 });
 
-// 显示查询结果（根据结果ID）
-function displayQueryResult(resultId) {
-    const result = queryResultsHistory.get(resultId);
-    if (!result) {
-        queryResults.innerHTML = `<div class="query-message">${t('query.noResults')}</div>`;
-        return;
+// 格式化单元格值（JSON 列等对象值序列化为字符串）
+function formatCellValue(value) {
+    if (typeof value === 'object') {
+        return JSON.stringify(value);
     }
-    
-    queryResultsHistory.currentResultId = resultId;
-    // 注意：切换结果时不清除排序状态，保持用户的排序选择
-    displayQueryResults(result.data);
-    updateQueryResultsTabs(); // 更新tab高亮
+    return String(value);
 }
 
-// 显示查询结果（直接显示数据）
-function displayQueryResults(rows) {
-    if (rows.length === 0) {
-        queryResults.innerHTML = `<div class="query-message">${t('query.emptyResult')}</div>`;
-        return;
-    }
-    
-    const columns = Object.keys(rows[0]);
-    
-    // 应用排序（如果有）
-    let sortedRows = [...rows];
-    if (queryResultsSortState.field && queryResultsSortState.direction) {
-        sortedRows = sortRows(rows, queryResultsSortState.field, queryResultsSortState.direction);
-    }
-    
-    // 创建表格容器
-    const tableContainer = document.createElement('div');
-    tableContainer.style.cssText = 'overflow-x: auto;';
-    
+// 重复的列名（如 SELECT a.id, b.id）追加序号，避免相互覆盖
+function uniqueColumnNames(names) {
+    const seen = {};
+    return names.map(col => {
+        if (seen[col]) {
+            seen[col]++;
+            return `${col} (${seen[col]})`;
+        }
+        seen[col] = 1;
+        return col;
+    });
+}
+
+// 将有序结果集（resultSet）转换为行对象数组
+function resultSetToRows(resultSet) {
+    const columns = uniqueColumnNames((resultSet.columns || []).map(col => col.name));
+    return (resultSet.rows || []).map(values => {
+        const row = {};
+        columns.forEach((col, i) => {
+            row[col] = values[i];
+        });
+        return row;
+    });
+}
+
+// 流式渲染时最多直接追加到表格的行数（其余行在接收完成后统一渲染）
+const STREAM_RENDER_LIMIT = 1000;
+
+// 读取 NDJSON 流式查询结果，边接收边渲染，返回完整的行对象数组
+async function readQueryStream(response) {
+    const reader = response.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = '';
+    let columns = [];
+    const rows = [];
+    
+    const container = document.createElement('div');
+    container.style.cssText = 'overflow-x: auto;';
+    const status = document.createElement('div');
+    status.className = 'query-message';
     const table = document.createElement('table');
     table.style.cssText = 'width: 100%; border-collapse: collapse;';
+    const tbody = document.createElement('tbody');
+    queryResults.innerHTML = '';
+    queryResults.appendChild(status);
+    queryResults.appendChild(container);
+    
+    const handleMessage = (message) => {
+        if (message.type === 'columns') {
+            columns = uniqueColumnNames(message.columns || []);
+            const thead = document.createElement('thead');
+            const headRow = document.createElement('tr');
+            columns.forEach(col => {
+                const th = document.createElement('th');
+                th.style.cssText = 'padding: 0.75rem; text-align: left; border-bottom: 2px solid var(--border-color); background: var(--surface-light);';
+                th.textContent = col;
+                headRow.appendChild(th);
+            });
+            thead.appendChild(headRow);
+            table.appendChild(thead);
+            table.appendChild(tbody);
+            container.appendChild(table);
+        } else if (message.type === 'rows') {
+            (message.rows || []).forEach(values => {
+                const row = {};
+                columns.forEach((col, i) => {
+                    row[col] = values[i];
+                });
+                rows.push(row);
+                if (rows.length > STREAM_RENDER_LIMIT) {
+                    return;
+                }
+                const tr = document.createElement('tr');
+                values.forEach(value => {
+                    const td = document.createElement('td');
+                    td.style.cssText = 'padding: 0.75rem;';
+                    td.textContent = value === null || value === undefined ? t('common.null') : formatCellValue(value);
+                    tr.appendChild(td);
+                });
+                tbody.appendChild(tr);
+            });
+            status.textContent = t('query.streaming', { count: rows.length });
+        } else if (message.type === 'error') {
+            throw new Error(translateApiError(message) || message.message);
+        }
+    };
     
-    // 创建表头
-    const thead = document.createElement('thead');
-    const headRow = document.createElement('tr');
-    columns.forEach(col => {
-        const th = document.createElement('th');
-        th.style.cssText = 'cursor: pointer; user-select: none; position: relative; padding: 0.75rem; text-align: left; border-bottom: 2px solid var(--border-color); background: var(--surface-light);';
-        th.className = 'sortable-header';
-        th.dataset.field = col;
-        
-        // 创建表头内容容器
-        const headerContent = document.createElement('span');
-        headerContent.textContent = col;
-        th.appendChild(headerContent);
-        
-        // 创建排序指示器
-        const sortIndicator = document.createElement('span');
-        sortIndicator.className = 'sort-indicator';
-        sortIndicator.style.cssText = 'position: absolute; right: 0.5rem; top: 50%; transform: translateY(-50%); font-size: 0.75rem; color: var(--text-secondary);';
-        
-        // 设置初始排序指示器状态
-        if (queryResultsSortState.field === col) {
-            if (queryResultsSortState.direction === 'asc') {
-                sortIndicator.textContent = '↑';
-                sortIndicator.style.color = 'var(--primary-color)';
-            } else if (queryResultsSortState.direction === 'desc') {
-                sortIndicator.textContent = '↓';
-                sortIndicator.style.color = 'var(--primary-color)';
+    while (true) {
+        const { done, value } = await reader.read();
+        if (value) {
+            buffer += decoder.decode(value, { stream: true });
+            let newline;
+            while ((newline = buffer.indexOf('\n')) >= 0) {
+                const line = buffer.slice(0, newline).trim();
+                buffer = buffer.slice(newline + 1);
+                if (line) {
+                    handleMessage(JSON.parse(line));
+                }
             }
         }
-        th.appendChild(sortIndicator);
-        
-        // 添加点击事件
-        th.addEventListener('click', () => {
-            handleQueryResultsSort(col);
-        });
-        
-        // 添加悬停效果
-        th.addEventListener('mouseenter', () => {
-            if (queryResultsSortState.field !== col) {
-                sortIndicator.textContent = '⇅';
-                sortIndicator.style.color = 'var(--text-secondary)';
-            }
-        });
-        th.addEventListener('mouseleave', () => {
-            if (queryResultsSortState.field !== col) {
-                sortIndicator.textContent = '';
+        if (done) {
+            break;
+        }
+    }
+    if (buffer.trim()) {
+        handleMessage(JSON.parse(buffer.trim()));
+    }
+    return rows;
+}
+
+// 取消正在执行的查询
+if (cancelQueryBtn) {
+    cancelQueryBtn.addEventListener('click', async () => {
+        if (!currentQueryId) {
+            return;
+        }
+        try {
+            const response = await apiRequest(`${API_BASE}/query/cancel`, {
+                method: 'POST',
+                body: JSON.stringify({ queryId: currentQueryId })
+            });
+            const data = await response.json();
+            if (response.ok && data.success) {
+                showNotification(t('query.cancelled'), 'success');
+            } else {
+                showNotification(translateApiError(data), 'error');
+            }
+        } catch (error) {
+            showNotification(error.message, 'error');
+        }
+    });
+}
+
+// 显式事务
+const beginTxBtn = document.getElementById('beginTxBtn');
+const commitTxBtn = document.getElementById('commitTxBtn');
+const rollbackTxBtn = document.getElementById('rollbackTxBtn');
+const txStatus = document.getElementById('txStatus');
+let transactionState = { active: false };
+
+// 根据事务状态更新事务按钮和“未提交的更改”提示
+function updateTransactionUI(tx) {
+    transactionState = tx || { active: false };
+    if (!beginTxBtn) {
+        return;
+    }
+    beginTxBtn.style.display = transactionState.active ? 'none' : 'inline-block';
+    commitTxBtn.style.display = transactionState.active ? 'inline-block' : 'none';
+    rollbackTxBtn.style.display = transactionState.active ? 'inline-block' : 'none';
+    if (!transactionState.active) {
+        txStatus.style.display = 'none';
+        return;
+    }
+    txStatus.style.display = 'inline-block';
+    txStatus.textContent = transactionState.dirty
+        ? t('tx.uncommitted', { count: transactionState.statements })
+        : t('tx.active');
+    txStatus.style.color = transactionState.dirty ? 'var(--danger-color)' : 'var(--text-secondary)';
+}
+
+// 从 /api/status 刷新事务状态（执行语句后调用，以更新未提交的更改提示）
+async function refreshTransactionStatus() {
+    if (!connectionId) {
+        return;
+    }
+    try {
+        const response = await apiRequest(`${API_BASE}/status`);
+        const data = await response.json();
+        if (response.ok && data.connected) {
+            updateTransactionUI(data.transaction);
+        }
+    } catch (error) {
+        console.log('刷新事务状态失败:', error);
+    }
+}
+
+// 开启、提交或回滚事务
+async function sendTransactionCommand(action, button) {
+    setButtonLoading(button, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/tx/${action}`, { method: 'POST' });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            showNotification(translateApiError(data), 'error');
+            // 事务可能已因空闲超时被自动回滚
+            await refreshTransactionStatus();
+            return;
+        }
+        if (action === 'begin') {
+            updateTransactionUI(data.transaction);
+            showNotification(t('tx.begun'), 'success');
+        } else {
+            updateTransactionUI(null);
+            showNotification(t(action === 'commit' ? 'tx.committed' : 'tx.rolledBack'), 'success');
+        }
+    } catch (error) {
+        showNotification(error.message, 'error');
+    } finally {
+        setButtonLoading(button, false);
+    }
+}
+
+if (beginTxBtn) {
+    beginTxBtn.addEventListener('click', () => sendTransactionCommand('begin', beginTxBtn));
+    commitTxBtn.addEventListener('click', () => sendTransactionCommand('commit', commitTxBtn));
+    rollbackTxBtn.addEventListener('click', () => {
+        if (transactionState.dirty && !confirm(t('tx.confirmRollback'))) {
+            return;
+        }
+        sendTransactionCommand('rollback', rollbackTxBtn);
+    });
+}
+
+// 执行多语句脚本
+const executeScriptBtn = document.getElementById('executeScriptBtn');
+const scriptContinueOnError = document.getElementById('scriptContinueOnError');
+if (executeScriptBtn) {
+    executeScriptBtn.addEventListener('click', async () => {
+        const script = sqlEditor ? sqlEditor.getValue().trim() : sqlQuery.value.trim();
+        if (!script) {
+            showNotification(t('query.empty'), 'error');
+            return;
+        }
+        
+        const queryId = 'q_' + Date.now() + '_' + Math.random().toString(36).substr(2, 9);
+        currentQueryId = queryId;
+        if (cancelQueryBtn) {
+            cancelQueryBtn.style.display = 'inline-block';
+        }
+        
+        showLoading(queryLoading);
+        setButtonLoading(executeScriptBtn, true);
+        try {
+            const response = await apiRequest(`${API_BASE}/query/script`, {
+                method: 'POST',
+                body: JSON.stringify({
+                    script,
+                    queryId,
+                    continueOnError: scriptContinueOnError ? scriptContinueOnError.checked : false
+                }),
+                timeout: 10 * 60 * 1000
+            });
+            const data = await response.json();
+            if (!response.ok || !data.success) {
+                queryResults.innerHTML = `<div class="query-message error">${translateApiError(data) || t('query.failed')}</div>`;
+                return;
+            }
+            // 结果集加入查询结果标签页，其余语句显示执行摘要
+            (data.results || []).forEach(result => {
+                if (result.resultSet) {
+                    queryResultsHistory.add(result.statement, resultSetToRows(result.resultSet));
+                }
+            });
+            updateQueryResultsTabs();
+            displayScriptResults(data);
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'none';
+            }
+        } catch (error) {
+            queryResults.innerHTML = `<div class="query-message error">${t('query.failed')}: ${error.message}</div>`;
+        } finally {
+            hideLoading(queryLoading);
+            setButtonLoading(executeScriptBtn, false);
+            if (transactionState.active) {
+                refreshTransactionStatus();
+            }
+            if (currentQueryId === queryId) {
+                currentQueryId = null;
+                if (cancelQueryBtn) {
+                    cancelQueryBtn.style.display = 'none';
+                }
+            }
+        }
+    });
+}
+
+// 执行计划：各数据库的计划统一为算子树，显示预估/实际行数、代价和耗时，原文可展开查看
+const explainQueryBtn = document.getElementById('explainQueryBtn');
+
+function formatPlanNumber(value) {
+    if (value === undefined || value === null) {
+        return '';
+    }
+    return Number.isInteger(value) ? String(value) : value.toFixed(2);
+}
+
+function renderPlanNode(node, maxCost) {
+    const metrics = [];
+    if (node.estimatedRows !== undefined) {
+        metrics.push(t('explain.estimatedRows', { rows: formatPlanNumber(node.estimatedRows) }));
+    }
+    if (node.actualRows !== undefined) {
+        metrics.push(t('explain.actualRows', { rows: formatPlanNumber(node.actualRows) }));
+    }
+    if (node.cost !== undefined) {
+        metrics.push(t('explain.cost', { cost: formatPlanNumber(node.cost) }));
+    }
+    if (node.actualTimeMs !== undefined) {
+        metrics.push(`${formatPlanNumber(node.actualTimeMs)} ms`);
+    }
+    let html = '<li><div class="plan-node">';
+    html += `<strong>${escapeHtml(node.operation)}</strong>`;
+    if (node.object) {
+        html += ` <span class="plan-object">${escapeHtml(node.object)}</span>`;
+    }
+    if (node.index) {
+        html += ` <span class="plan-index">${escapeHtml(t('explain.index', { index: node.index }))}</span>`;
+    }
+    if (metrics.length > 0) {
+        html += ` <span class="plan-metrics">${escapeHtml(metrics.join(' · '))}</span>`;
+    }
+    if (maxCost > 0 && node.cost !== undefined) {
+        html += `<div class="plan-cost-bar"><span style="width: ${Math.min(100, node.cost / maxCost * 100)}%;"></span></div>`;
+    }
+    if (node.detail) {
+        html += `<div class="plan-detail">${escapeHtml(node.detail)}</div>`;
+    }
+    html += '</div>';
+    if (node.children && node.children.length > 0) {
+        html += '<ul>' + node.children.map(child => renderPlanNode(child, maxCost)).join('') + '</ul>';
+    }
+    return html + '</li>';
+}
+
+function displayQueryPlan(plan) {
+    // 代价条相对于计划中的最大代价（代价的单位与数据库相关）
+    let maxCost = 0;
+    const visit = node => {
+        if (node.cost !== undefined) {
+            maxCost = Math.max(maxCost, node.cost);
+        }
+        (node.children || []).forEach(visit);
+    };
+    plan.nodes.forEach(visit);
+
+    let html = `<div class="query-message success">${escapeHtml(plan.analyzed ? t('explain.analyzedTitle') : t('explain.title'))}</div>`;
+    html += '<ul class="plan-tree">' + plan.nodes.map(node => renderPlanNode(node, maxCost)).join('') + '</ul>';
+    html += `<details class="plan-raw"><summary>${escapeHtml(t('explain.raw'))}</summary><pre>${escapeHtml(plan.raw)}</pre></details>`;
+    queryResults.innerHTML = html;
+}
+
+if (explainQueryBtn) {
+    explainQueryBtn.addEventListener('click', async () => {
+        const query = sqlEditor ? sqlEditor.getValue().trim() : sqlQuery.value.trim();
+        if (!query) {
+            showNotification(t('query.empty'), 'error');
+            return;
+        }
+
+        const queryId = 'q_' + Date.now() + '_' + Math.random().toString(36).substr(2, 9);
+        currentQueryId = queryId;
+        if (cancelQueryBtn) {
+            cancelQueryBtn.style.display = 'inline-block';
+        }
+
+        showLoading(queryLoading);
+        setButtonLoading(explainQueryBtn, true);
+        try {
+            const response = await apiRequest(`${API_BASE}/query/explain`, {
+                method: 'POST',
+                body: JSON.stringify({
+                    query,
+                    queryId,
+                    analyze: document.getElementById('explainAnalyze').checked
+                }),
+                timeout: 10 * 60 * 1000
+            });
+            const data = await response.json();
+            if (!response.ok || !data.success) {
+                queryResults.innerHTML = `<div class="query-message error">${escapeHtml(translateApiError(data) || t('explain.failed'))}</div>`;
+                return;
+            }
+            displayQueryPlan(data.plan);
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'none';
+            }
+        } catch (error) {
+            queryResults.innerHTML = `<div class="query-message error">${escapeHtml(t('explain.failed') + ': ' + error.message)}</div>`;
+        } finally {
+            hideLoading(queryLoading);
+            setButtonLoading(explainQueryBtn, false);
+            if (currentQueryId === queryId) {
+                currentQueryId = null;
+                if (cancelQueryBtn) {
+                    cancelQueryBtn.style.display = 'none';
+                }
+            }
+        }
+    });
+}
+
+// 显示脚本执行摘要（每条语句的状态、耗时和影响行数）
+function displayScriptResults(data) {
+    const summaryClass = data.failed > 0 ? 'error' : 'success';
+    let html = `<div class="query-message ${summaryClass}">${t('query.scriptSummary', { executed: data.executed, total: data.total, failed: data.failed })}</div>`;
+    html += '<div style="overflow-x: auto;"><table style="width: 100%; border-collapse: collapse;"><thead><tr>';
+    ['#', t('query.scriptStatement'), t('query.scriptStatus'), t('query.scriptDuration'), t('query.scriptOutcome')].forEach(title => {
+        html += `<th style="padding: 0.75rem; text-align: left; border-bottom: 2px solid var(--border-color); background: var(--surface-light);">${escapeHtml(title)}</th>`;
+    });
+    html += '</tr></thead><tbody>';
+    (data.results || []).forEach(result => {
+        let outcome = '';
+        if (!result.success) {
+            outcome = translateApiError({ errorCode: result.errorCode, params: [result.error] });
+        } else if (result.resultSet) {
+            outcome = t('query.scriptRows', { count: (result.resultSet.rows || []).length });
+        } else {
+            outcome = t('query.success', { affected: result.affected });
+        }
+        const status = result.success ? t('query.scriptOk') : t('query.scriptFailed');
+        html += `<tr>
+            <td style="padding: 0.75rem;">${result.index + 1}</td>
+            <td style="padding: 0.75rem;"><code>${escapeHtml(result.statement.length > 120 ? result.statement.slice(0, 120) + '…' : result.statement)}</code></td>
+            <td style="padding: 0.75rem; color: ${result.success ? 'var(--success-color)' : 'var(--danger-color)'};">${escapeHtml(status)}</td>
+            <td style="padding: 0.75rem;">${result.durationMs} ms</td>
+            <td style="padding: 0.75rem;">${escapeHtml(outcome)}</td>
+        </tr>`;
+    });
+    html += '</tbody></table></div>';
+    queryResults.innerHTML = html;
+}
+
+// 显示查询结果（根据结果ID）
+function displayQueryResult(resultId) {
+    const result = queryResultsHistory.get(resultId);
+    if (!result) {
+        queryResults.innerHTML = `<div class="query-message">${t('query.noResults')}</div>`;
+        return;
+    }
+    
+    queryResultsHistory.currentResultId = resultId;
+    // 注意：切换结果时不清除排序状态，保持用户的排序选择
+    displayQueryResults(result.data);
+    updateQueryResultsTabs(); // 更新tab高亮
+}
+
+// 显示查询结果（直接显示数据）
+function displayQueryResults(rows) {
+    if (rows.length === 0) {
+        queryResults.innerHTML = `<div class="query-message">${t('query.emptyResult')}</div>`;
+        return;
+    }
+    
+    const columns = Object.keys(rows[0]);
+    
+    // 应用排序（如果有）
+    let sortedRows = [...rows];
+    if (queryResultsSortState.field && queryResultsSortState.direction) {
+        sortedRows = sortRows(rows, queryResultsSortState.field, queryResultsSortState.direction);
+    }
+    
+    // 创建表格容器
+    const tableContainer = document.createElement('div');
+    tableContainer.style.cssText = 'overflow-x: auto;';
+    
+    const table = document.createElement('table');
+    table.style.cssText = 'width: 100%; border-collapse: collapse;';
+    
+    // 创建表头
+    const thead = document.createElement('thead');
+    const headRow = document.createElement('tr');
+    columns.forEach(col => {
+        const th = document.createElement('th');
+        th.style.cssText = 'cursor: pointer; user-select: none; position: relative; padding: 0.75rem; text-align: left; border-bottom: 2px solid var(--border-color); background: var(--surface-light);';
+        th.className = 'sortable-header';
+        th.dataset.field = col;
+        
+        // 创建表头内容容器
+        const headerContent = document.createElement('span');
+        headerContent.textContent = col;
+        th.appendChild(headerContent);
+        
+        // 创建排序指示器
+        const sortIndicator = document.createElement('span');
+        sortIndicator.className = 'sort-indicator';
+        sortIndicator.style.cssText = 'position: absolute; right: 0.5rem; top: 50%; transform: translateY(-50%); font-size: 0.75rem; color: var(--text-secondary);';
+        
+        // 设置初始排序指示器状态
+        if (queryResultsSortState.field === col) {
+            if (queryResultsSortState.direction === 'asc') {
+                sortIndicator.textContent = '↑';
+                sortIndicator.style.color = 'var(--primary-color)';
+            } else if (queryResultsSortState.direction === 'desc') {
+                sortIndicator.textContent = '↓';
+                sortIndicator.style.color = 'var(--primary-color)';
+            }
+        }
+        th.appendChild(sortIndicator);
+        
+        // 添加点击事件
+        th.addEventListener('click', () => {
+            handleQueryResultsSort(col);
+        });
+        
+        // 添加悬停效果
+        th.addEventListener('mouseenter', () => {
+            if (queryResultsSortState.field !== col) {
+                sortIndicator.textContent = '⇅';
+                sortIndicator.style.color = 'var(--text-secondary)';
+            }
+        });
+        th.addEventListener('mouseleave', () => {
+            if (queryResultsSortState.field !== col) {
+                sortIndicator.textContent = '';
             }
         });
         
@@ -5576,7 +7399,7 @@ function displayQueryResults(rows) {
                 nullSpan.textContent = t('common.null');
                 td.appendChild(nullSpan);
             } else {
-                td.textContent = String(value);
+                td.textContent = formatCellValue(value);
             }
             tr.appendChild(td);
         });
@@ -5751,7 +7574,7 @@ clearQuery.addEventListener('click', () => {
 // 显示/隐藏查询历史模态框
 if (showHistoryBtn) {
     showHistoryBtn.addEventListener('click', () => {
-        queryHistory.display();
+        queryHistory.reload();
         if (queryHistoryModal) {
             queryHistoryModal.style.display = 'flex';
         }
@@ -5777,70 +7600,200 @@ if (closeQueryHistoryBtn) {
 
 // 清空查询历史
 if (clearQueryHistory) {
-    clearQueryHistory.addEventListener('click', () => {
-        queryHistory.clear();
+    clearQueryHistory.addEventListener('click', async () => {
+        if (await queryHistory.clear()) {
+            queryHistory.reload();
+            showNotification(t('query.historyCleared'), 'success');
+        }
+    });
+}
+
+// 查询历史的搜索、筛选和翻页
+document.getElementById('queryHistorySearch').addEventListener('input', () => {
+    clearTimeout(queryHistory.searchTimer);
+    queryHistory.searchTimer = setTimeout(() => queryHistory.reload(), 300);
+});
+document.getElementById('queryHistoryStatus').addEventListener('change', () => queryHistory.reload());
+document.getElementById('queryHistoryCurrent').addEventListener('change', () => queryHistory.reload());
+document.getElementById('queryHistoryPrev').addEventListener('click', () => {
+    if (queryHistory.page > 1) {
+        queryHistory.page--;
         queryHistory.display();
-        showNotification(t('query.historyCleared'), 'success');
+    }
+});
+document.getElementById('queryHistoryNext').addEventListener('click', () => {
+    queryHistory.page++;
+    queryHistory.display();
+});
+
+// 导出表数据或查询结果：在导出模态框中选择格式、范围和列
+const exportModal = document.getElementById('exportModal');
+const exportFormat = document.getElementById('exportFormat');
+const exportDialect = document.getElementById('exportDialect');
+const exportScope = document.getElementById('exportScope');
+const exportColumnsList = document.getElementById('exportColumnsList');
+const confirmExport = document.getElementById('confirmExport');
+let exportTarget = 'table'; // table 导出表数据，query 导出查询结果
+let exportQuery = '';
+
+// 打开导出模态框，查询结果只能选择格式
+function openExportModal(target) {
+    exportTarget = target;
+    const isTable = target === 'table';
+    document.getElementById('exportScopeGroup').style.display = isTable ? 'block' : 'none';
+    document.getElementById('exportColumnsGroup').style.display = isTable ? 'block' : 'none';
+
+    // 默认使用当前数据库的方言
+    if (Array.from(exportDialect.options).some(o => o.value === currentDbType)) {
+        exportDialect.value = currentDbType;
+    }
+    document.getElementById('exportDialectGroup').style.display = exportFormat.value === 'sql' ? 'block' : 'none';
+
+    if (isTable) {
+        const hasFilters = hasActiveFilters(currentFilters);
+        exportScope.querySelector('option[value="filtered"]').disabled = !hasFilters;
+        exportScope.value = hasFilters ? 'filtered' : 'all';
+        exportColumnsList.innerHTML = currentColumns.map(col => `
+            <label class="export-column-option">
+                <input type="checkbox" value="${escapeHtml(col)}" checked>
+                <span>${escapeHtml(col)}</span>
+            </label>
+        `).join('');
+    }
+    exportModal.style.display = 'flex';
+}
+
+exportFormat.addEventListener('change', () => {
+    document.getElementById('exportDialectGroup').style.display = exportFormat.value === 'sql' ? 'block' : 'none';
+});
+
+function setExportColumnsChecked(checked) {
+    exportColumnsList.querySelectorAll('input[type="checkbox"]').forEach(input => {
+        input.checked = checked;
     });
 }
 
-// 导出表数据为Excel
-if (exportDataBtn) {
-    exportDataBtn.addEventListener('click', async () => {
+document.getElementById('exportSelectAllColumns').addEventListener('click', (e) => {
+    e.preventDefault();
+    setExportColumnsChecked(true);
+});
+document.getElementById('exportSelectNoColumns').addEventListener('click', (e) => {
+    e.preventDefault();
+    setExportColumnsChecked(false);
+});
+
+function closeExportModal() {
+    exportModal.style.display = 'none';
+}
+document.getElementById('closeExportModal').addEventListener('click', closeExportModal);
+document.getElementById('cancelExport').addEventListener('click', closeExportModal);
+
+// 下载导出文件，文件名优先使用响应头中的名称；body 为 null 时使用 GET 请求
+async function downloadExport(url, body, fallbackName) {
+    const response = await fetch(url, {
+        method: body ? 'POST' : 'GET',
+        headers: {
+            'Content-Type': 'application/json',
+            'X-Connection-ID': connectionId || ''
+        },
+        body: body ? JSON.stringify(body) : undefined
+    });
+
+    if (!response.ok) {
+        const errorData = await response.json();
+        throw new Error(translateApiError(errorData) || t('error.exportFailed'));
+    }
+
+    let filename = fallbackName;
+    const contentDisposition = response.headers.get('Content-Disposition');
+    if (contentDisposition) {
+        const filenameMatch = contentDisposition.match(/filename=(.+)/);
+        if (filenameMatch) {
+            filename = filenameMatch[1];
+        }
+    }
+
+    const blob = await response.blob();
+    const downloadUrl = window.URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = downloadUrl;
+    a.download = filename;
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    window.URL.revokeObjectURL(downloadUrl);
+}
+
+confirmExport.addEventListener('click', async () => {
+    const format = exportFormat.value;
+    const body = { format };
+    if (format === 'sql') {
+        body.dialect = exportDialect.value;
+    }
+    const extension = format === 'markdown' ? 'md' : format;
+    let url;
+    let fallbackName;
+    let successKey;
+
+    if (exportTarget === 'table') {
         if (!currentTable) {
             showNotification(t('error.noTable'), 'error');
             return;
         }
-        
-        setButtonLoading(exportDataBtn, true);
-        try {
-            const url = `${API_BASE}/table/export?table=${encodeURIComponent(currentTable)}&page=${currentPage}&pageSize=${pageSize}`;
-            const response = await fetch(url, {
-                method: 'GET',
-                headers: {
-                    'X-Connection-ID': connectionId || ''
-                }
-            });
-            
-            if (!response.ok) {
-                const errorData = await response.json();
-                showNotification(translateApiError(errorData) || t('error.exportFailed'), 'error');
-                return;
-            }
-            
-            // 获取文件名
-            const contentDisposition = response.headers.get('Content-Disposition');
-            let filename = `${currentTable}_page${currentPage}_${new Date().toISOString().slice(0, 10)}.xlsx`;
-            if (contentDisposition) {
-                const filenameMatch = contentDisposition.match(/filename=(.+)/);
-                if (filenameMatch) {
-                    filename = filenameMatch[1];
-                }
-            }
-            
-            // 下载文件
-            const blob = await response.blob();
-            const downloadUrl = window.URL.createObjectURL(blob);
-            const a = document.createElement('a');
-            a.href = downloadUrl;
-            a.download = filename;
-            document.body.appendChild(a);
-            a.click();
-            document.body.removeChild(a);
-            window.URL.revokeObjectURL(downloadUrl);
-            
-            showNotification(t('data.exportSuccess'), 'success');
-        } catch (error) {
-            showNotification(t('error.exportFailed') + ': ' + error.message, 'error');
-        } finally {
-            setButtonLoading(exportDataBtn, false);
+        const columns = Array.from(exportColumnsList.querySelectorAll('input[type="checkbox"]:checked')).map(input => input.value);
+        if (columns.length === 0) {
+            showNotification(t('export.noColumns'), 'error');
+            return;
+        }
+        body.table = currentTable;
+        if (columns.length < currentColumns.length) {
+            body.columns = columns;
+        }
+        if (dataTableSortState.sorts.length > 0) {
+            body.sort = dataTableSortState.sorts;
+        }
+        const scope = exportScope.value;
+        if (scope !== 'all' && hasActiveFilters(currentFilters)) {
+            body.filters = currentFilters;
+        }
+        if (scope === 'page') {
+            body.page = currentPage;
+            body.pageSize = pageSize;
+        }
+        url = `${API_BASE}/table/export`;
+        fallbackName = `${currentTable}_${new Date().toISOString().slice(0, 10)}.${extension}`;
+        successKey = 'data.exportSuccess';
+    } else {
+        body.query = exportQuery;
+        url = `${API_BASE}/query/export`;
+        fallbackName = `query_result_${new Date().toISOString().slice(0, 10)}.${extension}`;
+        successKey = 'query.exportSuccess';
+    }
+
+    setButtonLoading(confirmExport, true);
+    try {
+        await downloadExport(url, body, fallbackName);
+        closeExportModal();
+        showNotification(t(successKey), 'success');
+    } catch (error) {
+        showNotification(t('error.exportFailed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(confirmExport, false);
+    }
+});
+
+if (exportDataBtn) {
+    exportDataBtn.addEventListener('click', () => {
+        if (!currentTable) {
+            showNotification(t('error.noTable'), 'error');
+            return;
         }
+        openExportModal('table');
     });
 }
 
-// 导出查询结果为Excel
 if (exportQueryBtn) {
-    exportQueryBtn.addEventListener('click', async () => {
+    exportQueryBtn.addEventListener('click', () => {
         // 优先使用当前显示的结果的SQL，如果没有则使用编辑器中的SQL
         const currentResult = queryResultsHistory.getCurrent();
         let query = '';
@@ -5854,57 +7807,1529 @@ if (exportQueryBtn) {
             showNotification(t('query.empty'), 'error');
             return;
         }
-        
-        setButtonLoading(exportQueryBtn, true);
-        try {
-            const response = await fetch(`${API_BASE}/query/export`, {
+        exportQuery = query;
+        openExportModal('query');
+    });
+}
+
+// 导入表数据：选择文件后预览列并设置列映射，导入后显示被拒绝的行及原因
+const importModal = document.getElementById('importModal');
+const importFile = document.getElementById('importFile');
+const importMode = document.getElementById('importMode');
+const importMappingList = document.getElementById('importMappingList');
+const importResult = document.getElementById('importResult');
+const confirmImport = document.getElementById('confirmImport');
+const IMPORT_TIMEOUT = 30 * 60 * 1000; // 导入大文件可能耗时较长
+let importPreviewData = null;
+
+function openImportModal() {
+    importFile.value = '';
+    importMode.value = 'insert';
+    importPreviewData = null;
+    document.getElementById('importMappingGroup').style.display = 'none';
+    document.getElementById('importPreviewGroup').style.display = 'none';
+    importResult.style.display = 'none';
+    confirmImport.disabled = true;
+    importModal.style.display = 'flex';
+}
+
+function closeImportModal() {
+    importModal.style.display = 'none';
+}
+document.getElementById('closeImportModal').addEventListener('click', closeImportModal);
+document.getElementById('cancelImport').addEventListener('click', closeImportModal);
+
+// 构建导入请求的表单
+function buildImportForm(preview) {
+    const form = new FormData();
+    form.append('file', importFile.files[0]);
+    form.append('table', currentTable);
+    form.append('mode', importMode.value);
+    form.append('emptyAsNull', document.getElementById('importEmptyAsNull').checked ? 'true' : 'false');
+    form.append('atomic', document.getElementById('importAtomic').checked ? 'true' : 'false');
+    if (preview) {
+        form.append('preview', 'true');
+    } else {
+        const mapping = {};
+        importMappingList.querySelectorAll('select[data-source]').forEach(select => {
+            mapping[select.dataset.source] = select.value;
+        });
+        form.append('mapping', JSON.stringify(mapping));
+    }
+    return form;
+}
+
+// 读取文件的列和前几条记录，显示列映射
+importFile.addEventListener('change', async () => {
+    importPreviewData = null;
+    importResult.style.display = 'none';
+    confirmImport.disabled = true;
+    if (!importFile.files.length) return;
+
+    try {
+        const response = await apiRequest(`${API_BASE}/table/import`, { method: 'POST', body: buildImportForm(true) });
+        const data = await response.json();
+        if (!data.success) {
+            throw new Error(translateApiError(data));
+        }
+        importPreviewData = data;
+        renderImportMapping(data);
+        confirmImport.disabled = false;
+    } catch (error) {
+        document.getElementById('importMappingGroup').style.display = 'none';
+        document.getElementById('importPreviewGroup').style.display = 'none';
+        showNotification(t('error.readImportFileFailed') + ': ' + error.message, 'error');
+    }
+});
+
+function renderImportMapping(data) {
+    const columns = data.columns || [];
+    const fileColumns = (data.fileColumns || []).filter(name => name !== '');
+    const mapping = data.mapping || {};
+    importMappingList.innerHTML = fileColumns.map(source => {
+        const options = [`<option value="">${escapeHtml(t('import.skipColumn'))}</option>`]
+            .concat(columns.map(col => {
+                const selected = mapping[source] === col.name ? ' selected' : '';
+                return `<option value="${escapeHtml(col.name)}"${selected}>${escapeHtml(col.name)} (${escapeHtml(col.type)})</option>`;
+            }));
+        // 字段不固定的数据库可以导入表中还没有的字段
+        if (mapping[source] && !columns.some(col => col.name === mapping[source])) {
+            options.push(`<option value="${escapeHtml(mapping[source])}" selected>${escapeHtml(mapping[source])}</option>`);
+        }
+        return `
+            <span class="import-mapping-source" title="${escapeHtml(source)}">${escapeHtml(source)}</span>
+            <span class="import-mapping-arrow">→</span>
+            <select class="form-control" data-source="${escapeHtml(source)}">${options.join('')}</select>
+        `;
+    }).join('');
+    document.getElementById('importMappingGroup').style.display = fileColumns.length ? 'block' : 'none';
+
+    const rows = data.rows || [];
+    let html = '<table style="width: 100%; border-collapse: collapse;"><thead><tr>';
+    fileColumns.forEach(name => {
+        html += `<th style="padding: 0.4rem; text-align: left; border-bottom: 2px solid var(--border-color); background: var(--surface-light);">${escapeHtml(name)}</th>`;
+    });
+    html += '</tr></thead><tbody>';
+    rows.forEach(row => {
+        html += '<tr>';
+        fileColumns.forEach(name => {
+            html += `<td style="padding: 0.4rem; border-bottom: 1px solid var(--border-color);">${formatCellValue(row[name])}</td>`;
+        });
+        html += '</tr>';
+    });
+    html += '</tbody></table>';
+    document.getElementById('importPreview').innerHTML = html;
+    document.getElementById('importPreviewGroup').style.display = rows.length ? 'block' : 'none';
+}
+
+// 显示导入结果和被拒绝的行
+function renderImportResult(data) {
+    let summary = t('import.result', { imported: data.imported, total: data.total, rejected: data.rejected });
+    if (data.atomic && !data.committed) {
+        summary += ' ' + t(transactionState.active ? 'import.pendingInTransaction' : 'import.rolledBack');
+    }
+    let html = `<div>${escapeHtml(summary)}</div>`;
+    if (data.errors && data.errors.length) {
+        html += '<ul>';
+        data.errors.forEach(item => {
+            const row = item.rowEnd ? t('import.rowRange', { row: item.row, rowEnd: item.rowEnd }) : t('import.row', { row: item.row });
+            const column = item.column ? ` [${item.column}]` : '';
+            const reason = translateApiError({ errorCode: item.errorCode, params: item.error ? [item.error] : [] });
+            html += `<li>${escapeHtml(row + column + ': ' + reason)}</li>`;
+        });
+        html += '</ul>';
+        if (data.moreErrors) {
+            html += `<div style="color: var(--text-secondary); font-size: 0.875rem;">${escapeHtml(t('import.moreErrors'))}</div>`;
+        }
+    }
+    importResult.innerHTML = html;
+    importResult.style.display = 'block';
+}
+
+confirmImport.addEventListener('click', async () => {
+    if (!currentTable) {
+        showNotification(t('error.noTable'), 'error');
+        return;
+    }
+    if (!importFile.files.length || !importPreviewData) {
+        showNotification(t('import.noFile'), 'error');
+        return;
+    }
+    if (importMode.value === 'replace' && !confirm(t('import.replaceConfirm', { table: currentTable }))) {
+        return;
+    }
+
+    setButtonLoading(confirmImport, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/table/import`, {
+            method: 'POST',
+            body: buildImportForm(false),
+            timeout: IMPORT_TIMEOUT
+        });
+        const data = await response.json();
+        if (!data.success) {
+            throw new Error(translateApiError(data));
+        }
+        renderImportResult(data);
+        if (data.rejected > 0) {
+            showNotification(t('import.result', { imported: data.imported, total: data.total, rejected: data.rejected }), 'error');
+        } else {
+            showNotification(t('import.success', { count: data.imported }), 'success');
+        }
+        if (data.imported > 0) {
+            loadTableData();
+        }
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+    } catch (error) {
+        showNotification(t('error.importFailed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(confirmImport, false);
+    }
+});
+
+document.getElementById('importDataBtn').addEventListener('click', () => {
+    if (!currentTable) {
+        showNotification(t('error.noTable'), 'error');
+        return;
+    }
+    openImportModal();
+});
+
+// 备份和恢复：备份下载 .sql 转储文件，恢复逐条执行上传的脚本并显示进度
+const DUMP_DB_TYPES = ['mysql', 'postgresql', 'sqlite', 'sqlserver', 'oracle'];
+const RESTORE_TIMEOUT = 60 * 60 * 1000; // 恢复大文件可能耗时较长
+const dumpDatabaseBtn = document.getElementById('dumpDatabaseBtn');
+const restoreDatabaseBtn = document.getElementById('restoreDatabaseBtn');
+const dumpModal = document.getElementById('dumpModal');
+const dumpScope = document.getElementById('dumpScope');
+const confirmDump = document.getElementById('confirmDump');
+const restoreModal = document.getElementById('restoreModal');
+const restoreFile = document.getElementById('restoreFile');
+const restoreProgress = document.getElementById('restoreProgress');
+const restoreStatus = document.getElementById('restoreStatus');
+const restoreErrors = document.getElementById('restoreErrors');
+const confirmRestore = document.getElementById('confirmRestore');
+let restoreRunning = false;
+
+// 按数据库类型显示备份和恢复按钮
+function updateDumpButtons() {
+    dumpDatabaseBtn.style.display = DUMP_DB_TYPES.includes(currentDbType) ? '' : 'none';
+    restoreDatabaseBtn.style.display = ['redis', 'mongodb', 'elasticsearch'].includes(currentDbType) ? 'none' : '';
+}
+
+dumpDatabaseBtn.addEventListener('click', () => {
+    const tableOption = dumpScope.querySelector('option[value="table"]');
+    tableOption.disabled = !currentTable;
+    tableOption.textContent = currentTable ? `${t('dump.scopeTable')} (${currentTable})` : t('dump.scopeTable');
+    dumpScope.value = 'all';
+    dumpModal.style.display = 'flex';
+});
+
+function closeDumpModal() {
+    dumpModal.style.display = 'none';
+}
+document.getElementById('closeDumpModal').addEventListener('click', closeDumpModal);
+document.getElementById('cancelDump').addEventListener('click', closeDumpModal);
+
+confirmDump.addEventListener('click', async () => {
+    const body = {
+        noSchema: !document.getElementById('dumpSchema').checked,
+        noData: !document.getElementById('dumpData').checked,
+        dropTables: document.getElementById('dumpDropTables').checked
+    };
+    if (body.noSchema && body.noData) {
+        showNotification(t('dump.nothingSelected'), 'error');
+        return;
+    }
+    if (dumpScope.value === 'table' && currentTable) {
+        body.tables = [currentTable];
+    }
+
+    setButtonLoading(confirmDump, true);
+    try {
+        await downloadExport(`${API_BASE}/database/dump`, body, 'dump.sql');
+        closeDumpModal();
+        showNotification(t('dump.success'), 'success');
+    } catch (error) {
+        showNotification(t('dump.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(confirmDump, false);
+    }
+});
+
+restoreDatabaseBtn.addEventListener('click', () => {
+    if (restoreRunning) {
+        restoreModal.style.display = 'flex';
+        return;
+    }
+    restoreFile.value = '';
+    document.getElementById('restoreProgressGroup').style.display = 'none';
+    restoreErrors.style.display = 'none';
+    restoreErrors.innerHTML = '';
+    restoreModal.style.display = 'flex';
+});
+
+// 恢复执行期间关闭模态框不会中止恢复，重新打开可以继续查看进度
+function closeRestoreModal() {
+    restoreModal.style.display = 'none';
+}
+document.getElementById('closeRestoreModal').addEventListener('click', closeRestoreModal);
+document.getElementById('cancelRestore').addEventListener('click', closeRestoreModal);
+
+function updateRestoreProgress(executed, failed, total) {
+    restoreProgress.max = total || 1;
+    restoreProgress.value = executed;
+    restoreStatus.textContent = t('restore.progress', { executed, total, failed });
+}
+
+function appendRestoreError(message) {
+    let list = restoreErrors.querySelector('ul');
+    if (!list) {
+        restoreErrors.innerHTML = '<ul></ul>';
+        list = restoreErrors.querySelector('ul');
+    }
+    const reason = translateApiError({ errorCode: message.errorCode, params: [message.message] });
+    const li = document.createElement('li');
+    li.textContent = `#${message.index + 1} ${reason}`;
+    li.title = message.statement;
+    list.appendChild(li);
+    restoreErrors.style.display = 'block';
+}
+
+confirmRestore.addEventListener('click', async () => {
+    if (!restoreFile.files.length) {
+        showNotification(t('restore.noFile'), 'error');
+        return;
+    }
+    if (!confirm(t('restore.confirmMessage', { file: restoreFile.files[0].name }))) {
+        return;
+    }
+
+    const form = new FormData();
+    form.append('file', restoreFile.files[0]);
+    form.append('continueOnError', document.getElementById('restoreContinueOnError').checked ? 'true' : 'false');
+
+    restoreRunning = true;
+    restoreErrors.style.display = 'none';
+    restoreErrors.innerHTML = '';
+    document.getElementById('restoreProgressGroup').style.display = 'block';
+    updateRestoreProgress(0, 0, 0);
+    setButtonLoading(confirmRestore, true);
+    let result = null;
+    try {
+        const response = await apiRequest(`${API_BASE}/database/restore`, {
+            method: 'POST',
+            body: form,
+            timeout: RESTORE_TIMEOUT
+        });
+        const contentType = response.headers.get('Content-Type') || '';
+        if (!response.ok || !contentType.includes('application/x-ndjson')) {
+            const data = await response.json();
+            throw new Error(translateApiError(data));
+        }
+
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffer = '';
+        let total = 0;
+        const handleMessage = (message) => {
+            if (message.type === 'start') {
+                total = message.total;
+                updateRestoreProgress(0, 0, total);
+            } else if (message.type === 'progress') {
+                updateRestoreProgress(message.executed, message.failed, message.total);
+            } else if (message.type === 'error') {
+                appendRestoreError(message);
+            } else if (message.type === 'end') {
+                result = message;
+                updateRestoreProgress(message.executed, message.failed, message.total);
+            }
+        };
+        while (true) {
+            const { done, value } = await reader.read();
+            if (done) break;
+            buffer += decoder.decode(value, { stream: true });
+            let newline;
+            while ((newline = buffer.indexOf('\n')) >= 0) {
+                const line = buffer.slice(0, newline).trim();
+                buffer = buffer.slice(newline + 1);
+                if (line) {
+                    handleMessage(JSON.parse(line));
+                }
+            }
+        }
+        if (!result) {
+            throw new Error(t('restore.interrupted'));
+        }
+
+        if (result.failed > 0) {
+            const key = result.stopped ? 'restore.stopped' : 'restore.doneWithErrors';
+            showNotification(t(key, { executed: result.executed, total: result.total, failed: result.failed }), 'error');
+        } else {
+            showNotification(t('restore.success', { total: result.total }), 'success');
+        }
+    } catch (error) {
+        showNotification(t('restore.failed') + ': ' + error.message, 'error');
+    } finally {
+        restoreRunning = false;
+        setButtonLoading(confirmRestore, false);
+        loadTables();
+        if (currentTable) {
+            loadTableData();
+        }
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+    }
+});
+
+// 跨连接复制表：在后台复制当前表到另一个活动连接，轮询任务状态显示进度
+const COPY_POLL_INTERVAL = 1000;
+const copyModal = document.getElementById('copyModal');
+const copyTargetConnection = document.getElementById('copyTargetConnection');
+const copyTargetTable = document.getElementById('copyTargetTable');
+const copyProgress = document.getElementById('copyProgress');
+const copyStatus = document.getElementById('copyStatus');
+const confirmCopy = document.getElementById('confirmCopy');
+const cancelCopyJob = document.getElementById('cancelCopyJob');
+let copyJobId = null;
+let copyPollTimer = null;
+
+// 活动连接在下拉框中显示的名称
+function connectionLabel(conn) {
+    const info = conn.connectionInfo || {};
+    if (info.name && info.name.trim()) {
+        return info.name;
+    }
+    const location = info.host ? `${info.host}${info.port ? ':' + info.port : ''}` : (info.database || '');
+    return `${info.type || 'mysql'}://${location}`;
+}
+
+document.getElementById('copyTableBtn').addEventListener('click', () => {
+    if (copyJobId) {
+        copyModal.style.display = 'flex';
+        return;
+    }
+    if (!currentTable) {
+        showNotification(t('error.noTable'), 'error');
+        return;
+    }
+
+    copyTargetConnection.innerHTML = '';
+    activeConnections.forEach((conn, connId) => {
+        const option = document.createElement('option');
+        option.value = connId;
+        option.textContent = connId === connectionId
+            ? `${connectionLabel(conn)} (${t('copy.currentConnection')})`
+            : connectionLabel(conn);
+        copyTargetConnection.appendChild(option);
+    });
+    // 默认选择第一个其他连接
+    const other = Array.from(activeConnections.keys()).find(id => id !== connectionId);
+    if (other) {
+        copyTargetConnection.value = other;
+    }
+    copyTargetTable.value = currentTable;
+    document.getElementById('copyFilteredOption').style.display = hasActiveFilters(currentFilters) ? '' : 'none';
+    document.getElementById('copyProgressGroup').style.display = 'none';
+    cancelCopyJob.style.display = 'none';
+    confirmCopy.disabled = false;
+    copyModal.style.display = 'flex';
+});
+
+// 复制执行期间关闭模态框不会停止复制，重新打开可以继续查看进度
+function closeCopyModal() {
+    copyModal.style.display = 'none';
+}
+document.getElementById('closeCopyModal').addEventListener('click', closeCopyModal);
+document.getElementById('closeCopy').addEventListener('click', closeCopyModal);
+
+function updateCopyProgress(job) {
+    const total = job.total >= 0 ? job.total : job.copied;
+    copyProgress.max = total || 1;
+    copyProgress.value = job.copied;
+    let text = t('copy.progress', { copied: job.copied, total: job.total >= 0 ? job.total : '?' });
+    if (job.created) {
+        text += ' · ' + t('copy.tableCreated', { table: job.targetTable });
+    }
+    copyStatus.textContent = text;
+}
+
+function finishCopyJob(job) {
+    clearTimeout(copyPollTimer);
+    copyPollTimer = null;
+    copyJobId = null;
+    cancelCopyJob.style.display = 'none';
+    confirmCopy.disabled = false;
+    setButtonLoading(confirmCopy, false);
+    if (job.state === 'completed') {
+        showNotification(t('copy.success', { copied: job.copied, table: job.targetTable }), 'success');
+    } else if (job.state === 'cancelled') {
+        showNotification(t('copy.cancelled', { copied: job.copied }), 'success');
+    } else {
+        const reason = translateApiError({ errorCode: job.errorCode, params: [job.error] });
+        showNotification(t('copy.failed') + ': ' + reason, 'error');
+    }
+    // 复制到当前连接的其他表时刷新表列表
+    if (job.targetConnectionId === connectionId) {
+        loadTables();
+    }
+}
+
+async function pollCopyJob() {
+    if (!copyJobId) return;
+    try {
+        const response = await apiRequest(`${API_BASE}/copy/status?jobId=${encodeURIComponent(copyJobId)}`);
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            // 切换连接后任务不再可见
+            throw new Error(translateApiError(data));
+        }
+        updateCopyProgress(data.job);
+        if (data.job.state !== 'running') {
+            finishCopyJob(data.job);
+            return;
+        }
+    } catch (error) {
+        copyStatus.textContent = t('copy.failed') + ': ' + error.message;
+        clearTimeout(copyPollTimer);
+        copyPollTimer = null;
+        copyJobId = null;
+        cancelCopyJob.style.display = 'none';
+        setButtonLoading(confirmCopy, false);
+        return;
+    }
+    copyPollTimer = setTimeout(pollCopyJob, COPY_POLL_INTERVAL);
+}
+
+confirmCopy.addEventListener('click', async () => {
+    const targetConnectionId = copyTargetConnection.value;
+    const targetTable = copyTargetTable.value.trim();
+    if (!targetConnectionId) {
+        showNotification(t('copy.noTarget'), 'error');
+        return;
+    }
+    const body = {
+        targetConnectionId,
+        table: currentTable,
+        targetTable: targetTable || currentTable,
+        createTable: document.getElementById('copyCreateTable').checked,
+        truncate: document.getElementById('copyTruncate').checked
+    };
+    if (body.truncate && !confirm(t('copy.truncateConfirm', { table: body.targetTable }))) {
+        return;
+    }
+    if (document.getElementById('copyFiltered').checked && hasActiveFilters(currentFilters)) {
+        body.filters = currentFilters;
+    }
+
+    setButtonLoading(confirmCopy, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/copy/start`, {
+            method: 'POST',
+            body: JSON.stringify(body)
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        copyJobId = data.job.id;
+        document.getElementById('copyProgressGroup').style.display = 'block';
+        cancelCopyJob.style.display = '';
+        updateCopyProgress(data.job);
+        copyPollTimer = setTimeout(pollCopyJob, COPY_POLL_INTERVAL);
+    } catch (error) {
+        setButtonLoading(confirmCopy, false);
+        showNotification(t('copy.failed') + ': ' + error.message, 'error');
+    }
+});
+
+cancelCopyJob.addEventListener('click', async () => {
+    if (!copyJobId) return;
+    try {
+        const response = await apiRequest(`${API_BASE}/copy/cancel`, {
+            method: 'POST',
+            body: JSON.stringify({ jobId: copyJobId })
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+    } catch (error) {
+        showNotification(t('copy.failed') + ': ' + error.message, 'error');
+    }
+});
+
+// 表结构对比：比较两个连接（或同一连接中的两个库）的表结构，并生成使目标库与源库一致的脚本
+const schemaDiffModal = document.getElementById('schemaDiffModal');
+const schemaDiffSource = document.getElementById('schemaDiffSource');
+const schemaDiffTarget = document.getElementById('schemaDiffTarget');
+const schemaDiffSourceDatabase = document.getElementById('schemaDiffSourceDatabase');
+const schemaDiffTargetDatabase = document.getElementById('schemaDiffTargetDatabase');
+const schemaDiffResult = document.getElementById('schemaDiffResult');
+const schemaDiffScript = document.getElementById('schemaDiffScript');
+const confirmSchemaDiff = document.getElementById('confirmSchemaDiff');
+const schemaDiffOpenScript = document.getElementById('schemaDiffOpenScript');
+
+// 填充连接下拉框
+function fillConnectionSelect(select, selected) {
+    select.innerHTML = '';
+    activeConnections.forEach((conn, connId) => {
+        const option = document.createElement('option');
+        option.value = connId;
+        option.textContent = connId === connectionId
+            ? `${connectionLabel(conn)} (${t('copy.currentConnection')})`
+            : connectionLabel(conn);
+        select.appendChild(option);
+    });
+    if (selected) {
+        select.value = selected;
+    }
+}
+
+// 填充连接的数据库下拉框，空值表示连接的当前数据库
+function fillDatabaseSelect(select, connId) {
+    const conn = activeConnections.get(connId);
+    select.innerHTML = '';
+    const current = document.createElement('option');
+    current.value = '';
+    current.textContent = t('schemaDiff.currentDatabase');
+    select.appendChild(current);
+    ((conn && conn.databases) || []).forEach(name => {
+        const option = document.createElement('option');
+        option.value = name;
+        option.textContent = name;
+        select.appendChild(option);
+    });
+}
+
+document.getElementById('schemaDiffBtn').addEventListener('click', () => {
+    const other = Array.from(activeConnections.keys()).find(id => id !== connectionId);
+    fillConnectionSelect(schemaDiffSource, connectionId);
+    fillConnectionSelect(schemaDiffTarget, other || connectionId);
+    fillDatabaseSelect(schemaDiffSourceDatabase, schemaDiffSource.value);
+    fillDatabaseSelect(schemaDiffTargetDatabase, schemaDiffTarget.value);
+    schemaDiffResult.style.display = 'none';
+    document.getElementById('schemaDiffScriptGroup').style.display = 'none';
+    schemaDiffOpenScript.style.display = 'none';
+    schemaDiffModal.style.display = 'flex';
+});
+
+schemaDiffSource.addEventListener('change', () => fillDatabaseSelect(schemaDiffSourceDatabase, schemaDiffSource.value));
+schemaDiffTarget.addEventListener('change', () => fillDatabaseSelect(schemaDiffTargetDatabase, schemaDiffTarget.value));
+
+function closeSchemaDiffModal() {
+    schemaDiffModal.style.display = 'none';
+}
+document.getElementById('closeSchemaDiffModal').addEventListener('click', closeSchemaDiffModal);
+document.getElementById('closeSchemaDiff').addEventListener('click', closeSchemaDiffModal);
+
+// 列的描述：类型、可空性、默认值和主键
+function describeColumn(col) {
+    let text = `${col.type}${col.nullable ? '' : ' NOT NULL'}`;
+    if (col.default_value) {
+        text += ` DEFAULT ${col.default_value}`;
+    }
+    if (col.key) {
+        text += ` ${col.key}`;
+    }
+    return text;
+}
+
+function renderSchemaDiff(diff) {
+    schemaDiffResult.innerHTML = '';
+    const summary = document.createElement('div');
+    summary.textContent = diff.tables.length === 0
+        ? t('schemaDiff.identical', { count: diff.unchanged.length })
+        : t('schemaDiff.summary', { changed: diff.tables.length, unchanged: diff.unchanged.length });
+    schemaDiffResult.appendChild(summary);
+
+    diff.tables.forEach(table => {
+        const item = document.createElement('div');
+        item.className = 'schema-diff-table';
+        item.innerHTML = `<span class="schema-diff-status ${table.status}">${escapeHtml(t('schemaDiff.status.' + table.status))}</span> <strong>${escapeHtml(table.name)}</strong>`;
+        const list = document.createElement('ul');
+        const addLine = (text) => {
+            const li = document.createElement('li');
+            li.textContent = text;
+            list.appendChild(li);
+        };
+        (table.addedColumns || []).forEach(col => addLine(`+ ${col.name}: ${describeColumn(col)}`));
+        (table.removedColumns || []).forEach(col => addLine(`- ${col.name}: ${describeColumn(col)}`));
+        (table.changedColumns || []).forEach(change => {
+            addLine(`~ ${change.name}: ${describeColumn(change.target)} → ${describeColumn(change.source)}`);
+        });
+        if (list.children.length > 0) {
+            item.appendChild(list);
+        }
+        schemaDiffResult.appendChild(item);
+    });
+    schemaDiffResult.style.display = 'block';
+}
+
+confirmSchemaDiff.addEventListener('click', async () => {
+    const body = {
+        sourceConnectionId: schemaDiffSource.value,
+        targetConnectionId: schemaDiffTarget.value,
+        sourceDatabase: schemaDiffSourceDatabase.value,
+        targetDatabase: schemaDiffTargetDatabase.value,
+        script: true,
+        dropTables: document.getElementById('schemaDiffDropTables').checked,
+        dropColumns: document.getElementById('schemaDiffDropColumns').checked
+    };
+    setButtonLoading(confirmSchemaDiff, true);
+    try {
+        let response = await apiRequest(`${API_BASE}/schema/diff`, {
+            method: 'POST',
+            body: JSON.stringify(body),
+            timeout: 5 * 60 * 1000
+        });
+        let data = await response.json();
+        if (!response.ok && data.errorCode === 'error.schemaSyncNotSupported') {
+            // 目标库不支持生成脚本时只显示差异
+            body.script = false;
+            response = await apiRequest(`${API_BASE}/schema/diff`, {
                 method: 'POST',
-                headers: {
-                    'Content-Type': 'application/json',
-                    'X-Connection-ID': connectionId || ''
-                },
-                body: JSON.stringify({ query })
+                body: JSON.stringify(body),
+                timeout: 5 * 60 * 1000
             });
-            
-            if (!response.ok) {
-                const errorData = await response.json();
-                showNotification(translateApiError(errorData) || t('error.exportFailed'), 'error');
-                return;
+            data = await response.json();
+        }
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        renderSchemaDiff(data.diff);
+        const hasScript = Boolean(data.script);
+        schemaDiffScript.value = data.script || '';
+        document.getElementById('schemaDiffScriptGroup').style.display = hasScript ? 'block' : 'none';
+        schemaDiffOpenScript.style.display = hasScript && schemaDiffTarget.value === connectionId ? '' : 'none';
+    } catch (error) {
+        showNotification(t('schemaDiff.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(confirmSchemaDiff, false);
+    }
+});
+
+// 同步脚本只能在目标连接上执行，因此只在目标为当前连接时提供
+schemaDiffOpenScript.addEventListener('click', () => {
+    switchTab('query');
+    if (sqlEditor) {
+        sqlEditor.setValue(schemaDiffScript.value);
+        sqlEditor.focus();
+    } else {
+        sqlQuery.value = schemaDiffScript.value;
+    }
+    closeSchemaDiffModal();
+});
+
+// 数据对比：按主键对比当前表与另一张表（可以在另一个连接中）的数据，轮询任务状态显示进度和差异行
+const dataDiffModal = document.getElementById('dataDiffModal');
+const dataDiffTargetConnection = document.getElementById('dataDiffTargetConnection');
+const dataDiffTargetTable = document.getElementById('dataDiffTargetTable');
+const dataDiffProgress = document.getElementById('dataDiffProgress');
+const dataDiffStatus = document.getElementById('dataDiffStatus');
+const dataDiffResult = document.getElementById('dataDiffResult');
+const confirmDataDiff = document.getElementById('confirmDataDiff');
+const cancelDataDiffJob = document.getElementById('cancelDataDiffJob');
+const downloadDataDiffScript = document.getElementById('downloadDataDiffScript');
+let dataDiffJobId = null;
+let dataDiffScriptJobId = null;
+let dataDiffPollTimer = null;
+
+document.getElementById('dataDiffBtn').addEventListener('click', () => {
+    if (dataDiffJobId) {
+        dataDiffModal.style.display = 'flex';
+        return;
+    }
+    if (!currentTable) {
+        showNotification(t('error.noTable'), 'error');
+        return;
+    }
+
+    const other = Array.from(activeConnections.keys()).find(id => id !== connectionId);
+    fillConnectionSelect(dataDiffTargetConnection, other || connectionId);
+    dataDiffTargetTable.value = currentTable;
+    document.getElementById('dataDiffKey').value = '';
+    document.getElementById('dataDiffFilteredOption').style.display = hasActiveFilters(currentFilters) ? '' : 'none';
+    document.getElementById('dataDiffProgressGroup').style.display = 'none';
+    dataDiffResult.style.display = 'none';
+    cancelDataDiffJob.style.display = 'none';
+    downloadDataDiffScript.style.display = 'none';
+    dataDiffScriptJobId = null;
+    dataDiffModal.style.display = 'flex';
+});
+
+// 对比执行期间关闭模态框不会停止对比，重新打开可以继续查看进度
+function closeDataDiffModal() {
+    dataDiffModal.style.display = 'none';
+}
+document.getElementById('closeDataDiffModal').addEventListener('click', closeDataDiffModal);
+document.getElementById('closeDataDiff').addEventListener('click', closeDataDiffModal);
+
+function updateDataDiffProgress(job) {
+    const stats = job.stats;
+    const total = Math.max(stats.sourceTotal, stats.targetTotal);
+    dataDiffProgress.max = total || 1;
+    dataDiffProgress.value = Math.max(stats.sourceScanned, stats.targetScanned);
+    dataDiffStatus.textContent = t('dataDiff.progress', {
+        scanned: stats.sourceScanned,
+        total: stats.sourceTotal,
+        inserted: stats.inserted,
+        deleted: stats.deleted,
+        changed: stats.changed
+    });
+}
+
+// 差异行：changed 显示不同列的目标值 → 源值，inserted、deleted 显示整行
+function renderDataDiff(job) {
+    dataDiffResult.innerHTML = '';
+    const summary = document.createElement('div');
+    summary.textContent = job.rows.length === 0
+        ? t('dataDiff.identical', { count: job.stats.identical })
+        : t('dataDiff.summary', { identical: job.stats.identical });
+    dataDiffResult.appendChild(summary);
+
+    const describeRow = (row) => Object.entries(row || {})
+        .map(([name, value]) => `${name}=${value === null ? 'NULL' : formatCellValue(value)}`)
+        .join(', ');
+    job.rows.forEach(row => {
+        const item = document.createElement('div');
+        item.className = 'schema-diff-table';
+        const status = { inserted: 'added', deleted: 'removed', changed: 'changed' }[row.status];
+        item.innerHTML = `<span class="schema-diff-status ${status}">${escapeHtml(t('dataDiff.status.' + row.status))}</span> <strong>${escapeHtml(job.key)} = ${escapeHtml(formatCellValue(row.key))}</strong>`;
+        const list = document.createElement('ul');
+        const addLine = (text) => {
+            const li = document.createElement('li');
+            li.textContent = text;
+            list.appendChild(li);
+        };
+        if (row.status === 'changed') {
+            row.columns.forEach(name => {
+                const from = row.target[name] === null ? 'NULL' : formatCellValue(row.target[name]);
+                const to = row.source[name] === null ? 'NULL' : formatCellValue(row.source[name]);
+                addLine(`${name}: ${from} → ${to}`);
+            });
+        } else {
+            addLine(describeRow(row.status === 'inserted' ? row.source : row.target));
+        }
+        item.appendChild(list);
+        dataDiffResult.appendChild(item);
+    });
+    if (job.moreRows) {
+        const more = document.createElement('div');
+        more.textContent = t('dataDiff.moreRows', { count: job.rows.length });
+        dataDiffResult.appendChild(more);
+    }
+    dataDiffResult.style.display = 'block';
+}
+
+function finishDataDiffJob(job) {
+    clearTimeout(dataDiffPollTimer);
+    dataDiffPollTimer = null;
+    dataDiffJobId = null;
+    cancelDataDiffJob.style.display = 'none';
+    setButtonLoading(confirmDataDiff, false);
+    if (job.state === 'completed') {
+        renderDataDiff(job);
+        if (job.script && job.rows.length > 0) {
+            dataDiffScriptJobId = job.id;
+            downloadDataDiffScript.style.display = '';
+        }
+    } else if (job.state === 'cancelled') {
+        showNotification(t('dataDiff.cancelled'), 'success');
+    } else {
+        const reason = translateApiError({ errorCode: job.errorCode, params: [job.error] });
+        showNotification(t('dataDiff.failed') + ': ' + reason, 'error');
+    }
+}
+
+async function pollDataDiffJob() {
+    if (!dataDiffJobId) return;
+    try {
+        const response = await apiRequest(`${API_BASE}/datadiff/status?jobId=${encodeURIComponent(dataDiffJobId)}`);
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        updateDataDiffProgress(data.job);
+        if (data.job.state !== 'running') {
+            finishDataDiffJob(data.job);
+            return;
+        }
+    } catch (error) {
+        dataDiffStatus.textContent = t('dataDiff.failed') + ': ' + error.message;
+        clearTimeout(dataDiffPollTimer);
+        dataDiffPollTimer = null;
+        dataDiffJobId = null;
+        cancelDataDiffJob.style.display = 'none';
+        setButtonLoading(confirmDataDiff, false);
+        return;
+    }
+    dataDiffPollTimer = setTimeout(pollDataDiffJob, COPY_POLL_INTERVAL);
+}
+
+confirmDataDiff.addEventListener('click', async () => {
+    const body = {
+        targetConnectionId: dataDiffTargetConnection.value,
+        table: currentTable,
+        targetTable: dataDiffTargetTable.value.trim() || currentTable,
+        key: document.getElementById('dataDiffKey').value.trim(),
+        script: document.getElementById('dataDiffScript').checked
+    };
+    if (document.getElementById('dataDiffFiltered').checked && hasActiveFilters(currentFilters)) {
+        body.filters = currentFilters;
+    }
+
+    setButtonLoading(confirmDataDiff, true);
+    dataDiffResult.style.display = 'none';
+    downloadDataDiffScript.style.display = 'none';
+    dataDiffScriptJobId = null;
+    try {
+        const response = await apiRequest(`${API_BASE}/datadiff/start`, {
+            method: 'POST',
+            body: JSON.stringify(body)
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        dataDiffJobId = data.job.id;
+        document.getElementById('dataDiffProgressGroup').style.display = 'block';
+        cancelDataDiffJob.style.display = '';
+        updateDataDiffProgress(data.job);
+        dataDiffPollTimer = setTimeout(pollDataDiffJob, COPY_POLL_INTERVAL);
+    } catch (error) {
+        setButtonLoading(confirmDataDiff, false);
+        showNotification(t('dataDiff.failed') + ': ' + error.message, 'error');
+    }
+});
+
+cancelDataDiffJob.addEventListener('click', async () => {
+    if (!dataDiffJobId) return;
+    try {
+        const response = await apiRequest(`${API_BASE}/datadiff/cancel`, {
+            method: 'POST',
+            body: JSON.stringify({ jobId: dataDiffJobId })
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+    } catch (error) {
+        showNotification(t('dataDiff.failed') + ': ' + error.message, 'error');
+    }
+});
+
+downloadDataDiffScript.addEventListener('click', async () => {
+    if (!dataDiffScriptJobId) return;
+    try {
+        await downloadExport(`${API_BASE}/datadiff/script?jobId=${encodeURIComponent(dataDiffScriptJobId)}`, null, 'sync.sql');
+    } catch (error) {
+        showNotification(t('dataDiff.failed') + ': ' + error.message, 'error');
+    }
+});
+
+// ER 图：由外键生成当前数据库（或指定表及其相邻表）的 Mermaid 和 Graphviz DOT 文本
+const erDiagramModal = document.getElementById('erDiagramModal');
+const erDiagramFormat = document.getElementById('erDiagramFormat');
+const erDiagramOutput = document.getElementById('erDiagramOutput');
+const confirmErDiagram = document.getElementById('confirmErDiagram');
+let erDiagramResult = null;
+
+function showErDiagramOutput() {
+    const hasResult = Boolean(erDiagramResult);
+    erDiagramOutput.value = hasResult ? erDiagramResult[erDiagramFormat.value] : '';
+    document.getElementById('erDiagramOutputGroup').style.display = hasResult ? 'block' : 'none';
+    document.getElementById('erDiagramCopy').style.display = hasResult ? '' : 'none';
+    document.getElementById('erDiagramDownload').style.display = hasResult ? '' : 'none';
+}
+
+document.getElementById('erDiagramBtn').addEventListener('click', () => {
+    document.getElementById('erDiagramTables').value = currentTable || '';
+    erDiagramResult = null;
+    document.getElementById('erDiagramSummary').style.display = 'none';
+    showErDiagramOutput();
+    erDiagramModal.style.display = 'flex';
+});
+
+function closeErDiagramModal() {
+    erDiagramModal.style.display = 'none';
+}
+document.getElementById('closeErDiagramModal').addEventListener('click', closeErDiagramModal);
+document.getElementById('closeErDiagram').addEventListener('click', closeErDiagramModal);
+erDiagramFormat.addEventListener('change', showErDiagramOutput);
+
+confirmErDiagram.addEventListener('click', async () => {
+    const params = new URLSearchParams({
+        tables: document.getElementById('erDiagramTables').value.trim(),
+        depth: document.getElementById('erDiagramDepth').value || '1'
+    });
+    setButtonLoading(confirmErDiagram, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/schema/er?${params}`, { timeout: 5 * 60 * 1000 });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        erDiagramResult = data.diagram;
+        const summary = document.getElementById('erDiagramSummary');
+        summary.textContent = t('erDiagram.summary', {
+            tables: erDiagramResult.tables.length,
+            relations: erDiagramResult.relations.length
+        });
+        summary.style.display = 'block';
+        showErDiagramOutput();
+    } catch (error) {
+        showNotification(t('erDiagram.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(confirmErDiagram, false);
+    }
+});
+
+document.getElementById('erDiagramCopy').addEventListener('click', async () => {
+    try {
+        await navigator.clipboard.writeText(erDiagramOutput.value);
+    } catch (error) {
+        erDiagramOutput.select();
+        document.execCommand('copy');
+    }
+    showNotification(t('erDiagram.copied'), 'success');
+});
+
+document.getElementById('erDiagramDownload').addEventListener('click', () => {
+    const format = erDiagramFormat.value;
+    const blob = new Blob([erDiagramOutput.value], { type: 'text/plain;charset=utf-8' });
+    const downloadUrl = window.URL.createObjectURL(blob);
+    const a = document.createElement('a');
+    a.href = downloadUrl;
+    a.download = format === 'dot' ? 'er.dot' : 'er.mmd';
+    document.body.appendChild(a);
+    a.click();
+    document.body.removeChild(a);
+    window.URL.revokeObjectURL(downloadUrl);
+});
+
+// 表设计器：编辑表定义（列、类型、默认值、主键和索引），由服务端生成当前方言的 CREATE / ALTER 语句，预览后执行
+const tableDesignModal = document.getElementById('tableDesignModal');
+const tableDesignColumns = document.getElementById('tableDesignColumns');
+const tableDesignIndexes = document.getElementById('tableDesignIndexes');
+const tableDesignSQL = document.getElementById('tableDesignSQL');
+const previewTableDesignBtn = document.getElementById('previewTableDesign');
+const applyTableDesignBtn = document.getElementById('applyTableDesign');
+const designTableBtn = document.getElementById('designTableBtn');
+// 正在修改的表，为空表示新建表
+let tableDesignOriginal = '';
+
+// 定义改变后需要重新预览才能执行
+function resetTableDesignPreview() {
+    applyTableDesignBtn.disabled = true;
+    document.getElementById('tableDesignWarnings').style.display = 'none';
+    document.getElementById('tableDesignSQLGroup').style.display = 'none';
+}
+
+function designInput(type, value) {
+    const input = document.createElement('input');
+    input.type = type;
+    if (type === 'checkbox') {
+        input.checked = Boolean(value);
+    } else {
+        input.className = 'form-control';
+        input.value = value || '';
+    }
+    const cell = document.createElement('td');
+    cell.appendChild(input);
+    return cell;
+}
+
+function designRemoveCell(row) {
+    const cell = document.createElement('td');
+    const button = document.createElement('button');
+    button.className = 'btn btn-secondary';
+    button.textContent = '×';
+    button.addEventListener('click', () => {
+        row.remove();
+        resetTableDesignPreview();
+    });
+    cell.appendChild(button);
+    return cell;
+}
+
+function addDesignColumnRow(col) {
+    const row = document.createElement('tr');
+    row.dataset.originalName = col.originalName || '';
+    row.appendChild(designInput('text', col.name));
+    row.appendChild(designInput('text', col.type));
+    row.appendChild(designInput('checkbox', col.nullable));
+    row.appendChild(designInput('text', col.default));
+    row.appendChild(designInput('checkbox', col.primaryKey));
+    row.appendChild(designInput('checkbox', col.autoIncrement));
+    row.appendChild(designRemoveCell(row));
+    tableDesignColumns.appendChild(row);
+}
+
+function addDesignIndexRow(idx) {
+    const row = document.createElement('tr');
+    row.appendChild(designInput('text', idx.name));
+    row.appendChild(designInput('text', (idx.columns || []).join(', ')));
+    row.appendChild(designInput('checkbox', idx.unique));
+    row.appendChild(designRemoveCell(row));
+    tableDesignIndexes.appendChild(row);
+}
+
+function openTableDesign(original, design) {
+    tableDesignOriginal = original;
+    document.getElementById('tableDesignTitle').textContent = original
+        ? t('tableDesign.editTitle', { table: original })
+        : t('tableDesign.createTitle');
+    document.getElementById('tableDesignName').value = design.name || '';
+    tableDesignColumns.innerHTML = '';
+    tableDesignIndexes.innerHTML = '';
+    (design.columns || []).forEach(addDesignColumnRow);
+    (design.indexes || []).forEach(addDesignIndexRow);
+    resetTableDesignPreview();
+    tableDesignModal.style.display = 'flex';
+}
+
+// 从表单收集表定义
+function collectTableDesign() {
+    const columns = Array.from(tableDesignColumns.querySelectorAll('tr')).map(row => {
+        const inputs = row.querySelectorAll('input');
+        return {
+            name: inputs[0].value.trim(),
+            originalName: row.dataset.originalName,
+            type: inputs[1].value.trim(),
+            nullable: inputs[2].checked,
+            default: inputs[3].value.trim(),
+            primaryKey: inputs[4].checked,
+            autoIncrement: inputs[5].checked
+        };
+    });
+    const indexes = Array.from(tableDesignIndexes.querySelectorAll('tr')).map(row => {
+        const inputs = row.querySelectorAll('input');
+        return {
+            name: inputs[0].value.trim(),
+            columns: inputs[1].value.split(',').map(name => name.trim()).filter(Boolean),
+            unique: inputs[2].checked
+        };
+    });
+    return {
+        originalTable: tableDesignOriginal,
+        table: { name: document.getElementById('tableDesignName').value.trim(), columns, indexes }
+    };
+}
+
+document.getElementById('createTableBtn').addEventListener('click', () => {
+    openTableDesign('', { name: '', columns: [{ name: 'id', type: '', primaryKey: true }], indexes: [] });
+});
+
+designTableBtn.addEventListener('click', async () => {
+    if (!currentTable) return;
+    setButtonLoading(designTableBtn, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/table/design?table=${encodeURIComponent(currentTable)}`);
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        openTableDesign(currentTable, data.design);
+    } catch (error) {
+        showNotification(t('tableDesign.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(designTableBtn, false);
+    }
+});
+
+document.getElementById('tableDesignAddColumn').addEventListener('click', () => {
+    addDesignColumnRow({ nullable: true });
+    resetTableDesignPreview();
+});
+document.getElementById('tableDesignAddIndex').addEventListener('click', () => {
+    addDesignIndexRow({});
+    resetTableDesignPreview();
+});
+tableDesignModal.querySelector('.modal-body').addEventListener('input', resetTableDesignPreview);
+
+function closeTableDesignModal() {
+    tableDesignModal.style.display = 'none';
+}
+document.getElementById('closeTableDesignModal').addEventListener('click', closeTableDesignModal);
+document.getElementById('closeTableDesign').addEventListener('click', closeTableDesignModal);
+
+async function postTableDesign(action) {
+    const response = await apiRequest(`${API_BASE}/table/design/${action}`, {
+        method: 'POST',
+        body: JSON.stringify(collectTableDesign()),
+        timeout: 5 * 60 * 1000
+    });
+    const data = await response.json();
+    if (!response.ok || !data.success) {
+        throw new Error(translateApiError(data));
+    }
+    return data;
+}
+
+previewTableDesignBtn.addEventListener('click', async () => {
+    setButtonLoading(previewTableDesignBtn, true);
+    try {
+        const data = await postTableDesign('preview');
+        const warnings = document.getElementById('tableDesignWarnings');
+        warnings.innerHTML = '';
+        data.warnings.forEach(warning => {
+            const item = document.createElement('div');
+            item.textContent = warning;
+            warnings.appendChild(item);
+        });
+        warnings.style.display = data.warnings.length > 0 ? 'block' : 'none';
+        tableDesignSQL.value = data.statements.length > 0
+            ? data.statements.map(stmt => stmt + ';').join('\n\n')
+            : t('tableDesign.noChanges');
+        document.getElementById('tableDesignSQLGroup').style.display = 'block';
+        applyTableDesignBtn.disabled = data.statements.length === 0;
+    } catch (error) {
+        showNotification(t('tableDesign.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(previewTableDesignBtn, false);
+    }
+});
+
+applyTableDesignBtn.addEventListener('click', async () => {
+    setButtonLoading(applyTableDesignBtn, true);
+    try {
+        const data = await postTableDesign('apply');
+        showNotification(t('tableDesign.applied', { count: data.statements.length }), 'success');
+        closeTableDesignModal();
+        const name = collectTableDesign().table.name;
+        if (tableDesignOriginal && tableDesignOriginal === currentTable) {
+            currentTable = name;
+            loadTableSchema();
+        }
+        loadTables();
+    } catch (error) {
+        showNotification(t('tableDesign.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(applyTableDesignBtn, false);
+    }
+});
+
+// 保存的查询：个人或共享的查询片段，支持 :name 参数
+const savedQueriesModal = document.getElementById('savedQueriesModal');
+const savedQueriesList = document.getElementById('savedQueriesList');
+const savedQueryEditModal = document.getElementById('savedQueryEditModal');
+const savedQueryRunModal = document.getElementById('savedQueryRunModal');
+const savedQueryParams = document.getElementById('savedQueryParams');
+const confirmSavedQueryEdit = document.getElementById('confirmSavedQueryEdit');
+const confirmSavedQueryRun = document.getElementById('confirmSavedQueryRun');
+let editingSavedQueryId = 0;
+let runningSavedQuery = null;
+let savedQuerySearchTimer = null;
+
+// 当前编辑器中的语句
+function currentEditorQuery() {
+    return sqlEditor ? sqlEditor.getValue().trim() : sqlQuery.value.trim();
+}
+
+// 把语句放入编辑器
+function setEditorQuery(query) {
+    if (sqlEditor) {
+        sqlEditor.setValue(query);
+        sqlEditor.focus();
+    } else {
+        sqlQuery.value = query;
+    }
+}
+
+// 加载并显示保存的查询列表
+async function loadSavedQueries() {
+    const params = new URLSearchParams();
+    const search = document.getElementById('savedQuerySearch').value.trim();
+    const tag = document.getElementById('savedQueryTagFilter').value.trim();
+    if (search) {
+        params.set('search', search);
+    }
+    if (tag) {
+        params.set('tag', tag);
+    }
+    
+    try {
+        const response = await apiRequest(`${API_BASE}/query/saved?${params}`);
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        renderSavedQueries(data.queries || []);
+    } catch (error) {
+        savedQueriesList.innerHTML = `<div class="query-message error">${escapeHtml(error.message)}</div>`;
+    }
+}
+
+// 渲染保存的查询列表
+function renderSavedQueries(queries) {
+    savedQueriesList.innerHTML = '';
+    if (queries.length === 0) {
+        savedQueriesList.innerHTML = `<div style="padding: 2rem; color: var(--text-secondary); text-align: center; font-size: 0.875rem;">${t('savedQuery.empty')}</div>`;
+        return;
+    }
+    
+    queries.forEach(saved => {
+        const item = document.createElement('div');
+        item.className = 'query-history-item saved-query-item';
+        const tags = (saved.tags || []).map(tag => `<span class="saved-query-tag">${escapeHtml(tag)}</span>`).join('');
+        item.innerHTML = `
+            <div class="saved-query-header">
+                <strong>${escapeHtml(saved.name)}</strong>
+                ${saved.shared ? `<span class="saved-query-shared">${t('savedQuery.sharedBadge')}</span>` : ''}
+                ${tags}
+            </div>
+            ${saved.description ? `<div class="saved-query-description">${escapeHtml(saved.description)}</div>` : ''}
+            <div class="query-history-query">${escapeHtml(saved.query)}</div>
+            <div class="query-history-meta">
+                ${saved.owner ? `<span>${t('savedQuery.owner', { owner: escapeHtml(saved.owner) })}</span>` : ''}
+                <span>${escapeHtml(new Date(saved.updatedAt).toLocaleString())}</span>
+                ${saved.parameters.length > 0 ? `<span>${t('savedQuery.parameters', { names: saved.parameters.map(name => ':' + escapeHtml(name)).join(', ') })}</span>` : ''}
+            </div>
+            <div class="saved-query-actions">
+                <button class="btn btn-secondary" data-action="open">${t('savedQuery.open')}</button>
+                <button class="btn btn-primary" data-action="run">${t('query.execute')}</button>
+                ${saved.editable ? `<button class="btn btn-secondary" data-action="edit">${t('common.edit')}</button>
+                <button class="btn btn-danger" data-action="delete">${t('common.delete')}</button>` : ''}
+            </div>
+        `;
+        
+        item.querySelector('[data-action="open"]').addEventListener('click', () => {
+            setEditorQuery(saved.query);
+            savedQueriesModal.style.display = 'none';
+        });
+        item.querySelector('[data-action="run"]').addEventListener('click', () => {
+            savedQueriesModal.style.display = 'none';
+            openSavedQueryRun(saved);
+        });
+        if (saved.editable) {
+            item.querySelector('[data-action="edit"]').addEventListener('click', () => openSavedQueryEdit(saved));
+            item.querySelector('[data-action="delete"]').addEventListener('click', () => deleteSavedQuery(saved));
+        }
+        savedQueriesList.appendChild(item);
+    });
+}
+
+// 打开保存/编辑模态框，saved 为空时新建
+function openSavedQueryEdit(saved) {
+    editingSavedQueryId = saved ? saved.id : 0;
+    document.getElementById('savedQueryName').value = saved ? saved.name : '';
+    document.getElementById('savedQueryDescription').value = saved ? saved.description : '';
+    document.getElementById('savedQueryTags').value = saved ? (saved.tags || []).join(', ') : '';
+    document.getElementById('savedQueryText').value = saved ? saved.query : currentEditorQuery();
+    document.getElementById('savedQueryShared').checked = saved ? saved.shared : false;
+    savedQueryEditModal.style.display = 'flex';
+    document.getElementById('savedQueryName').focus();
+}
+
+// 删除保存的查询
+async function deleteSavedQuery(saved) {
+    if (!confirm(t('savedQuery.confirmDelete', { name: saved.name }))) {
+        return;
+    }
+    try {
+        const response = await apiRequest(`${API_BASE}/query/saved/delete`, {
+            method: 'POST',
+            body: JSON.stringify({ id: saved.id })
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        loadSavedQueries();
+    } catch (error) {
+        showNotification(error.message, 'error');
+    }
+}
+
+// 执行保存的查询：有参数时先填写参数
+function openSavedQueryRun(saved) {
+    if (saved.parameters.length === 0) {
+        setEditorQuery(saved.query);
+        runSavedQuery(saved, {});
+        return;
+    }
+    
+    runningSavedQuery = saved;
+    document.getElementById('savedQueryRunTitle').textContent = saved.name;
+    document.getElementById('savedQueryRunPreview').textContent = saved.query;
+    savedQueryParams.innerHTML = saved.parameters.map(name => `
+        <div class="form-group">
+            <label>:${escapeHtml(name)}</label>
+            <input type="text" class="form-control" data-param="${escapeHtml(name)}">
+        </div>
+    `).join('');
+    savedQueryRunModal.style.display = 'flex';
+    const first = savedQueryParams.querySelector('input');
+    if (first) {
+        first.focus();
+    }
+}
+
+// 执行保存的查询，参数由服务端通过驱动绑定
+async function runSavedQuery(saved, params) {
+    const queryId = 'q_' + Date.now() + '_' + Math.random().toString(36).substr(2, 9);
+    currentQueryId = queryId;
+    if (cancelQueryBtn) {
+        cancelQueryBtn.style.display = 'inline-block';
+    }
+    
+    showLoading(queryLoading);
+    try {
+        const response = await apiRequest(`${API_BASE}/query/saved/run`, {
+            method: 'POST',
+            body: JSON.stringify({ id: saved.id, params, queryId }),
+            timeout: 10 * 60 * 1000
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            queryResults.innerHTML = `<div class="query-message error">${escapeHtml(translateApiError(data) || t('query.failed'))}</div>`;
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'none';
             }
-            
-            // 获取文件名
-            const contentDisposition = response.headers.get('Content-Disposition');
-            let filename = `query_result_${new Date().toISOString().slice(0, 10)}.xlsx`;
-            if (contentDisposition) {
-                const filenameMatch = contentDisposition.match(/filename=(.+)/);
-                if (filenameMatch) {
-                    filename = filenameMatch[1];
-                }
+            return;
+        }
+        
+        if (data.data) {
+            const resultId = queryResultsHistory.add(saved.query, data.data);
+            updateQueryResultsTabs();
+            displayQueryResult(resultId);
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'inline-block';
+                exportQueryBtn.setAttribute('data-i18n', 'query.export');
+                exportQueryBtn.textContent = t('query.export');
+            }
+        } else {
+            queryResults.innerHTML = `<div class="query-message success">${t('query.success', { affected: data.affected })}</div>`;
+            if (exportQueryBtn) {
+                exportQueryBtn.style.display = 'none';
+            }
+            const queryResultsTabs = document.getElementById('queryResultsTabs');
+            if (queryResultsTabs) {
+                queryResultsTabs.style.display = 'none';
+            }
+        }
+    } catch (error) {
+        queryResults.innerHTML = `<div class="query-message error">${t('query.failed')}: ${escapeHtml(error.message)}</div>`;
+        if (exportQueryBtn) {
+            exportQueryBtn.style.display = 'none';
+        }
+    } finally {
+        hideLoading(queryLoading);
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+        if (currentQueryId === queryId) {
+            currentQueryId = null;
+            if (cancelQueryBtn) {
+                cancelQueryBtn.style.display = 'none';
             }
-            
-            // 下载文件
-            const blob = await response.blob();
-            const downloadUrl = window.URL.createObjectURL(blob);
-            const a = document.createElement('a');
-            a.href = downloadUrl;
-            a.download = filename;
-            document.body.appendChild(a);
-            a.click();
-            document.body.removeChild(a);
-            window.URL.revokeObjectURL(downloadUrl);
-            
-            showNotification(t('query.exportSuccess'), 'success');
-        } catch (error) {
-            showNotification(t('error.exportFailed') + ': ' + error.message, 'error');
-        } finally {
-            setButtonLoading(exportQueryBtn, false);
         }
+    }
+}
+
+document.getElementById('savedQueriesBtn').addEventListener('click', () => {
+    loadSavedQueries();
+    savedQueriesModal.style.display = 'flex';
+});
+
+document.getElementById('saveQueryBtn').addEventListener('click', () => {
+    if (!currentEditorQuery()) {
+        showNotification(t('query.empty'), 'error');
+        return;
+    }
+    openSavedQueryEdit(null);
+});
+
+['closeSavedQueriesModal', 'closeSavedQueries'].forEach(id => {
+    document.getElementById(id).addEventListener('click', () => {
+        savedQueriesModal.style.display = 'none';
+    });
+});
+['closeSavedQueryEditModal', 'cancelSavedQueryEdit'].forEach(id => {
+    document.getElementById(id).addEventListener('click', () => {
+        savedQueryEditModal.style.display = 'none';
+    });
+});
+['closeSavedQueryRunModal', 'cancelSavedQueryRun'].forEach(id => {
+    document.getElementById(id).addEventListener('click', () => {
+        savedQueryRunModal.style.display = 'none';
+        runningSavedQuery = null;
+    });
+});
+
+['savedQuerySearch', 'savedQueryTagFilter'].forEach(id => {
+    document.getElementById(id).addEventListener('input', () => {
+        clearTimeout(savedQuerySearchTimer);
+        savedQuerySearchTimer = setTimeout(loadSavedQueries, 300);
+    });
+});
+
+confirmSavedQueryEdit.addEventListener('click', async () => {
+    const payload = {
+        id: editingSavedQueryId,
+        name: document.getElementById('savedQueryName').value.trim(),
+        description: document.getElementById('savedQueryDescription').value.trim(),
+        tags: document.getElementById('savedQueryTags').value.split(',').map(tag => tag.trim()).filter(tag => tag),
+        query: document.getElementById('savedQueryText').value,
+        shared: document.getElementById('savedQueryShared').checked
+    };
+    if (!payload.name || !payload.query.trim()) {
+        showNotification(t('savedQuery.required'), 'error');
+        return;
+    }
+    
+    setButtonLoading(confirmSavedQueryEdit, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/query/saved/save`, {
+            method: 'POST',
+            body: JSON.stringify(payload)
+        });
+        const data = await response.json();
+        if (!response.ok || !data.success) {
+            throw new Error(translateApiError(data));
+        }
+        savedQueryEditModal.style.display = 'none';
+        showNotification(t('savedQuery.saved', { name: data.query.name }), 'success');
+        if (savedQueriesModal.style.display === 'flex') {
+            loadSavedQueries();
+        }
+    } catch (error) {
+        showNotification(error.message, 'error');
+    } finally {
+        setButtonLoading(confirmSavedQueryEdit, false);
+    }
+});
+
+confirmSavedQueryRun.addEventListener('click', () => {
+    if (!runningSavedQuery) {
+        return;
+    }
+    const params = {};
+    savedQueryParams.querySelectorAll('input[data-param]').forEach(input => {
+        params[input.dataset.param] = input.value;
     });
+    const saved = runningSavedQuery;
+    savedQueryRunModal.style.display = 'none';
+    runningSavedQuery = null;
+    runSavedQuery(saved, params);
+});
+
+// 编辑表单中显示的值：null 显示为空，对象和数组显示为 JSON
+function editValue(value) {
+    if (value === null || value === undefined) {
+        return '';
+    }
+    return formatCellValue(value);
 }
 
 // 编辑行（全局函数，供外部调用）
 window.editRow = function(rowData) {
     currentRowData = rowData;
+    setEditMode('update');
     
     // 获取列信息
     apiRequest(`${API_BASE}/table/columns?table=${currentTable}`)
@@ -5913,11 +9338,11 @@ window.editRow = function(rowData) {
             if (data.success) {
                 let formHTML = '';
                 data.columns.forEach(col => {
-                    const value = rowData[col.name] || '';
+                    const value = editValue(rowData[col.name]);
                     formHTML += `
                         <div class="edit-form-group">
                             <label>${escapeHtml(col.name)} <span style="color: var(--text-secondary);">(${col.type})</span></label>
-                            <input type="text" id="edit_${col.name}" value="${escapeHtml(String(value))}" ${col.key === 'PRI' ? 'readonly style="background: var(--surface);"' : ''}>
+                            <input type="text" id="edit_${col.name}" value="${escapeHtml(value)}" ${col.key === 'PRI' ? 'readonly style="background: var(--surface);"' : ''}>
                         </div>
                     `;
                 });
@@ -5930,8 +9355,129 @@ window.editRow = function(rowData) {
         });
 }
 
+// 切换编辑模态框的模式（编辑行/新增行）
+function setEditMode(mode) {
+    editMode = mode;
+    const titleKey = mode === 'insert' ? 'insert.title' : 'edit.title';
+    editModalTitle.setAttribute('data-i18n', titleKey);
+    editModalTitle.textContent = t(titleKey);
+}
+
+// 新增行时由数据库生成值的列（自增/标识列、计算列，文档数据库的 _id）
+function isGeneratedColumn(col) {
+    if (col.auto_increment || col.generated) {
+        return true;
+    }
+    return col.name === '_id' && (currentDbType === 'mongodb' || currentDbType === 'elasticsearch');
+}
+
+// 新增行时必须填写的列：不可为 NULL、没有默认值且不是生成列
+function isRequiredColumn(col) {
+    return !col.nullable && !col.default_value && !isGeneratedColumn(col);
+}
+
+// 打开新增行表单
+function openInsertRow() {
+    if (!currentTable) return;
+    currentRowData = null;
+    setEditMode('insert');
+
+    apiRequest(`${API_BASE}/table/columns?table=${encodeURIComponent(currentTable)}`)
+        .then(res => res.json())
+        .then(data => {
+            if (!data.success) {
+                showNotification(translateApiError(data), 'error');
+                return;
+            }
+            currentInsertColumns = data.columns || [];
+            let formHTML = '';
+            currentInsertColumns.forEach(col => {
+                let placeholder = '';
+                if (isGeneratedColumn(col)) {
+                    placeholder = t('insert.generated');
+                } else if (col.default_value) {
+                    placeholder = t('insert.defaultValue') + ': ' + col.default_value;
+                } else if (col.nullable) {
+                    placeholder = 'NULL';
+                }
+                formHTML += `
+                    <div class="edit-form-group">
+                        <label>${escapeHtml(col.name)}${isRequiredColumn(col) ? ' <span style="color: var(--danger-color);">*</span>' : ''} <span style="color: var(--text-secondary);">(${escapeHtml(col.type)})</span></label>
+                        <input type="text" id="edit_${col.name}" placeholder="${escapeHtml(placeholder)}">
+                    </div>
+                `;
+            });
+            editForm.innerHTML = formHTML;
+            editModal.style.display = 'flex';
+        })
+        .catch(err => {
+            showNotification(t('error.getTableColumnsFailed') + ': ' + err.message, 'error');
+        });
+}
+
+// 保存新增的行：留空的列不提交，由数据库使用默认值或生成值
+async function saveInsertRow() {
+    const data = {};
+    for (const col of currentInsertColumns) {
+        const input = document.getElementById(`edit_${col.name}`);
+        const value = input ? input.value.trim() : '';
+        if (value !== '') {
+            data[col.name] = value;
+        } else if (isRequiredColumn(col)) {
+            showNotification(t('insert.required') + ': ' + col.name, 'error');
+            return;
+        }
+    }
+
+    setButtonLoading(saveEdit, true);
+    try {
+        const response = await apiRequest(`${API_BASE}/row/insert`, {
+            method: 'POST',
+            body: JSON.stringify({
+                table: currentTable,
+                data: data
+            })
+        });
+        const result = await response.json();
+        if (!response.ok || !result.success) {
+            showNotification(translateApiError(result) || t('insert.failed'), 'error');
+            return;
+        }
+
+        let message = t('insert.success');
+        if (result.key && Object.keys(result.key).length > 0) {
+            message += ' (' + Object.entries(result.key).map(([k, v]) => `${k}=${formatCellValue(v)}`).join(', ') + ')';
+        }
+        showNotification(message, 'success');
+        editModal.style.display = 'none';
+        loadTableData();
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+    } catch (error) {
+        showNotification(t('insert.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(saveEdit, false);
+    }
+}
+
+// 批量编辑模式下新增的行先暂存，提交变更集时插入
+addRowBtn.addEventListener('click', () => {
+    if (batchEdit.active) {
+        addPendingInsert();
+    } else {
+        openInsertRow();
+    }
+});
+
 // 保存编辑
 saveEdit.addEventListener('click', async () => {
+    if (editMode === 'insert') {
+        if (currentTable) {
+            await saveInsertRow();
+        }
+        return;
+    }
     if (!currentTable || !currentRowData) return;
     
     // 获取主键列
@@ -5947,17 +9493,22 @@ saveEdit.addEventListener('click', async () => {
         where[pk.name] = currentRowData[pk.name];
     });
     
-    // 构建更新数据
+    // 构建更新数据（只提交修改过的列）
     const updateData = {};
     columns.forEach(col => {
         if (col.key !== 'PRI') {
             const input = document.getElementById(`edit_${col.name}`);
-            if (input) {
+            if (input && input.value.trim() !== editValue(currentRowData[col.name]).trim()) {
                 const value = input.value.trim();
                 updateData[col.name] = value === '' ? null : value;
             }
         }
     });
+    if (Object.keys(updateData).length === 0) {
+        showNotification(t('edit.noChanges'), 'success');
+        editModal.style.display = 'none';
+        return;
+    }
     
     try {
         const response = await apiRequest(`${API_BASE}/row/update`, {
@@ -5977,9 +9528,16 @@ saveEdit.addEventListener('click', async () => {
         }
         
         if (response.ok && data.success) {
-            showNotification(t('edit.save'), 'success');
+            if (data.undoId) {
+                showUndoNotification(t('edit.save'), data.undoId);
+            } else {
+                showNotification(t('edit.save'), 'success');
+            }
             editModal.style.display = 'none';
             loadTableData();
+            if (transactionState.active) {
+                refreshTransactionStatus();
+            }
         }
     } catch (error) {
         showNotification(t('edit.failed') + ': ' + error.message, 'error');
@@ -6031,9 +9589,16 @@ confirmDelete.addEventListener('click', async () => {
         }
         
         if (response.ok && data.success) {
-            showNotification(t('delete.success'), 'success');
+            if (data.undoId) {
+                showUndoNotification(t('delete.success'), data.undoId);
+            } else {
+                showNotification(t('delete.success'), 'success');
+            }
             deleteModal.style.display = 'none';
             loadTableData();
+            if (transactionState.active) {
+                refreshTransactionStatus();
+            }
         }
     } catch (error) {
         showNotification(t('delete.failed') + ': ' + error.message, 'error');
@@ -6059,28 +9624,439 @@ cancelDelete.addEventListener('click', () => {
     deleteModal.style.display = 'none';
 });
 
-// 处理数据表格排序
-function handleDataTableSort(field) {
-    // 切换排序状态：无排序 -> 升序 -> 降序 -> 无排序
-    if (dataTableSortState.field !== field) {
-        // 新字段：设置为升序
-        dataTableSortState.field = field;
-        dataTableSortState.direction = 'asc';
+// ==================== 批量编辑 ====================
+// 批量编辑模式下，单元格修改、新增行和删除行先暂存为待提交的修改，
+// 通过 /api/row/changeset 一次提交；失败的修改保留在表格中，修正后可以单独重试
+const batchEditBtn = document.getElementById('batchEditBtn');
+const pendingChangesBar = document.getElementById('pendingChangesBar');
+const pendingChangesInfo = document.getElementById('pendingChangesInfo');
+const previewChangesBtn = document.getElementById('previewChangesBtn');
+const applyChangesBtn = document.getElementById('applyChangesBtn');
+const discardChangesBtn = document.getElementById('discardChangesBtn');
+const changesetPreviewModal = document.getElementById('changesetPreviewModal');
+const changesetPreviewBody = document.getElementById('changesetPreviewBody');
+const applyChangesetPreview = document.getElementById('applyChangesetPreview');
+
+const batchEdit = {
+    active: false,
+    table: null,
+    columns: [],        // 当前表的列信息，用于确定主键
+    rows: [],           // 当前页的行数据
+    changes: new Map(), // 行标识（r+行号 或 n+序号）-> { op, data, where, tr }
+    nextInsertId: 0
+};
+
+// 重新显示表格数据时清空待提交的修改，切换表后退出批量编辑
+function resetBatchEdit(rows) {
+    batchEdit.rows = rows;
+    batchEdit.changes.clear();
+    if (batchEdit.active && batchEdit.table !== currentTable) {
+        setBatchEditActive(false);
+    }
+    updatePendingChangesBar();
+}
+
+function setBatchEditActive(active) {
+    batchEdit.active = active;
+    batchEdit.table = active ? currentTable : null;
+    const key = active ? 'data.exitBatchEdit' : 'data.batchEdit';
+    batchEditBtn.setAttribute('data-i18n', key);
+    batchEditBtn.textContent = t(key);
+    dataTableBody.classList.toggle('batch-editing', active);
+    updatePendingChangesBar();
+}
+
+function updatePendingChangesBar() {
+    const count = batchEdit.changes.size;
+    pendingChangesBar.style.display = batchEdit.active ? 'flex' : 'none';
+    pendingChangesInfo.textContent = count > 0 ? t('changeset.pending', { count }) : t('changeset.hint');
+    previewChangesBtn.disabled = count === 0;
+    applyChangesBtn.disabled = count === 0;
+    discardChangesBtn.disabled = count === 0;
+}
+
+// 进入批量编辑前加载列信息
+batchEditBtn.addEventListener('click', async () => {
+    if (batchEdit.active) {
+        if (batchEdit.changes.size > 0 && !confirm(t('changeset.discardConfirm'))) {
+            return;
+        }
+        setBatchEditActive(false);
+        loadTableData();
+        return;
+    }
+    if (!currentTable) return;
+
+    try {
+        const response = await apiRequest(`${API_BASE}/table/columns?table=${encodeURIComponent(currentTable)}`);
+        const data = await response.json();
+        if (!data.success) {
+            showNotification(translateApiError(data), 'error');
+            return;
+        }
+        batchEdit.columns = data.columns || [];
+        setBatchEditActive(true);
+    } catch (error) {
+        showNotification(t('error.getTableColumnsFailed') + ': ' + error.message, 'error');
+    }
+});
+
+// 行标识：已有的行使用行号，新增的行使用序号
+function batchRowId(tr) {
+    return tr.dataset.insertId !== undefined ? 'n' + tr.dataset.insertId : 'r' + tr.dataset.rowIndex;
+}
+
+// 已有行的定位条件（主键列的原值）
+function batchRowWhere(row) {
+    const where = {};
+    batchEdit.columns.filter(col => col.key === 'PRI').forEach(pk => {
+        where[pk.name] = row[pk.name];
+    });
+    return where;
+}
+
+// 显示单元格的值，null 显示为 NULL，新增行中未填写的列显示为默认值
+function renderBatchCell(td, value, isInsert) {
+    td.textContent = '';
+    if (value === null || value === undefined) {
+        const span = document.createElement('span');
+        span.style.color = 'var(--text-secondary)';
+        span.textContent = isInsert && value === undefined ? t('insert.defaultValue') : t('common.null');
+        td.appendChild(span);
     } else {
-        // 同一字段：切换方向
-        if (dataTableSortState.direction === 'asc') {
-            dataTableSortState.direction = 'desc';
-        } else if (dataTableSortState.direction === 'desc') {
-            // 降序后再次点击：取消排序
-            dataTableSortState.field = null;
-            dataTableSortState.direction = null;
+        td.textContent = String(value);
+    }
+}
+
+// 双击单元格进行编辑：回车或失去焦点时保存，Esc 取消
+dataTableBody.addEventListener('dblclick', (e) => {
+    if (!batchEdit.active) return;
+    const td = e.target.closest('td[data-column]');
+    if (!td || td.querySelector('input')) return;
+    const tr = td.closest('tr');
+    const column = td.dataset.column;
+    const isInsert = tr.dataset.insertId !== undefined;
+    const change = batchEdit.changes.get(batchRowId(tr));
+    if (!isInsert) {
+        // 主键列用于定位行，不能在批量编辑中修改；ClickHouse 不支持修改已有的行
+        const col = batchEdit.columns.find(c => c.name === column);
+        if ((col && col.key === 'PRI') || currentDbType === 'clickhouse' || (change && change.op === 'delete')) {
+            return;
+        }
+    }
+
+    const row = isInsert ? {} : batchEdit.rows[tr.dataset.rowIndex];
+    const current = change && change.data && column in change.data ? change.data[column] : row[column];
+    const input = document.createElement('input');
+    input.type = 'text';
+    input.className = 'cell-editor';
+    input.value = current === undefined ? '' : editValue(current);
+    td.textContent = '';
+    td.appendChild(input);
+    input.focus();
+    input.select();
+
+    let done = false;
+    const finish = (save) => {
+        if (done) return;
+        done = true;
+        if (save) {
+            setBatchCellValue(tr, td, column, input.value.trim());
+        } else {
+            renderBatchCell(td, current, isInsert);
+        }
+    };
+    input.addEventListener('keydown', (ev) => {
+        if (ev.key === 'Enter') {
+            finish(true);
+        } else if (ev.key === 'Escape') {
+            finish(false);
+        }
+    });
+    input.addEventListener('blur', () => finish(true));
+});
+
+// 记录单元格的新值：已有行中留空表示 NULL，新增行中留空表示使用默认值
+function setBatchCellValue(tr, td, column, text) {
+    const id = batchRowId(tr);
+    const isInsert = tr.dataset.insertId !== undefined;
+    let change = batchEdit.changes.get(id);
+
+    if (isInsert) {
+        if (text === '') {
+            delete change.data[column];
+        } else {
+            change.data[column] = text;
+        }
+        renderBatchCell(td, change.data[column], true);
+        td.classList.toggle('cell-changed', column in change.data);
+    } else {
+        const row = batchEdit.rows[tr.dataset.rowIndex];
+        const changed = text !== editValue(row[column]).trim();
+        if (!change && changed) {
+            change = { op: 'update', data: {}, where: batchRowWhere(row), tr };
+            batchEdit.changes.set(id, change);
+        }
+        if (change) {
+            if (changed) {
+                change.data[column] = text === '' ? null : text;
+            } else {
+                delete change.data[column];
+            }
+            if (Object.keys(change.data).length === 0) {
+                batchEdit.changes.delete(id);
+            }
+        }
+        renderBatchCell(td, changed ? (text === '' ? null : text) : row[column], false);
+        td.classList.toggle('cell-changed', changed);
+    }
+    tr.classList.remove('row-change-failed');
+    tr.removeAttribute('title');
+    updatePendingChangesBar();
+}
+
+// 标记或取消标记删除行，取消后恢复之前的单元格修改
+function togglePendingDelete(tr) {
+    const id = batchRowId(tr);
+    const change = batchEdit.changes.get(id);
+    if (change && change.op === 'delete') {
+        if (Object.keys(change.data).length > 0) {
+            change.op = 'update';
+        } else {
+            batchEdit.changes.delete(id);
+        }
+        tr.classList.remove('row-pending-delete');
+    } else {
+        const row = batchEdit.rows[tr.dataset.rowIndex];
+        batchEdit.changes.set(id, { op: 'delete', data: change ? change.data : {}, where: batchRowWhere(row), tr });
+        tr.classList.add('row-pending-delete');
+    }
+    tr.classList.remove('row-change-failed');
+    tr.removeAttribute('title');
+    updatePendingChangesBar();
+}
+
+// 在表格顶部添加一行待插入的数据
+function addPendingInsert() {
+    const headers = Array.from(dataTableHead.querySelectorAll('th[data-field]')).map(th => th.dataset.field);
+    if (headers.length === 0) return;
+    // 表格为空时移除“没有数据”提示
+    if (batchEdit.rows.length === 0) {
+        dataTableBody.querySelectorAll('tr:not([data-row-index]):not([data-insert-id])').forEach(tr => tr.remove());
+    }
+
+    const tr = document.createElement('tr');
+    tr.className = 'row-pending-insert';
+    tr.dataset.insertId = batchEdit.nextInsertId++;
+    headers.forEach(col => {
+        const td = document.createElement('td');
+        td.dataset.column = col;
+        renderBatchCell(td, undefined, true);
+        tr.appendChild(td);
+    });
+    if (dataTableHead.querySelector('.action-column-header')) {
+        const actionTd = document.createElement('td');
+        actionTd.className = 'action-column-cell';
+        const removeBtn = document.createElement('button');
+        removeBtn.className = 'btn btn-secondary action-btn';
+        removeBtn.textContent = t('common.cancel');
+        removeBtn.addEventListener('click', () => {
+            batchEdit.changes.delete(batchRowId(tr));
+            tr.remove();
+            updatePendingChangesBar();
+        });
+        actionTd.appendChild(removeBtn);
+        tr.appendChild(actionTd);
+    }
+    dataTableBody.insertBefore(tr, dataTableBody.firstChild);
+    batchEdit.changes.set(batchRowId(tr), { op: 'insert', data: {}, where: null, tr });
+    updatePendingChangesBar();
+}
+
+// 按添加顺序生成变更集请求
+function buildChangeset() {
+    const ids = Array.from(batchEdit.changes.keys());
+    const changes = ids.map(id => {
+        const change = batchEdit.changes.get(id);
+        if (change.op === 'insert') {
+            return { op: 'insert', data: change.data };
+        }
+        if (change.op === 'delete') {
+            return { op: 'delete', where: change.where };
+        }
+        return { op: 'update', data: change.data, where: change.where };
+    });
+    return { ids, changes };
+}
+
+async function sendChangeset(preview) {
+    const { ids, changes } = buildChangeset();
+    const response = await apiRequest(`${API_BASE}/row/changeset`, {
+        method: 'POST',
+        body: JSON.stringify({ table: currentTable, changes, preview })
+    });
+    const data = await response.json();
+    return { ids, response, data };
+}
+
+// 预览将要执行的语句
+previewChangesBtn.addEventListener('click', async () => {
+    if (batchEdit.changes.size === 0) return;
+    setButtonLoading(previewChangesBtn, true);
+    try {
+        const { ids, response, data } = await sendChangeset(true);
+        if (!response.ok || !data.success) {
+            showNotification(translateApiError(data) || t('changeset.failed'), 'error');
+            return;
+        }
+        markChangesetResults(ids, data.results);
+        let html = '';
+        data.results.forEach(result => {
+            let body;
+            if (result.status === 'failed') {
+                body = `<div style="color: var(--danger-color);">${escapeHtml(changesetErrorMessage(result))}</div>`;
+            } else if (result.status === 'skipped') {
+                body = `<div style="color: var(--text-secondary);">${escapeHtml(t('changeset.skipped'))}</div>`;
+            } else {
+                body = `<pre class="changeset-statement">${escapeHtml(result.statement)}</pre>`;
+                if (result.args && result.args.length > 0) {
+                    body += `<div style="color: var(--text-secondary); font-size: 0.875rem;">${escapeHtml(t('changeset.args'))}: ${escapeHtml(result.args.map(formatCellValue).join(', '))}</div>`;
+                }
+            }
+            html += `<div class="changeset-preview-item"><strong>#${result.index + 1} ${escapeHtml(result.op)}</strong>${body}</div>`;
+        });
+        changesetPreviewBody.innerHTML = html;
+        applyChangesetPreview.disabled = data.failed > 0;
+        changesetPreviewModal.style.display = 'flex';
+    } catch (error) {
+        showNotification(t('changeset.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(previewChangesBtn, false);
+    }
+});
+
+// 提交变更集：全部成功后刷新表格，否则移除已生效的修改，标记失败的行
+async function applyChangeset(button) {
+    if (batchEdit.changes.size === 0) return;
+    setButtonLoading(button, true);
+    try {
+        const { ids, response, data } = await sendChangeset(false);
+        if (!response.ok || !data.success) {
+            showNotification(translateApiError(data) || t('changeset.failed'), 'error');
+            return;
+        }
+        changesetPreviewModal.style.display = 'none';
+        if (transactionState.active) {
+            refreshTransactionStatus();
+        }
+
+        const message = t('changeset.result', { applied: data.applied, failed: data.failed });
+        if (data.applied === data.total) {
+            showNotification(message, 'success');
+            loadTableData();
+            return;
+        }
+        if (data.results.some(result => result.status === 'rolledBack')) {
+            showNotification(message + ' ' + t('changeset.rolledBack'), 'error');
+        } else {
+            showNotification(message, 'error');
+        }
+        markChangesetResults(ids, data.results);
+    } catch (error) {
+        showNotification(t('changeset.failed') + ': ' + error.message, 'error');
+    } finally {
+        setButtonLoading(button, false);
+    }
+}
+
+applyChangesBtn.addEventListener('click', () => applyChangeset(applyChangesBtn));
+applyChangesetPreview.addEventListener('click', () => applyChangeset(applyChangesetPreview));
+
+// 按每项修改的状态更新表格：已生效的修改从待提交列表中移除，失败的行标记错误信息
+function markChangesetResults(ids, results) {
+    results.forEach(result => {
+        const id = ids[result.index];
+        const change = batchEdit.changes.get(id);
+        if (!change) return;
+        const tr = change.tr;
+        if (result.status === 'applied') {
+            batchEdit.changes.delete(id);
+            if (change.op === 'update') {
+                const row = batchEdit.rows[tr.dataset.rowIndex];
+                Object.assign(row, change.data);
+                tr.querySelectorAll('.cell-changed').forEach(td => td.classList.remove('cell-changed'));
+            } else {
+                // 删除的行和新增的行在刷新表格后显示
+                tr.remove();
+            }
+        } else if (result.status === 'failed') {
+            tr.classList.add('row-change-failed');
+            tr.title = changesetErrorMessage(result);
+        }
+    });
+    updatePendingChangesBar();
+}
+
+function changesetErrorMessage(result) {
+    return translateApiError({ errorCode: result.errorCode, params: result.error ? [result.error] : [] });
+}
+
+discardChangesBtn.addEventListener('click', () => {
+    if (!confirm(t('changeset.discardConfirm'))) return;
+    loadTableData();
+});
+
+document.getElementById('closeChangesetPreview').addEventListener('click', () => {
+    changesetPreviewModal.style.display = 'none';
+});
+document.getElementById('cancelChangesetPreview').addEventListener('click', () => {
+    changesetPreviewModal.style.display = 'none';
+});
+
+// 处理数据表格排序（服务端排序）
+// 点击：按该列排序，依次切换 升序 -> 降序 -> 无排序
+// Shift+点击：追加为次要排序列，或切换已有排序列的方向
+function handleDataTableSort(field, append = false) {
+    const sorts = dataTableSortState.sorts;
+    const index = sorts.findIndex(s => s.column === field);
+    
+    if (append) {
+        if (index < 0) {
+            sorts.push({ column: field, direction: 'asc' });
+        } else if (sorts[index].direction === 'asc') {
+            sorts[index].direction = 'desc';
+        } else {
+            sorts.splice(index, 1);
         }
+    } else if (index < 0 || sorts.length > 1) {
+        dataTableSortState.sorts = [{ column: field, direction: 'asc' }];
+    } else if (sorts[index].direction === 'asc') {
+        sorts[index].direction = 'desc';
+    } else {
+        dataTableSortState.sorts = [];
     }
     
-    // 使用当前已显示的数据重新显示（应用排序）
-    if (currentTableData.rows.length > 0) {
-        displayTableData(currentTableData.rows, currentTableData.total, currentTableData.isClickHouse);
+    // 排序变化后分页游标失效，从第一页重新加载
+    currentPage = 1;
+    lastId = null;
+    firstId = null;
+    pageIdMap.clear();
+    idHistory = [];
+    maxVisitedPage = 0;
+    loadTableData();
+}
+
+// getDataTableSortParam 返回表数据请求的排序参数
+// withFilters 为 true 时同时附带过滤条件（用于 /table/page-id 定位排序后的页）
+function getDataTableSortParam(withFilters = false) {
+    if (dataTableSortState.sorts.length === 0) {
+        return '';
+    }
+    let param = `&sort=${encodeURIComponent(JSON.stringify(dataTableSortState.sorts))}`;
+    if (withFilters && hasActiveFilters(currentFilters)) {
+        param += `&filters=${encodeURIComponent(JSON.stringify(currentFilters))}`;
     }
+    return param;
 }
 
 // 处理查询结果排序
@@ -6187,6 +10163,46 @@ function showNotification(message, type) {
     }, 3000);
 }
 
+// 显示带撤销按钮的通知，点击后撤销刚才的行修改
+function showUndoNotification(message, undoId) {
+    const notification = document.createElement('div');
+    notification.className = 'undo-notification';
+    notification.innerHTML = `<span>${escapeHtml(message)}</span><button type="button" class="undo-notification-btn">${t('rowUndo.undo')}</button>`;
+    document.body.appendChild(notification);
+
+    const close = () => {
+        notification.style.animation = 'slideOut 0.3s';
+        setTimeout(() => notification.remove(), 300);
+    };
+    const timer = setTimeout(close, 10000);
+
+    const button = notification.querySelector('.undo-notification-btn');
+    button.addEventListener('click', async () => {
+        clearTimeout(timer);
+        button.disabled = true;
+        try {
+            const response = await apiRequest(`${API_BASE}/row/undo`, {
+                method: 'POST',
+                body: JSON.stringify({ undoId })
+            });
+            const data = await response.json();
+            close();
+            if (!response.ok || !data.success) {
+                showNotification(translateApiError(data) || t('rowUndo.failed'), 'error');
+                return;
+            }
+            showNotification(t('rowUndo.success'), 'success');
+            loadTableData();
+            if (transactionState.active) {
+                refreshTransactionStatus();
+            }
+        } catch (error) {
+            close();
+            showNotification(t('rowUndo.failed') + ': ' + error.message, 'error');
+        }
+    });
+}
+
 // 添加动画样式
 const style = document.createElement('style');
 style.textContent = `
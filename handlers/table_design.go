@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// tableDesignRequest 表设计器的请求：originalTable 为空时建表，否则修改该表
+type tableDesignRequest struct {
+	OriginalTable string               `json:"originalTable"`
+	Table         database.TableDesign `json:"table"`
+}
+
+// GetTableDesign 返回已有表的表定义（table 查询参数），作为表设计器修改表时的初始值
+func (s *Server) GetTableDesign(w http.ResponseWriter, r *http.Request) {
+	tableName := r.URL.Query().Get("table")
+	if tableName == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingTableName)
+		return
+	}
+	s.serveMetadata(w, r, "design", func(ctx context.Context, session *ConnectionSession, mdb database.MetadataDatabase) (interface{}, error) {
+		meta, err := mdb.GetTableMetadata(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		return database.DesignFromMetadata(session.dbType, meta), nil
+	})
+}
+
+// PreviewTableDesign 生成使表符合提交的表定义的 CREATE TABLE / ALTER TABLE 语句（当前连接的方言），不执行
+// 请求体：{"originalTable": "", "table": {"name": "...", "columns": [...], "indexes": [...]}}
+// 返回的 warnings 为需要手动处理的变更
+func (s *Server) PreviewTableDesign(w http.ResponseWriter, r *http.Request) {
+	_, statements, warnings, ok := s.buildTableDesign(w, r)
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"statements": statements,
+		"warnings":   warnings,
+	})
+}
+
+// ApplyTableDesign 按与预览相同的方式重新生成语句，经过 SQL 校验器后按顺序执行
+// 执行前先校验所有语句；某条语句失败时停止，之前的语句已经生效（大多数数据库的 DDL 不能回滚）
+func (s *Server) ApplyTableDesign(w http.ResponseWriter, r *http.Request) {
+	session, statements, warnings, ok := s.buildTableDesign(w, r)
+	if !ok {
+		return
+	}
+
+	infos := make([]database.StatementInfo, len(statements))
+	for i, stmt := range statements {
+		infos[i] = database.ClassifySQL(session.dbType, stmt)
+		if err := s.validateSQL(stmt, infos[i].Keyword); err != nil {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeSQLValidationFailed, fmt.Sprintf("#%d %v", i+1, err))
+			return
+		}
+	}
+
+	ctx, cancel := s.queryContext(r, session)
+	defer cancel()
+	db, done := s.sessionDB(session, true)
+	defer done()
+	for i, stmt := range statements {
+		started := time.Now()
+		affected, errCode, err := executeStatement(ctx, db, infos[i], stmt)
+		s.auditStatement(r, session, AuditSourceTableDesign, infos[i], stmt, nil, started, affected, err)
+		if err != nil {
+			if errCode == ErrCodeExecuteUpdateFailed {
+				errCode = ErrCodeTableDesignFailed
+			}
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, errCode), fmt.Sprintf("#%d %v", i+1, err))
+			return
+		}
+	}
+	s.getLogger().Info(ctx, "Table design applied to %s: %d statements", session.dbType, len(statements))
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"statements": statements,
+		"warnings":   warnings,
+	})
+}
+
+// buildTableDesign 解析请求并生成语句；修改已有表时需要读取表的元数据。失败时已写入错误响应
+func (s *Server) buildTableDesign(w http.ResponseWriter, r *http.Request) (*ConnectionSession, []string, []string, bool) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return nil, nil, nil, false
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return nil, nil, nil, false
+	}
+
+	var req tableDesignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeParseRequestFailed, err)
+		return nil, nil, nil, false
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return nil, nil, nil, false
+	}
+	if !database.SupportsCreateTable(session.dbType) {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTableDesignNotSupported, session.dbType)
+		return nil, nil, nil, false
+	}
+
+	var current *database.TableMetadata
+	if req.OriginalTable != "" {
+		mdb, ok := metadataDatabase(session.db)
+		if !ok {
+			writeJSONError(w, http.StatusBadRequest, ErrCodeTableDesignNotSupported, session.dbType)
+			return nil, nil, nil, false
+		}
+		ctx, cancel := s.queryContext(r, session)
+		defer cancel()
+		current, err = mdb.GetTableMetadata(ctx, req.OriginalTable)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, queryErrorCode(ctx, err, ErrCodeGetMetadataFailed), err)
+			return nil, nil, nil, false
+		}
+	}
+
+	statements, warnings, err := database.BuildTableDesignSQL(session.dbType, current, req.Table)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeInvalidTableDesign, err)
+		return nil, nil, nil, false
+	}
+	if statements == nil {
+		statements = []string{}
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return session, statements, warnings, true
+}
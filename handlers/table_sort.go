@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// parseSortParam 解析 GET 请求中的 sort 参数（SortSpec 数组的 JSON 字符串）
+func parseSortParam(r *http.Request) ([]database.SortSpec, error) {
+	sortStr := r.URL.Query().Get("sort")
+	if sortStr == "" {
+		return nil, nil
+	}
+	var sorts []database.SortSpec
+	if err := json.Unmarshal([]byte(sortStr), &sorts); err != nil {
+		return nil, err
+	}
+	return sorts, nil
+}
+
+// validateSortColumns 校验排序条件，排序列必须是表中存在的列
+// MongoDB、Elasticsearch、Redis 的列信息来自采样数据，只做格式校验
+func validateSortColumns(dbType string, sorts []database.SortSpec, columns []database.ColumnInfo) error {
+	if err := database.ValidateSorts(sorts); err != nil {
+		return err
+	}
+	switch dbType {
+	case "mongodb", "elasticsearch", "redis":
+		return nil
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col.Name] = true
+	}
+	for _, s := range sorts {
+		if !known[s.Column] {
+			return fmt.Errorf("unknown column: %s", s.Column)
+		}
+	}
+	return nil
+}
+
+// rowCursor 生成行的分页游标（排序列 + 主键），编码失败时返回 nil
+func rowCursor(row map[string]interface{}, sorts []database.SortSpec, primaryKey string) interface{} {
+	token, err := database.EncodeCursor(database.CursorFromRow(row, database.SortKeys(sorts, primaryKey)))
+	if err != nil {
+		return nil
+	}
+	return token
+}
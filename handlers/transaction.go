@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gotoailab/simple-db-web/database"
+)
+
+// defaultTransactionIdleTimeout 默认的空闲事务超时时间
+const defaultTransactionIdleTimeout = 5 * time.Minute
+
+// sessionTransaction 会话中打开的显式事务
+type sessionTransaction struct {
+	tx           database.Transaction
+	startedAt    time.Time
+	lastActivity time.Time
+	statements   int  // 在事务中执行的语句数
+	active       int  // 正在执行的语句数，大于 0 时不会因空闲而回滚
+	dirty        bool // 是否执行过修改类语句（存在未提交的更改）
+	timer        *time.Timer
+}
+
+// SetTransactionIdleTimeout 设置空闲事务超时时间
+// 事务超过该时间没有执行任何语句时自动回滚，0 表示不自动回滚
+// 示例：
+//
+//	server.SetTransactionIdleTimeout(10 * time.Minute)
+func (s *Server) SetTransactionIdleTimeout(timeout time.Duration) {
+	s.txIdleTimeoutMutex.Lock()
+	defer s.txIdleTimeoutMutex.Unlock()
+	s.txIdleTimeout = timeout
+}
+
+// getTransactionIdleTimeout 获取空闲事务超时时间
+func (s *Server) getTransactionIdleTimeout() time.Duration {
+	s.txIdleTimeoutMutex.RLock()
+	defer s.txIdleTimeoutMutex.RUnlock()
+	return s.txIdleTimeout
+}
+
+// sessionDB 返回执行语句使用的数据库，语句（包括读取流式结果）结束后必须调用 done
+// 会话中有打开的事务时返回在事务中执行语句的包装：在 done 之前事务不会因空闲而回滚，
+// done 时重新开始空闲计时；write 表示语句会修改数据
+func (s *Server) sessionDB(session *ConnectionSession, write bool) (database.ContextDatabase, func()) {
+	session.txMutex.Lock()
+	defer session.txMutex.Unlock()
+
+	st := session.tx
+	if st == nil {
+		return database.AsContextDatabase(session.db), func() {}
+	}
+	st.lastActivity = time.Now()
+	st.statements++
+	st.active++
+	if write {
+		st.dirty = true
+	}
+	if st.timer != nil {
+		st.timer.Stop()
+	}
+
+	var once sync.Once
+	done := func() {
+		once.Do(func() {
+			session.txMutex.Lock()
+			defer session.txMutex.Unlock()
+			st.active--
+			st.lastActivity = time.Now()
+			if st.active == 0 && st.timer != nil && session.tx == st {
+				st.timer.Reset(s.getTransactionIdleTimeout())
+			}
+		})
+	}
+	return database.WithTransaction(session.db, st.tx), done
+}
+
+// hasTransaction 判断会话是否有打开的事务
+func (session *ConnectionSession) hasTransaction() bool {
+	session.txMutex.Lock()
+	defer session.txMutex.Unlock()
+	return session.tx != nil
+}
+
+// takeTransaction 取出并清除会话中的事务，停止空闲计时
+func (session *ConnectionSession) takeTransaction() *sessionTransaction {
+	session.txMutex.Lock()
+	defer session.txMutex.Unlock()
+
+	st := session.tx
+	session.tx = nil
+	if st != nil && st.timer != nil {
+		st.timer.Stop()
+	}
+	return st
+}
+
+// transactionStatus 返回会话的事务状态，用于 /api/status 和事务接口的响应
+func (session *ConnectionSession) transactionStatus() map[string]interface{} {
+	session.txMutex.Lock()
+	defer session.txMutex.Unlock()
+
+	st := session.tx
+	if st == nil {
+		return map[string]interface{}{"active": false}
+	}
+	return map[string]interface{}{
+		"active":       true,
+		"startedAt":    st.startedAt,
+		"lastActivity": st.lastActivity,
+		"statements":   st.statements,
+		"dirty":        st.dirty,
+	}
+}
+
+// rollbackSessionTransaction 回滚会话中打开的事务（断开连接等场景使用）
+func (s *Server) rollbackSessionTransaction(ctx context.Context, session *ConnectionSession) {
+	if st := session.takeTransaction(); st != nil {
+		if err := st.tx.Rollback(); err != nil {
+			s.getLogger().Warn(ctx, "Failed to roll back transaction: %v", err)
+		}
+	}
+}
+
+// BeginTransaction 在会话中开启显式事务
+func (s *Server) BeginTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	tdb, ok := session.db.(database.TransactionalDatabase)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeTransactionNotSupported)
+		return
+	}
+	if session.hasTransaction() {
+		writeJSONError(w, http.StatusConflict, ErrCodeTransactionActive)
+		return
+	}
+
+	tx, err := tdb.BeginTx(r.Context())
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, ErrCodeBeginTransactionFailed, err)
+		return
+	}
+
+	now := time.Now()
+	st := &sessionTransaction{tx: tx, startedAt: now, lastActivity: now}
+	if timeout := s.getTransactionIdleTimeout(); timeout > 0 {
+		st.timer = time.AfterFunc(timeout, func() {
+			s.expireTransaction(connectionID, session, st)
+		})
+	}
+
+	session.txMutex.Lock()
+	if session.tx != nil {
+		// 并发的 BEGIN 请求已经开启了事务
+		session.txMutex.Unlock()
+		if st.timer != nil {
+			st.timer.Stop()
+		}
+		tx.Rollback()
+		writeJSONError(w, http.StatusConflict, ErrCodeTransactionActive)
+		return
+	}
+	session.tx = st
+	session.txMutex.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":     true,
+		"transaction": session.transactionStatus(),
+	})
+}
+
+// CommitTransaction 提交会话中的事务
+func (s *Server) CommitTransaction(w http.ResponseWriter, r *http.Request) {
+	s.finishTransaction(w, r, true)
+}
+
+// RollbackTransaction 回滚会话中的事务
+func (s *Server) RollbackTransaction(w http.ResponseWriter, r *http.Request) {
+	s.finishTransaction(w, r, false)
+}
+
+// finishTransaction 提交或回滚会话中的事务
+func (s *Server) finishTransaction(w http.ResponseWriter, r *http.Request, commit bool) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed)
+		return
+	}
+
+	connectionID := getConnectionID(r)
+	if connectionID == "" {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeMissingConnectionID)
+		return
+	}
+
+	session, err := s.getSession(connectionID)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeConnectionNotExists, err)
+		return
+	}
+
+	st := session.takeTransaction()
+	if st == nil {
+		writeJSONError(w, http.StatusBadRequest, ErrCodeNoActiveTransaction)
+		return
+	}
+
+	if commit {
+		err = st.tx.Commit()
+	} else {
+		err = st.tx.Rollback()
+	}
+	if err != nil {
+		errCode := ErrCodeRollbackTransactionFailed
+		if commit {
+			errCode = ErrCodeCommitTransactionFailed
+		}
+		writeJSONError(w, http.StatusInternalServerError, errCode, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":    true,
+		"committed":  commit,
+		"statements": st.statements,
+	})
+}
+
+// expireTransaction 空闲超时后自动回滚事务
+func (s *Server) expireTransaction(connectionID string, session *ConnectionSession, st *sessionTransaction) {
+	session.txMutex.Lock()
+	if session.tx != st {
+		// 事务已经结束
+		session.txMutex.Unlock()
+		return
+	}
+	if timeout := s.getTransactionIdleTimeout(); st.active > 0 || (timeout > 0 && time.Since(st.lastActivity) < timeout) {
+		// 计时触发后又开始执行语句：语句结束时会重新开始计时
+		session.txMutex.Unlock()
+		return
+	}
+	session.tx = nil
+	session.txMutex.Unlock()
+
+	ctx := context.Background()
+	if err := st.tx.Rollback(); err != nil {
+		s.getLogger().Warn(ctx, "Failed to roll back idle transaction of connection %s: %v", connectionID, err)
+		return
+	}
+	s.getLogger().Info(ctx, "Idle transaction of connection %s rolled back after %v", connectionID, time.Since(st.lastActivity))
+}